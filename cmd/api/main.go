@@ -2,14 +2,14 @@ package main
 
 import (
 	"github.com/bowoBp/LoanFlow/pkg/api"
-	"log"
+	"log/slog"
 )
 
 func main() {
 	app := api.Default()
 	err := app.Start()
 	if err != nil {
-		log.Print(err)
+		slog.Error("api stopped", "error", err)
 		panic(err)
 	}
 }
@@ -0,0 +1,155 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// APIKeyRepoInterface is an autogenerated mock type for the APIKeyRepoInterface type
+type APIKeyRepoInterface struct {
+	mock.Mock
+}
+
+// GetAPIKeyByHash provides a mock function with given fields: ctx, keyHash
+func (_m *APIKeyRepoInterface) GetAPIKeyByHash(ctx context.Context, keyHash string) (*domians.APIKey, error) {
+	ret := _m.Called(ctx, keyHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAPIKeyByHash")
+	}
+
+	var r0 *domians.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domians.APIKey, error)); ok {
+		return rf(ctx, keyHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domians.APIKey); ok {
+		r0 = rf(ctx, keyHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, keyHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAPIKeyByID provides a mock function with given fields: ctx, keyID
+func (_m *APIKeyRepoInterface) GetAPIKeyByID(ctx context.Context, keyID uint) (*domians.APIKey, error) {
+	ret := _m.Called(ctx, keyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAPIKeyByID")
+	}
+
+	var r0 *domians.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*domians.APIKey, error)); ok {
+		return rf(ctx, keyID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domians.APIKey); ok {
+		r0 = rf(ctx, keyID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, keyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAPIKeys provides a mock function with given fields: ctx
+func (_m *APIKeyRepoInterface) GetAPIKeys(ctx context.Context) ([]domians.APIKey, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAPIKeys")
+	}
+
+	var r0 []domians.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domians.APIKey, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domians.APIKey); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domians.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StoreAPIKey provides a mock function with given fields: ctx, key
+func (_m *APIKeyRepoInterface) StoreAPIKey(ctx context.Context, key *domians.APIKey) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreAPIKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.APIKey) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAPIKey provides a mock function with given fields: ctx, key, updateData
+func (_m *APIKeyRepoInterface) UpdateAPIKey(ctx context.Context, key *domians.APIKey, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, key, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAPIKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.APIKey, map[string]interface{}) error); ok {
+		r0 = rf(ctx, key, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAPIKeyRepoInterface creates a new instance of APIKeyRepoInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAPIKeyRepoInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *APIKeyRepoInterface {
+	mock := &APIKeyRepoInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
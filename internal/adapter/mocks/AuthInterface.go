@@ -13,17 +13,23 @@ type AuthInterface struct {
 	mock.Mock
 }
 
-// Authentication provides a mock function with given fields:
-func (_m *AuthInterface) Authentication() gin.HandlerFunc {
-	ret := _m.Called()
+// Authentication provides a mock function with given fields: scopes
+func (_m *AuthInterface) Authentication(scopes ...string) gin.HandlerFunc {
+	_va := make([]interface{}, len(scopes))
+	for _i := range scopes {
+		_va[_i] = scopes[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Authentication")
 	}
 
 	var r0 gin.HandlerFunc
-	if rf, ok := ret.Get(0).(func() gin.HandlerFunc); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(...string) gin.HandlerFunc); ok {
+		r0 = rf(scopes...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(gin.HandlerFunc)
@@ -59,6 +65,32 @@ func (_m *AuthInterface) Authorize(roles ...string) gin.HandlerFunc {
 	return r0
 }
 
+// AuthorizePermission provides a mock function with given fields: permissions
+func (_m *AuthInterface) AuthorizePermission(permissions ...string) gin.HandlerFunc {
+	_va := make([]interface{}, len(permissions))
+	for _i := range permissions {
+		_va[_i] = permissions[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthorizePermission")
+	}
+
+	var r0 gin.HandlerFunc
+	if rf, ok := ret.Get(0).(func(...string) gin.HandlerFunc); ok {
+		r0 = rf(permissions...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(gin.HandlerFunc)
+		}
+	}
+
+	return r0
+}
+
 // NewAuthInterface creates a new instance of AuthInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewAuthInterface(t interface {
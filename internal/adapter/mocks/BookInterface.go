@@ -0,0 +1,153 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	ledger "github.com/bowoBp/LoanFlow/internal/ledger"
+
+	mock "github.com/stretchr/testify/mock"
+
+	money "github.com/bowoBp/LoanFlow/pkg/money"
+)
+
+// BookInterface is an autogenerated mock type for the BookInterface type
+type BookInterface struct {
+	mock.Mock
+}
+
+// Disburse provides a mock function with given fields: ctx, tx, loan, userID
+func (_m *BookInterface) Disburse(ctx context.Context, tx ledger.Store, loan *domians.Loan, userID uint) error {
+	ret := _m.Called(ctx, tx, loan, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Disburse")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ledger.Store, *domians.Loan, uint) error); ok {
+		r0 = rf(ctx, tx, loan, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Invest provides a mock function with given fields: ctx, tx, loan, investorID, amount
+func (_m *BookInterface) Invest(ctx context.Context, tx ledger.Store, loan *domians.Loan, investorID uint, amount money.Money) error {
+	ret := _m.Called(ctx, tx, loan, investorID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Invest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ledger.Store, *domians.Loan, uint, money.Money) error); ok {
+		r0 = rf(ctx, tx, loan, investorID, amount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Post provides a mock function with given fields: ctx, tx, entry, lines
+func (_m *BookInterface) Post(ctx context.Context, tx ledger.Store, entry *domians.JournalEntry, lines []ledger.Line) error {
+	ret := _m.Called(ctx, tx, entry, lines)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Post")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ledger.Store, *domians.JournalEntry, []ledger.Line) error); ok {
+		r0 = rf(ctx, tx, entry, lines)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Release provides a mock function with given fields: ctx, tx, loan, investments, userID
+func (_m *BookInterface) Release(ctx context.Context, tx ledger.Store, loan *domians.Loan, investments []domians.LoanInvestor, userID uint) error {
+	ret := _m.Called(ctx, tx, loan, investments, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ledger.Store, *domians.Loan, []domians.LoanInvestor, uint) error); ok {
+		r0 = rf(ctx, tx, loan, investments, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Repay provides a mock function with given fields: ctx, tx, loan, investments, amount, userID
+func (_m *BookInterface) Repay(ctx context.Context, tx ledger.Store, loan *domians.Loan, investments []domians.LoanInvestor, amount money.Money, userID uint) error {
+	ret := _m.Called(ctx, tx, loan, investments, amount, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Repay")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ledger.Store, *domians.Loan, []domians.LoanInvestor, money.Money, uint) error); ok {
+		r0 = rf(ctx, tx, loan, investments, amount, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TopUp provides a mock function with given fields: ctx, tx, topUp, userID
+func (_m *BookInterface) TopUp(ctx context.Context, tx ledger.Store, topUp *domians.TopUp, userID uint) (*domians.JournalEntry, error) {
+	ret := _m.Called(ctx, tx, topUp, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TopUp")
+	}
+
+	var r0 *domians.JournalEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ledger.Store, *domians.TopUp, uint) (*domians.JournalEntry, error)); ok {
+		return rf(ctx, tx, topUp, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ledger.Store, *domians.TopUp, uint) *domians.JournalEntry); ok {
+		r0 = rf(ctx, tx, topUp, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.JournalEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ledger.Store, *domians.TopUp, uint) error); ok {
+		r1 = rf(ctx, tx, topUp, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewBookInterface creates a new instance of BookInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBookInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BookInterface {
+	mock := &BookInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,232 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	admin "github.com/bowoBp/LoanFlow/internal/services/admin"
+
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// DefaultAdminTransactionInterface is an autogenerated mock type for the DefaultAdminTransactionInterface type
+type DefaultAdminTransactionInterface struct {
+	mock.Mock
+}
+
+// Begin provides a mock function with given fields:
+func (_m *DefaultAdminTransactionInterface) Begin() (admin.DefaultAdminTransactionInterface, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Begin")
+	}
+
+	var r0 admin.DefaultAdminTransactionInterface
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (admin.DefaultAdminTransactionInterface, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() admin.DefaultAdminTransactionInterface); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(admin.DefaultAdminTransactionInterface)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateRoleAudit provides a mock function with given fields: ctx, audit
+func (_m *DefaultAdminTransactionInterface) CreateRoleAudit(ctx context.Context, audit *domians.RoleAudit) error {
+	ret := _m.Called(ctx, audit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRoleAudit")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.RoleAudit) error); ok {
+		r0 = rf(ctx, audit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteRolePermissions provides a mock function with given fields: ctx, role
+func (_m *DefaultAdminTransactionInterface) DeleteRolePermissions(ctx context.Context, role string) error {
+	ret := _m.Called(ctx, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRolePermissions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// End provides a mock function with given fields: err
+func (_m *DefaultAdminTransactionInterface) End(err error) error {
+	ret := _m.Called(err)
+
+	if len(ret) == 0 {
+		panic("no return value specified for End")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(error) error); ok {
+		r0 = rf(err)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetUserByID provides a mock function with given fields: ctx, id
+func (_m *DefaultAdminTransactionInterface) GetUserByID(ctx context.Context, id uint) (*domians.User, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByID")
+	}
+
+	var r0 *domians.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*domians.User, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domians.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeSessionsByUserID provides a mock function with given fields: ctx, userID, revokedAt
+func (_m *DefaultAdminTransactionInterface) RevokeSessionsByUserID(ctx context.Context, userID uint, revokedAt time.Time) error {
+	ret := _m.Called(ctx, userID, revokedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSessionsByUserID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, time.Time) error); ok {
+		r0 = rf(ctx, userID, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreRolePermissions provides a mock function with given fields: ctx, permissions
+func (_m *DefaultAdminTransactionInterface) StoreRolePermissions(ctx context.Context, permissions []domians.RolePermission) error {
+	ret := _m.Called(ctx, permissions)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreRolePermissions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domians.RolePermission) error); ok {
+		r0 = rf(ctx, permissions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreUser provides a mock function with given fields: ctx, user
+func (_m *DefaultAdminTransactionInterface) StoreUser(ctx context.Context, user *domians.User) (*domians.User, error) {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreUser")
+	}
+
+	var r0 *domians.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.User) (*domians.User, error)); ok {
+		return rf(ctx, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.User) *domians.User); ok {
+		r0 = rf(ctx, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domians.User) error); ok {
+		r1 = rf(ctx, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateUser provides a mock function with given fields: ctx, user, updateData
+func (_m *DefaultAdminTransactionInterface) UpdateUser(ctx context.Context, user *domians.User, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, user, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.User, map[string]interface{}) error); ok {
+		r0 = rf(ctx, user, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDefaultAdminTransactionInterface creates a new instance of DefaultAdminTransactionInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDefaultAdminTransactionInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DefaultAdminTransactionInterface {
+	mock := &DefaultAdminTransactionInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,193 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	ledger "github.com/bowoBp/LoanFlow/internal/services/ledger"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DefaultLedgerTransactionInterface is an autogenerated mock type for the DefaultLedgerTransactionInterface type
+type DefaultLedgerTransactionInterface struct {
+	mock.Mock
+}
+
+// Begin provides a mock function with given fields:
+func (_m *DefaultLedgerTransactionInterface) Begin() (ledger.DefaultLedgerTransactionInterface, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Begin")
+	}
+
+	var r0 ledger.DefaultLedgerTransactionInterface
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (ledger.DefaultLedgerTransactionInterface, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() ledger.DefaultLedgerTransactionInterface); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ledger.DefaultLedgerTransactionInterface)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateJournalEntry provides a mock function with given fields: ctx, entry
+func (_m *DefaultLedgerTransactionInterface) CreateJournalEntry(ctx context.Context, entry *domians.JournalEntry) error {
+	ret := _m.Called(ctx, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateJournalEntry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.JournalEntry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// End provides a mock function with given fields: err
+func (_m *DefaultLedgerTransactionInterface) End(err error) error {
+	ret := _m.Called(err)
+
+	if len(ret) == 0 {
+		panic("no return value specified for End")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(error) error); ok {
+		r0 = rf(err)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetOrCreateAccount provides a mock function with given fields: ctx, account
+func (_m *DefaultLedgerTransactionInterface) GetOrCreateAccount(ctx context.Context, account *domians.LedgerAccount) (*domians.LedgerAccount, error) {
+	ret := _m.Called(ctx, account)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrCreateAccount")
+	}
+
+	var r0 *domians.LedgerAccount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LedgerAccount) (*domians.LedgerAccount, error)); ok {
+		return rf(ctx, account)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LedgerAccount) *domians.LedgerAccount); ok {
+		r0 = rf(ctx, account)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.LedgerAccount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domians.LedgerAccount) error); ok {
+		r1 = rf(ctx, account)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTopUpByIDForUpdate provides a mock function with given fields: ctx, topUpID
+func (_m *DefaultLedgerTransactionInterface) GetTopUpByIDForUpdate(ctx context.Context, topUpID uint) (*domians.TopUp, error) {
+	ret := _m.Called(ctx, topUpID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTopUpByIDForUpdate")
+	}
+
+	var r0 *domians.TopUp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*domians.TopUp, error)); ok {
+		return rf(ctx, topUpID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domians.TopUp); ok {
+		r0 = rf(ctx, topUpID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.TopUp)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, topUpID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateLedgerAccount provides a mock function with given fields: ctx, account, updateData
+func (_m *DefaultLedgerTransactionInterface) UpdateLedgerAccount(ctx context.Context, account *domians.LedgerAccount, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, account, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLedgerAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LedgerAccount, map[string]interface{}) error); ok {
+		r0 = rf(ctx, account, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTopUp provides a mock function with given fields: ctx, topUp, updateData
+func (_m *DefaultLedgerTransactionInterface) UpdateTopUp(ctx context.Context, topUp *domians.TopUp, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, topUp, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTopUp")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.TopUp, map[string]interface{}) error); ok {
+		r0 = rf(ctx, topUp, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDefaultLedgerTransactionInterface creates a new instance of DefaultLedgerTransactionInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDefaultLedgerTransactionInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DefaultLedgerTransactionInterface {
+	mock := &DefaultLedgerTransactionInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -9,6 +9,8 @@ import (
 	loan "github.com/bowoBp/LoanFlow/internal/services/loan"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // DefaultLoanTransactionInterface is an autogenerated mock type for the DefaultLoanTransactionInterface type
@@ -64,6 +66,42 @@ func (_m *DefaultLoanTransactionInterface) Begin() (loan.DefaultLoanTransactionI
 	return r0, r1
 }
 
+// CreateInstallments provides a mock function with given fields: ctx, installments
+func (_m *DefaultLoanTransactionInterface) CreateInstallments(ctx context.Context, installments []domians.LoanInstallment) error {
+	ret := _m.Called(ctx, installments)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateInstallments")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domians.LoanInstallment) error); ok {
+		r0 = rf(ctx, installments)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateJournalEntry provides a mock function with given fields: ctx, entry
+func (_m *DefaultLoanTransactionInterface) CreateJournalEntry(ctx context.Context, entry *domians.JournalEntry) error {
+	ret := _m.Called(ctx, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateJournalEntry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.JournalEntry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateLoan provides a mock function with given fields: ctx, _a1
 func (_m *DefaultLoanTransactionInterface) CreateLoan(ctx context.Context, _a1 *domians.Loan) (*domians.Loan, error) {
 	ret := _m.Called(ctx, _a1)
@@ -112,6 +150,24 @@ func (_m *DefaultLoanTransactionInterface) CreateLoanState(ctx context.Context,
 	return r0
 }
 
+// CreateNotifications provides a mock function with given fields: ctx, notifications
+func (_m *DefaultLoanTransactionInterface) CreateNotifications(ctx context.Context, notifications []domians.Notification) error {
+	ret := _m.Called(ctx, notifications)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateNotifications")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domians.Notification) error); ok {
+		r0 = rf(ctx, notifications)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DisburseDetail provides a mock function with given fields: ctx, disbursed
 func (_m *DefaultLoanTransactionInterface) DisburseDetail(ctx context.Context, disbursed *domians.LoanDisbursementDetail) error {
 	ret := _m.Called(ctx, disbursed)
@@ -148,6 +204,96 @@ func (_m *DefaultLoanTransactionInterface) End(err error) error {
 	return r0
 }
 
+// GetLoanByIDForUpdate provides a mock function with given fields: ctx, loanID
+func (_m *DefaultLoanTransactionInterface) GetLoanByIDForUpdate(ctx context.Context, loanID uint) (*domians.Loan, error) {
+	ret := _m.Called(ctx, loanID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoanByIDForUpdate")
+	}
+
+	var r0 *domians.Loan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*domians.Loan, error)); ok {
+		return rf(ctx, loanID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domians.Loan); ok {
+		r0 = rf(ctx, loanID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.Loan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, loanID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLoanInvestors provides a mock function with given fields: ctx, loanID
+func (_m *DefaultLoanTransactionInterface) GetLoanInvestors(ctx context.Context, loanID uint) ([]domians.LoanInvestor, error) {
+	ret := _m.Called(ctx, loanID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoanInvestors")
+	}
+
+	var r0 []domians.LoanInvestor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]domians.LoanInvestor, error)); ok {
+		return rf(ctx, loanID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []domians.LoanInvestor); ok {
+		r0 = rf(ctx, loanID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domians.LoanInvestor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, loanID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrCreateAccount provides a mock function with given fields: ctx, account
+func (_m *DefaultLoanTransactionInterface) GetOrCreateAccount(ctx context.Context, account *domians.LedgerAccount) (*domians.LedgerAccount, error) {
+	ret := _m.Called(ctx, account)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrCreateAccount")
+	}
+
+	var r0 *domians.LedgerAccount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LedgerAccount) (*domians.LedgerAccount, error)); ok {
+		return rf(ctx, account)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LedgerAccount) *domians.LedgerAccount); ok {
+		r0 = rf(ctx, account)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.LedgerAccount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domians.LedgerAccount) error); ok {
+		r1 = rf(ctx, account)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // InvestLoan provides a mock function with given fields: ctx, investLoan
 func (_m *DefaultLoanTransactionInterface) InvestLoan(ctx context.Context, investLoan *domians.LoanInvestor) error {
 	ret := _m.Called(ctx, investLoan)
@@ -166,6 +312,42 @@ func (_m *DefaultLoanTransactionInterface) InvestLoan(ctx context.Context, inves
 	return r0
 }
 
+// ReleaseInvestments provides a mock function with given fields: ctx, loanID, releasedAt
+func (_m *DefaultLoanTransactionInterface) ReleaseInvestments(ctx context.Context, loanID uint, releasedAt time.Time) error {
+	ret := _m.Called(ctx, loanID, releasedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseInvestments")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, time.Time) error); ok {
+		r0 = rf(ctx, loanID, releasedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateLedgerAccount provides a mock function with given fields: ctx, account, updateData
+func (_m *DefaultLoanTransactionInterface) UpdateLedgerAccount(ctx context.Context, account *domians.LedgerAccount, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, account, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLedgerAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LedgerAccount, map[string]interface{}) error); ok {
+		r0 = rf(ctx, account, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateLoan provides a mock function with given fields: ctx, _a1, updateData
 func (_m *DefaultLoanTransactionInterface) UpdateLoan(ctx context.Context, _a1 *domians.Loan, updateData map[string]interface{}) error {
 	ret := _m.Called(ctx, _a1, updateData)
@@ -184,6 +366,24 @@ func (_m *DefaultLoanTransactionInterface) UpdateLoan(ctx context.Context, _a1 *
 	return r0
 }
 
+// UpdateLoanInvestor provides a mock function with given fields: ctx, investor, updateData
+func (_m *DefaultLoanTransactionInterface) UpdateLoanInvestor(ctx context.Context, investor *domians.LoanInvestor, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, investor, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLoanInvestor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LoanInvestor, map[string]interface{}) error); ok {
+		r0 = rf(ctx, investor, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewDefaultLoanTransactionInterface creates a new instance of DefaultLoanTransactionInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewDefaultLoanTransactionInterface(t interface {
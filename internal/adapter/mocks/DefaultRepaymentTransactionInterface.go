@@ -0,0 +1,307 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	repayment "github.com/bowoBp/LoanFlow/internal/services/repayment"
+)
+
+// DefaultRepaymentTransactionInterface is an autogenerated mock type for the DefaultRepaymentTransactionInterface type
+type DefaultRepaymentTransactionInterface struct {
+	mock.Mock
+}
+
+// Begin provides a mock function with given fields:
+func (_m *DefaultRepaymentTransactionInterface) Begin() (repayment.DefaultRepaymentTransactionInterface, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Begin")
+	}
+
+	var r0 repayment.DefaultRepaymentTransactionInterface
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (repayment.DefaultRepaymentTransactionInterface, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() repayment.DefaultRepaymentTransactionInterface); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repayment.DefaultRepaymentTransactionInterface)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateJournalEntry provides a mock function with given fields: ctx, entry
+func (_m *DefaultRepaymentTransactionInterface) CreateJournalEntry(ctx context.Context, entry *domians.JournalEntry) error {
+	ret := _m.Called(ctx, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateJournalEntry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.JournalEntry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateLoanState provides a mock function with given fields: ctx, state
+func (_m *DefaultRepaymentTransactionInterface) CreateLoanState(ctx context.Context, state *domians.LoanStateHistory) error {
+	ret := _m.Called(ctx, state)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateLoanState")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LoanStateHistory) error); ok {
+		r0 = rf(ctx, state)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateRepayment provides a mock function with given fields: ctx, _a1
+func (_m *DefaultRepaymentTransactionInterface) CreateRepayment(ctx context.Context, _a1 *domians.LoanRepayment) error {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRepayment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LoanRepayment) error); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// End provides a mock function with given fields: err
+func (_m *DefaultRepaymentTransactionInterface) End(err error) error {
+	ret := _m.Called(err)
+
+	if len(ret) == 0 {
+		panic("no return value specified for End")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(error) error); ok {
+		r0 = rf(err)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetInstallmentsByLoanID provides a mock function with given fields: ctx, loanID
+func (_m *DefaultRepaymentTransactionInterface) GetInstallmentsByLoanID(ctx context.Context, loanID uint) ([]domians.LoanInstallment, error) {
+	ret := _m.Called(ctx, loanID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInstallmentsByLoanID")
+	}
+
+	var r0 []domians.LoanInstallment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]domians.LoanInstallment, error)); ok {
+		return rf(ctx, loanID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []domians.LoanInstallment); ok {
+		r0 = rf(ctx, loanID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domians.LoanInstallment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, loanID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLoanByIDForUpdate provides a mock function with given fields: ctx, loanID
+func (_m *DefaultRepaymentTransactionInterface) GetLoanByIDForUpdate(ctx context.Context, loanID uint) (*domians.Loan, error) {
+	ret := _m.Called(ctx, loanID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoanByIDForUpdate")
+	}
+
+	var r0 *domians.Loan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*domians.Loan, error)); ok {
+		return rf(ctx, loanID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domians.Loan); ok {
+		r0 = rf(ctx, loanID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.Loan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, loanID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLoanInvestors provides a mock function with given fields: ctx, loanID
+func (_m *DefaultRepaymentTransactionInterface) GetLoanInvestors(ctx context.Context, loanID uint) ([]domians.LoanInvestor, error) {
+	ret := _m.Called(ctx, loanID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoanInvestors")
+	}
+
+	var r0 []domians.LoanInvestor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]domians.LoanInvestor, error)); ok {
+		return rf(ctx, loanID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []domians.LoanInvestor); ok {
+		r0 = rf(ctx, loanID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domians.LoanInvestor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, loanID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrCreateAccount provides a mock function with given fields: ctx, account
+func (_m *DefaultRepaymentTransactionInterface) GetOrCreateAccount(ctx context.Context, account *domians.LedgerAccount) (*domians.LedgerAccount, error) {
+	ret := _m.Called(ctx, account)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrCreateAccount")
+	}
+
+	var r0 *domians.LedgerAccount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LedgerAccount) (*domians.LedgerAccount, error)); ok {
+		return rf(ctx, account)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LedgerAccount) *domians.LedgerAccount); ok {
+		r0 = rf(ctx, account)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.LedgerAccount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domians.LedgerAccount) error); ok {
+		r1 = rf(ctx, account)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateInstallment provides a mock function with given fields: ctx, installment, updateData
+func (_m *DefaultRepaymentTransactionInterface) UpdateInstallment(ctx context.Context, installment *domians.LoanInstallment, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, installment, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateInstallment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LoanInstallment, map[string]interface{}) error); ok {
+		r0 = rf(ctx, installment, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateLedgerAccount provides a mock function with given fields: ctx, account, updateData
+func (_m *DefaultRepaymentTransactionInterface) UpdateLedgerAccount(ctx context.Context, account *domians.LedgerAccount, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, account, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLedgerAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LedgerAccount, map[string]interface{}) error); ok {
+		r0 = rf(ctx, account, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateLoan provides a mock function with given fields: ctx, loan, updateData
+func (_m *DefaultRepaymentTransactionInterface) UpdateLoan(ctx context.Context, loan *domians.Loan, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, loan, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLoan")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.Loan, map[string]interface{}) error); ok {
+		r0 = rf(ctx, loan, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDefaultRepaymentTransactionInterface creates a new instance of DefaultRepaymentTransactionInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDefaultRepaymentTransactionInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DefaultRepaymentTransactionInterface {
+	mock := &DefaultRepaymentTransactionInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,473 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	user "github.com/bowoBp/LoanFlow/internal/services/user"
+)
+
+// DefaultUserTransactionInterface is an autogenerated mock type for the DefaultUserTransactionInterface type
+type DefaultUserTransactionInterface struct {
+	mock.Mock
+}
+
+// Begin provides a mock function with given fields:
+func (_m *DefaultUserTransactionInterface) Begin() (user.DefaultUserTransactionInterface, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Begin")
+	}
+
+	var r0 user.DefaultUserTransactionInterface
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (user.DefaultUserTransactionInterface, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() user.DefaultUserTransactionInterface); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(user.DefaultUserTransactionInterface)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateNotifications provides a mock function with given fields: ctx, notifications
+func (_m *DefaultUserTransactionInterface) CreateNotifications(ctx context.Context, notifications []domians.Notification) error {
+	ret := _m.Called(ctx, notifications)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateNotifications")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domians.Notification) error); ok {
+		r0 = rf(ctx, notifications)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateSession provides a mock function with given fields: ctx, session
+func (_m *DefaultUserTransactionInterface) CreateSession(ctx context.Context, session *domians.Session) error {
+	ret := _m.Called(ctx, session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.Session) error); ok {
+		r0 = rf(ctx, session)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteRecoveryCodes provides a mock function with given fields: ctx, userID
+func (_m *DefaultUserTransactionInterface) DeleteRecoveryCodes(ctx context.Context, userID uint) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRecoveryCodes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// End provides a mock function with given fields: err
+func (_m *DefaultUserTransactionInterface) End(err error) error {
+	ret := _m.Called(err)
+
+	if len(ret) == 0 {
+		panic("no return value specified for End")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(error) error); ok {
+		r0 = rf(err)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetRefreshTokenByHash provides a mock function with given fields: ctx, tokenHash
+func (_m *DefaultUserTransactionInterface) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*domians.RefreshToken, error) {
+	ret := _m.Called(ctx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRefreshTokenByHash")
+	}
+
+	var r0 *domians.RefreshToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domians.RefreshToken, error)); ok {
+		return rf(ctx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domians.RefreshToken); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.RefreshToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByID provides a mock function with given fields: ctx, id
+func (_m *DefaultUserTransactionInterface) GetUserByID(ctx context.Context, id uint) (*domians.User, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByID")
+	}
+
+	var r0 *domians.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*domians.User, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domians.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserTokenByHash provides a mock function with given fields: ctx, purpose, tokenHash
+func (_m *DefaultUserTransactionInterface) GetUserTokenByHash(ctx context.Context, purpose string, tokenHash string) (*domians.UserToken, error) {
+	ret := _m.Called(ctx, purpose, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserTokenByHash")
+	}
+
+	var r0 *domians.UserToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domians.UserToken, error)); ok {
+		return rf(ctx, purpose, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domians.UserToken); ok {
+		r0 = rf(ctx, purpose, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.UserToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, purpose, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeSessionsByUserID provides a mock function with given fields: ctx, userID, revokedAt
+func (_m *DefaultUserTransactionInterface) RevokeSessionsByUserID(ctx context.Context, userID uint, revokedAt time.Time) error {
+	ret := _m.Called(ctx, userID, revokedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSessionsByUserID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, time.Time) error); ok {
+		r0 = rf(ctx, userID, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreRecoveryCodes provides a mock function with given fields: ctx, codes
+func (_m *DefaultUserTransactionInterface) StoreRecoveryCodes(ctx context.Context, codes []domians.RecoveryCode) error {
+	ret := _m.Called(ctx, codes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreRecoveryCodes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domians.RecoveryCode) error); ok {
+		r0 = rf(ctx, codes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreRefreshToken provides a mock function with given fields: ctx, token
+func (_m *DefaultUserTransactionInterface) StoreRefreshToken(ctx context.Context, token *domians.RefreshToken) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreRefreshToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.RefreshToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreUser provides a mock function with given fields: ctx, _a1
+func (_m *DefaultUserTransactionInterface) StoreUser(ctx context.Context, _a1 *domians.User) (*domians.User, error) {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreUser")
+	}
+
+	var r0 *domians.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.User) (*domians.User, error)); ok {
+		return rf(ctx, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.User) *domians.User); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domians.User) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StoreUserToken provides a mock function with given fields: ctx, token
+func (_m *DefaultUserTransactionInterface) StoreUserToken(ctx context.Context, token *domians.UserToken) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreUserToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.UserToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRefreshToken provides a mock function with given fields: ctx, token, updateData
+func (_m *DefaultUserTransactionInterface) UpdateRefreshToken(ctx context.Context, token *domians.RefreshToken, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, token, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRefreshToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.RefreshToken, map[string]interface{}) error); ok {
+		r0 = rf(ctx, token, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateSession provides a mock function with given fields: ctx, session, updateData
+func (_m *DefaultUserTransactionInterface) UpdateSession(ctx context.Context, session *domians.Session, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, session, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.Session, map[string]interface{}) error); ok {
+		r0 = rf(ctx, session, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTOTPStep provides a mock function with given fields: ctx, userID, step
+func (_m *DefaultUserTransactionInterface) UpdateTOTPStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	ret := _m.Called(ctx, userID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTOTPStep")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int64) (bool, error)); ok {
+		return rf(ctx, userID, step)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int64) bool); ok {
+		r0 = rf(ctx, userID, step)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, int64) error); ok {
+		r1 = rf(ctx, userID, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateUser provides a mock function with given fields: ctx, _a1, updateData
+func (_m *DefaultUserTransactionInterface) UpdateUser(ctx context.Context, _a1 *domians.User, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, _a1, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.User, map[string]interface{}) error); ok {
+		r0 = rf(ctx, _a1, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUserToken provides a mock function with given fields: ctx, token, updateData
+func (_m *DefaultUserTransactionInterface) UpdateUserToken(ctx context.Context, token *domians.UserToken, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, token, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.UserToken, map[string]interface{}) error); ok {
+		r0 = rf(ctx, token, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseRecoveryCode provides a mock function with given fields: ctx, userID, codeHash, usedAt
+func (_m *DefaultUserTransactionInterface) UseRecoveryCode(ctx context.Context, userID uint, codeHash string, usedAt time.Time) (bool, error) {
+	ret := _m.Called(ctx, userID, codeHash, usedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, time.Time) (bool, error)); ok {
+		return rf(ctx, userID, codeHash, usedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, time.Time) bool); ok {
+		r0 = rf(ctx, userID, codeHash, usedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string, time.Time) error); ok {
+		r1 = rf(ctx, userID, codeHash, usedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseUserTokens provides a mock function with given fields: ctx, userID, purpose, usedAt
+func (_m *DefaultUserTransactionInterface) UseUserTokens(ctx context.Context, userID uint, purpose string, usedAt time.Time) error {
+	ret := _m.Called(ctx, userID, purpose, usedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseUserTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, time.Time) error); ok {
+		r0 = rf(ctx, userID, purpose, usedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDefaultUserTransactionInterface creates a new instance of DefaultUserTransactionInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDefaultUserTransactionInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DefaultUserTransactionInterface {
+	mock := &DefaultUserTransactionInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
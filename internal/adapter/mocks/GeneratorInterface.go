@@ -0,0 +1,62 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	agreement "github.com/bowoBp/LoanFlow/internal/agreement"
+
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// GeneratorInterface is an autogenerated mock type for the GeneratorInterface type
+type GeneratorInterface struct {
+	mock.Mock
+}
+
+// Generate provides a mock function with given fields: ctx, tx, loan
+func (_m *GeneratorInterface) Generate(ctx context.Context, tx agreement.InvestorStore, loan *domians.Loan) ([]domians.LoanInvestor, error) {
+	ret := _m.Called(ctx, tx, loan)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Generate")
+	}
+
+	var r0 []domians.LoanInvestor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, agreement.InvestorStore, *domians.Loan) ([]domians.LoanInvestor, error)); ok {
+		return rf(ctx, tx, loan)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, agreement.InvestorStore, *domians.Loan) []domians.LoanInvestor); ok {
+		r0 = rf(ctx, tx, loan)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domians.LoanInvestor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, agreement.InvestorStore, *domians.Loan) error); ok {
+		r1 = rf(ctx, tx, loan)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewGeneratorInterface creates a new instance of GeneratorInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewGeneratorInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *GeneratorInterface {
+	mock := &GeneratorInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,143 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// IdempotencyRepoInterface is an autogenerated mock type for the IdempotencyRepoInterface type
+type IdempotencyRepoInterface struct {
+	mock.Mock
+}
+
+// DeleteExpiredKeys provides a mock function with given fields: ctx, now
+func (_m *IdempotencyRepoInterface) DeleteExpiredKeys(ctx context.Context, now time.Time) error {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredKeys")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteKey provides a mock function with given fields: ctx, key
+func (_m *IdempotencyRepoInterface) DeleteKey(ctx context.Context, key *domians.IdempotencyKey) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.IdempotencyKey) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetKey provides a mock function with given fields: ctx, userID, key
+func (_m *IdempotencyRepoInterface) GetKey(ctx context.Context, userID uint, key string) (*domians.IdempotencyKey, error) {
+	ret := _m.Called(ctx, userID, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetKey")
+	}
+
+	var r0 *domians.IdempotencyKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) (*domians.IdempotencyKey, error)); ok {
+		return rf(ctx, userID, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) *domians.IdempotencyKey); ok {
+		r0 = rf(ctx, userID, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.IdempotencyKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string) error); ok {
+		r1 = rf(ctx, userID, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReserveKey provides a mock function with given fields: ctx, key
+func (_m *IdempotencyRepoInterface) ReserveKey(ctx context.Context, key *domians.IdempotencyKey) (bool, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveKey")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.IdempotencyKey) (bool, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.IdempotencyKey) bool); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domians.IdempotencyKey) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateKey provides a mock function with given fields: ctx, key, updateData
+func (_m *IdempotencyRepoInterface) UpdateKey(ctx context.Context, key *domians.IdempotencyKey, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, key, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.IdempotencyKey, map[string]interface{}) error); ok {
+		r0 = rf(ctx, key, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIdempotencyRepoInterface creates a new instance of IdempotencyRepoInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIdempotencyRepoInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IdempotencyRepoInterface {
+	mock := &IdempotencyRepoInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
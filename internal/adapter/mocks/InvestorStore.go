@@ -0,0 +1,77 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// InvestorStore is an autogenerated mock type for the InvestorStore type
+type InvestorStore struct {
+	mock.Mock
+}
+
+// GetLoanInvestors provides a mock function with given fields: ctx, loanID
+func (_m *InvestorStore) GetLoanInvestors(ctx context.Context, loanID uint) ([]domians.LoanInvestor, error) {
+	ret := _m.Called(ctx, loanID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoanInvestors")
+	}
+
+	var r0 []domians.LoanInvestor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]domians.LoanInvestor, error)); ok {
+		return rf(ctx, loanID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []domians.LoanInvestor); ok {
+		r0 = rf(ctx, loanID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domians.LoanInvestor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, loanID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateLoanInvestor provides a mock function with given fields: ctx, investor, updateData
+func (_m *InvestorStore) UpdateLoanInvestor(ctx context.Context, investor *domians.LoanInvestor, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, investor, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLoanInvestor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LoanInvestor, map[string]interface{}) error); ok {
+		r0 = rf(ctx, investor, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewInvestorStore creates a new instance of InvestorStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewInvestorStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *InvestorStore {
+	mock := &InvestorStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -43,9 +43,9 @@ func (_m *JwtInterface) ExtractPayloadFromToken(requestToken string) (helper.Jwt
 	return r0, r1
 }
 
-// GenerateToken provides a mock function with given fields: id, userRole, userName, createdAt
-func (_m *JwtInterface) GenerateToken(id uint, userRole string, userName string, createdAt time.Time) (string, error) {
-	ret := _m.Called(id, userRole, userName, createdAt)
+// GenerateToken provides a mock function with given fields: id, userRole, userName, locale, createdAt, mfa
+func (_m *JwtInterface) GenerateToken(id uint, userRole string, userName string, locale string, createdAt time.Time, mfa bool) (string, error) {
+	ret := _m.Called(id, userRole, userName, locale, createdAt, mfa)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GenerateToken")
@@ -53,17 +53,17 @@ func (_m *JwtInterface) GenerateToken(id uint, userRole string, userName string,
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint, string, string, time.Time) (string, error)); ok {
-		return rf(id, userRole, userName, createdAt)
+	if rf, ok := ret.Get(0).(func(uint, string, string, string, time.Time, bool) (string, error)); ok {
+		return rf(id, userRole, userName, locale, createdAt, mfa)
 	}
-	if rf, ok := ret.Get(0).(func(uint, string, string, time.Time) string); ok {
-		r0 = rf(id, userRole, userName, createdAt)
+	if rf, ok := ret.Get(0).(func(uint, string, string, string, time.Time, bool) string); ok {
+		r0 = rf(id, userRole, userName, locale, createdAt, mfa)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(uint, string, string, time.Time) error); ok {
-		r1 = rf(id, userRole, userName, createdAt)
+	if rf, ok := ret.Get(1).(func(uint, string, string, string, time.Time, bool) error); ok {
+		r1 = rf(id, userRole, userName, locale, createdAt, mfa)
 	} else {
 		r1 = ret.Error(1)
 	}
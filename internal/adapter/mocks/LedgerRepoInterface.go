@@ -0,0 +1,307 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	dto "github.com/bowoBp/LoanFlow/internal/dto"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LedgerRepoInterface is an autogenerated mock type for the LedgerRepoInterface type
+type LedgerRepoInterface struct {
+	mock.Mock
+}
+
+// CreateJournalEntry provides a mock function with given fields: ctx, entry
+func (_m *LedgerRepoInterface) CreateJournalEntry(ctx context.Context, entry *domians.JournalEntry) error {
+	ret := _m.Called(ctx, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateJournalEntry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.JournalEntry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateTopUp provides a mock function with given fields: ctx, topUp
+func (_m *LedgerRepoInterface) CreateTopUp(ctx context.Context, topUp *domians.TopUp) (bool, error) {
+	ret := _m.Called(ctx, topUp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTopUp")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.TopUp) (bool, error)); ok {
+		return rf(ctx, topUp)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.TopUp) bool); ok {
+		r0 = rf(ctx, topUp)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domians.TopUp) error); ok {
+		r1 = rf(ctx, topUp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccountByID provides a mock function with given fields: ctx, accountID
+func (_m *LedgerRepoInterface) GetAccountByID(ctx context.Context, accountID uint) (*domians.LedgerAccount, error) {
+	ret := _m.Called(ctx, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccountByID")
+	}
+
+	var r0 *domians.LedgerAccount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*domians.LedgerAccount, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domians.LedgerAccount); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.LedgerAccount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccountLines provides a mock function with given fields: ctx, accountID, query
+func (_m *LedgerRepoInterface) GetAccountLines(ctx context.Context, accountID uint, query dto.GetListQuery) ([]domians.JournalLine, int64, error) {
+	ret := _m.Called(ctx, accountID, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccountLines")
+	}
+
+	var r0 []domians.JournalLine
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, dto.GetListQuery) ([]domians.JournalLine, int64, error)); ok {
+		return rf(ctx, accountID, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, dto.GetListQuery) []domians.JournalLine); ok {
+		r0 = rf(ctx, accountID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domians.JournalLine)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, dto.GetListQuery) int64); ok {
+		r1 = rf(ctx, accountID, query)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint, dto.GetListQuery) error); ok {
+		r2 = rf(ctx, accountID, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetAccountsByUserID provides a mock function with given fields: ctx, userID
+func (_m *LedgerRepoInterface) GetAccountsByUserID(ctx context.Context, userID uint) ([]domians.LedgerAccount, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccountsByUserID")
+	}
+
+	var r0 []domians.LedgerAccount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]domians.LedgerAccount, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []domians.LedgerAccount); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domians.LedgerAccount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrCreateAccount provides a mock function with given fields: ctx, account
+func (_m *LedgerRepoInterface) GetOrCreateAccount(ctx context.Context, account *domians.LedgerAccount) (*domians.LedgerAccount, error) {
+	ret := _m.Called(ctx, account)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrCreateAccount")
+	}
+
+	var r0 *domians.LedgerAccount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LedgerAccount) (*domians.LedgerAccount, error)); ok {
+		return rf(ctx, account)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LedgerAccount) *domians.LedgerAccount); ok {
+		r0 = rf(ctx, account)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.LedgerAccount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domians.LedgerAccount) error); ok {
+		r1 = rf(ctx, account)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTopUpByIDForUpdate provides a mock function with given fields: ctx, topUpID
+func (_m *LedgerRepoInterface) GetTopUpByIDForUpdate(ctx context.Context, topUpID uint) (*domians.TopUp, error) {
+	ret := _m.Called(ctx, topUpID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTopUpByIDForUpdate")
+	}
+
+	var r0 *domians.TopUp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*domians.TopUp, error)); ok {
+		return rf(ctx, topUpID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domians.TopUp); ok {
+		r0 = rf(ctx, topUpID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.TopUp)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, topUpID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTopUps provides a mock function with given fields: ctx, query, filter
+func (_m *LedgerRepoInterface) GetTopUps(ctx context.Context, query dto.GetListQuery, filter dto.TopUpFilter) ([]domians.TopUp, int64, error) {
+	ret := _m.Called(ctx, query, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTopUps")
+	}
+
+	var r0 []domians.TopUp
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetListQuery, dto.TopUpFilter) ([]domians.TopUp, int64, error)); ok {
+		return rf(ctx, query, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetListQuery, dto.TopUpFilter) []domians.TopUp); ok {
+		r0 = rf(ctx, query, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domians.TopUp)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.GetListQuery, dto.TopUpFilter) int64); ok {
+		r1 = rf(ctx, query, filter)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, dto.GetListQuery, dto.TopUpFilter) error); ok {
+		r2 = rf(ctx, query, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// UpdateLedgerAccount provides a mock function with given fields: ctx, account, updateData
+func (_m *LedgerRepoInterface) UpdateLedgerAccount(ctx context.Context, account *domians.LedgerAccount, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, account, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLedgerAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LedgerAccount, map[string]interface{}) error); ok {
+		r0 = rf(ctx, account, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTopUp provides a mock function with given fields: ctx, topUp, updateData
+func (_m *LedgerRepoInterface) UpdateTopUp(ctx context.Context, topUp *domians.TopUp, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, topUp, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTopUp")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.TopUp, map[string]interface{}) error); ok {
+		r0 = rf(ctx, topUp, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewLedgerRepoInterface creates a new instance of LedgerRepoInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLedgerRepoInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LedgerRepoInterface {
+	mock := &LedgerRepoInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
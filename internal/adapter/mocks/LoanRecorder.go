@@ -0,0 +1,37 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	money "github.com/bowoBp/LoanFlow/pkg/money"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LoanRecorder is an autogenerated mock type for the LoanRecorder type
+type LoanRecorder struct {
+	mock.Mock
+}
+
+// LoanEvent provides a mock function with given fields: event
+func (_m *LoanRecorder) LoanEvent(event string) {
+	_m.Called(event)
+}
+
+// LoanInvested provides a mock function with given fields: currency, amount
+func (_m *LoanRecorder) LoanInvested(currency money.Currency, amount money.Money) {
+	_m.Called(currency, amount)
+}
+
+// NewLoanRecorder creates a new instance of LoanRecorder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLoanRecorder(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LoanRecorder {
+	mock := &LoanRecorder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -9,6 +9,8 @@ import (
 	dto "github.com/bowoBp/LoanFlow/internal/dto"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // LoanRepoInterface is an autogenerated mock type for the LoanRepoInterface type
@@ -34,6 +36,36 @@ func (_m *LoanRepoInterface) ApproveDetail(ctx context.Context, detail *domians.
 	return r0
 }
 
+// CountLoansByState provides a mock function with given fields: ctx
+func (_m *LoanRepoInterface) CountLoansByState(ctx context.Context) ([]dto.LoanStateCount, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountLoansByState")
+	}
+
+	var r0 []dto.LoanStateCount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]dto.LoanStateCount, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []dto.LoanStateCount); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dto.LoanStateCount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateLoan provides a mock function with given fields: ctx, loan
 func (_m *LoanRepoInterface) CreateLoan(ctx context.Context, loan *domians.Loan) (*domians.Loan, error) {
 	ret := _m.Called(ctx, loan)
@@ -130,9 +162,69 @@ func (_m *LoanRepoInterface) GetLoanByID(ctx context.Context, loanID uint) (*dom
 	return r0, r1
 }
 
-// GetLoans provides a mock function with given fields: ctx, query
-func (_m *LoanRepoInterface) GetLoans(ctx context.Context, query dto.GetListQuery) ([]domians.Loan, int64, error) {
-	ret := _m.Called(ctx, query)
+// GetLoanByIDForUpdate provides a mock function with given fields: ctx, loanID
+func (_m *LoanRepoInterface) GetLoanByIDForUpdate(ctx context.Context, loanID uint) (*domians.Loan, error) {
+	ret := _m.Called(ctx, loanID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoanByIDForUpdate")
+	}
+
+	var r0 *domians.Loan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*domians.Loan, error)); ok {
+		return rf(ctx, loanID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domians.Loan); ok {
+		r0 = rf(ctx, loanID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.Loan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, loanID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLoanInvestors provides a mock function with given fields: ctx, loanID
+func (_m *LoanRepoInterface) GetLoanInvestors(ctx context.Context, loanID uint) ([]domians.LoanInvestor, error) {
+	ret := _m.Called(ctx, loanID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoanInvestors")
+	}
+
+	var r0 []domians.LoanInvestor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]domians.LoanInvestor, error)); ok {
+		return rf(ctx, loanID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []domians.LoanInvestor); ok {
+		r0 = rf(ctx, loanID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domians.LoanInvestor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, loanID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLoans provides a mock function with given fields: ctx, query, filter
+func (_m *LoanRepoInterface) GetLoans(ctx context.Context, query dto.GetListQuery, filter dto.LoanFilter) ([]domians.Loan, int64, error) {
+	ret := _m.Called(ctx, query, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetLoans")
@@ -141,25 +233,25 @@ func (_m *LoanRepoInterface) GetLoans(ctx context.Context, query dto.GetListQuer
 	var r0 []domians.Loan
 	var r1 int64
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, dto.GetListQuery) ([]domians.Loan, int64, error)); ok {
-		return rf(ctx, query)
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetListQuery, dto.LoanFilter) ([]domians.Loan, int64, error)); ok {
+		return rf(ctx, query, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, dto.GetListQuery) []domians.Loan); ok {
-		r0 = rf(ctx, query)
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetListQuery, dto.LoanFilter) []domians.Loan); ok {
+		r0 = rf(ctx, query, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domians.Loan)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, dto.GetListQuery) int64); ok {
-		r1 = rf(ctx, query)
+	if rf, ok := ret.Get(1).(func(context.Context, dto.GetListQuery, dto.LoanFilter) int64); ok {
+		r1 = rf(ctx, query, filter)
 	} else {
 		r1 = ret.Get(1).(int64)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, dto.GetListQuery) error); ok {
-		r2 = rf(ctx, query)
+	if rf, ok := ret.Get(2).(func(context.Context, dto.GetListQuery, dto.LoanFilter) error); ok {
+		r2 = rf(ctx, query, filter)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -167,6 +259,36 @@ func (_m *LoanRepoInterface) GetLoans(ctx context.Context, query dto.GetListQuer
 	return r0, r1, r2
 }
 
+// GetStateDurations provides a mock function with given fields: ctx, buckets
+func (_m *LoanRepoInterface) GetStateDurations(ctx context.Context, buckets []float64) ([]dto.LoanStateDuration, error) {
+	ret := _m.Called(ctx, buckets)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStateDurations")
+	}
+
+	var r0 []dto.LoanStateDuration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []float64) ([]dto.LoanStateDuration, error)); ok {
+		return rf(ctx, buckets)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []float64) []dto.LoanStateDuration); ok {
+		r0 = rf(ctx, buckets)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dto.LoanStateDuration)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []float64) error); ok {
+		r1 = rf(ctx, buckets)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // InvestLoan provides a mock function with given fields: ctx, investLoan
 func (_m *LoanRepoInterface) InvestLoan(ctx context.Context, investLoan *domians.LoanInvestor) error {
 	ret := _m.Called(ctx, investLoan)
@@ -185,6 +307,24 @@ func (_m *LoanRepoInterface) InvestLoan(ctx context.Context, investLoan *domians
 	return r0
 }
 
+// ReleaseInvestments provides a mock function with given fields: ctx, loanID, releasedAt
+func (_m *LoanRepoInterface) ReleaseInvestments(ctx context.Context, loanID uint, releasedAt time.Time) error {
+	ret := _m.Called(ctx, loanID, releasedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseInvestments")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, time.Time) error); ok {
+		r0 = rf(ctx, loanID, releasedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateLoan provides a mock function with given fields: ctx, loan, updateData
 func (_m *LoanRepoInterface) UpdateLoan(ctx context.Context, loan *domians.Loan, updateData map[string]interface{}) error {
 	ret := _m.Called(ctx, loan, updateData)
@@ -203,6 +343,24 @@ func (_m *LoanRepoInterface) UpdateLoan(ctx context.Context, loan *domians.Loan,
 	return r0
 }
 
+// UpdateLoanInvestor provides a mock function with given fields: ctx, investor, updateData
+func (_m *LoanRepoInterface) UpdateLoanInvestor(ctx context.Context, investor *domians.LoanInvestor, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, investor, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLoanInvestor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LoanInvestor, map[string]interface{}) error); ok {
+		r0 = rf(ctx, investor, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewLoanRepoInterface creates a new instance of LoanRepoInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewLoanRepoInterface(t interface {
@@ -0,0 +1,205 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// LoginAttemptRepoInterface is an autogenerated mock type for the LoginAttemptRepoInterface type
+type LoginAttemptRepoInterface struct {
+	mock.Mock
+}
+
+// CreateAuthEvent provides a mock function with given fields: ctx, event
+func (_m *LoginAttemptRepoInterface) CreateAuthEvent(ctx context.Context, event *domians.AuthEvent) error {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAuthEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.AuthEvent) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteLoginThrottle provides a mock function with given fields: ctx, scope, key
+func (_m *LoginAttemptRepoInterface) DeleteLoginThrottle(ctx context.Context, scope string, key string) error {
+	ret := _m.Called(ctx, scope, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLoginThrottle")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, scope, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAuthEvents provides a mock function with given fields: ctx, userID
+func (_m *LoginAttemptRepoInterface) GetAuthEvents(ctx context.Context, userID uint) ([]domians.AuthEvent, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuthEvents")
+	}
+
+	var r0 []domians.AuthEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]domians.AuthEvent, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []domians.AuthEvent); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domians.AuthEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFailedLoginIPs provides a mock function with given fields: ctx, userID, since
+func (_m *LoginAttemptRepoInterface) GetFailedLoginIPs(ctx context.Context, userID uint, since time.Time) ([]string, error) {
+	ret := _m.Called(ctx, userID, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFailedLoginIPs")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, time.Time) ([]string, error)); ok {
+		return rf(ctx, userID, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, time.Time) []string); ok {
+		r0 = rf(ctx, userID, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, time.Time) error); ok {
+		r1 = rf(ctx, userID, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLoginThrottle provides a mock function with given fields: ctx, scope, key
+func (_m *LoginAttemptRepoInterface) GetLoginThrottle(ctx context.Context, scope string, key string) (*domians.LoginThrottle, error) {
+	ret := _m.Called(ctx, scope, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoginThrottle")
+	}
+
+	var r0 *domians.LoginThrottle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domians.LoginThrottle, error)); ok {
+		return rf(ctx, scope, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domians.LoginThrottle); ok {
+		r0 = rf(ctx, scope, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.LoginThrottle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, scope, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IncrementLoginFailures provides a mock function with given fields: ctx, scope, key, failedAt, windowStart
+func (_m *LoginAttemptRepoInterface) IncrementLoginFailures(ctx context.Context, scope string, key string, failedAt time.Time, windowStart time.Time) (*domians.LoginThrottle, error) {
+	ret := _m.Called(ctx, scope, key, failedAt, windowStart)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrementLoginFailures")
+	}
+
+	var r0 *domians.LoginThrottle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, time.Time) (*domians.LoginThrottle, error)); ok {
+		return rf(ctx, scope, key, failedAt, windowStart)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, time.Time) *domians.LoginThrottle); ok {
+		r0 = rf(ctx, scope, key, failedAt, windowStart)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.LoginThrottle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, scope, key, failedAt, windowStart)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockLogin provides a mock function with given fields: ctx, scope, key, lockedUntil
+func (_m *LoginAttemptRepoInterface) LockLogin(ctx context.Context, scope string, key string, lockedUntil time.Time) error {
+	ret := _m.Called(ctx, scope, key, lockedUntil)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockLogin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = rf(ctx, scope, key, lockedUntil)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewLoginAttemptRepoInterface creates a new instance of LoginAttemptRepoInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLoginAttemptRepoInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LoginAttemptRepoInterface {
+	mock := &LoginAttemptRepoInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,115 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// NotificationRepoInterface is an autogenerated mock type for the NotificationRepoInterface type
+type NotificationRepoInterface struct {
+	mock.Mock
+}
+
+// ClaimDueNotifications provides a mock function with given fields: ctx, now, limit, lease
+func (_m *NotificationRepoInterface) ClaimDueNotifications(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]domians.Notification, error) {
+	ret := _m.Called(ctx, now, limit, lease)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimDueNotifications")
+	}
+
+	var r0 []domians.Notification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int, time.Duration) ([]domians.Notification, error)); ok {
+		return rf(ctx, now, limit, lease)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int, time.Duration) []domians.Notification); ok {
+		r0 = rf(ctx, now, limit, lease)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domians.Notification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int, time.Duration) error); ok {
+		r1 = rf(ctx, now, limit, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateNotifications provides a mock function with given fields: ctx, notifications
+func (_m *NotificationRepoInterface) CreateNotifications(ctx context.Context, notifications []domians.Notification) error {
+	ret := _m.Called(ctx, notifications)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateNotifications")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domians.Notification) error); ok {
+		r0 = rf(ctx, notifications)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteFinishedNotifications provides a mock function with given fields: ctx, before
+func (_m *NotificationRepoInterface) DeleteFinishedNotifications(ctx context.Context, before time.Time) error {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteFinishedNotifications")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateNotification provides a mock function with given fields: ctx, notification, updateData
+func (_m *NotificationRepoInterface) UpdateNotification(ctx context.Context, notification *domians.Notification, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, notification, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateNotification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.Notification, map[string]interface{}) error); ok {
+		r0 = rf(ctx, notification, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewNotificationRepoInterface creates a new instance of NotificationRepoInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNotificationRepoInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *NotificationRepoInterface {
+	mock := &NotificationRepoInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,47 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	notification "github.com/bowoBp/LoanFlow/internal/notification"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NotifierInterface is an autogenerated mock type for the NotifierInterface type
+type NotifierInterface struct {
+	mock.Mock
+}
+
+// Enqueue provides a mock function with given fields: ctx, tx, name, recipients
+func (_m *NotifierInterface) Enqueue(ctx context.Context, tx notification.NotificationStore, name string, recipients []notification.Recipient) error {
+	ret := _m.Called(ctx, tx, name, recipients)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enqueue")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, notification.NotificationStore, string, []notification.Recipient) error); ok {
+		r0 = rf(ctx, tx, name, recipients)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewNotifierInterface creates a new instance of NotifierInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNotifierInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *NotifierInterface {
+	mock := &NotifierInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PermissionRepoInterface is an autogenerated mock type for the PermissionRepoInterface type
+type PermissionRepoInterface struct {
+	mock.Mock
+}
+
+// DeleteRolePermissions provides a mock function with given fields: ctx, role
+func (_m *PermissionRepoInterface) DeleteRolePermissions(ctx context.Context, role string) error {
+	ret := _m.Called(ctx, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRolePermissions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetRolePermissions provides a mock function with given fields: ctx
+func (_m *PermissionRepoInterface) GetRolePermissions(ctx context.Context) ([]domians.RolePermission, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRolePermissions")
+	}
+
+	var r0 []domians.RolePermission
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domians.RolePermission, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domians.RolePermission); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domians.RolePermission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StoreRolePermissions provides a mock function with given fields: ctx, permissions
+func (_m *PermissionRepoInterface) StoreRolePermissions(ctx context.Context, permissions []domians.RolePermission) error {
+	ret := _m.Called(ctx, permissions)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreRolePermissions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domians.RolePermission) error); ok {
+		r0 = rf(ctx, permissions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPermissionRepoInterface creates a new instance of PermissionRepoInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPermissionRepoInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PermissionRepoInterface {
+	mock := &PermissionRepoInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
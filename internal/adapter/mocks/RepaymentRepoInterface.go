@@ -0,0 +1,143 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RepaymentRepoInterface is an autogenerated mock type for the RepaymentRepoInterface type
+type RepaymentRepoInterface struct {
+	mock.Mock
+}
+
+// CreateInstallments provides a mock function with given fields: ctx, installments
+func (_m *RepaymentRepoInterface) CreateInstallments(ctx context.Context, installments []domians.LoanInstallment) error {
+	ret := _m.Called(ctx, installments)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateInstallments")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domians.LoanInstallment) error); ok {
+		r0 = rf(ctx, installments)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateRepayment provides a mock function with given fields: ctx, repayment
+func (_m *RepaymentRepoInterface) CreateRepayment(ctx context.Context, repayment *domians.LoanRepayment) error {
+	ret := _m.Called(ctx, repayment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRepayment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LoanRepayment) error); ok {
+		r0 = rf(ctx, repayment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetInstallmentsByLoanID provides a mock function with given fields: ctx, loanID
+func (_m *RepaymentRepoInterface) GetInstallmentsByLoanID(ctx context.Context, loanID uint) ([]domians.LoanInstallment, error) {
+	ret := _m.Called(ctx, loanID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInstallmentsByLoanID")
+	}
+
+	var r0 []domians.LoanInstallment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]domians.LoanInstallment, error)); ok {
+		return rf(ctx, loanID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []domians.LoanInstallment); ok {
+		r0 = rf(ctx, loanID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domians.LoanInstallment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, loanID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRepaymentsByLoanID provides a mock function with given fields: ctx, loanID
+func (_m *RepaymentRepoInterface) GetRepaymentsByLoanID(ctx context.Context, loanID uint) ([]domians.LoanRepayment, error) {
+	ret := _m.Called(ctx, loanID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRepaymentsByLoanID")
+	}
+
+	var r0 []domians.LoanRepayment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]domians.LoanRepayment, error)); ok {
+		return rf(ctx, loanID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []domians.LoanRepayment); ok {
+		r0 = rf(ctx, loanID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domians.LoanRepayment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, loanID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateInstallment provides a mock function with given fields: ctx, installment, updateData
+func (_m *RepaymentRepoInterface) UpdateInstallment(ctx context.Context, installment *domians.LoanInstallment, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, installment, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateInstallment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.LoanInstallment, map[string]interface{}) error); ok {
+		r0 = rf(ctx, installment, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRepaymentRepoInterface creates a new instance of RepaymentRepoInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRepaymentRepoInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RepaymentRepoInterface {
+	mock := &RepaymentRepoInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
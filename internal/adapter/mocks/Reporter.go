@@ -0,0 +1,51 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Reporter is an autogenerated mock type for the Reporter type
+type Reporter struct {
+	mock.Mock
+}
+
+// Recover provides a mock function with given fields: ctx, recovered
+func (_m *Reporter) Recover(ctx context.Context, recovered interface{}) error {
+	ret := _m.Called(ctx, recovered)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Recover")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}) error); ok {
+		r0 = rf(ctx, recovered)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Report provides a mock function with given fields: ctx, err
+func (_m *Reporter) Report(ctx context.Context, err error) {
+	_m.Called(ctx, err)
+}
+
+// NewReporter creates a new instance of Reporter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReporter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Reporter {
+	mock := &Reporter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
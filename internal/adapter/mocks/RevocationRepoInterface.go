@@ -0,0 +1,145 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// RevocationRepoInterface is an autogenerated mock type for the RevocationRepoInterface type
+type RevocationRepoInterface struct {
+	mock.Mock
+}
+
+// DeleteExpiredRevocations provides a mock function with given fields: ctx, now
+func (_m *RevocationRepoInterface) DeleteExpiredRevocations(ctx context.Context, now time.Time) error {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredRevocations")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetRevokedToken provides a mock function with given fields: ctx, tokenID
+func (_m *RevocationRepoInterface) GetRevokedToken(ctx context.Context, tokenID string) (*domians.RevokedToken, error) {
+	ret := _m.Called(ctx, tokenID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRevokedToken")
+	}
+
+	var r0 *domians.RevokedToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domians.RevokedToken, error)); ok {
+		return rf(ctx, tokenID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domians.RevokedToken); ok {
+		r0 = rf(ctx, tokenID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.RevokedToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserRevocation provides a mock function with given fields: ctx, userID
+func (_m *RevocationRepoInterface) GetUserRevocation(ctx context.Context, userID uint) (*domians.UserRevocation, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserRevocation")
+	}
+
+	var r0 *domians.UserRevocation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*domians.UserRevocation, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domians.UserRevocation); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.UserRevocation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StoreRevokedToken provides a mock function with given fields: ctx, token
+func (_m *RevocationRepoInterface) StoreRevokedToken(ctx context.Context, token *domians.RevokedToken) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreRevokedToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.RevokedToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreUserRevocation provides a mock function with given fields: ctx, revocation
+func (_m *RevocationRepoInterface) StoreUserRevocation(ctx context.Context, revocation *domians.UserRevocation) error {
+	ret := _m.Called(ctx, revocation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreUserRevocation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.UserRevocation) error); ok {
+		r0 = rf(ctx, revocation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRevocationRepoInterface creates a new instance of RevocationRepoInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRevocationRepoInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RevocationRepoInterface {
+	mock := &RevocationRepoInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
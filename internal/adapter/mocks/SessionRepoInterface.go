@@ -0,0 +1,211 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// SessionRepoInterface is an autogenerated mock type for the SessionRepoInterface type
+type SessionRepoInterface struct {
+	mock.Mock
+}
+
+// CreateSession provides a mock function with given fields: ctx, session
+func (_m *SessionRepoInterface) CreateSession(ctx context.Context, session *domians.Session) error {
+	ret := _m.Called(ctx, session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.Session) error); ok {
+		r0 = rf(ctx, session)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetActiveSessions provides a mock function with given fields: ctx, userID, now
+func (_m *SessionRepoInterface) GetActiveSessions(ctx context.Context, userID uint, now time.Time) ([]domians.Session, error) {
+	ret := _m.Called(ctx, userID, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetActiveSessions")
+	}
+
+	var r0 []domians.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, time.Time) ([]domians.Session, error)); ok {
+		return rf(ctx, userID, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, time.Time) []domians.Session); ok {
+		r0 = rf(ctx, userID, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domians.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, time.Time) error); ok {
+		r1 = rf(ctx, userID, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRefreshTokenByHash provides a mock function with given fields: ctx, tokenHash
+func (_m *SessionRepoInterface) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*domians.RefreshToken, error) {
+	ret := _m.Called(ctx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRefreshTokenByHash")
+	}
+
+	var r0 *domians.RefreshToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domians.RefreshToken, error)); ok {
+		return rf(ctx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domians.RefreshToken); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.RefreshToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSessionByID provides a mock function with given fields: ctx, sessionID
+func (_m *SessionRepoInterface) GetSessionByID(ctx context.Context, sessionID uint) (*domians.Session, error) {
+	ret := _m.Called(ctx, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSessionByID")
+	}
+
+	var r0 *domians.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*domians.Session, error)); ok {
+		return rf(ctx, sessionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domians.Session); ok {
+		r0 = rf(ctx, sessionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeSessionsByUserID provides a mock function with given fields: ctx, userID, revokedAt
+func (_m *SessionRepoInterface) RevokeSessionsByUserID(ctx context.Context, userID uint, revokedAt time.Time) error {
+	ret := _m.Called(ctx, userID, revokedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSessionsByUserID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, time.Time) error); ok {
+		r0 = rf(ctx, userID, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreRefreshToken provides a mock function with given fields: ctx, token
+func (_m *SessionRepoInterface) StoreRefreshToken(ctx context.Context, token *domians.RefreshToken) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreRefreshToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.RefreshToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRefreshToken provides a mock function with given fields: ctx, token, updateData
+func (_m *SessionRepoInterface) UpdateRefreshToken(ctx context.Context, token *domians.RefreshToken, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, token, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRefreshToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.RefreshToken, map[string]interface{}) error); ok {
+		r0 = rf(ctx, token, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateSession provides a mock function with given fields: ctx, session, updateData
+func (_m *SessionRepoInterface) UpdateSession(ctx context.Context, session *domians.Session, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, session, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.Session, map[string]interface{}) error); ok {
+		r0 = rf(ctx, session, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSessionRepoInterface creates a new instance of SessionRepoInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSessionRepoInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SessionRepoInterface {
+	mock := &SessionRepoInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -18,17 +18,35 @@ type UsecaseInterface struct {
 	mock.Mock
 }
 
-// ApproveLoan provides a mock function with given fields: ctx, loanID, userID, payload
-func (_m *UsecaseInterface) ApproveLoan(ctx context.Context, loanID uint, userID uint, payload loan.ApproveLoanRequest) error {
-	ret := _m.Called(ctx, loanID, userID, payload)
+// ApproveLoan provides a mock function with given fields: ctx, loanID, userID, role, payload
+func (_m *UsecaseInterface) ApproveLoan(ctx context.Context, loanID uint, userID uint, role string, payload loan.ApproveLoanRequest) error {
+	ret := _m.Called(ctx, loanID, userID, role, payload)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ApproveLoan")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, loan.ApproveLoanRequest) error); ok {
-		r0 = rf(ctx, loanID, userID, payload)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, string, loan.ApproveLoanRequest) error); ok {
+		r0 = rf(ctx, loanID, userID, role, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CancelLoan provides a mock function with given fields: ctx, loanID, userID, role, payload
+func (_m *UsecaseInterface) CancelLoan(ctx context.Context, loanID uint, userID uint, role string, payload loan.CancelLoanRequest) error {
+	ret := _m.Called(ctx, loanID, userID, role, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelLoan")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, string, loan.CancelLoanRequest) error); ok {
+		r0 = rf(ctx, loanID, userID, role, payload)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -54,17 +72,17 @@ func (_m *UsecaseInterface) CreateLoan(ctx context.Context, payload loan.CreateL
 	return r0
 }
 
-// DisburseLoan provides a mock function with given fields: ctx, loanID, userID, payload
-func (_m *UsecaseInterface) DisburseLoan(ctx context.Context, loanID uint, userID uint, payload loan.DisburseLoanRequest) error {
-	ret := _m.Called(ctx, loanID, userID, payload)
+// DisburseLoan provides a mock function with given fields: ctx, loanID, userID, role, payload
+func (_m *UsecaseInterface) DisburseLoan(ctx context.Context, loanID uint, userID uint, role string, payload loan.DisburseLoanRequest) error {
+	ret := _m.Called(ctx, loanID, userID, role, payload)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DisburseLoan")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, loan.DisburseLoanRequest) error); ok {
-		r0 = rf(ctx, loanID, userID, payload)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, string, loan.DisburseLoanRequest) error); ok {
+		r0 = rf(ctx, loanID, userID, role, payload)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -139,17 +157,35 @@ func (_m *UsecaseInterface) GetLoans(ctx context.Context, query dto.GetListQuery
 	return r0, r1, r2
 }
 
-// StoreInvest provides a mock function with given fields: ctx, loanID, userID, payload
-func (_m *UsecaseInterface) StoreInvest(ctx context.Context, loanID uint, userID uint, payload loan.InvestLoanRequest) error {
-	ret := _m.Called(ctx, loanID, userID, payload)
+// RejectLoan provides a mock function with given fields: ctx, loanID, userID, role, payload
+func (_m *UsecaseInterface) RejectLoan(ctx context.Context, loanID uint, userID uint, role string, payload loan.RejectLoanRequest) error {
+	ret := _m.Called(ctx, loanID, userID, role, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RejectLoan")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, string, loan.RejectLoanRequest) error); ok {
+		r0 = rf(ctx, loanID, userID, role, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreInvest provides a mock function with given fields: ctx, loanID, userID, role, payload
+func (_m *UsecaseInterface) StoreInvest(ctx context.Context, loanID uint, userID uint, role string, payload loan.InvestLoanRequest) error {
+	ret := _m.Called(ctx, loanID, userID, role, payload)
 
 	if len(ret) == 0 {
 		panic("no return value specified for StoreInvest")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, loan.InvestLoanRequest) error); ok {
-		r0 = rf(ctx, loanID, userID, payload)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, string, loan.InvestLoanRequest) error); ok {
+		r0 = rf(ctx, loanID, userID, role, payload)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -7,6 +7,8 @@ import (
 
 	domians "github.com/bowoBp/LoanFlow/internal/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // UserRepoInterface is an autogenerated mock type for the UserRepoInterface type
@@ -44,42 +46,30 @@ func (_m *UserRepoInterface) CheckEmail(ctx context.Context, userName string) (*
 	return r0, r1
 }
 
-// CheckRefreshToken provides a mock function with given fields: ctx, id
-func (_m *UserRepoInterface) CheckRefreshToken(ctx context.Context, id uint) (*domians.RefreshToken, error) {
-	ret := _m.Called(ctx, id)
+// CreateRoleAudit provides a mock function with given fields: ctx, audit
+func (_m *UserRepoInterface) CreateRoleAudit(ctx context.Context, audit *domians.RoleAudit) error {
+	ret := _m.Called(ctx, audit)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CheckRefreshToken")
+		panic("no return value specified for CreateRoleAudit")
 	}
 
-	var r0 *domians.RefreshToken
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint) (*domians.RefreshToken, error)); ok {
-		return rf(ctx, id)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, uint) *domians.RefreshToken); ok {
-		r0 = rf(ctx, id)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domians.RefreshToken)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
-		r1 = rf(ctx, id)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.RoleAudit) error); ok {
+		r0 = rf(ctx, audit)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// DeleteRefreshTokenByUserID provides a mock function with given fields: ctx, userID
-func (_m *UserRepoInterface) DeleteRefreshTokenByUserID(ctx context.Context, userID uint) error {
+// DeleteRecoveryCodes provides a mock function with given fields: ctx, userID
+func (_m *UserRepoInterface) DeleteRecoveryCodes(ctx context.Context, userID uint) error {
 	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteRefreshTokenByUserID")
+		panic("no return value specified for DeleteRecoveryCodes")
 	}
 
 	var r0 error
@@ -122,29 +112,29 @@ func (_m *UserRepoInterface) GetAllUser(ctx context.Context) ([]domians.User, er
 	return r0, r1
 }
 
-// StoreRefreshToken provides a mock function with given fields: ctx, token
-func (_m *UserRepoInterface) StoreRefreshToken(ctx context.Context, token *domians.RefreshToken) (*domians.RefreshToken, error) {
-	ret := _m.Called(ctx, token)
+// GetRoleAudits provides a mock function with given fields: ctx, userID
+func (_m *UserRepoInterface) GetRoleAudits(ctx context.Context, userID uint) ([]domians.RoleAudit, error) {
+	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for StoreRefreshToken")
+		panic("no return value specified for GetRoleAudits")
 	}
 
-	var r0 *domians.RefreshToken
+	var r0 []domians.RoleAudit
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domians.RefreshToken) (*domians.RefreshToken, error)); ok {
-		return rf(ctx, token)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]domians.RoleAudit, error)); ok {
+		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *domians.RefreshToken) *domians.RefreshToken); ok {
-		r0 = rf(ctx, token)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []domians.RoleAudit); ok {
+		r0 = rf(ctx, userID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domians.RefreshToken)
+			r0 = ret.Get(0).([]domians.RoleAudit)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *domians.RefreshToken) error); ok {
-		r1 = rf(ctx, token)
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -152,6 +142,84 @@ func (_m *UserRepoInterface) StoreRefreshToken(ctx context.Context, token *domia
 	return r0, r1
 }
 
+// GetUserByID provides a mock function with given fields: ctx, id
+func (_m *UserRepoInterface) GetUserByID(ctx context.Context, id uint) (*domians.User, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByID")
+	}
+
+	var r0 *domians.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*domians.User, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domians.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserTokenByHash provides a mock function with given fields: ctx, purpose, tokenHash
+func (_m *UserRepoInterface) GetUserTokenByHash(ctx context.Context, purpose string, tokenHash string) (*domians.UserToken, error) {
+	ret := _m.Called(ctx, purpose, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserTokenByHash")
+	}
+
+	var r0 *domians.UserToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domians.UserToken, error)); ok {
+		return rf(ctx, purpose, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domians.UserToken); ok {
+		r0 = rf(ctx, purpose, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domians.UserToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, purpose, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StoreRecoveryCodes provides a mock function with given fields: ctx, codes
+func (_m *UserRepoInterface) StoreRecoveryCodes(ctx context.Context, codes []domians.RecoveryCode) error {
+	ret := _m.Called(ctx, codes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreRecoveryCodes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domians.RecoveryCode) error); ok {
+		r0 = rf(ctx, codes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // StoreUser provides a mock function with given fields: ctx, cust
 func (_m *UserRepoInterface) StoreUser(ctx context.Context, cust *domians.User) (*domians.User, error) {
 	ret := _m.Called(ctx, cust)
@@ -182,16 +250,16 @@ func (_m *UserRepoInterface) StoreUser(ctx context.Context, cust *domians.User)
 	return r0, r1
 }
 
-// UpdateRefreshToken provides a mock function with given fields: ctx, token
-func (_m *UserRepoInterface) UpdateRefreshToken(ctx context.Context, token *domians.RefreshToken) error {
+// StoreUserToken provides a mock function with given fields: ctx, token
+func (_m *UserRepoInterface) StoreUserToken(ctx context.Context, token *domians.UserToken) error {
 	ret := _m.Called(ctx, token)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateRefreshToken")
+		panic("no return value specified for StoreUserToken")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domians.RefreshToken) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.UserToken) error); ok {
 		r0 = rf(ctx, token)
 	} else {
 		r0 = ret.Error(0)
@@ -200,6 +268,116 @@ func (_m *UserRepoInterface) UpdateRefreshToken(ctx context.Context, token *domi
 	return r0
 }
 
+// UpdateTOTPStep provides a mock function with given fields: ctx, userID, step
+func (_m *UserRepoInterface) UpdateTOTPStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	ret := _m.Called(ctx, userID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTOTPStep")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int64) (bool, error)); ok {
+		return rf(ctx, userID, step)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int64) bool); ok {
+		r0 = rf(ctx, userID, step)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, int64) error); ok {
+		r1 = rf(ctx, userID, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateUser provides a mock function with given fields: ctx, user, updateData
+func (_m *UserRepoInterface) UpdateUser(ctx context.Context, user *domians.User, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, user, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.User, map[string]interface{}) error); ok {
+		r0 = rf(ctx, user, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUserToken provides a mock function with given fields: ctx, token, updateData
+func (_m *UserRepoInterface) UpdateUserToken(ctx context.Context, token *domians.UserToken, updateData map[string]interface{}) error {
+	ret := _m.Called(ctx, token, updateData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domians.UserToken, map[string]interface{}) error); ok {
+		r0 = rf(ctx, token, updateData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseRecoveryCode provides a mock function with given fields: ctx, userID, codeHash, usedAt
+func (_m *UserRepoInterface) UseRecoveryCode(ctx context.Context, userID uint, codeHash string, usedAt time.Time) (bool, error) {
+	ret := _m.Called(ctx, userID, codeHash, usedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, time.Time) (bool, error)); ok {
+		return rf(ctx, userID, codeHash, usedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, time.Time) bool); ok {
+		r0 = rf(ctx, userID, codeHash, usedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string, time.Time) error); ok {
+		r1 = rf(ctx, userID, codeHash, usedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseUserTokens provides a mock function with given fields: ctx, userID, purpose, usedAt
+func (_m *UserRepoInterface) UseUserTokens(ctx context.Context, userID uint, purpose string, usedAt time.Time) error {
+	ret := _m.Called(ctx, userID, purpose, usedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseUserTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, time.Time) error); ok {
+		r0 = rf(ctx, userID, purpose, usedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUserRepoInterface creates a new instance of UserRepoInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserRepoInterface(t interface {
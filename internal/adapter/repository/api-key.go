@@ -0,0 +1,105 @@
+package Repository
+
+import (
+	"context"
+	"errors"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"gorm.io/gorm"
+)
+
+type (
+	APIKeyRepo struct {
+		db *gorm.DB
+	}
+
+	APIKeyRepoInterface interface {
+		StoreAPIKey(
+			ctx context.Context,
+			key *domians.APIKey,
+		) error
+		GetAPIKeyByID(
+			ctx context.Context,
+			keyID uint,
+		) (*domians.APIKey, error)
+		GetAPIKeyByHash(
+			ctx context.Context,
+			keyHash string,
+		) (*domians.APIKey, error)
+		GetAPIKeys(
+			ctx context.Context,
+		) ([]domians.APIKey, error)
+		UpdateAPIKey(
+			ctx context.Context,
+			key *domians.APIKey,
+			updateData map[string]any,
+		) error
+	}
+)
+
+func NewAPIKeyRepo(db *gorm.DB) APIKeyRepoInterface {
+	return &APIKeyRepo{
+		db: db,
+	}
+}
+
+func (repo APIKeyRepo) StoreAPIKey(
+	ctx context.Context,
+	key *domians.APIKey,
+) error {
+	return repo.db.WithContext(ctx).Omit("User").Create(key).Error
+}
+
+func (repo APIKeyRepo) GetAPIKeyByID(
+	ctx context.Context,
+	keyID uint,
+) (*domians.APIKey, error) {
+	var key domians.APIKey
+	if err := repo.db.WithContext(ctx).
+		First(&key, keyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetAPIKeyByHash loads the key with the user it acts for.
+func (repo APIKeyRepo) GetAPIKeyByHash(
+	ctx context.Context,
+	keyHash string,
+) (*domians.APIKey, error) {
+	var key domians.APIKey
+	if err := repo.db.WithContext(ctx).
+		Preload("User").
+		Where("key_hash = ?", keyHash).
+		First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (repo APIKeyRepo) GetAPIKeys(
+	ctx context.Context,
+) ([]domians.APIKey, error) {
+	var keys = make([]domians.APIKey, 0)
+	err := repo.db.WithContext(ctx).
+		Order("created_at DESC").
+		Find(&keys).
+		Error
+	return keys, err
+}
+
+func (repo APIKeyRepo) UpdateAPIKey(
+	ctx context.Context,
+	key *domians.APIKey,
+	updateData map[string]any,
+) error {
+	return repo.db.WithContext(ctx).
+		Model(key).
+		Updates(updateData).
+		Error
+}
@@ -0,0 +1,109 @@
+package Repository
+
+import (
+	"context"
+	"errors"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type (
+	IdempotencyRepo struct {
+		db *gorm.DB
+	}
+
+	IdempotencyRepoInterface interface {
+		ReserveKey(
+			ctx context.Context,
+			key *domians.IdempotencyKey,
+		) (bool, error)
+		GetKey(
+			ctx context.Context,
+			userID uint,
+			key string,
+		) (*domians.IdempotencyKey, error)
+		UpdateKey(
+			ctx context.Context,
+			key *domians.IdempotencyKey,
+			updateData map[string]any,
+		) error
+		DeleteKey(
+			ctx context.Context,
+			key *domians.IdempotencyKey,
+		) error
+		DeleteExpiredKeys(
+			ctx context.Context,
+			now time.Time,
+		) error
+	}
+)
+
+func NewIdempotencyRepo(db *gorm.DB) IdempotencyRepoInterface {
+	return &IdempotencyRepo{
+		db: db,
+	}
+}
+
+// ReserveKey stores the key unless the user already used it, false is
+// returned when the key exists.
+func (repo IdempotencyRepo) ReserveKey(
+	ctx context.Context,
+	key *domians.IdempotencyKey,
+) (bool, error) {
+	res := repo.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(key)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (repo IdempotencyRepo) GetKey(
+	ctx context.Context,
+	userID uint,
+	key string,
+) (*domians.IdempotencyKey, error) {
+	var idempotencyKey domians.IdempotencyKey
+	if err := repo.db.WithContext(ctx).
+		Where("user_id = ? AND idempotency_key = ?", userID, key).
+		First(&idempotencyKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &idempotencyKey, nil
+}
+
+func (repo IdempotencyRepo) UpdateKey(
+	ctx context.Context,
+	key *domians.IdempotencyKey,
+	updateData map[string]any,
+) error {
+	return repo.db.WithContext(ctx).
+		Model(key).
+		Updates(updateData).
+		Error
+}
+
+func (repo IdempotencyRepo) DeleteKey(
+	ctx context.Context,
+	key *domians.IdempotencyKey,
+) error {
+	return repo.db.WithContext(ctx).
+		Delete(&domians.IdempotencyKey{}, key.ID).
+		Error
+}
+
+func (repo IdempotencyRepo) DeleteExpiredKeys(
+	ctx context.Context,
+	now time.Time,
+) error {
+	return repo.db.WithContext(ctx).
+		Where("expires_at <= ?", now).
+		Delete(&domians.IdempotencyKey{}).
+		Error
+}
@@ -0,0 +1,252 @@
+package Repository
+
+import (
+	"context"
+	"errors"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	LedgerRepo struct {
+		db *gorm.DB
+	}
+
+	LedgerRepoInterface interface {
+		GetOrCreateAccount(
+			ctx context.Context,
+			account *domians.LedgerAccount,
+		) (*domians.LedgerAccount, error)
+		UpdateLedgerAccount(
+			ctx context.Context,
+			account *domians.LedgerAccount,
+			updateData map[string]any,
+		) error
+		CreateJournalEntry(
+			ctx context.Context,
+			entry *domians.JournalEntry,
+		) error
+		GetAccountByID(
+			ctx context.Context,
+			accountID uint,
+		) (*domians.LedgerAccount, error)
+		GetAccountsByUserID(
+			ctx context.Context,
+			userID uint,
+		) ([]domians.LedgerAccount, error)
+		GetAccountLines(
+			ctx context.Context,
+			accountID uint,
+			query dto.GetListQuery,
+		) ([]domians.JournalLine, int64, error)
+		CreateTopUp(
+			ctx context.Context,
+			topUp *domians.TopUp,
+		) (bool, error)
+		GetTopUpByIDForUpdate(
+			ctx context.Context,
+			topUpID uint,
+		) (*domians.TopUp, error)
+		GetTopUps(
+			ctx context.Context,
+			query dto.GetListQuery,
+			filter dto.TopUpFilter,
+		) ([]domians.TopUp, int64, error)
+		UpdateTopUp(
+			ctx context.Context,
+			topUp *domians.TopUp,
+			updateData map[string]any,
+		) error
+	}
+)
+
+func NewLedgerRepo(db *gorm.DB) LedgerRepoInterface {
+	return &LedgerRepo{
+		db: db,
+	}
+}
+
+// GetOrCreateAccount creates the account when its code does not exist yet
+// and returns it locked until the running transaction ends, it must be
+// called on a repo built from a transaction.
+func (repo LedgerRepo) GetOrCreateAccount(
+	ctx context.Context,
+	account *domians.LedgerAccount,
+) (*domians.LedgerAccount, error) {
+	err := repo.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "code"}},
+			DoNothing: true,
+		}).
+		Create(account).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	var locked domians.LedgerAccount
+	err = repo.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("code = ?", account.Code).
+		First(&locked).
+		Error
+	return &locked, err
+}
+
+func (repo LedgerRepo) UpdateLedgerAccount(
+	ctx context.Context,
+	account *domians.LedgerAccount,
+	updateData map[string]any,
+) error {
+	return repo.db.WithContext(ctx).
+		Model(&domians.LedgerAccount{ID: account.ID}).
+		Updates(updateData).
+		Error
+}
+
+// CreateJournalEntry stores the entry together with its lines.
+func (repo LedgerRepo) CreateJournalEntry(
+	ctx context.Context,
+	entry *domians.JournalEntry,
+) error {
+	return repo.db.WithContext(ctx).
+		Omit("Lines.JournalEntry", "Lines.Account").
+		Create(entry).
+		Error
+}
+
+func (repo LedgerRepo) GetAccountByID(
+	ctx context.Context,
+	accountID uint,
+) (*domians.LedgerAccount, error) {
+	var account domians.LedgerAccount
+	if err := repo.db.WithContext(ctx).
+		First(&account, accountID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (repo LedgerRepo) GetAccountsByUserID(
+	ctx context.Context,
+	userID uint,
+) ([]domians.LedgerAccount, error) {
+	var accounts = make([]domians.LedgerAccount, 0)
+	err := repo.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Find(&accounts).
+		Error
+	return accounts, err
+}
+
+// GetAccountLines returns the lines of an account, newest first.
+func (repo LedgerRepo) GetAccountLines(
+	ctx context.Context,
+	accountID uint,
+	query dto.GetListQuery,
+) ([]domians.JournalLine, int64, error) {
+	var (
+		lines = make([]domians.JournalLine, 0)
+		count int64
+	)
+
+	db := repo.db.WithContext(ctx).
+		Model(&domians.JournalLine{}).
+		Where("account_id = ?", accountID)
+	err := db.Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = db.Preload("JournalEntry").
+		Order("id DESC").
+		Limit(query.PerPage).
+		Offset(query.PerPage * (query.Page - 1)).
+		Find(&lines).Error
+	return lines, count, err
+}
+
+// CreateTopUp stores the top-up unless its payment reference was already
+// used, false is returned then.
+func (repo LedgerRepo) CreateTopUp(
+	ctx context.Context,
+	topUp *domians.TopUp,
+) (bool, error) {
+	res := repo.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "payment_reference"}},
+			DoNothing: true,
+		}).
+		Create(topUp)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// GetTopUpByIDForUpdate locks the top-up row until the running transaction
+// ends, it must be called on a repo built from a transaction.
+func (repo LedgerRepo) GetTopUpByIDForUpdate(
+	ctx context.Context,
+	topUpID uint,
+) (*domians.TopUp, error) {
+	var topUp domians.TopUp
+	if err := repo.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&topUp, topUpID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &topUp, nil
+}
+
+// GetTopUps returns the top-ups matching filter, oldest first so staff
+// review them in the order they were asked for.
+func (repo LedgerRepo) GetTopUps(
+	ctx context.Context,
+	query dto.GetListQuery,
+	filter dto.TopUpFilter,
+) ([]domians.TopUp, int64, error) {
+	var (
+		topUps = make([]domians.TopUp, 0)
+		count  int64
+	)
+
+	db := repo.db.WithContext(ctx).
+		Model(&domians.TopUp{})
+	if filter.UserID != 0 {
+		db.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Status != "" {
+		db.Where("status = ?", filter.Status)
+	}
+	err := db.Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = db.Order("id ASC").
+		Limit(query.PerPage).
+		Offset(query.PerPage * (query.Page - 1)).
+		Find(&topUps).Error
+	return topUps, count, err
+}
+
+func (repo LedgerRepo) UpdateTopUp(
+	ctx context.Context,
+	topUp *domians.TopUp,
+	updateData map[string]any,
+) error {
+	return repo.db.WithContext(ctx).
+		Model(&domians.TopUp{ID: topUp.ID}).
+		Updates(updateData).
+		Error
+}
@@ -3,10 +3,13 @@ package Repository
 import (
 	"context"
 	"errors"
+	"github.com/bowoBp/LoanFlow/internal/constant"
 	domians "github.com/bowoBp/LoanFlow/internal/domain"
 	"github.com/bowoBp/LoanFlow/internal/dto"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"strings"
+	"time"
 )
 
 type (
@@ -23,6 +26,10 @@ type (
 			ctx context.Context,
 			loanID uint,
 		) (*domians.Loan, error)
+		GetLoanByIDForUpdate(
+			ctx context.Context,
+			loanID uint,
+		) (*domians.Loan, error)
 		CreateLoanState(
 			ctx context.Context,
 			loanState *domians.LoanStateHistory,
@@ -47,10 +54,41 @@ type (
 		GetLoans(
 			ctx context.Context,
 			query dto.GetListQuery,
+			filter dto.LoanFilter,
 		) ([]domians.Loan, int64, error)
+		GetLoanInvestors(
+			ctx context.Context,
+			loanID uint,
+		) ([]domians.LoanInvestor, error)
+		ReleaseInvestments(
+			ctx context.Context,
+			loanID uint,
+			releasedAt time.Time,
+		) error
+		UpdateLoanInvestor(
+			ctx context.Context,
+			investor *domians.LoanInvestor,
+			updateData map[string]any,
+		) error
+		CountLoansByState(
+			ctx context.Context,
+		) ([]dto.LoanStateCount, error)
+		GetStateDurations(
+			ctx context.Context,
+			buckets []float64,
+		) ([]dto.LoanStateDuration, error)
 	}
 )
 
+// stateStays lists how long each loan stayed in a state, from the history
+// row entering the state to the next row changing it. Partial investments
+// keep the state and are skipped, the current state of a loan has no end
+// and a NULL duration.
+const stateStays = `SELECT new_state AS state,
+	EXTRACT(EPOCH FROM LEAD(action_at) OVER (PARTITION BY loan_id ORDER BY action_at, id) - action_at)::float8 AS seconds
+FROM loan_state_histories
+WHERE new_state IS DISTINCT FROM previous_state`
+
 func NewLoanRepo(db *gorm.DB) LoanRepoInterface {
 	return &LoanRepo{
 		db: db,
@@ -89,16 +127,43 @@ func (repo LoanRepo) GetLoanByID(
 	return &loan, nil
 }
 
+// GetLoanByIDForUpdate locks the loan row until the running transaction
+// ends, it must be called on a repo built from a transaction.
+func (repo LoanRepo) GetLoanByIDForUpdate(
+	ctx context.Context,
+	loanID uint,
+) (*domians.Loan, error) {
+	var loan domians.Loan
+	if err := repo.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&loan, loanID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &loan, nil
+}
+
+// UpdateLoan only updates the loan when its version is still loan.Version,
+// ErrLoanConflict is returned when another request changed it first.
 func (repo LoanRepo) UpdateLoan(
 	ctx context.Context,
 	loan *domians.Loan,
 	updateData map[string]any,
 ) error {
-	return repo.db.WithContext(ctx).
+	res := repo.db.WithContext(ctx).
 		Omit(clause.Associations).
-		Model(&loan).
-		Updates(updateData).
-		Error
+		Model(&domians.Loan{ID: loan.ID}).
+		Where("version = ?", loan.Version).
+		Updates(updateData)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return constant.ErrLoanConflict
+	}
+	return nil
 }
 
 func (repo LoanRepo) ApproveDetail(
@@ -125,6 +190,7 @@ func (repo LoanRepo) InvestLoan(
 func (repo LoanRepo) GetLoans(
 	ctx context.Context,
 	query dto.GetListQuery,
+	filter dto.LoanFilter,
 ) ([]domians.Loan, int64, error) {
 	var (
 		loans = make([]domians.Loan, 0)
@@ -136,6 +202,20 @@ func (repo LoanRepo) GetLoans(
 	if query.Search != "" {
 		db.Where("state like ?", "%"+query.Search+"%")
 	}
+	if filter.BorrowerID != 0 {
+		db.Where("borrower_id = ?", filter.BorrowerID)
+	}
+	if filter.InvestorID != 0 {
+		invested := repo.db.
+			Model(&domians.LoanInvestor{}).
+			Select("loan_id").
+			Where("investor_id = ?", filter.InvestorID)
+		if len(filter.OpenStates) > 0 {
+			db.Where("(state IN ? OR id IN (?))", filter.OpenStates, invested)
+		} else {
+			db.Where("id IN (?)", invested)
+		}
+	}
 	dbCount := db
 	err := dbCount.Count(&count).Error
 
@@ -148,3 +228,102 @@ func (repo LoanRepo) GetLoans(
 		Find(&loans).Error
 	return loans, count, err
 }
+
+func (repo LoanRepo) GetLoanInvestors(
+	ctx context.Context,
+	loanID uint,
+) ([]domians.LoanInvestor, error) {
+	var investors = make([]domians.LoanInvestor, 0)
+	err := repo.db.WithContext(ctx).
+		Preload("Investor").
+		Where("loan_id = ?", loanID).
+		Order("created_at ASC").
+		Find(&investors).
+		Error
+	return investors, err
+}
+
+func (repo LoanRepo) ReleaseInvestments(
+	ctx context.Context,
+	loanID uint,
+	releasedAt time.Time,
+) error {
+	return repo.db.WithContext(ctx).
+		Model(&domians.LoanInvestor{}).
+		Where("loan_id = ? AND status = ?", loanID, constant.InvestmentActive).
+		Updates(map[string]any{
+			"status":      constant.InvestmentReleased,
+			"released_at": releasedAt,
+			"updated_at":  releasedAt,
+		}).
+		Error
+}
+
+func (repo LoanRepo) UpdateLoanInvestor(
+	ctx context.Context,
+	investor *domians.LoanInvestor,
+	updateData map[string]any,
+) error {
+	return repo.db.WithContext(ctx).
+		Omit(clause.Associations).
+		Model(investor).
+		Updates(updateData).
+		Error
+}
+
+func (repo LoanRepo) CountLoansByState(
+	ctx context.Context,
+) ([]dto.LoanStateCount, error) {
+	var counts []dto.LoanStateCount
+	err := repo.db.WithContext(ctx).
+		Model(&domians.Loan{}).
+		Select("state, COUNT(*) AS count").
+		Group("state").
+		Order("state").
+		Scan(&counts).
+		Error
+	return counts, err
+}
+
+// GetStateDurations aggregates the finished stays of stateStays by state,
+// a stay is counted in every bucket whose upper bound in seconds it fits.
+func (repo LoanRepo) GetStateDurations(
+	ctx context.Context,
+	buckets []float64,
+) ([]dto.LoanStateDuration, error) {
+	columns := []string{"state", "COUNT(*)", "COALESCE(SUM(seconds), 0)"}
+	args := make([]any, 0, len(buckets))
+	for _, bucket := range buckets {
+		columns = append(columns, "COUNT(*) FILTER (WHERE seconds <= ?)")
+		args = append(args, bucket)
+	}
+	rows, err := repo.db.WithContext(ctx).
+		Raw(
+			"WITH stays AS ("+stateStays+") SELECT "+strings.Join(columns, ", ")+
+				" FROM stays WHERE seconds IS NOT NULL GROUP BY state ORDER BY state",
+			args...,
+		).
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var durations []dto.LoanStateDuration
+	for rows.Next() {
+		duration := dto.LoanStateDuration{Buckets: make(map[float64]uint64, len(buckets))}
+		counts := make([]uint64, len(buckets))
+		dest := []any{&duration.State, &duration.Count, &duration.Sum}
+		for i := range counts {
+			dest = append(dest, &counts[i])
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for i, bucket := range buckets {
+			duration.Buckets[bucket] = counts[i]
+		}
+		durations = append(durations, duration)
+	}
+	return durations, rows.Err()
+}
@@ -0,0 +1,170 @@
+package Repository
+
+import (
+	"context"
+	"errors"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type (
+	LoginAttemptRepo struct {
+		db *gorm.DB
+	}
+
+	LoginAttemptRepoInterface interface {
+		GetLoginThrottle(
+			ctx context.Context,
+			scope, key string,
+		) (*domians.LoginThrottle, error)
+		IncrementLoginFailures(
+			ctx context.Context,
+			scope, key string,
+			failedAt, windowStart time.Time,
+		) (*domians.LoginThrottle, error)
+		LockLogin(
+			ctx context.Context,
+			scope, key string,
+			lockedUntil time.Time,
+		) error
+		DeleteLoginThrottle(
+			ctx context.Context,
+			scope, key string,
+		) error
+		CreateAuthEvent(
+			ctx context.Context,
+			event *domians.AuthEvent,
+		) error
+		GetAuthEvents(
+			ctx context.Context,
+			userID uint,
+		) ([]domians.AuthEvent, error)
+		GetFailedLoginIPs(
+			ctx context.Context,
+			userID uint,
+			since time.Time,
+		) ([]string, error)
+	}
+)
+
+func NewLoginAttemptRepo(db *gorm.DB) LoginAttemptRepoInterface {
+	return &LoginAttemptRepo{
+		db: db,
+	}
+}
+
+func (repo LoginAttemptRepo) GetLoginThrottle(
+	ctx context.Context,
+	scope, key string,
+) (*domians.LoginThrottle, error) {
+	var throttle domians.LoginThrottle
+	if err := repo.db.WithContext(ctx).
+		Where("scope = ? AND throttle_key = ?", scope, key).
+		First(&throttle).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// IncrementLoginFailures counts a failed login in one statement so
+// concurrent attempts are all counted, the count starts over when the last
+// failure is before windowStart.
+func (repo LoginAttemptRepo) IncrementLoginFailures(
+	ctx context.Context,
+	scope, key string,
+	failedAt, windowStart time.Time,
+) (*domians.LoginThrottle, error) {
+	throttle := &domians.LoginThrottle{
+		Scope:        scope,
+		Key:          key,
+		Failures:     1,
+		LastFailedAt: failedAt,
+	}
+	err := repo.db.WithContext(ctx).
+		Clauses(
+			clause.OnConflict{
+				Columns: []clause.Column{{Name: "scope"}, {Name: "throttle_key"}},
+				DoUpdates: clause.Assignments(map[string]any{
+					"failures": gorm.Expr(
+						"CASE WHEN login_throttles.last_failed_at < ? THEN 1 ELSE login_throttles.failures + 1 END",
+						windowStart,
+					),
+					"last_failed_at": failedAt,
+				}),
+			},
+			clause.Returning{},
+		).
+		Create(throttle).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return throttle, nil
+}
+
+func (repo LoginAttemptRepo) LockLogin(
+	ctx context.Context,
+	scope, key string,
+	lockedUntil time.Time,
+) error {
+	return repo.db.WithContext(ctx).
+		Model(&domians.LoginThrottle{}).
+		Where("scope = ? AND throttle_key = ?", scope, key).
+		Update("locked_until", lockedUntil).
+		Error
+}
+
+func (repo LoginAttemptRepo) DeleteLoginThrottle(
+	ctx context.Context,
+	scope, key string,
+) error {
+	return repo.db.WithContext(ctx).
+		Where("scope = ? AND throttle_key = ?", scope, key).
+		Delete(&domians.LoginThrottle{}).
+		Error
+}
+
+func (repo LoginAttemptRepo) CreateAuthEvent(
+	ctx context.Context,
+	event *domians.AuthEvent,
+) error {
+	return repo.db.WithContext(ctx).Create(event).Error
+}
+
+func (repo LoginAttemptRepo) GetAuthEvents(
+	ctx context.Context,
+	userID uint,
+) ([]domians.AuthEvent, error) {
+	var events = make([]domians.AuthEvent, 0)
+	err := repo.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&events).
+		Error
+	return events, err
+}
+
+// GetFailedLoginIPs returns the IP addresses the user failed to login from
+// since the given time.
+func (repo LoginAttemptRepo) GetFailedLoginIPs(
+	ctx context.Context,
+	userID uint,
+	since time.Time,
+) ([]string, error) {
+	var ips = make([]string, 0)
+	err := repo.db.WithContext(ctx).
+		Model(&domians.AuthEvent{}).
+		Distinct("ip_address").
+		Where("user_id = ? AND event = ? AND created_at >= ? AND ip_address <> ''",
+			userID, constant.AuthEventLoginFailed, since).
+		Order("ip_address").
+		Pluck("ip_address", &ips).
+		Error
+	return ips, err
+}
@@ -0,0 +1,122 @@
+package Repository
+
+import (
+	"context"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type (
+	NotificationRepo struct {
+		db *gorm.DB
+	}
+
+	NotificationRepoInterface interface {
+		CreateNotifications(
+			ctx context.Context,
+			notifications []domians.Notification,
+		) error
+		ClaimDueNotifications(
+			ctx context.Context,
+			now time.Time,
+			limit int,
+			lease time.Duration,
+		) ([]domians.Notification, error)
+		UpdateNotification(
+			ctx context.Context,
+			notification *domians.Notification,
+			updateData map[string]any,
+		) error
+		DeleteFinishedNotifications(
+			ctx context.Context,
+			before time.Time,
+		) error
+	}
+)
+
+func NewNotificationRepo(db *gorm.DB) NotificationRepoInterface {
+	return &NotificationRepo{
+		db: db,
+	}
+}
+
+func (repo NotificationRepo) CreateNotifications(
+	ctx context.Context,
+	notifications []domians.Notification,
+) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return repo.db.WithContext(ctx).
+		Omit(clause.Associations).
+		Create(&notifications).
+		Error
+}
+
+// ClaimDueNotifications marks a batch of due notifications as sending for
+// the length of lease, so other dispatchers skip them. The rows are locked
+// with SKIP LOCKED while they are claimed, and a claim left by a dispatcher
+// that stopped is taken again once its lease is over.
+func (repo NotificationRepo) ClaimDueNotifications(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+	lease time.Duration,
+) ([]domians.Notification, error) {
+	var notifications = make([]domians.Notification, 0)
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND next_attempt_at <= ?",
+				[]string{constant.NotificationPending, constant.NotificationSending}, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&notifications).
+			Error
+		if err != nil || len(notifications) == 0 {
+			return err
+		}
+		ids := make([]uint, len(notifications))
+		for i := range notifications {
+			ids[i] = notifications[i].ID
+			notifications[i].Status = constant.NotificationSending
+			notifications[i].NextAttemptAt = now.Add(lease)
+		}
+		return tx.Model(&domians.Notification{}).
+			Where("id IN ?", ids).
+			Updates(map[string]any{
+				"status":          constant.NotificationSending,
+				"next_attempt_at": now.Add(lease),
+			}).
+			Error
+	})
+	return notifications, err
+}
+
+func (repo NotificationRepo) UpdateNotification(
+	ctx context.Context,
+	notification *domians.Notification,
+	updateData map[string]any,
+) error {
+	return repo.db.WithContext(ctx).
+		Omit(clause.Associations).
+		Model(notification).
+		Updates(updateData).
+		Error
+}
+
+// DeleteFinishedNotifications deletes the sent and failed notifications
+// last changed before before.
+func (repo NotificationRepo) DeleteFinishedNotifications(
+	ctx context.Context,
+	before time.Time,
+) error {
+	return repo.db.WithContext(ctx).
+		Where("status IN ? AND updated_at < ?",
+			[]string{constant.NotificationSent, constant.NotificationFailed}, before).
+		Delete(&domians.Notification{}).
+		Error
+}
@@ -0,0 +1,64 @@
+package Repository
+
+import (
+	"context"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"gorm.io/gorm"
+)
+
+type (
+	PermissionRepo struct {
+		db *gorm.DB
+	}
+
+	PermissionRepoInterface interface {
+		GetRolePermissions(
+			ctx context.Context,
+		) ([]domians.RolePermission, error)
+		DeleteRolePermissions(
+			ctx context.Context,
+			role string,
+		) error
+		StoreRolePermissions(
+			ctx context.Context,
+			permissions []domians.RolePermission,
+		) error
+	}
+)
+
+func NewPermissionRepo(db *gorm.DB) PermissionRepoInterface {
+	return &PermissionRepo{
+		db: db,
+	}
+}
+
+func (repo PermissionRepo) GetRolePermissions(
+	ctx context.Context,
+) ([]domians.RolePermission, error) {
+	var permissions = make([]domians.RolePermission, 0)
+	err := repo.db.WithContext(ctx).
+		Order("role, permission").
+		Find(&permissions).
+		Error
+	return permissions, err
+}
+
+func (repo PermissionRepo) DeleteRolePermissions(
+	ctx context.Context,
+	role string,
+) error {
+	return repo.db.WithContext(ctx).
+		Where("role = ?", role).
+		Delete(&domians.RolePermission{}).
+		Error
+}
+
+func (repo PermissionRepo) StoreRolePermissions(
+	ctx context.Context,
+	permissions []domians.RolePermission,
+) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+	return repo.db.WithContext(ctx).Create(&permissions).Error
+}
@@ -0,0 +1,105 @@
+package Repository
+
+import (
+	"context"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	RepaymentRepo struct {
+		db *gorm.DB
+	}
+
+	RepaymentRepoInterface interface {
+		CreateInstallments(
+			ctx context.Context,
+			installments []domians.LoanInstallment,
+		) error
+		GetInstallmentsByLoanID(
+			ctx context.Context,
+			loanID uint,
+		) ([]domians.LoanInstallment, error)
+		UpdateInstallment(
+			ctx context.Context,
+			installment *domians.LoanInstallment,
+			updateData map[string]any,
+		) error
+		CreateRepayment(
+			ctx context.Context,
+			repayment *domians.LoanRepayment,
+		) error
+		GetRepaymentsByLoanID(
+			ctx context.Context,
+			loanID uint,
+		) ([]domians.LoanRepayment, error)
+	}
+)
+
+func NewRepaymentRepo(db *gorm.DB) RepaymentRepoInterface {
+	return &RepaymentRepo{
+		db: db,
+	}
+}
+
+func (repo RepaymentRepo) CreateInstallments(
+	ctx context.Context,
+	installments []domians.LoanInstallment,
+) error {
+	if len(installments) == 0 {
+		return nil
+	}
+	return repo.db.WithContext(ctx).
+		Omit(clause.Associations).
+		Create(&installments).
+		Error
+}
+
+func (repo RepaymentRepo) GetInstallmentsByLoanID(
+	ctx context.Context,
+	loanID uint,
+) ([]domians.LoanInstallment, error) {
+	var installments = make([]domians.LoanInstallment, 0)
+	err := repo.db.WithContext(ctx).
+		Where("loan_id = ?", loanID).
+		Order("sequence ASC").
+		Find(&installments).
+		Error
+	return installments, err
+}
+
+func (repo RepaymentRepo) UpdateInstallment(
+	ctx context.Context,
+	installment *domians.LoanInstallment,
+	updateData map[string]any,
+) error {
+	return repo.db.WithContext(ctx).
+		Omit(clause.Associations).
+		Model(installment).
+		Updates(updateData).
+		Error
+}
+
+func (repo RepaymentRepo) CreateRepayment(
+	ctx context.Context,
+	repayment *domians.LoanRepayment,
+) error {
+	return repo.db.WithContext(ctx).
+		Omit(clause.Associations).
+		Create(repayment).
+		Error
+}
+
+func (repo RepaymentRepo) GetRepaymentsByLoanID(
+	ctx context.Context,
+	loanID uint,
+) ([]domians.LoanRepayment, error) {
+	var repayments = make([]domians.LoanRepayment, 0)
+	err := repo.db.WithContext(ctx).
+		Where("loan_id = ?", loanID).
+		Order("paid_at ASC").
+		Find(&repayments).
+		Error
+	return repayments, err
+}
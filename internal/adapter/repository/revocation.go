@@ -0,0 +1,119 @@
+package Repository
+
+import (
+	"context"
+	"errors"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type (
+	RevocationRepo struct {
+		db *gorm.DB
+	}
+
+	RevocationRepoInterface interface {
+		StoreRevokedToken(
+			ctx context.Context,
+			token *domians.RevokedToken,
+		) error
+		GetRevokedToken(
+			ctx context.Context,
+			tokenID string,
+		) (*domians.RevokedToken, error)
+		StoreUserRevocation(
+			ctx context.Context,
+			revocation *domians.UserRevocation,
+		) error
+		GetUserRevocation(
+			ctx context.Context,
+			userID uint,
+		) (*domians.UserRevocation, error)
+		DeleteExpiredRevocations(
+			ctx context.Context,
+			now time.Time,
+		) error
+	}
+)
+
+func NewRevocationRepo(db *gorm.DB) RevocationRepoInterface {
+	return &RevocationRepo{
+		db: db,
+	}
+}
+
+// StoreRevokedToken ignores a token that is already revoked.
+func (repo RevocationRepo) StoreRevokedToken(
+	ctx context.Context,
+	token *domians.RevokedToken,
+) error {
+	return repo.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(token).
+		Error
+}
+
+func (repo RevocationRepo) GetRevokedToken(
+	ctx context.Context,
+	tokenID string,
+) (*domians.RevokedToken, error) {
+	var token domians.RevokedToken
+	if err := repo.db.WithContext(ctx).
+		Where("token_id = ?", tokenID).
+		First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// StoreUserRevocation replaces the previous revocation of the user.
+func (repo RevocationRepo) StoreUserRevocation(
+	ctx context.Context,
+	revocation *domians.UserRevocation,
+) error {
+	return repo.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"revoked_at", "expires_at"}),
+		}).
+		Create(revocation).
+		Error
+}
+
+func (repo RevocationRepo) GetUserRevocation(
+	ctx context.Context,
+	userID uint,
+) (*domians.UserRevocation, error) {
+	var revocation domians.UserRevocation
+	if err := repo.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		First(&revocation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &revocation, nil
+}
+
+func (repo RevocationRepo) DeleteExpiredRevocations(
+	ctx context.Context,
+	now time.Time,
+) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("expires_at <= ?", now).
+			Delete(&domians.RevokedToken{}).
+			Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("expires_at <= ?", now).
+			Delete(&domians.UserRevocation{}).
+			Error
+	})
+}
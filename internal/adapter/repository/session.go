@@ -0,0 +1,163 @@
+package Repository
+
+import (
+	"context"
+	"errors"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type (
+	SessionRepo struct {
+		db *gorm.DB
+	}
+
+	SessionRepoInterface interface {
+		CreateSession(
+			ctx context.Context,
+			session *domians.Session,
+		) error
+		GetSessionByID(
+			ctx context.Context,
+			sessionID uint,
+		) (*domians.Session, error)
+		GetActiveSessions(
+			ctx context.Context,
+			userID uint,
+			now time.Time,
+		) ([]domians.Session, error)
+		UpdateSession(
+			ctx context.Context,
+			session *domians.Session,
+			updateData map[string]any,
+		) error
+		RevokeSessionsByUserID(
+			ctx context.Context,
+			userID uint,
+			revokedAt time.Time,
+		) error
+		StoreRefreshToken(
+			ctx context.Context,
+			token *domians.RefreshToken,
+		) error
+		GetRefreshTokenByHash(
+			ctx context.Context,
+			tokenHash string,
+		) (*domians.RefreshToken, error)
+		UpdateRefreshToken(
+			ctx context.Context,
+			token *domians.RefreshToken,
+			updateData map[string]any,
+		) error
+	}
+)
+
+func NewSessionRepo(db *gorm.DB) SessionRepoInterface {
+	return &SessionRepo{
+		db: db,
+	}
+}
+
+func (repo SessionRepo) CreateSession(
+	ctx context.Context,
+	session *domians.Session,
+) error {
+	return repo.db.WithContext(ctx).Create(session).Error
+}
+
+func (repo SessionRepo) GetSessionByID(
+	ctx context.Context,
+	sessionID uint,
+) (*domians.Session, error) {
+	var session domians.Session
+	if err := repo.db.WithContext(ctx).
+		First(&session, sessionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetActiveSessions returns the sessions that are neither revoked nor
+// expired, the last used first.
+func (repo SessionRepo) GetActiveSessions(
+	ctx context.Context,
+	userID uint,
+	now time.Time,
+) ([]domians.Session, error) {
+	var sessions = make([]domians.Session, 0)
+	err := repo.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_used_at DESC").
+		Find(&sessions).
+		Error
+	return sessions, err
+}
+
+func (repo SessionRepo) UpdateSession(
+	ctx context.Context,
+	session *domians.Session,
+	updateData map[string]any,
+) error {
+	return repo.db.WithContext(ctx).
+		Model(&domians.Session{ID: session.ID}).
+		Updates(updateData).
+		Error
+}
+
+func (repo SessionRepo) RevokeSessionsByUserID(
+	ctx context.Context,
+	userID uint,
+	revokedAt time.Time,
+) error {
+	return repo.db.WithContext(ctx).
+		Model(&domians.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", revokedAt).
+		Error
+}
+
+func (repo SessionRepo) StoreRefreshToken(
+	ctx context.Context,
+	token *domians.RefreshToken,
+) error {
+	return repo.db.WithContext(ctx).
+		Omit(clause.Associations).
+		Create(token).
+		Error
+}
+
+// GetRefreshTokenByHash locks the token row so concurrent refreshes with
+// the same token are serialized, the session is preloaded.
+func (repo SessionRepo) GetRefreshTokenByHash(
+	ctx context.Context,
+	tokenHash string,
+) (*domians.RefreshToken, error) {
+	var token domians.RefreshToken
+	if err := repo.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Session").
+		Where("token_hash = ?", tokenHash).
+		First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (repo SessionRepo) UpdateRefreshToken(
+	ctx context.Context,
+	token *domians.RefreshToken,
+	updateData map[string]any,
+) error {
+	return repo.db.WithContext(ctx).
+		Model(&domians.RefreshToken{ID: token.ID}).
+		Updates(updateData).
+		Error
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"github.com/bowoBp/LoanFlow/internal/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
 )
 
 type (
@@ -27,23 +29,72 @@ type (
 			userName string,
 		) (*domians.User, error)
 
-		StoreRefreshToken(
+		GetUserByID(
 			ctx context.Context,
-			token *domians.RefreshToken,
-		) (*domians.RefreshToken, error)
+			id uint,
+		) (*domians.User, error)
 
-		CheckRefreshToken(
+		UpdateUser(
 			ctx context.Context,
-			id uint,
-		) (*domians.RefreshToken, error)
+			user *domians.User,
+			updateData map[string]any,
+		) error
+
+		CreateRoleAudit(
+			ctx context.Context,
+			audit *domians.RoleAudit,
+		) error
+
+		GetRoleAudits(
+			ctx context.Context,
+			userID uint,
+		) ([]domians.RoleAudit, error)
+
+		StoreUserToken(
+			ctx context.Context,
+			token *domians.UserToken,
+		) error
+
+		GetUserTokenByHash(
+			ctx context.Context,
+			purpose, tokenHash string,
+		) (*domians.UserToken, error)
+
+		UseUserTokens(
+			ctx context.Context,
+			userID uint,
+			purpose string,
+			usedAt time.Time,
+		) error
+
+		UpdateUserToken(
+			ctx context.Context,
+			token *domians.UserToken,
+			updateData map[string]any,
+		) error
+
+		UpdateTOTPStep(
+			ctx context.Context,
+			userID uint,
+			step int64,
+		) (bool, error)
+
+		StoreRecoveryCodes(
+			ctx context.Context,
+			codes []domians.RecoveryCode,
+		) error
 
-		UpdateRefreshToken(
+		DeleteRecoveryCodes(
 			ctx context.Context,
-			token *domians.RefreshToken) error
+			userID uint,
+		) error
 
-		DeleteRefreshTokenByUserID(
+		UseRecoveryCode(
 			ctx context.Context,
-			userID uint) error
+			userID uint,
+			codeHash string,
+			usedAt time.Time,
+		) (bool, error)
 	}
 )
 
@@ -61,16 +112,6 @@ func (repo UserRepo) StoreUser(
 	return cust, err
 }
 
-func (repo UserRepo) StoreRefreshToken(
-	ctx context.Context,
-	token *domians.RefreshToken,
-) (*domians.RefreshToken, error) {
-	err := repo.db.WithContext(ctx).
-		Create(&token).
-		Error
-	return token, err
-}
-
 func (repo UserRepo) GetAllUser(
 	ctx context.Context,
 ) ([]domians.User, error) {
@@ -98,33 +139,145 @@ func (repo UserRepo) CheckEmail(
 	return customer, err
 }
 
-func (repo UserRepo) CheckRefreshToken(
+func (repo UserRepo) GetUserByID(
 	ctx context.Context,
 	id uint,
-) (*domians.RefreshToken, error) {
-	token := &domians.RefreshToken{}
+) (*domians.User, error) {
+	var user domians.User
+	if err := repo.db.WithContext(ctx).
+		First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (repo UserRepo) UpdateUser(
+	ctx context.Context,
+	user *domians.User,
+	updateData map[string]any,
+) error {
+	return repo.db.WithContext(ctx).
+		Model(&domians.User{ID: user.ID}).
+		Updates(updateData).
+		Error
+}
 
+func (repo UserRepo) CreateRoleAudit(
+	ctx context.Context,
+	audit *domians.RoleAudit,
+) error {
+	return repo.db.WithContext(ctx).Create(audit).Error
+}
+
+func (repo UserRepo) GetRoleAudits(
+	ctx context.Context,
+	userID uint,
+) ([]domians.RoleAudit, error) {
+	var audits = make([]domians.RoleAudit, 0)
 	err := repo.db.WithContext(ctx).
-		Model(&domians.RefreshToken{}).
-		Where("user_id = ? ", id).
-		First(token).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&audits).
 		Error
+	return audits, err
+}
 
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return nil, nil
+func (repo UserRepo) StoreUserToken(
+	ctx context.Context,
+	token *domians.UserToken,
+) error {
+	return repo.db.WithContext(ctx).Create(token).Error
+}
+
+// GetUserTokenByHash locks the token row so a token can only be used once
+// by concurrent requests.
+func (repo UserRepo) GetUserTokenByHash(
+	ctx context.Context,
+	purpose, tokenHash string,
+) (*domians.UserToken, error) {
+	var token domians.UserToken
+	if err := repo.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("purpose = ? AND token_hash = ?", purpose, tokenHash).
+		First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
 	}
-	return token, err
+	return &token, nil
 }
-func (repo UserRepo) UpdateRefreshToken(
+
+// UseUserTokens marks every unused token of the user for purpose as used.
+func (repo UserRepo) UseUserTokens(
 	ctx context.Context,
-	token *domians.RefreshToken) error {
-	return repo.db.WithContext(ctx).Save(token).Error
+	userID uint,
+	purpose string,
+	usedAt time.Time,
+) error {
+	return repo.db.WithContext(ctx).
+		Model(&domians.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", usedAt).
+		Error
 }
 
-func (repo UserRepo) DeleteRefreshTokenByUserID(
+func (repo UserRepo) UpdateUserToken(
 	ctx context.Context,
-	userID uint) error {
+	token *domians.UserToken,
+	updateData map[string]any,
+) error {
+	return repo.db.WithContext(ctx).
+		Model(&domians.UserToken{ID: token.ID}).
+		Updates(updateData).
+		Error
+}
+
+// UpdateTOTPStep records the step of a TOTP code, false is returned when
+// the same or a later step was already used so a code works only once.
+func (repo UserRepo) UpdateTOTPStep(
+	ctx context.Context,
+	userID uint,
+	step int64,
+) (bool, error) {
+	result := repo.db.WithContext(ctx).
+		Model(&domians.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (repo UserRepo) StoreRecoveryCodes(
+	ctx context.Context,
+	codes []domians.RecoveryCode,
+) error {
+	return repo.db.WithContext(ctx).Create(&codes).Error
+}
+
+func (repo UserRepo) DeleteRecoveryCodes(
+	ctx context.Context,
+	userID uint,
+) error {
 	return repo.db.WithContext(ctx).
 		Where("user_id = ?", userID).
-		Delete(&domians.RefreshToken{}).Error
+		Delete(&domians.RecoveryCode{}).
+		Error
+}
+
+// UseRecoveryCode marks an unused code of the user as used, false is
+// returned when there is no such code.
+func (repo UserRepo) UseRecoveryCode(
+	ctx context.Context,
+	userID uint,
+	codeHash string,
+	usedAt time.Time,
+) (bool, error) {
+	result := repo.db.WithContext(ctx).
+		Model(&domians.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", usedAt)
+	return result.RowsAffected == 1, result.Error
 }
@@ -0,0 +1,159 @@
+package agreement
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/pkg/document"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"html/template"
+	"time"
+)
+
+//go:embed templates/letter.html
+var defaultTemplate string
+
+type (
+	// InvestorStore reads and updates the investments of a loan, usually the
+	// running db transaction of the caller.
+	InvestorStore interface {
+		GetLoanInvestors(
+			ctx context.Context,
+			loanID uint,
+		) ([]domians.LoanInvestor, error)
+		UpdateLoanInvestor(
+			ctx context.Context,
+			investor *domians.LoanInvestor,
+			updateData map[string]any,
+		) error
+	}
+
+	// Letter is the data passed to the agreement template.
+	Letter struct {
+		LoanID         uint
+		BorrowerID     uint
+		Currency       money.Currency
+		Principal      money.Money
+		Rate           money.Rate
+		ROI            money.Money
+		Tenor          uint
+		InvestorID     uint
+		AmountInvested money.Money
+		SharePercent   float64
+		ROIShare       money.Money
+		IssuedAt       time.Time
+	}
+
+	Generator struct {
+		template  *template.Template
+		documents document.Store
+	}
+
+	GeneratorInterface interface {
+		Generate(
+			ctx context.Context,
+			tx InvestorStore,
+			loan *domians.Loan,
+		) ([]domians.LoanInvestor, error)
+	}
+)
+
+func NewGenerator(
+	tmpl *template.Template,
+	documents document.Store,
+) GeneratorInterface {
+	return Generator{
+		template:  tmpl,
+		documents: documents,
+	}
+}
+
+// ParseTemplate reads the letter template from path, the bundled template
+// is used when path is empty.
+func ParseTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return template.New("letter.html").Parse(defaultTemplate)
+	}
+	return template.ParseFiles(path)
+}
+
+// Generate renders one letter per active investment of a fully invested
+// loan, saves it to the document store and links it on the investment.
+// The investments are returned with their new links.
+func (g Generator) Generate(
+	ctx context.Context,
+	tx InvestorStore,
+	loan *domians.Loan,
+) ([]domians.LoanInvestor, error) {
+	investors, err := tx.GetLoanInvestors(ctx, loan.ID)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		active    = make([]domians.LoanInvestor, 0, len(investors))
+		amounts   = make([]money.Money, 0, len(investors))
+		principal money.Money
+	)
+	for _, investor := range investors {
+		if investor.Status == constant.InvestmentActive {
+			active = append(active, investor)
+			amounts = append(amounts, investor.AmountInvested)
+			principal = principal.Add(investor.AmountInvested)
+		}
+	}
+	if !principal.IsPositive() {
+		return nil, constant.ErrAgreementInvestors
+	}
+
+	issuedAt := time.Now()
+	// ROI is shared pro-rata to the invested amounts
+	shares := loan.ROI.Allocate(amounts)
+	for i := range active {
+		letter := Letter{
+			LoanID:         loan.ID,
+			BorrowerID:     loan.BorrowerID,
+			Currency:       loan.Currency,
+			Principal:      principal,
+			Rate:           loan.Rate,
+			ROI:            loan.ROI,
+			Tenor:          loan.Tenor,
+			InvestorID:     active[i].InvestorID,
+			AmountInvested: active[i].AmountInvested,
+			SharePercent:   active[i].AmountInvested.Ratio(principal) * 100,
+			ROIShare:       shares[i],
+			IssuedAt:       issuedAt,
+		}
+		var content bytes.Buffer
+		err = g.template.Execute(&content, letter)
+		if err != nil {
+			return nil, err
+		}
+
+		link, err := g.documents.Save(
+			ctx,
+			fmt.Sprintf("agreements/loan-%d/investment-%d.html", loan.ID, active[i].ID),
+			content.Bytes(),
+		)
+		if err != nil {
+			return nil, err
+		}
+		err = tx.UpdateLoanInvestor(
+			ctx,
+			&domians.LoanInvestor{
+				ID: active[i].ID,
+			},
+			map[string]any{
+				"agreement_letter_link": link,
+				"updated_at":            issuedAt,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		active[i].AgreementLetterLink = link
+	}
+	return active, nil
+}
@@ -0,0 +1,89 @@
+package agreement_test
+
+import (
+	"context"
+	"github.com/bowoBp/LoanFlow/internal/adapter/mocks"
+	"github.com/bowoBp/LoanFlow/internal/agreement"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/pkg/document"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerator_Generate(t *testing.T) {
+	loan := &domians.Loan{
+		ID:         1,
+		BorrowerID: 2,
+		Rate:       money.MustParseRate("10"),
+		ROI:        money.New(100000),
+		Tenor:      12,
+		State:      constant.Invested,
+	}
+
+	tests := []struct {
+		name         string
+		mockBehavior func(tx *mocks.InvestorStore)
+		wantShares   []string
+		expectedErr  error
+	}{
+		{
+			name: "Success - one letter per active investment",
+			mockBehavior: func(tx *mocks.InvestorStore) {
+				tx.On("GetLoanInvestors", mock.Anything, loan.ID).
+					Return([]domians.LoanInvestor{
+						{ID: 10, LoanID: loan.ID, InvestorID: 7, AmountInvested: money.MustParse("333333.33"), Status: constant.InvestmentActive},
+						{ID: 11, LoanID: loan.ID, InvestorID: 8, AmountInvested: money.New(100000), Status: constant.InvestmentReleased},
+						{ID: 12, LoanID: loan.ID, InvestorID: 9, AmountInvested: money.MustParse("666666.67"), Status: constant.InvestmentActive},
+					}, nil).Once()
+				tx.On("UpdateLoanInvestor", mock.Anything, &domians.LoanInvestor{ID: 10}, mock.MatchedBy(func(data map[string]any) bool {
+					return data["agreement_letter_link"] == "https://docs.loanflow.test/agreements/loan-1/investment-10.html"
+				})).Return(nil).Once()
+				tx.On("UpdateLoanInvestor", mock.Anything, &domians.LoanInvestor{ID: 12}, mock.MatchedBy(func(data map[string]any) bool {
+					return data["agreement_letter_link"] == "https://docs.loanflow.test/agreements/loan-1/investment-12.html"
+				})).Return(nil).Once()
+			},
+			// rounding leftovers go to the last investment
+			wantShares: []string{"33333.33", "66666.67"},
+		},
+		{
+			name: "Error - no active investment",
+			mockBehavior: func(tx *mocks.InvestorStore) {
+				tx.On("GetLoanInvestors", mock.Anything, loan.ID).
+					Return([]domians.LoanInvestor{}, nil).Once()
+			},
+			expectedErr: constant.ErrAgreementInvestors,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := new(mocks.InvestorStore)
+			tt.mockBehavior(tx)
+
+			dir := t.TempDir()
+			tmpl, err := agreement.ParseTemplate("")
+			assert.NoError(t, err)
+			generator := agreement.NewGenerator(
+				tmpl,
+				document.NewLocalStore(dir, "https://docs.loanflow.test/"),
+			)
+
+			investors, err := generator.Generate(context.Background(), tx, loan)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Len(t, investors, len(tt.wantShares))
+			for i, investor := range investors {
+				content, err := os.ReadFile(filepath.Join(dir, "agreements", "loan-1",
+					filepath.Base(investor.AgreementLetterLink)))
+				assert.NoError(t, err)
+				assert.Contains(t, string(content), tt.wantShares[i])
+			}
+			tx.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package constant
+
+import "time"
+
+const (
+	// APIKeyHeader is the request header carrying the API key of a partner
+	// system, it is used instead of a Bearer token
+	APIKeyHeader = "X-API-Key"
+	// APIKeyPrefix starts every API key so a leaked key is easy to recognise
+	APIKeyPrefix = "lf_"
+	// APIKeyTouchInterval is how often the last use of a key is written
+	APIKeyTouchInterval = time.Minute
+
+	// Principal types
+	PrincipalUser   = "user"
+	PrincipalAPIKey = "api_key"
+
+	// API key scopes
+	ScopeLoansRead     = "loans:read"
+	ScopeLoansCreate   = "loans:create"
+	ScopeLoansApprove  = "loans:approve"
+	ScopeLoansInvest   = "loans:invest"
+	ScopeLoansDisburse = "loans:disburse"
+	ScopeLoansReject   = "loans:reject"
+	ScopeLoansCancel   = "loans:cancel"
+)
+
+var (
+	// APIKeyScopes lists every scope an API key can be given
+	APIKeyScopes = []string{
+		ScopeLoansRead,
+		ScopeLoansCreate,
+		ScopeLoansApprove,
+		ScopeLoansInvest,
+		ScopeLoansDisburse,
+		ScopeLoansReject,
+		ScopeLoansCancel,
+	}
+)
@@ -0,0 +1,60 @@
+package constant
+
+import "fmt"
+
+type (
+	// Kind groups the domain errors by how a client should react to them,
+	// the HTTP layer answers every kind with its own status code.
+	Kind int
+
+	// Error is a domain error with a stable machine-readable Code, clients
+	// match on the code as the message may be reworded.
+	Error struct {
+		Kind    Kind
+		Code    string
+		Message string
+	}
+)
+
+const (
+	KindInternal Kind = iota
+	KindInvalid
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindUnprocessable
+	KindTooManyRequests
+)
+
+// CodeInternal is the code of every error that is not a domain error.
+const CodeInternal = "internal_error"
+
+// domainErrors lists every error made with newError, see Errors.
+var domainErrors []*Error
+
+func newError(kind Kind, code, message string) *Error {
+	err := &Error{
+		Kind:    kind,
+		Code:    code,
+		Message: message,
+	}
+	domainErrors = append(domainErrors, err)
+	return err
+}
+
+// Errors returns every domain error, the message catalogue has a
+// translation for each code.
+func Errors() []*Error {
+	return domainErrors
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// InvalidRequest marks the error of a request that cannot be read, e.g. a
+// malformed body or path parameter.
+func InvalidRequest(err error) error {
+	return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+}
@@ -1,16 +1,87 @@
 package constant
 
-import "errors"
-
 var (
-	ErrRegister      = errors.New("password or username is required")
-	ErrUserNotFound  = errors.New("user not found")
-	ErrPriceIsMinus  = errors.New("not allowed negative price")
-	ErrProductName   = errors.New("product name can't empty")
-	DuplicateEmail   = errors.New("duplicate email")
-	LoanNotFound     = errors.New("loan not found")
-	ErrStateApprove  = errors.New("only loans in 'proposed' state can be approved")
-	ErrStateDisburse = errors.New("only loans in 'Invested' state can be disburse")
-	ErrStateInvest   = errors.New("loan is not in 'approved' state'")
-	ErrInvestAmount  = errors.New("investment amount exceeds the principal amount")
+	ErrInvalidRequest = newError(KindInvalid, "request_invalid", "invalid request")
+	ErrInvalidToken   = newError(KindUnauthorized, "token_invalid", "invalid token")
+
+	ErrRegister      = newError(KindInvalid, "register_invalid", "password or username is required")
+	ErrUserNotFound  = newError(KindNotFound, "user_not_found", "user not found")
+	ErrPriceIsMinus  = newError(KindInvalid, "price_negative", "not allowed negative price")
+	ErrProductName   = newError(KindInvalid, "product_name_required", "product name can't empty")
+	DuplicateEmail   = newError(KindConflict, "email_duplicate", "duplicate email")
+	ErrRole          = newError(KindInvalid, "role_invalid", "invalid user role")
+	ErrRegisterRole  = newError(KindInvalid, "register_role_invalid", "only BORROWER or INVESTOR can register, other roles are given by an admin")
+	ErrRoleUnchanged = newError(KindInvalid, "role_unchanged", "user already has this role")
+	ErrRoleSelf      = newError(KindForbidden, "role_self_change", "admins cannot change their own role")
+	ErrLocale        = newError(KindInvalid, "locale_unsupported", "unsupported locale")
+
+	ErrPermission        = newError(KindInvalid, "permission_invalid", "invalid permission")
+	ErrPermissionDenied  = newError(KindForbidden, "permission_denied", "role is not allowed to perform this action")
+	ErrPermissionLockout = newError(KindInvalid, "permission_lockout", "ADMIN must keep the user.manage permission")
+
+	ErrLogin              = newError(KindUnauthorized, "login_invalid", "invalid email or password")
+	ErrLoginLocked        = newError(KindTooManyRequests, "login_locked", "too many failed logins, try again later")
+	ErrRefreshToken       = newError(KindUnauthorized, "refresh_token_invalid", "refresh token is invalid or expired")
+	ErrRefreshTokenReused = newError(KindUnauthorized, "refresh_token_reused", "refresh token was already used, the session has been revoked")
+	ErrSessionNotFound    = newError(KindNotFound, "session_not_found", "session not found")
+	ErrTokenRevoked       = newError(KindUnauthorized, "token_revoked", "token has been revoked")
+	ErrUserToken          = newError(KindInvalid, "user_token_invalid", "token is invalid or expired")
+	ErrPassword           = newError(KindInvalid, "password_required", "password is required")
+	ErrEmailVerified      = newError(KindConflict, "email_already_verified", "email is already verified")
+	ErrEmailNotVerified   = newError(KindForbidden, "email_not_verified", "verify your email before creating or funding a loan")
+	ErrTOTPEnabled        = newError(KindConflict, "totp_enabled", "two-factor authentication is already enabled")
+	ErrTOTPNotEnrolled    = newError(KindInvalid, "totp_not_enrolled", "start the two-factor enrolment first")
+	ErrTOTPNotEnabled     = newError(KindInvalid, "totp_not_enabled", "two-factor authentication is not enabled")
+	ErrTOTPCode           = newError(KindInvalid, "totp_code_invalid", "invalid two-factor code")
+	ErrMFAChallenge       = newError(KindUnauthorized, "mfa_challenge_invalid", "two-factor challenge is invalid or expired")
+	ErrMFARequired        = newError(KindForbidden, "mfa_required", "two-factor authentication is required for your role")
+	ErrAPIKey             = newError(KindUnauthorized, "api_key_invalid", "API key is invalid, expired or revoked")
+	ErrAPIKeyScope        = newError(KindForbidden, "api_key_scope_denied", "API key is not allowed to make this request")
+	ErrAPIKeyScopes       = newError(KindInvalid, "api_key_scopes_invalid", "API key needs a name and at least one valid scope")
+	ErrAPIKeyExpiry       = newError(KindInvalid, "api_key_expiry_invalid", "API key expiry must be in the future")
+	ErrAPIKeyNotFound     = newError(KindNotFound, "api_key_not_found", "API key not found")
+
+	LoanNotFound     = newError(KindNotFound, "loan_not_found", "loan not found")
+	ErrStateApprove  = newError(KindConflict, "loan_state_approve", "only loans in 'proposed' state can be approved")
+	ErrStateDisburse = newError(KindConflict, "loan_state_disburse", "only loans in 'Invested' state can be disburse")
+	ErrStateInvest   = newError(KindConflict, "loan_state_invest", "loan is not in 'approved' state'")
+	ErrInvestAmount  = newError(KindUnprocessable, "invest_amount_exceeded", "investment amount exceeds the principal amount")
+	ErrCurrency      = newError(KindInvalid, "currency_unsupported", "unsupported loan currency")
+
+	ErrStateRepay       = newError(KindConflict, "loan_state_repay", "only loans in 'disbursed' or 'repaying' state can be repaid")
+	ErrRepaymentAmount  = newError(KindUnprocessable, "repayment_amount_invalid", "repayment amount must be greater than zero and not exceed the outstanding balance")
+	ErrScheduleNotFound = newError(KindNotFound, "schedule_not_found", "repayment schedule not found")
+
+	ErrUnknownTransition    = newError(KindInternal, "loan_transition_unknown", "unknown loan state transition")
+	ErrTransitionNotAllowed = newError(KindConflict, "loan_transition_not_allowed", "loan state transition is not allowed")
+	ErrTransitionForbidden  = newError(KindForbidden, "loan_transition_forbidden", "role is not allowed to perform this loan action")
+
+	ErrStateReject  = newError(KindConflict, "loan_state_reject", "only loans in 'proposed' state can be rejected")
+	ErrStateCancel  = newError(KindConflict, "loan_state_cancel", "only loans in 'proposed' or 'approved' state can be cancelled")
+	ErrLoanNotOwned = newError(KindForbidden, "loan_not_owned", "loan does not belong to the current user")
+	ErrReasonCode   = newError(KindInvalid, "reason_code_invalid", "invalid reason code")
+
+	ErrLoanForbidden = newError(KindForbidden, "loan_forbidden", "role is not allowed to view loans")
+
+	ErrAgreementInvestors = newError(KindUnprocessable, "agreement_investors_missing", "agreement letter needs at least one active investment")
+
+	ErrNotificationTemplate = newError(KindInternal, "notification_template_not_found", "notification template not found")
+
+	ErrLoanConflict = newError(KindConflict, "loan_conflict", "loan was changed by another request, please try again")
+
+	ErrIdempotencyKeyReused  = newError(KindUnprocessable, "idempotency_key_reused", "Idempotency-Key was already used with a different request")
+	ErrIdempotencyInProgress = newError(KindConflict, "idempotency_in_progress", "a request with this Idempotency-Key is still being processed")
+
+	ErrInsufficientBalance = newError(KindUnprocessable, "balance_insufficient", "insufficient wallet balance")
+	ErrUnbalancedEntry     = newError(KindInternal, "journal_entry_unbalanced", "journal entry debits and credits are not balanced")
+	ErrLedgerAmount        = newError(KindInvalid, "ledger_amount_invalid", "amount must be greater than zero")
+	ErrLedgerCurrency      = newError(KindUnprocessable, "ledger_currency_mismatch", "money cannot move between accounts of different currencies")
+	ErrAccountNotFound     = newError(KindNotFound, "account_not_found", "ledger account not found")
+	ErrAccountNotOwned     = newError(KindForbidden, "account_not_owned", "ledger account does not belong to the current user")
+
+	ErrTopUpNotFound   = newError(KindNotFound, "top_up_not_found", "top-up not found")
+	ErrTopUpReference  = newError(KindConflict, "top_up_reference_used", "payment reference is already used by another top-up")
+	ErrTopUpReviewed   = newError(KindConflict, "top_up_reviewed", "top-up is already confirmed or rejected")
+	ErrTopUpMismatch   = newError(KindUnprocessable, "top_up_payment_mismatch", "payment reference or amount does not match the top-up")
+	ErrTopUpSelfReview = newError(KindForbidden, "top_up_self_review", "a top-up cannot be confirmed or rejected by the user who asked for it")
 )
@@ -0,0 +1,20 @@
+package constant
+
+import "time"
+
+const (
+	// IdempotencyHeader is the request header carrying the idempotency key
+	IdempotencyHeader = "Idempotency-Key"
+	// IdempotencyReplayedHeader is set on a response replayed from a stored key
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+	// Idempotency key status
+	IdempotencyProcessing = "processing"
+	IdempotencyCompleted  = "completed"
+
+	// IdempotencyKeyTTL is how long a stored response is replayed
+	IdempotencyKeyTTL = 24 * time.Hour
+	// IdempotencyProcessingLease is how long a key is held for a request
+	// still running, a key left by a server that stopped is freed after it
+	IdempotencyProcessingLease = time.Minute
+)
@@ -0,0 +1,23 @@
+package constant
+
+const (
+	// Ledger account types. Asset accounts grow with a debit, the others
+	// grow with a credit.
+	LedgerAccountCash           = "platform_cash"   // asset, money held by the platform
+	LedgerAccountFee            = "platform_fee"    // income, fee taken from repayments
+	LedgerAccountWallet         = "investor_wallet" // liability, investor money not invested yet
+	LedgerAccountLoanEscrow     = "loan_escrow"     // liability, investor money committed to a loan
+	LedgerAccountLoanReceivable = "loan_receivable" // asset, what the borrower still owes
+
+	// Journal entry types
+	JournalTopUp        = "top_up"
+	JournalInvestment   = "investment"
+	JournalRelease      = "release"
+	JournalDisbursement = "disbursement"
+	JournalRepayment    = "repayment"
+
+	// Top-up status
+	TopUpPending   = "pending"
+	TopUpConfirmed = "confirmed"
+	TopUpRejected  = "rejected"
+)
@@ -0,0 +1,35 @@
+package constant
+
+const (
+	// Reject reason codes, used by staff when rejecting a proposed loan
+	RejectIncompleteDocuments = "INCOMPLETE_DOCUMENTS"
+	RejectCreditRisk          = "CREDIT_RISK"
+	RejectFraudSuspected      = "FRAUD_SUSPECTED"
+	RejectPolicyViolation     = "POLICY_VIOLATION"
+	RejectOther               = "OTHER"
+
+	// Cancel reason codes, used by borrower when withdrawing a loan request
+	CancelNoLongerNeeded   = "NO_LONGER_NEEDED"
+	CancelFoundAlternative = "FOUND_ALTERNATIVE"
+	CancelTermsNotSuitable = "TERMS_NOT_SUITABLE"
+	CancelFundingTooSlow   = "FUNDING_TOO_SLOW"
+	CancelOther            = "OTHER"
+)
+
+var (
+	RejectReasonCodes = []string{
+		RejectIncompleteDocuments,
+		RejectCreditRisk,
+		RejectFraudSuspected,
+		RejectPolicyViolation,
+		RejectOther,
+	}
+
+	CancelReasonCodes = []string{
+		CancelNoLongerNeeded,
+		CancelFoundAlternative,
+		CancelTermsNotSuitable,
+		CancelFundingTooSlow,
+		CancelOther,
+	}
+)
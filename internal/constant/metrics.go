@@ -0,0 +1,9 @@
+package constant
+
+import "time"
+
+const (
+	// MetricsQueryTimeout bounds the database queries refreshing the loan
+	// metrics
+	MetricsQueryTimeout = 5 * time.Second
+)
@@ -0,0 +1,33 @@
+package constant
+
+import "time"
+
+const (
+	// Notification channel
+	ChannelEmail = "email"
+
+	// Notification status
+	NotificationPending = "pending"
+	NotificationSending = "sending"
+	NotificationSent    = "sent"
+	NotificationFailed  = "failed"
+
+	// Notification templates
+	TemplateLoanFunded        = "loan_funded"
+	TemplatePasswordReset     = "password_reset"
+	TemplateEmailVerification = "email_verification"
+
+	// NotificationMaxAttempts is how many times a notification is sent
+	// before it is marked as failed
+	NotificationMaxAttempts = 5
+	// NotificationBackoff is the wait before the first retry, doubled on
+	// every next attempt
+	NotificationBackoff = time.Minute
+	// NotificationLease is how long a dispatcher owns the notifications it
+	// claimed, they are claimed again by any dispatcher after that
+	NotificationLease = 5 * time.Minute
+	// NotificationRetention is how long the sent and failed notifications
+	// are kept, they are looked for every NotificationPurgeInterval
+	NotificationRetention     = 30 * 24 * time.Hour
+	NotificationPurgeInterval = time.Hour
+)
@@ -0,0 +1,47 @@
+package constant
+
+import "time"
+
+const (
+	// Permissions checked by the routes, the roles holding them are stored
+	// in role_permissions and edited by admins
+	PermLoanRead           = "loan.read"
+	PermLoanReadAll        = "loan.read_all"
+	PermLoanCreate         = "loan.create"
+	PermLoanApprove        = "loan.approve"
+	PermLoanReject         = "loan.reject"
+	PermLoanDisburse       = "loan.disburse"
+	PermLoanCancel         = "loan.cancel"
+	PermLoanRepay          = "loan.repay"
+	PermInvestmentCreate   = "investment.create"
+	PermLedgerRead         = "ledger.read"
+	PermLedgerReadAll      = "ledger.read_all"
+	PermLedgerTopUpRequest = "ledger.top_up_request"
+	PermLedgerTopUp        = "ledger.top_up"
+	PermUserManage         = "user.manage"
+
+	// PermissionCacheTTL is how long an instance keeps the role permissions
+	// before reading them again, an edit on another instance is seen after
+	// at most this long
+	PermissionCacheTTL = time.Minute
+)
+
+var (
+	// Permissions lists every permission a role can be given
+	Permissions = []string{
+		PermLoanRead,
+		PermLoanReadAll,
+		PermLoanCreate,
+		PermLoanApprove,
+		PermLoanReject,
+		PermLoanDisburse,
+		PermLoanCancel,
+		PermLoanRepay,
+		PermInvestmentCreate,
+		PermLedgerRead,
+		PermLedgerReadAll,
+		PermLedgerTopUpRequest,
+		PermLedgerTopUp,
+		PermUserManage,
+	}
+)
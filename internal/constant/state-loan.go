@@ -5,4 +5,39 @@ const (
 	Approved  = "approved"
 	Invested  = "invested"
 	Disbursed = "disbursed"
+	Repaying  = "repaying"
+	PaidOff   = "paid_off"
+	Rejected  = "rejected"
+	Cancelled = "cancelled"
+)
+
+const (
+	// Loan events, used as transition names in the loan state machine
+	EventPropose  = "propose"
+	EventApprove  = "approve"
+	EventFund     = "fund"
+	EventInvest   = "invest"
+	EventDisburse = "disburse"
+	EventRepay    = "repay"
+	EventPayOff   = "pay_off"
+	EventReject   = "reject"
+	EventCancel   = "cancel"
+)
+
+const (
+	// Installment status
+	InstallmentPending = "pending"
+	InstallmentPartial = "partial"
+	InstallmentPaid    = "paid"
+
+	// Investment status
+	InvestmentActive   = "active"
+	InvestmentReleased = "released"
+
+	// DefaultTenor is used when a loan is proposed without tenor (in months)
+	DefaultTenor = 12
+
+	// InvestMaxAttempts is how many times an investment is tried when the
+	// loan is changed by another request at the same time
+	InvestMaxAttempts = 3
 )
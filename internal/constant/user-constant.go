@@ -1,5 +1,7 @@
 package constant
 
+import "time"
+
 const (
 	// Roles
 	RoleAdmin    = "ADMIN"    // Untuk pengguna dengan akses penuh (web admin)
@@ -13,3 +15,59 @@ const (
 	RoleBorrowerDesc = "User applying for loans"
 	RoleInvestorDesc = "User investing in loans"
 )
+
+var (
+	// Roles lists every valid user role
+	Roles = []string{RoleAdmin, RoleStaff, RoleBorrower, RoleInvestor}
+	// PublicRoles can be chosen at self registration, STAFF and ADMIN are
+	// only given by an admin
+	PublicRoles = []string{RoleBorrower, RoleInvestor}
+)
+
+const (
+	// AccessTokenTTL is how long an access token is valid, a revoked token
+	// is kept on the denylist until it would have expired
+	AccessTokenTTL = 24 * time.Hour
+	// RefreshTokenTTL is how long a refresh token can be used, every refresh
+	// extends the session by the same duration
+	RefreshTokenTTL = 7 * 24 * time.Hour
+
+	// User token purposes
+	TokenPasswordReset     = "password_reset"
+	TokenEmailVerification = "email_verification"
+	TokenMFAChallenge      = "mfa_challenge"
+
+	// PasswordResetTTL is how long a password reset link can be used
+	PasswordResetTTL = time.Hour
+	// EmailVerificationTTL is how long an email verification link can be used
+	EmailVerificationTTL = 48 * time.Hour
+
+	// MFAChallengeTTL is how long the second login step can be completed
+	MFAChallengeTTL = 5 * time.Minute
+	// MFAMaxAttempts is how many wrong codes a login challenge accepts
+	MFAMaxAttempts = 5
+	// RecoveryCodeCount is how many recovery codes are given at enrolment
+	RecoveryCodeCount = 10
+	// TOTPIssuer is the account issuer shown in authenticator apps
+	TOTPIssuer = "LoanFlow"
+
+	// Login throttle scopes
+	ThrottleAccount = "account"
+	ThrottleIP      = "ip"
+
+	// LoginAccountFreeAttempts is how many failed logins an account gets
+	// before it is locked, LoginIPFreeAttempts the same for an IP address
+	LoginAccountFreeAttempts = 5
+	LoginIPFreeAttempts      = 20
+	// LoginLockBase is the first lock, it doubles with every further failure
+	// up to LoginLockMax
+	LoginLockBase = time.Minute
+	LoginLockMax  = time.Hour
+	// LoginFailureWindow resets the count when the last failure is older
+	LoginFailureWindow = 24 * time.Hour
+
+	// Auth audit events
+	AuthEventLoginFailed   = "login_failed"
+	AuthEventLoginLocked   = "login_locked"
+	AuthEventLoginUnlocked = "login_unlocked"
+)
@@ -0,0 +1,22 @@
+package domians
+
+import "time"
+
+type (
+	// IdempotencyKey stores the response of a state-changing request so a
+	// retry with the same Idempotency-Key header gets the same response.
+	IdempotencyKey struct {
+		ID           uint      `gorm:"primaryKey;column:id" json:"id"`
+		UserID       uint      `gorm:"column:user_id" json:"user_id"`
+		Key          string    `gorm:"size:255;column:idempotency_key" json:"idempotency_key"`
+		Method       string    `gorm:"size:10;column:method" json:"method"`
+		Path         string    `gorm:"column:path" json:"path"`
+		Fingerprint  string    `gorm:"size:64;column:fingerprint" json:"fingerprint"`
+		Status       string    `gorm:"size:20;column:status" json:"status"` // "processing","completed"
+		ResponseCode int       `gorm:"column:response_code" json:"response_code"`
+		ResponseBody string    `gorm:"column:response_body" json:"response_body"`
+		ExpiresAt    time.Time `gorm:"column:expires_at" json:"expires_at"`
+		CreatedAt    time.Time `gorm:"column:created_at" json:"created_at"`
+		UpdatedAt    time.Time `gorm:"column:updated_at" json:"updated_at"`
+	}
+)
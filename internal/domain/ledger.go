@@ -0,0 +1,93 @@
+package domians
+
+import (
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"gorm.io/gorm"
+	"time"
+)
+
+type (
+	// LedgerAccount holds money of a user, a loan or the platform in a
+	// single currency. Balance is kept on the account's normal side, see
+	// constant.LedgerAccount*.
+	LedgerAccount struct {
+		ID        uint           `gorm:"primaryKey;column:id" json:"id"`
+		Code      string         `gorm:"size:100;unique;column:code" json:"code"` // e.g. "investor_wallet:7", "loan_escrow:3"
+		Type      string         `gorm:"size:30;column:type" json:"type"`
+		UserID    *uint          `gorm:"column:user_id" json:"user_id,omitempty"`
+		LoanID    *uint          `gorm:"column:loan_id" json:"loan_id,omitempty"`
+		Currency  money.Currency `gorm:"size:3;column:currency" json:"currency"`
+		Balance   money.Money    `gorm:"column:balance" json:"balance"`
+		CreatedAt time.Time      `gorm:"column:created_at" json:"created_at"`
+		UpdatedAt time.Time      `gorm:"column:updated_at" json:"updated_at"`
+	}
+
+	// JournalEntry is one balanced money movement, the sum of the debits
+	// of its lines equals the sum of the credits.
+	JournalEntry struct {
+		ID          uint           `gorm:"primaryKey;column:id" json:"id"`
+		Type        string         `gorm:"size:30;column:type" json:"type"` // "top_up","investment","release","disbursement","repayment"
+		Currency    money.Currency `gorm:"size:3;column:currency" json:"currency"`
+		Reference   string         `gorm:"size:100;column:reference" json:"reference"`
+		Description string         `gorm:"column:description" json:"description"`
+		CreatedBy   uint           `gorm:"column:created_by" json:"created_by"`
+		CreatedAt   time.Time      `gorm:"column:created_at" json:"created_at"`
+
+		Lines []JournalLine `gorm:"foreignKey:JournalEntryID" json:"lines,omitempty"`
+	}
+
+	JournalLine struct {
+		ID             uint        `gorm:"primaryKey;column:id" json:"id"`
+		JournalEntryID uint        `gorm:"column:journal_entry_id" json:"journal_entry_id"`
+		AccountID      uint        `gorm:"column:account_id" json:"account_id"`
+		Debit          money.Money `gorm:"column:debit" json:"debit"`
+		Credit         money.Money `gorm:"column:credit" json:"credit"`
+		BalanceAfter   money.Money `gorm:"column:balance_after" json:"balance_after"`
+		CreatedAt      time.Time   `gorm:"column:created_at" json:"created_at"`
+
+		// Relation back to JournalEntry and LedgerAccount
+		JournalEntry *JournalEntry  `gorm:"foreignKey:JournalEntryID" json:"journal_entry,omitempty"`
+		Account      *LedgerAccount `gorm:"foreignKey:AccountID" json:"account,omitempty"`
+	}
+
+	// TopUp is money an investor paid to the platform, the wallet is only
+	// credited once staff matched it with the payment received.
+	TopUp struct {
+		ID               uint           `gorm:"primaryKey;column:id" json:"id"`
+		UserID           uint           `gorm:"column:user_id" json:"user_id"`
+		Currency         money.Currency `gorm:"size:3;column:currency" json:"currency"`
+		Amount           money.Money    `gorm:"column:amount" json:"amount"`
+		PaymentReference string         `gorm:"size:100;unique;column:payment_reference" json:"payment_reference"`
+		Status           string         `gorm:"size:20;column:status" json:"status"` // "pending","confirmed","rejected"
+		JournalEntryID   *uint          `gorm:"column:journal_entry_id" json:"journal_entry_id,omitempty"`
+		ReviewedBy       *uint          `gorm:"column:reviewed_by" json:"reviewed_by,omitempty"`
+		ReviewedAt       *time.Time     `gorm:"column:reviewed_at" json:"reviewed_at,omitempty"`
+		Remarks          string         `gorm:"column:remarks" json:"remarks,omitempty"`
+		CreatedAt        time.Time      `gorm:"column:created_at" json:"created_at"`
+		UpdatedAt        time.Time      `gorm:"column:updated_at" json:"updated_at"`
+	}
+)
+
+// AfterFind puts the balance in the currency of the account.
+func (a *LedgerAccount) AfterFind(*gorm.DB) error {
+	a.Balance = a.Balance.In(a.Currency)
+	return nil
+}
+
+// AfterFind puts the amounts of the lines loaded with the entry in its
+// currency.
+func (e *JournalEntry) AfterFind(*gorm.DB) error {
+	for i := range e.Lines {
+		line := &e.Lines[i]
+		line.Debit = line.Debit.In(e.Currency)
+		line.Credit = line.Credit.In(e.Currency)
+		line.BalanceAfter = line.BalanceAfter.In(e.Currency)
+	}
+	return nil
+}
+
+// AfterFind puts the amount in the currency of the top-up.
+func (t *TopUp) AfterFind(*gorm.DB) error {
+	t.Amount = t.Amount.In(t.Currency)
+	return nil
+}
@@ -1,18 +1,26 @@
 package domians
 
-import "time"
+import (
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"gorm.io/gorm"
+	"time"
+)
 
 type (
 	Loan struct {
-		ID                  uint      `gorm:"primaryKey;column:id" json:"id"`
-		BorrowerID          uint      `gorm:"column:borrower_id" json:"borrower_id"`
-		PrincipalAmount     float64   `gorm:"column:principal_amount" json:"principal_amount"`
-		Rate                float64   `gorm:"column:rate" json:"rate"`
-		ROI                 float64   `gorm:"column:roi" json:"roi"`
-		State               string    `gorm:"size:20;column:state" json:"state"` // "proposed","approved","invested","disbursed"
-		AgreementLetterLink string    `gorm:"column:agreement_letter_link" json:"agreement_letter_link,omitempty"`
-		CreatedAt           time.Time `gorm:"column:created_at" json:"created_at"`
-		UpdatedAt           time.Time `gorm:"column:updated_at" json:"updated_at"`
+		ID                  uint           `gorm:"primaryKey;column:id" json:"id"`
+		BorrowerID          uint           `gorm:"column:borrower_id" json:"borrower_id"`
+		PrincipalAmount     money.Money    `gorm:"column:principal_amount" json:"principal_amount"`
+		FundedAmount        money.Money    `gorm:"column:funded_amount" json:"funded_amount"`
+		Rate                money.Rate     `gorm:"column:rate" json:"rate"`
+		ROI                 money.Money    `gorm:"column:roi" json:"roi"`
+		Currency            money.Currency `gorm:"size:3;column:currency" json:"currency"`
+		Tenor               uint           `gorm:"column:tenor" json:"tenor"`
+		State               string         `gorm:"size:20;column:state" json:"state"` // "proposed","approved","invested","disbursed","repaying","paid_off","rejected","cancelled"
+		AgreementLetterLink string         `gorm:"column:agreement_letter_link" json:"agreement_letter_link,omitempty"`
+		Version             uint           `gorm:"column:version" json:"version"` // bumped on every state change
+		CreatedAt           time.Time      `gorm:"column:created_at" json:"created_at"`
+		UpdatedAt           time.Time      `gorm:"column:updated_at" json:"updated_at"`
 
 		// Relation back to user (borrower)
 		Borrower User `gorm:"foreignKey:BorrowerID" json:"borrower,omitempty"`
@@ -20,6 +28,7 @@ type (
 		LoanApprovalDetails []LoanApprovalDetail     `gorm:"foreignKey:LoanID" json:"loan_approval_details,omitempty"`
 		LoanInvestors       []LoanInvestor           `gorm:"foreignKey:LoanID" json:"loan_investors,omitempty"`
 		LoanDisbursements   []LoanDisbursementDetail `gorm:"foreignKey:LoanID" json:"loan_disbursement_details,omitempty"`
+		LoanInstallments    []LoanInstallment        `gorm:"foreignKey:LoanID" json:"loan_installments,omitempty"`
 	}
 
 	LoanApprovalDetail struct {
@@ -38,12 +47,15 @@ type (
 	}
 
 	LoanInvestor struct {
-		ID             uint      `gorm:"primaryKey;column:id" json:"id"`
-		LoanID         uint      `gorm:"column:loan_id" json:"loan_id"`
-		InvestorID     uint      `gorm:"column:investor_id" json:"investor_id"`
-		AmountInvested float64   `gorm:"column:amount_invested" json:"amount_invested"`
-		CreatedAt      time.Time `gorm:"column:created_at" json:"created_at"`
-		UpdatedAt      time.Time `gorm:"column:updated_at" json:"updated_at"`
+		ID                  uint        `gorm:"primaryKey;column:id" json:"id"`
+		LoanID              uint        `gorm:"column:loan_id" json:"loan_id"`
+		InvestorID          uint        `gorm:"column:investor_id" json:"investor_id"`
+		AmountInvested      money.Money `gorm:"column:amount_invested" json:"amount_invested"`
+		Status              string      `gorm:"size:20;column:status" json:"status"` // "active","released"
+		AgreementLetterLink string      `gorm:"column:agreement_letter_link" json:"agreement_letter_link,omitempty"`
+		ReleasedAt          *time.Time  `gorm:"column:released_at" json:"released_at,omitempty"`
+		CreatedAt           time.Time   `gorm:"column:created_at" json:"created_at"`
+		UpdatedAt           time.Time   `gorm:"column:updated_at" json:"updated_at"`
 
 		// Relation back to Loan
 		Loan Loan `gorm:"foreignKey:LoanID" json:"loan,omitempty"`
@@ -71,8 +83,28 @@ type (
 		LoanID        uint      `gorm:"column:loan_id" json:"loan_id"`
 		PreviousState string    `gorm:"column:previous_state" json:"previous_state"`
 		NewState      string    `gorm:"column:new_state" json:"new_state"`
+		ReasonCode    string    `gorm:"column:reason_code" json:"reason_code,omitempty"`
 		ActionBy      uint      `gorm:"column:action_by" json:"action_by"`
 		ActionAt      time.Time `gorm:"autoCreateTime;column:action_at" json:"action_at"`
 		Remarks       string    `gorm:"column:remarks" json:"remarks"`
 	}
 )
+
+// AfterFind puts the amounts of the loan, and of the investments and
+// installments loaded with it, in the currency of the loan.
+func (l *Loan) AfterFind(*gorm.DB) error {
+	l.PrincipalAmount = l.PrincipalAmount.In(l.Currency)
+	l.FundedAmount = l.FundedAmount.In(l.Currency)
+	l.ROI = l.ROI.In(l.Currency)
+	for i := range l.LoanInvestors {
+		l.LoanInvestors[i].AmountInvested = l.LoanInvestors[i].AmountInvested.In(l.Currency)
+	}
+	for i := range l.LoanInstallments {
+		installment := &l.LoanInstallments[i]
+		installment.PrincipalDue = installment.PrincipalDue.In(l.Currency)
+		installment.InterestDue = installment.InterestDue.In(l.Currency)
+		installment.AmountDue = installment.AmountDue.In(l.Currency)
+		installment.AmountPaid = installment.AmountPaid.In(l.Currency)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package domians
+
+import "time"
+
+type (
+	// Notification is a single message to a single recipient, it is stored
+	// before being sent so every delivery attempt can be tracked. The body
+	// can carry a single-use link, it is cleared once the notification is
+	// sent or given up.
+	Notification struct {
+		ID            uint       `gorm:"primaryKey;column:id" json:"id"`
+		UserID        uint       `gorm:"column:user_id" json:"user_id"`
+		Channel       string     `gorm:"size:20;column:channel" json:"channel"` // "email"
+		Recipient     string     `gorm:"column:recipient" json:"recipient"`
+		Template      string     `gorm:"size:50;column:template" json:"template"`
+		Subject       string     `gorm:"column:subject" json:"subject"`
+		Body          string     `gorm:"column:body" json:"body"`
+		Status        string     `gorm:"size:20;column:status" json:"status"` // "pending","sending","sent","failed"
+		Attempts      uint       `gorm:"column:attempts" json:"attempts"`
+		LastError     string     `gorm:"column:last_error" json:"last_error,omitempty"`
+		NextAttemptAt time.Time  `gorm:"column:next_attempt_at" json:"next_attempt_at"`
+		SentAt        *time.Time `gorm:"column:sent_at" json:"sent_at,omitempty"`
+		CreatedAt     time.Time  `gorm:"column:created_at" json:"created_at"`
+		UpdatedAt     time.Time  `gorm:"column:updated_at" json:"updated_at"`
+
+		// Relation back to User
+		User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	}
+)
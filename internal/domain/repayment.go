@@ -0,0 +1,42 @@
+package domians
+
+import (
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"time"
+)
+
+type (
+	LoanInstallment struct {
+		ID           uint        `gorm:"primaryKey;column:id" json:"id"`
+		LoanID       uint        `gorm:"column:loan_id" json:"loan_id"`
+		Sequence     uint        `gorm:"column:sequence" json:"sequence"`
+		DueDate      time.Time   `gorm:"column:due_date" json:"due_date"`
+		PrincipalDue money.Money `gorm:"column:principal_due" json:"principal_due"`
+		InterestDue  money.Money `gorm:"column:interest_due" json:"interest_due"`
+		AmountDue    money.Money `gorm:"column:amount_due" json:"amount_due"`
+		AmountPaid   money.Money `gorm:"column:amount_paid" json:"amount_paid"`
+		Status       string      `gorm:"size:20;column:status" json:"status"` // "pending","partial","paid"
+		PaidAt       *time.Time  `gorm:"column:paid_at" json:"paid_at,omitempty"`
+		CreatedAt    time.Time   `gorm:"column:created_at" json:"created_at"`
+		UpdatedAt    time.Time   `gorm:"column:updated_at" json:"updated_at"`
+
+		// Relation back to Loan
+		Loan Loan `gorm:"foreignKey:LoanID" json:"loan,omitempty"`
+	}
+
+	LoanRepayment struct {
+		ID        uint        `gorm:"primaryKey;column:id" json:"id"`
+		LoanID    uint        `gorm:"column:loan_id" json:"loan_id"`
+		PaidBy    uint        `gorm:"column:paid_by" json:"paid_by"`
+		Amount    money.Money `gorm:"column:amount" json:"amount"`
+		PaidAt    time.Time   `gorm:"column:paid_at" json:"paid_at"`
+		Remarks   string      `gorm:"column:remarks" json:"remarks,omitempty"`
+		CreatedAt time.Time   `gorm:"column:created_at" json:"created_at"`
+		UpdatedAt time.Time   `gorm:"column:updated_at" json:"updated_at"`
+
+		// Relation back to Loan
+		Loan Loan `gorm:"foreignKey:LoanID" json:"loan,omitempty"`
+		// Relation back to payer (User)
+		Payer User `gorm:"foreignKey:PaidBy" json:"payer,omitempty"`
+	}
+)
@@ -0,0 +1,22 @@
+package domians
+
+import "time"
+
+type (
+	// RevokedToken is an access token logged out before its expiry, it is
+	// kept until ExpiresAt when the token would be rejected anyway.
+	RevokedToken struct {
+		TokenID   string    `gorm:"primaryKey;size:64;column:token_id" json:"token_id"`
+		UserID    uint      `gorm:"column:user_id" json:"user_id"`
+		ExpiresAt time.Time `gorm:"column:expires_at" json:"expires_at"`
+		CreatedAt time.Time `gorm:"column:created_at" json:"created_at"`
+	}
+
+	// UserRevocation rejects every access token of the user issued before
+	// RevokedAt, e.g. after a role change.
+	UserRevocation struct {
+		UserID    uint      `gorm:"primaryKey;column:user_id" json:"user_id"`
+		RevokedAt time.Time `gorm:"column:revoked_at" json:"revoked_at"`
+		ExpiresAt time.Time `gorm:"column:expires_at" json:"expires_at"`
+	}
+)
@@ -10,9 +10,19 @@ type (
 		Role         string    `gorm:"size:50;column:role" json:"role"`
 		Name         string    `gorm:"size:100;column:name" json:"name,omitempty"`
 		Phone        string    `gorm:"size:20;column:phone" json:"phone,omitempty"`
+		Locale       string    `gorm:"size:5;column:locale" json:"locale,omitempty"` // bahasa pilihan, kosong mengikuti Accept-Language
 		CreatedAt    time.Time `gorm:"column:created_at" json:"created_at"`
 		UpdatedAt    time.Time `gorm:"column:updated_at" json:"updated_at"`
 
+		// Diisi saat user membuka link verifikasi email
+		EmailVerifiedAt *time.Time `gorm:"column:email_verified_at" json:"email_verified_at,omitempty"`
+
+		// Two-factor authentication (TOTP), secret terisi sejak enrolment dan
+		// TOTPEnabledAt terisi setelah kode pertama dikonfirmasi
+		TOTPSecret    string     `gorm:"size:64;column:totp_secret" json:"-"`
+		TOTPEnabledAt *time.Time `gorm:"column:totp_enabled_at" json:"totp_enabled_at,omitempty"`
+		TOTPLastStep  int64      `gorm:"column:totp_last_step" json:"-"` // step terakhir yang dipakai, mencegah replay
+
 		// Relation (optional):
 		// Satu user (role=BORROWER) bisa memiliki banyak loans
 		BorrowerLoans []Loan `gorm:"foreignKey:BorrowerID;references:ID" json:"borrower_loans,omitempty"`
@@ -27,15 +37,121 @@ type (
 		LoanInvestors []LoanInvestor `gorm:"foreignKey:InvestorID;references:ID" json:"loan_investors,omitempty"`
 	}
 
+	// Session is one login of a user on a device, its refresh tokens form a
+	// family: every refresh uses up the current token and issues the next.
+	Session struct {
+		ID         uint       `gorm:"primaryKey;column:id" json:"id"`
+		UserID     uint       `gorm:"column:user_id" json:"user_id"`
+		UserAgent  string     `gorm:"column:user_agent" json:"user_agent"`
+		IPAddress  string     `gorm:"size:45;column:ip_address" json:"ip_address"`
+		LastUsedAt time.Time  `gorm:"column:last_used_at" json:"last_used_at"`
+		ExpiresAt  time.Time  `gorm:"column:expires_at" json:"expires_at"`
+		RevokedAt  *time.Time `gorm:"column:revoked_at" json:"revoked_at,omitempty"`
+		MFA        bool       `gorm:"column:mfa" json:"mfa"` // login completed with a TOTP or recovery code
+		CreatedAt  time.Time  `gorm:"column:created_at" json:"created_at"`
+		UpdatedAt  time.Time  `gorm:"column:updated_at" json:"updated_at"`
+	}
+
 	RefreshToken struct {
-		ID           uint      `gorm:"primaryKey;column:id" json:"id"`                            // Primary key
-		UserID       uint      `gorm:"not null;column:user_id;index" json:"user_id"`              // FK to users.id
-		RefreshToken string    `gorm:"not null;unique;column:refresh_token" json:"refresh_token"` // Unique refresh token
-		ExpiresAt    time.Time `gorm:"not null;column:expires_at" json:"expires_at"`              // Expiry time of the token
-		CreatedAt    time.Time `gorm:"autoCreateTime;column:created_at" json:"created_at"`        // Time when the token was created
-		UpdatedAt    time.Time `gorm:"autoUpdateTime;column:updated_at" json:"updated_at"`        // Time when the token was last updated
+		ID        uint       `gorm:"primaryKey;column:id" json:"id"`                     // Primary key
+		UserID    uint       `gorm:"not null;column:user_id;index" json:"user_id"`       // FK to users.id
+		SessionID uint       `gorm:"not null;column:session_id;index" json:"session_id"` // FK to sessions.id
+		TokenHash string     `gorm:"not null;unique;size:64;column:token_hash" json:"-"` // sha256 of the token, the token itself is never stored
+		ExpiresAt time.Time  `gorm:"not null;column:expires_at" json:"expires_at"`       // Expiry time of the token
+		UsedAt    *time.Time `gorm:"column:used_at" json:"used_at,omitempty"`            // Set when the token was rotated
+		CreatedAt time.Time  `gorm:"autoCreateTime;column:created_at" json:"created_at"` // Time when the token was created
+		UpdatedAt time.Time  `gorm:"autoUpdateTime;column:updated_at" json:"updated_at"` // Time when the token was last updated
+
+		// Relation with the User and Session model
+		User    User     `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+		Session *Session `gorm:"foreignKey:SessionID;references:ID" json:"session,omitempty"`
+	}
+
+	// UserToken is a single-use token sent by email, to reset the password
+	// or to verify the email address. Only its hash is stored.
+	UserToken struct {
+		ID        uint       `gorm:"primaryKey;column:id" json:"id"`
+		UserID    uint       `gorm:"column:user_id" json:"user_id"`
+		Purpose   string     `gorm:"size:30;column:purpose" json:"purpose"`
+		TokenHash string     `gorm:"size:64;column:token_hash" json:"-"`
+		ExpiresAt time.Time  `gorm:"column:expires_at" json:"expires_at"`
+		UsedAt    *time.Time `gorm:"column:used_at" json:"used_at,omitempty"`
+		Attempts  uint       `gorm:"column:attempts" json:"attempts"`
+		CreatedAt time.Time  `gorm:"column:created_at" json:"created_at"`
+	}
+
+	// RecoveryCode can be used once instead of a TOTP code when the
+	// authenticator is lost.
+	RecoveryCode struct {
+		ID        uint       `gorm:"primaryKey;column:id" json:"id"`
+		UserID    uint       `gorm:"column:user_id" json:"user_id"`
+		CodeHash  string     `gorm:"size:64;column:code_hash" json:"-"`
+		UsedAt    *time.Time `gorm:"column:used_at" json:"used_at,omitempty"`
+		CreatedAt time.Time  `gorm:"column:created_at" json:"created_at"`
+	}
+
+	// RoleAudit records a role given to a user by an admin, PreviousRole is
+	// empty when the admin created the user.
+	RoleAudit struct {
+		ID           uint      `gorm:"primaryKey;column:id" json:"id"`
+		UserID       uint      `gorm:"column:user_id" json:"user_id"`
+		ActorID      uint      `gorm:"column:actor_id" json:"actor_id"`
+		PreviousRole string    `gorm:"size:50;column:previous_role" json:"previous_role"`
+		NewRole      string    `gorm:"size:50;column:new_role" json:"new_role"`
+		Reason       string    `gorm:"column:reason" json:"reason,omitempty"`
+		CreatedAt    time.Time `gorm:"column:created_at" json:"created_at"`
+	}
+
+	// RolePermission grants a permission to every user of the role.
+	RolePermission struct {
+		Role       string    `gorm:"primaryKey;size:50;column:role" json:"role"`
+		Permission string    `gorm:"primaryKey;size:50;column:permission" json:"permission"`
+		CreatedBy  *uint     `gorm:"column:created_by" json:"created_by,omitempty"`
+		CreatedAt  time.Time `gorm:"column:created_at" json:"created_at"`
+	}
+)
+
+type (
+	// LoginThrottle counts the failed logins of an account (by email) or of
+	// an IP address, while LockedUntil is in the future logins are refused.
+	LoginThrottle struct {
+		Scope        string     `gorm:"primaryKey;size:10;column:scope" json:"scope"` // "account","ip"
+		Key          string     `gorm:"primaryKey;size:255;column:throttle_key" json:"key"`
+		Failures     int        `gorm:"column:failures" json:"failures"`
+		LockedUntil  *time.Time `gorm:"column:locked_until" json:"locked_until,omitempty"`
+		LastFailedAt time.Time  `gorm:"column:last_failed_at" json:"last_failed_at"`
+	}
+
+	// AuthEvent is an audit record of the login security of an account,
+	// UserID is empty for a login with an unknown email.
+	AuthEvent struct {
+		ID        uint      `gorm:"primaryKey;column:id" json:"id"`
+		UserID    *uint     `gorm:"column:user_id" json:"user_id,omitempty"`
+		ActorID   *uint     `gorm:"column:actor_id" json:"actor_id,omitempty"` // admin who unlocked the account
+		Event     string    `gorm:"size:30;column:event" json:"event"`
+		Email     string    `gorm:"column:email" json:"email"`
+		IPAddress string    `gorm:"size:45;column:ip_address" json:"ip_address,omitempty"`
+		CreatedAt time.Time `gorm:"column:created_at" json:"created_at"`
+	}
+)
+
+type (
+	// APIKey lets a partner system call the API for the user that owns it,
+	// limited to its scopes. Only the hash of the key is stored.
+	APIKey struct {
+		ID         uint       `gorm:"primaryKey;column:id" json:"id"`
+		Name       string     `gorm:"column:name" json:"name"`
+		UserID     uint       `gorm:"column:user_id" json:"user_id"`
+		Prefix     string     `gorm:"size:16;column:prefix" json:"prefix"` // start of the key to tell keys apart
+		KeyHash    string     `gorm:"size:64;column:key_hash" json:"-"`
+		Scopes     []string   `gorm:"serializer:json;column:scopes" json:"scopes"`
+		CreatedBy  uint       `gorm:"column:created_by" json:"created_by"`
+		ExpiresAt  *time.Time `gorm:"column:expires_at" json:"expires_at,omitempty"`
+		LastUsedAt *time.Time `gorm:"column:last_used_at" json:"last_used_at,omitempty"`
+		RevokedAt  *time.Time `gorm:"column:revoked_at" json:"revoked_at,omitempty"`
+		CreatedAt  time.Time  `gorm:"column:created_at" json:"created_at"`
 
 		// Relation with the User model
-		User User `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+		User User `gorm:"foreignKey:UserID;references:ID" json:"-"`
 	}
 )
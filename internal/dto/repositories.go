@@ -5,3 +5,18 @@ type UserCheckPhoneAndEmail struct {
 	Email string `json:"email"`
 	Phone string `json:"phone"`
 }
+
+// LoanStateCount is the number of loans in State.
+type LoanStateCount struct {
+	State string
+	Count int64
+}
+
+// LoanStateDuration aggregates the time the loans spent in State before
+// moving on, Buckets counts the stays up to each upper bound in seconds.
+type LoanStateDuration struct {
+	State   string
+	Count   uint64
+	Sum     float64
+	Buckets map[float64]uint64
+}
@@ -6,6 +6,21 @@ type GetListQuery struct {
 	Search  string `json:"search"`
 }
 
+// LoanFilter narrows a loan list, the zero value lists every loan.
+type LoanFilter struct {
+	BorrowerID uint
+	// InvestorID lists the loans the investor invested in together with
+	// the loans in one of OpenStates
+	InvestorID uint
+	OpenStates []string
+}
+
+// TopUpFilter narrows a top-up list, the zero value lists every top-up.
+type TopUpFilter struct {
+	UserID uint
+	Status string
+}
+
 type Sorting struct {
-	CreatedAt string `json:"createdAt" validate:"enum=ASC DESC"`
+	CreatedAt string `json:"createdAt" validate:"omitempty,oneof=ASC DESC"`
 }
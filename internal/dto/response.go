@@ -1,5 +1,7 @@
 package dto
 
+import "sort"
+
 type ResponseMeta struct {
 	Success      bool   `json:"success"`
 	MessageTitle string `json:"messageTitle"`
@@ -9,8 +11,17 @@ type ResponseMeta struct {
 
 type ErrorResponse struct {
 	ResponseMeta
-	Data   any `json:"data"`
-	Errors any `json:"errors,omitempty"`
+	// Code identifies the error for clients, it does not change when the
+	// message is reworded
+	Code   string `json:"code,omitempty"`
+	Data   any    `json:"data"`
+	Errors any    `json:"errors,omitempty"`
+}
+
+func NewErrorResponse(code, msg string) *ErrorResponse {
+	response := DefaultErrorResponseWithMessage(msg)
+	response.Code = code
+	return response
 }
 
 func DefaultErrorResponse() *ErrorResponse {
@@ -59,11 +70,17 @@ type Response struct {
 	Data any `json:"data"`
 }
 
+// DefaultInvalidInputFormResponse answers the messages of every invalid
+// field, the message is the first one of the first field by name.
 func DefaultInvalidInputFormResponse(errs map[string][]string) *Response {
 	var msg string
-	for _, val := range errs {
-		msg = val[0]
-		break
+	fields := make([]string, 0, len(errs))
+	for field := range errs {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	if len(fields) > 0 && len(errs[fields[0]]) > 0 {
+		msg = errs[fields[0]][0]
 	}
 
 	return &Response{
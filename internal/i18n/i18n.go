@@ -0,0 +1,164 @@
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"github.com/bowoBp/LoanFlow/internal/dto"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+const (
+	EnUS = "en-US"
+	IdID = "id-ID"
+
+	// DefaultLocale answers the requests without a supported locale, its
+	// messages for the error codes are the messages of the constant errors
+	DefaultLocale = EnUS
+
+	// LocaleKey is the context key of the negotiated locale of a request
+	LocaleKey = "locale"
+
+	// Response titles
+	TitleSuccess      = "title.success"
+	TitleError        = "title.error"
+	TitleInvalidInput = "title.invalid_input"
+	TitleLogin        = "title.login"
+	TitleMFARequired  = "title.mfa_required"
+)
+
+// Locales lists the supported locales, every locale has the same keys.
+var Locales = []string{EnUS, IdID}
+
+// catalogue maps a locale to its messages by key. Error messages are keyed
+// by the error code, the other keys are namespaced e.g. "success.loan_created".
+var catalogue = mustLoad()
+
+func mustLoad() map[string]map[string]string {
+	messages := make(map[string]map[string]string, len(Locales))
+	for _, locale := range Locales {
+		raw, err := localeFiles.ReadFile(path.Join("locales", locale+".json"))
+		if err != nil {
+			panic(err)
+		}
+		var entries map[string]string
+		if err = json.Unmarshal(raw, &entries); err != nil {
+			panic(fmt.Sprintf("locale %s: %s", locale, err))
+		}
+		messages[locale] = entries
+	}
+	return messages
+}
+
+// Supported reports whether locale is one of Locales.
+func Supported(locale string) bool {
+	return slices.Contains(Locales, locale)
+}
+
+// Keys returns the sorted keys of the messages of locale.
+func Keys(locale string) []string {
+	keys := make([]string, 0, len(catalogue[locale]))
+	for key := range catalogue[locale] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Translate formats the message of key in locale with args. A key missing
+// from locale falls back to DefaultLocale and then to the key itself.
+func Translate(locale, key string, args ...any) string {
+	msg, ok := catalogue[locale][key]
+	if !ok {
+		msg, ok = catalogue[DefaultLocale][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// T translates key to the locale of ctx, see FromContext.
+func T(ctx context.Context, key string, args ...any) string {
+	return Translate(FromContext(ctx), key, args...)
+}
+
+// FromContext returns the locale stored under LocaleKey, DefaultLocale when
+// the request has none.
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return DefaultLocale
+	}
+	locale, ok := ctx.Value(LocaleKey).(string)
+	if !ok || !Supported(locale) {
+		return DefaultLocale
+	}
+	return locale
+}
+
+// Success is dto.NewSuccessResponse with the title and the message of key
+// translated to the locale of ctx.
+func Success(ctx context.Context, data any, key string, resTime string) *dto.Response {
+	response := dto.NewSuccessResponse(data, T(ctx, key), resTime)
+	response.MessageTitle = T(ctx, TitleSuccess)
+	return response
+}
+
+// Negotiate picks the supported locale an Accept-Language header prefers,
+// e.g. "id,en-US;q=0.8" is id-ID. A bare language matches the first locale
+// of that language, DefaultLocale is returned when nothing matches.
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		locale  string
+		quality float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if locale := match(tag); locale != "" && quality > 0 {
+			candidates = append(candidates, candidate{locale: locale, quality: quality})
+		}
+	}
+	if len(candidates) == 0 {
+		return DefaultLocale
+	}
+	// the header order breaks ties between equal qualities
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	return candidates[0].locale
+}
+
+func match(tag string) string {
+	for _, locale := range Locales {
+		if strings.EqualFold(tag, locale) {
+			return locale
+		}
+	}
+	language, _, _ := strings.Cut(tag, "-")
+	for _, locale := range Locales {
+		if strings.EqualFold(language, strings.SplitN(locale, "-", 2)[0]) {
+			return locale
+		}
+	}
+	return ""
+}
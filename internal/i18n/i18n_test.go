@@ -0,0 +1,124 @@
+package i18n_test
+
+import (
+	"context"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	"github.com/bowoBp/LoanFlow/internal/i18n"
+	"github.com/stretchr/testify/assert"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestCatalogue_Keys fails when a key is missing from one of the locales.
+func TestCatalogue_Keys(t *testing.T) {
+	want := i18n.Keys(i18n.DefaultLocale)
+	assert.NotEmpty(t, want)
+	for _, locale := range i18n.Locales {
+		got := i18n.Keys(locale)
+		for _, key := range want {
+			assert.Contains(t, got, key, "key %q is missing from %s", key, locale)
+		}
+		for _, key := range got {
+			assert.Contains(t, want, key, "key %q of %s is missing from %s", key, locale, i18n.DefaultLocale)
+		}
+	}
+}
+
+func TestCatalogue_ErrorCodes(t *testing.T) {
+	keys := i18n.Keys(i18n.DefaultLocale)
+	assert.Contains(t, keys, constant.CodeInternal)
+	for _, err := range constant.Errors() {
+		assert.Contains(t, keys, err.Code, "error code %q has no message", err.Code)
+		// the default locale answers the same message as err.Error()
+		assert.Equal(t, err.Message, i18n.Translate(i18n.DefaultLocale, err.Code))
+	}
+}
+
+var verbPattern = regexp.MustCompile(`%[a-z]`)
+
+func TestCatalogue_Placeholders(t *testing.T) {
+	for _, key := range i18n.Keys(i18n.DefaultLocale) {
+		want := verbPattern.FindAllString(i18n.Translate(i18n.DefaultLocale, key), -1)
+		for _, locale := range i18n.Locales {
+			got := verbPattern.FindAllString(i18n.Translate(locale, key), -1)
+			assert.Equal(t, want, got, "placeholders of %q in %s", key, locale)
+		}
+	}
+}
+
+var keyPattern = regexp.MustCompile(`"((?:success|validation|title|notification)\.[a-z_.]+)"`)
+
+// TestCatalogue_UsedKeys fails when the code or a notification template uses
+// a key that is not in the catalogue.
+func TestCatalogue_UsedKeys(t *testing.T) {
+	keys := i18n.Keys(i18n.DefaultLocale)
+	for _, root := range []string{"..", "../../pkg"} {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			if !strings.HasSuffix(path, ".go") && !strings.HasSuffix(path, ".tmpl") {
+				return nil
+			}
+			if strings.HasSuffix(path, "_test.go") {
+				return nil
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			for _, match := range keyPattern.FindAllStringSubmatch(string(content), -1) {
+				// validation keys are completed with the rule, e.g. "validation." + tag
+				if strings.HasSuffix(match[1], ".") {
+					continue
+				}
+				assert.Contains(t, keys, match[1], "%s uses a missing key", path)
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	assert.Equal(t, "user not found", i18n.Translate(i18n.EnUS, constant.ErrUserNotFound.Code))
+	assert.Equal(t, "pengguna tidak ditemukan", i18n.Translate(i18n.IdID, constant.ErrUserNotFound.Code))
+	assert.Equal(t, "amount minimal 0.01", i18n.Translate(i18n.IdID, "validation.min", "amount", "0.01"))
+	// unknown locales fall back to the default locale, unknown keys to the key
+	assert.Equal(t, "user not found", i18n.Translate("fr-FR", constant.ErrUserNotFound.Code))
+	assert.Equal(t, "unknown.key", i18n.Translate(i18n.IdID, "unknown.key"))
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, i18n.DefaultLocale, i18n.FromContext(context.Background()))
+	assert.Equal(t, i18n.IdID, i18n.FromContext(context.WithValue(context.Background(), i18n.LocaleKey, i18n.IdID)))
+	assert.Equal(t, i18n.DefaultLocale, i18n.FromContext(context.WithValue(context.Background(), i18n.LocaleKey, "fr-FR")))
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		want           string
+	}{
+		{name: "Empty header", acceptLanguage: "", want: i18n.EnUS},
+		{name: "Exact locale", acceptLanguage: "id-ID", want: i18n.IdID},
+		{name: "Case insensitive", acceptLanguage: "ID-id", want: i18n.IdID},
+		{name: "Bare language", acceptLanguage: "id", want: i18n.IdID},
+		{name: "Other region of a language", acceptLanguage: "en-GB", want: i18n.EnUS},
+		{name: "Quality order", acceptLanguage: "en-US;q=0.5, id;q=0.9", want: i18n.IdID},
+		{name: "Header order breaks ties", acceptLanguage: "id-ID, en-US", want: i18n.IdID},
+		{name: "Unsupported languages are skipped", acceptLanguage: "fr-FR, de;q=0.9, id;q=0.1", want: i18n.IdID},
+		{name: "Zero quality is refused", acceptLanguage: "id;q=0", want: i18n.EnUS},
+		{name: "Nothing supported", acceptLanguage: "fr-FR, *", want: i18n.EnUS},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, i18n.Negotiate(tt.acceptLanguage))
+		})
+	}
+}
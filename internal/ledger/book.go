@@ -0,0 +1,469 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"slices"
+	"time"
+)
+
+type (
+	// Store keeps the accounts and journal entries, usually the running db
+	// transaction of the caller so the postings commit with the loan change.
+	Store interface {
+		GetOrCreateAccount(
+			ctx context.Context,
+			account *domians.LedgerAccount,
+		) (*domians.LedgerAccount, error)
+		UpdateLedgerAccount(
+			ctx context.Context,
+			account *domians.LedgerAccount,
+			updateData map[string]any,
+		) error
+		CreateJournalEntry(
+			ctx context.Context,
+			entry *domians.JournalEntry,
+		) error
+	}
+
+	// Line moves Debit or Credit on Account, exactly one of them is set.
+	Line struct {
+		Account domians.LedgerAccount
+		Debit   money.Money
+		Credit  money.Money
+	}
+
+	Book struct {
+		// repaymentFee is the platform fee taken from every repayment
+		repaymentFee money.Rate
+	}
+
+	BookInterface interface {
+		Post(
+			ctx context.Context,
+			tx Store,
+			entry *domians.JournalEntry,
+			lines []Line,
+		) error
+		TopUp(
+			ctx context.Context,
+			tx Store,
+			topUp *domians.TopUp,
+			userID uint,
+		) (*domians.JournalEntry, error)
+		Invest(
+			ctx context.Context,
+			tx Store,
+			loan *domians.Loan,
+			investorID uint,
+			amount money.Money,
+		) error
+		Release(
+			ctx context.Context,
+			tx Store,
+			loan *domians.Loan,
+			investments []domians.LoanInvestor,
+			userID uint,
+		) error
+		Disburse(
+			ctx context.Context,
+			tx Store,
+			loan *domians.Loan,
+			userID uint,
+		) error
+		Repay(
+			ctx context.Context,
+			tx Store,
+			loan *domians.Loan,
+			investments []domians.LoanInvestor,
+			amount money.Money,
+			userID uint,
+		) error
+	}
+)
+
+func NewBook(repaymentFee money.Rate) BookInterface {
+	return Book{
+		repaymentFee: repaymentFee,
+	}
+}
+
+// CashAccount holds the money of the platform in currency. The accounts of
+// the platform and the wallets exist once per currency, the DefaultCurrency
+// ones keep the codes they had before accounts carried a currency.
+func CashAccount(currency money.Currency) domians.LedgerAccount {
+	return domians.LedgerAccount{
+		Code:     accountCode(constant.LedgerAccountCash, currency),
+		Type:     constant.LedgerAccountCash,
+		Currency: currencyOf(currency),
+	}
+}
+
+func FeeAccount(currency money.Currency) domians.LedgerAccount {
+	return domians.LedgerAccount{
+		Code:     accountCode(constant.LedgerAccountFee, currency),
+		Type:     constant.LedgerAccountFee,
+		Currency: currencyOf(currency),
+	}
+}
+
+func WalletAccount(userID uint, currency money.Currency) domians.LedgerAccount {
+	return domians.LedgerAccount{
+		Code:     accountCode(fmt.Sprintf("%s:%d", constant.LedgerAccountWallet, userID), currency),
+		Type:     constant.LedgerAccountWallet,
+		UserID:   &userID,
+		Currency: currencyOf(currency),
+	}
+}
+
+// EscrowAccount holds the investor money of the loan in the currency of the
+// loan.
+func EscrowAccount(loan *domians.Loan) domians.LedgerAccount {
+	return domians.LedgerAccount{
+		Code:     fmt.Sprintf("%s:%d", constant.LedgerAccountLoanEscrow, loan.ID),
+		Type:     constant.LedgerAccountLoanEscrow,
+		LoanID:   &loan.ID,
+		Currency: currencyOf(loan.Currency),
+	}
+}
+
+// ReceivableAccount belongs to the borrower, its balance is what the
+// borrower still owes on the loan.
+func ReceivableAccount(loan *domians.Loan) domians.LedgerAccount {
+	return domians.LedgerAccount{
+		Code:     fmt.Sprintf("%s:%d", constant.LedgerAccountLoanReceivable, loan.ID),
+		Type:     constant.LedgerAccountLoanReceivable,
+		UserID:   &loan.BorrowerID,
+		LoanID:   &loan.ID,
+		Currency: currencyOf(loan.Currency),
+	}
+}
+
+// DebitNormal reports whether the account type grows with a debit.
+func DebitNormal(accountType string) bool {
+	return accountType == constant.LedgerAccountCash ||
+		accountType == constant.LedgerAccountLoanReceivable
+}
+
+// Post stores a balanced journal entry and moves the balance of every
+// account in lines. Accounts are locked in code order so concurrent
+// postings can't deadlock, an investor wallet never goes below zero. All
+// the accounts of an entry must hold the same currency, the entry is in
+// that currency.
+func (b Book) Post(
+	ctx context.Context,
+	tx Store,
+	entry *domians.JournalEntry,
+	lines []Line,
+) error {
+	var debits, credits money.Money
+	amounts := make([]money.Money, 0, 2*len(lines))
+	for _, line := range lines {
+		amounts = append(amounts, line.Debit, line.Credit)
+	}
+	if !sameCurrency(amounts...) {
+		return constant.ErrLedgerCurrency
+	}
+	for _, line := range lines {
+		if line.Debit.IsNegative() || line.Credit.IsNegative() ||
+			line.Debit.IsZero() == line.Credit.IsZero() {
+			return constant.ErrUnbalancedEntry
+		}
+		debits = debits.Add(line.Debit)
+		credits = credits.Add(line.Credit)
+	}
+	if len(lines) < 2 || debits.Cmp(credits) != 0 {
+		return constant.ErrUnbalancedEntry
+	}
+
+	codes := make([]string, 0, len(lines))
+	templates := make(map[string]domians.LedgerAccount, len(lines))
+	for _, line := range lines {
+		if _, ok := templates[line.Account.Code]; !ok {
+			codes = append(codes, line.Account.Code)
+			templates[line.Account.Code] = line.Account
+		}
+	}
+	slices.Sort(codes)
+
+	accounts := make(map[string]*domians.LedgerAccount, len(codes))
+	balances := make(map[string]money.Money, len(codes))
+	for _, code := range codes {
+		account := templates[code]
+		locked, err := tx.GetOrCreateAccount(ctx, &account)
+		if err != nil {
+			return err
+		}
+		accounts[code] = locked
+		balances[code] = locked.Balance
+	}
+	// the stored currency counts, an account keeps the one it was opened in
+	entry.Currency = currencyOf(accounts[codes[0]].Currency)
+	for _, account := range accounts {
+		if currencyOf(account.Currency) != entry.Currency {
+			return constant.ErrLedgerCurrency
+		}
+	}
+	if !sameCurrency(append(amounts, money.Zero.In(entry.Currency))...) {
+		return constant.ErrLedgerCurrency
+	}
+	for code, balance := range balances {
+		balances[code] = balance.In(entry.Currency)
+	}
+
+	now := time.Now()
+	entry.CreatedAt = now
+	entry.Lines = make([]domians.JournalLine, len(lines))
+	for i, line := range lines {
+		account := accounts[line.Account.Code]
+		change := line.Credit.Sub(line.Debit)
+		if DebitNormal(account.Type) {
+			change = line.Debit.Sub(line.Credit)
+		}
+		balances[account.Code] = balances[account.Code].Add(change)
+		entry.Lines[i] = domians.JournalLine{
+			AccountID:    account.ID,
+			Debit:        line.Debit.In(entry.Currency),
+			Credit:       line.Credit.In(entry.Currency),
+			BalanceAfter: balances[account.Code],
+			CreatedAt:    now,
+		}
+	}
+
+	for _, code := range codes {
+		if accounts[code].Type == constant.LedgerAccountWallet && balances[code].IsNegative() {
+			return constant.ErrInsufficientBalance
+		}
+	}
+	for _, code := range codes {
+		account := accounts[code]
+		err := tx.UpdateLedgerAccount(ctx, account, map[string]any{
+			"balance":    balances[code],
+			"updated_at": now,
+		})
+		if err != nil {
+			return err
+		}
+		account.Balance = balances[code]
+	}
+	return tx.CreateJournalEntry(ctx, entry)
+}
+
+// TopUp adds the money paid in for topUp to the wallet of its user, userID
+// is the staff member who confirmed the payment.
+func (b Book) TopUp(
+	ctx context.Context,
+	tx Store,
+	topUp *domians.TopUp,
+	userID uint,
+) (*domians.JournalEntry, error) {
+	if !topUp.Amount.IsPositive() {
+		return nil, constant.ErrLedgerAmount
+	}
+	entry := &domians.JournalEntry{
+		Type:        constant.JournalTopUp,
+		Reference:   fmt.Sprintf("top_up:%d", topUp.ID),
+		Description: "wallet top-up " + topUp.PaymentReference,
+		CreatedBy:   userID,
+	}
+	err := b.Post(ctx, tx, entry, []Line{
+		{Account: CashAccount(topUp.Currency), Debit: topUp.Amount},
+		{Account: WalletAccount(topUp.UserID, topUp.Currency), Credit: topUp.Amount},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Invest moves the invested amount from the investor wallet to the loan
+// escrow, ErrInsufficientBalance is returned when the wallet is short.
+func (b Book) Invest(
+	ctx context.Context,
+	tx Store,
+	loan *domians.Loan,
+	investorID uint,
+	amount money.Money,
+) error {
+	if !amount.IsPositive() {
+		return constant.ErrLedgerAmount
+	}
+	return b.Post(
+		ctx,
+		tx,
+		&domians.JournalEntry{
+			Type:        constant.JournalInvestment,
+			Reference:   loanReference(loan),
+			Description: "investment",
+			CreatedBy:   investorID,
+		},
+		[]Line{
+			{Account: WalletAccount(investorID, loan.Currency), Debit: amount},
+			{Account: EscrowAccount(loan), Credit: amount},
+		},
+	)
+}
+
+// Release gives the active investments of a cancelled loan back to the
+// investor wallets.
+func (b Book) Release(
+	ctx context.Context,
+	tx Store,
+	loan *domians.Loan,
+	investments []domians.LoanInvestor,
+	userID uint,
+) error {
+	var (
+		total money.Money
+		lines = make([]Line, 0, len(investments)+1)
+	)
+	for _, investment := range investments {
+		if investment.Status != constant.InvestmentActive {
+			continue
+		}
+		total = total.Add(investment.AmountInvested)
+		lines = append(lines, Line{
+			Account: WalletAccount(investment.InvestorID, loan.Currency),
+			Credit:  investment.AmountInvested,
+		})
+	}
+	if total.IsZero() {
+		return nil
+	}
+	lines = append(lines, Line{Account: EscrowAccount(loan), Debit: total})
+	return b.Post(
+		ctx,
+		tx,
+		&domians.JournalEntry{
+			Type:        constant.JournalRelease,
+			Reference:   loanReference(loan),
+			Description: "investments released",
+			CreatedBy:   userID,
+		},
+		lines,
+	)
+}
+
+// Disburse pays the principal out to the borrower. The borrower then owes
+// principal and ROI, which is also what the escrow owes the investors.
+func (b Book) Disburse(
+	ctx context.Context,
+	tx Store,
+	loan *domians.Loan,
+	userID uint,
+) error {
+	lines := []Line{
+		{Account: ReceivableAccount(loan), Debit: loan.PrincipalAmount.Add(loan.ROI)},
+		{Account: CashAccount(loan.Currency), Credit: loan.PrincipalAmount},
+	}
+	if loan.ROI.IsPositive() {
+		lines = append(lines, Line{Account: EscrowAccount(loan), Credit: loan.ROI})
+	}
+	return b.Post(
+		ctx,
+		tx,
+		&domians.JournalEntry{
+			Type:        constant.JournalDisbursement,
+			Reference:   loanReference(loan),
+			Description: "loan disbursed",
+			CreatedBy:   userID,
+		},
+		lines,
+	)
+}
+
+// Repay books the money paid by the borrower and shares it, less the
+// platform fee, over the investor wallets by the size of their active
+// investment, see money.Money.Allocate for the rounding.
+func (b Book) Repay(
+	ctx context.Context,
+	tx Store,
+	loan *domians.Loan,
+	investments []domians.LoanInvestor,
+	amount money.Money,
+	userID uint,
+) error {
+	var (
+		fee      = b.repaymentFee.Of(amount)
+		active   = make([]domians.LoanInvestor, 0, len(investments))
+		invested = make([]money.Money, 0, len(investments))
+	)
+	for _, investment := range investments {
+		if investment.Status == constant.InvestmentActive {
+			active = append(active, investment)
+			invested = append(invested, investment.AmountInvested)
+		}
+	}
+	if len(active) == 0 {
+		return constant.ErrAgreementInvestors
+	}
+
+	lines := []Line{
+		{Account: CashAccount(loan.Currency), Debit: amount},
+		{Account: ReceivableAccount(loan), Credit: amount},
+		{Account: EscrowAccount(loan), Debit: amount},
+	}
+	if fee.IsPositive() {
+		lines = append(lines, Line{Account: FeeAccount(loan.Currency), Credit: fee})
+	}
+	shares := amount.Sub(fee).Allocate(invested)
+	for i, investment := range active {
+		if shares[i].IsPositive() {
+			lines = append(lines, Line{
+				Account: WalletAccount(investment.InvestorID, loan.Currency),
+				Credit:  shares[i],
+			})
+		}
+	}
+	return b.Post(
+		ctx,
+		tx,
+		&domians.JournalEntry{
+			Type:        constant.JournalRepayment,
+			Reference:   loanReference(loan),
+			Description: "loan repayment",
+			CreatedBy:   userID,
+		},
+		lines,
+	)
+}
+
+func loanReference(loan *domians.Loan) string {
+	return fmt.Sprintf("loan:%d", loan.ID)
+}
+
+// accountCode adds the currency to the code of an account that exists once
+// per currency, except for the DefaultCurrency.
+func accountCode(code string, currency money.Currency) string {
+	if currencyOf(currency) == money.DefaultCurrency {
+		return code
+	}
+	return code + ":" + string(currency)
+}
+
+// sameCurrency reports whether amounts can be added together, the amounts
+// without currency go with any.
+func sameCurrency(amounts ...money.Money) bool {
+	var currency money.Money
+	for _, amount := range amounts {
+		if !currency.SameCurrency(amount) {
+			return false
+		}
+		if amount.Currency() != "" {
+			currency = amount
+		}
+	}
+	return true
+}
+
+// currencyOf reads an empty currency, of rows stored before it was kept,
+// as the DefaultCurrency.
+func currencyOf(currency money.Currency) money.Currency {
+	if currency == "" {
+		return money.DefaultCurrency
+	}
+	return currency
+}
@@ -0,0 +1,233 @@
+package ledger_test
+
+import (
+	"context"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/ledger"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// memoryStore keeps the accounts and entries of a single transaction.
+type memoryStore struct {
+	accounts map[string]*domians.LedgerAccount
+	entries  []domians.JournalEntry
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{accounts: map[string]*domians.LedgerAccount{}}
+}
+
+func (s *memoryStore) GetOrCreateAccount(
+	ctx context.Context,
+	account *domians.LedgerAccount,
+) (*domians.LedgerAccount, error) {
+	if _, ok := s.accounts[account.Code]; !ok {
+		created := *account
+		created.ID = uint(len(s.accounts) + 1)
+		s.accounts[account.Code] = &created
+	}
+	stored := *s.accounts[account.Code]
+	return &stored, nil
+}
+
+func (s *memoryStore) UpdateLedgerAccount(
+	ctx context.Context,
+	account *domians.LedgerAccount,
+	updateData map[string]any,
+) error {
+	s.accounts[account.Code].Balance = updateData["balance"].(money.Money)
+	return nil
+}
+
+func (s *memoryStore) CreateJournalEntry(
+	ctx context.Context,
+	entry *domians.JournalEntry,
+) error {
+	entry.ID = uint(len(s.entries) + 1)
+	s.entries = append(s.entries, *entry)
+	return nil
+}
+
+func (s *memoryStore) balance(account domians.LedgerAccount) money.Money {
+	if stored, ok := s.accounts[account.Code]; ok {
+		return stored.Balance
+	}
+	return money.Zero
+}
+
+// idr is units in IDR, the currency of the accounts in the tests.
+func idr(units int64) money.Money {
+	return money.New(units).In(money.IDR)
+}
+
+func TestBook_Post(t *testing.T) {
+	book := ledger.NewBook(money.Rate{})
+	entry := &domians.JournalEntry{Type: constant.JournalTopUp}
+
+	tests := []struct {
+		name        string
+		lines       []ledger.Line
+		expectedErr error
+	}{
+		{
+			name: "Error - debits and credits differ",
+			lines: []ledger.Line{
+				{Account: ledger.CashAccount(money.IDR), Debit: money.New(100)},
+				{Account: ledger.WalletAccount(1, money.IDR), Credit: money.MustParse("99.99")},
+			},
+			expectedErr: constant.ErrUnbalancedEntry,
+		},
+		{
+			name: "Error - line on both sides",
+			lines: []ledger.Line{
+				{Account: ledger.CashAccount(money.IDR), Debit: money.New(100), Credit: money.New(100)},
+				{Account: ledger.WalletAccount(1, money.IDR), Credit: money.New(0)},
+			},
+			expectedErr: constant.ErrUnbalancedEntry,
+		},
+		{
+			name: "Error - wallet goes below zero",
+			lines: []ledger.Line{
+				{Account: ledger.WalletAccount(1, money.IDR), Debit: money.New(100)},
+				{Account: ledger.CashAccount(money.IDR), Credit: money.New(100)},
+			},
+			expectedErr: constant.ErrInsufficientBalance,
+		},
+		{
+			name: "Error - accounts in different currencies",
+			lines: []ledger.Line{
+				{Account: ledger.CashAccount(money.IDR), Debit: money.New(100)},
+				{Account: ledger.WalletAccount(1, "USD"), Credit: money.New(100)},
+			},
+			expectedErr: constant.ErrLedgerCurrency,
+		},
+		{
+			name: "Error - amounts in different currencies",
+			lines: []ledger.Line{
+				{Account: ledger.CashAccount(money.IDR), Debit: money.New(100).In("USD")},
+				{Account: ledger.WalletAccount(1, money.IDR), Credit: money.New(100).In(money.IDR)},
+			},
+			expectedErr: constant.ErrLedgerCurrency,
+		},
+		{
+			name: "Error - amounts in another currency than the accounts",
+			lines: []ledger.Line{
+				{Account: ledger.CashAccount(money.IDR), Debit: money.New(100).In("USD")},
+				{Account: ledger.WalletAccount(1, money.IDR), Credit: money.New(100)},
+			},
+			expectedErr: constant.ErrLedgerCurrency,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryStore()
+			err := book.Post(context.Background(), store, entry, tt.lines)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Empty(t, store.entries)
+		})
+	}
+}
+
+func TestBook_LoanLifecycle(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		book  = ledger.NewBook(money.MustParseRate("1")) // 1% repayment fee
+		store = newMemoryStore()
+		loan  = &domians.Loan{ID: 3, BorrowerID: 2, PrincipalAmount: money.New(300), ROI: money.New(30)}
+	)
+
+	// top-ups
+	_, err := book.TopUp(ctx, store, &domians.TopUp{ID: 1, UserID: 7, Amount: money.New(250)}, 1)
+	assert.NoError(t, err)
+	_, err = book.TopUp(ctx, store, &domians.TopUp{ID: 2, UserID: 8, Amount: money.New(100)}, 1)
+	assert.NoError(t, err)
+	_, err = book.TopUp(ctx, store, &domians.TopUp{ID: 3, UserID: 8, Amount: money.New(0)}, 1)
+	assert.Equal(t, constant.ErrLedgerAmount, err)
+
+	// investments, the second investor is short on the first try
+	assert.NoError(t, book.Invest(ctx, store, loan, 7, money.New(200)))
+	assert.Equal(t, constant.ErrInsufficientBalance, book.Invest(ctx, store, loan, 8, money.New(150)))
+	assert.NoError(t, book.Invest(ctx, store, loan, 8, money.New(100)))
+	assert.Equal(t, idr(50), store.balance(ledger.WalletAccount(7, money.IDR)))
+	assert.Equal(t, idr(0), store.balance(ledger.WalletAccount(8, money.IDR)))
+	assert.Equal(t, idr(300), store.balance(ledger.EscrowAccount(loan)))
+
+	// disbursement, the borrower owes principal and ROI
+	assert.NoError(t, book.Disburse(ctx, store, loan, 1))
+	assert.Equal(t, idr(330), store.balance(ledger.ReceivableAccount(loan)))
+	assert.Equal(t, idr(330), store.balance(ledger.EscrowAccount(loan)))
+	assert.Equal(t, idr(50), store.balance(ledger.CashAccount(money.IDR)))
+
+	// repayment, shared 2:1 after the 1% fee
+	investments := []domians.LoanInvestor{
+		{InvestorID: 7, AmountInvested: money.New(200), Status: constant.InvestmentActive},
+		{InvestorID: 8, AmountInvested: money.New(100), Status: constant.InvestmentActive},
+		{InvestorID: 9, AmountInvested: money.New(500), Status: constant.InvestmentReleased},
+	}
+	assert.NoError(t, book.Repay(ctx, store, loan, investments, money.New(100), 2))
+	assert.Equal(t, idr(1), store.balance(ledger.FeeAccount(money.IDR)))
+	assert.Equal(t, idr(116), store.balance(ledger.WalletAccount(7, money.IDR)))
+	assert.Equal(t, idr(33), store.balance(ledger.WalletAccount(8, money.IDR)))
+	assert.Equal(t, money.Zero, store.balance(ledger.WalletAccount(9, money.IDR)))
+	assert.Equal(t, idr(230), store.balance(ledger.ReceivableAccount(loan)))
+	assert.Equal(t, idr(230), store.balance(ledger.EscrowAccount(loan)))
+
+	// every stored entry is balanced
+	for _, entry := range store.entries {
+		var debits, credits money.Money
+		for _, line := range entry.Lines {
+			debits = debits.Add(line.Debit)
+			credits = credits.Add(line.Credit)
+		}
+		assert.Equal(t, debits, credits, entry.Type)
+	}
+}
+
+func TestBook_Release(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		book  = ledger.NewBook(money.Rate{})
+		store = newMemoryStore()
+		loan  = &domians.Loan{ID: 4, BorrowerID: 2, PrincipalAmount: money.New(300)}
+	)
+	_, err := book.TopUp(ctx, store, &domians.TopUp{ID: 1, UserID: 7, Amount: money.New(120)}, 1)
+	assert.NoError(t, err)
+	assert.NoError(t, book.Invest(ctx, store, loan, 7, money.New(80)))
+	assert.NoError(t, book.Invest(ctx, store, loan, 7, money.New(40)))
+
+	err = book.Release(ctx, store, loan, []domians.LoanInvestor{
+		{InvestorID: 7, AmountInvested: money.New(80), Status: constant.InvestmentActive},
+		{InvestorID: 7, AmountInvested: money.New(40), Status: constant.InvestmentActive},
+	}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, idr(120), store.balance(ledger.WalletAccount(7, money.IDR)))
+	assert.Equal(t, idr(0), store.balance(ledger.EscrowAccount(loan)))
+
+	// nothing is left to release
+	assert.NoError(t, book.Release(ctx, store, loan, nil, 2))
+	assert.Len(t, store.entries, 4)
+}
+
+func TestBook_Currency(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		book  = ledger.NewBook(money.Rate{})
+		store = newMemoryStore()
+		loan  = &domians.Loan{ID: 5, BorrowerID: 2, Currency: "USD", PrincipalAmount: money.New(100)}
+	)
+	entry, err := book.TopUp(ctx, store, &domians.TopUp{ID: 1, UserID: 7, Amount: money.New(120)}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, money.IDR, entry.Currency)
+	assert.Equal(t, money.IDR, entry.Lines[0].Debit.Currency())
+
+	// the IDR wallet does not pay for a loan in another currency
+	assert.Equal(t, constant.ErrInsufficientBalance, book.Invest(ctx, store, loan, 7, money.New(80)))
+	assert.Equal(t, "investor_wallet:7", ledger.WalletAccount(7, money.IDR).Code)
+	assert.Equal(t, "investor_wallet:7:USD", ledger.WalletAccount(7, "USD").Code)
+	assert.Equal(t, money.Currency("USD"), ledger.EscrowAccount(loan).Currency)
+}
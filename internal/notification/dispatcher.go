@@ -0,0 +1,124 @@
+package notification
+
+import (
+	"context"
+	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/pkg/mailer"
+	"log/slog"
+	"time"
+)
+
+type (
+	// Dispatcher sends the stored notifications and retries the failed
+	// ones with an exponential backoff.
+	Dispatcher struct {
+		repo      Repository.NotificationRepoInterface
+		sender    mailer.Sender
+		batchSize int
+	}
+)
+
+func NewDispatcher(
+	repo Repository.NotificationRepoInterface,
+	sender mailer.Sender,
+) Dispatcher {
+	return Dispatcher{
+		repo:      repo,
+		sender:    sender,
+		batchSize: 100,
+	}
+}
+
+// Run dispatches the due notifications every interval and purges the
+// finished ones every constant.NotificationPurgeInterval until ctx is done.
+func (d Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	purge := time.NewTicker(constant.NotificationPurgeInterval)
+	defer purge.Stop()
+	for {
+		_, err := d.Dispatch(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "notification.Dispatcher.Run", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-purge.C:
+			err = d.Purge(ctx, time.Now())
+			if err != nil {
+				slog.ErrorContext(ctx, "notification.Dispatcher.Run", "error", err)
+			}
+		}
+	}
+}
+
+// Purge deletes the notifications sent or given up longer than
+// constant.NotificationRetention before now.
+func (d Dispatcher) Purge(ctx context.Context, now time.Time) error {
+	return d.repo.DeleteFinishedNotifications(ctx, now.Add(-constant.NotificationRetention))
+}
+
+// Dispatch claims one batch of due notifications, sends them and records
+// the outcome of every attempt, it returns how many were sent.
+func (d Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	notifications, err := d.repo.ClaimDueNotifications(ctx, time.Now(), d.batchSize, constant.NotificationLease)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for i := range notifications {
+		err = d.send(ctx, &notifications[i])
+		if err != nil {
+			return sent, err
+		}
+		if notifications[i].Status == constant.NotificationSent {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+func (d Dispatcher) send(ctx context.Context, notification *domians.Notification) error {
+	errSend := d.sender.Send(ctx, mailer.Message{
+		To:      notification.Recipient,
+		Subject: notification.Subject,
+		Body:    notification.Body,
+	})
+	now := time.Now()
+	notification.Attempts++
+	updateData := map[string]any{
+		"attempts":   notification.Attempts,
+		"updated_at": now,
+	}
+	// the body is not needed any more once the notification is finished,
+	// it is cleared so the single-use links it carries are not kept
+	switch {
+	case errSend == nil:
+		notification.Status = constant.NotificationSent
+		updateData["sent_at"] = now
+		updateData["last_error"] = ""
+		updateData["body"] = ""
+	case notification.Attempts >= constant.NotificationMaxAttempts:
+		notification.Status = constant.NotificationFailed
+		updateData["last_error"] = errSend.Error()
+		updateData["body"] = ""
+	default:
+		backoff := constant.NotificationBackoff << (notification.Attempts - 1)
+		notification.Status = constant.NotificationPending
+		updateData["last_error"] = errSend.Error()
+		updateData["next_attempt_at"] = now.Add(backoff)
+	}
+	updateData["status"] = notification.Status
+
+	return d.repo.UpdateNotification(
+		ctx,
+		&domians.Notification{
+			ID: notification.ID,
+		},
+		updateData,
+	)
+}
@@ -0,0 +1,225 @@
+package notification_test
+
+import (
+	"context"
+	"errors"
+	"github.com/bowoBp/LoanFlow/internal/adapter/mocks"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/i18n"
+	"github.com/bowoBp/LoanFlow/internal/notification"
+	"github.com/bowoBp/LoanFlow/pkg/mailer"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingSender fails the first failures sends and then succeeds
+type failingSender struct {
+	failures int
+	sent     []mailer.Message
+}
+
+func (s *failingSender) Send(ctx context.Context, msg mailer.Message) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("smtp unavailable")
+	}
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func TestNotifier_Enqueue(t *testing.T) {
+	templates, err := notification.ParseTemplates("")
+	assert.NoError(t, err)
+	notifier := notification.NewNotifier(templates)
+
+	tx := new(mocks.NotificationStore)
+	tx.On("CreateNotifications", mock.Anything, mock.MatchedBy(func(notifications []domians.Notification) bool {
+		return len(notifications) == 2 &&
+			notifications[0].Recipient == "a@mail.com" &&
+			notifications[0].Subject == "Loan #1 is fully funded" &&
+			strings.Contains(notifications[0].Body, "https://docs/a.html") &&
+			notifications[1].UserID == 8 &&
+			notifications[1].Status == constant.NotificationPending &&
+			notifications[1].Channel == constant.ChannelEmail
+	})).Return(nil).Once()
+
+	err = notifier.Enqueue(context.Background(), tx, constant.TemplateLoanFunded, []notification.Recipient{
+		{UserID: 7, Address: "a@mail.com", Data: notification.LoanFunded{LoanID: 1, AgreementLink: "https://docs/a.html"}},
+		{UserID: 8, Address: "b@mail.com", Data: notification.LoanFunded{LoanID: 1, AgreementLink: "https://docs/b.html"}},
+	})
+	assert.NoError(t, err)
+	tx.AssertExpectations(t)
+
+	err = notifier.Enqueue(context.Background(), tx, "unknown", nil)
+	assert.Equal(t, constant.ErrNotificationTemplate, err)
+}
+
+func TestNotifier_EnqueueLocale(t *testing.T) {
+	templates, err := notification.ParseTemplates("")
+	assert.NoError(t, err)
+	notifier := notification.NewNotifier(templates)
+
+	tx := new(mocks.NotificationStore)
+	tx.On("CreateNotifications", mock.Anything, mock.MatchedBy(func(notifications []domians.Notification) bool {
+		return len(notifications) == 2 &&
+			notifications[0].Subject == "Pinjaman #1 sudah terdanai penuh" &&
+			strings.HasPrefix(notifications[0].Body, "Halo budi,") &&
+			strings.Contains(notifications[0].Body, "Jumlah investasi: IDR 1000000.00") &&
+			notifications[1].Subject == "Loan #1 is fully funded"
+	})).Return(nil).Once()
+
+	data := notification.LoanFunded{
+		Name:           "budi",
+		LoanID:         1,
+		Currency:       money.IDR,
+		AmountInvested: money.MustParse("1000000"),
+	}
+	err = notifier.Enqueue(context.Background(), tx, constant.TemplateLoanFunded, []notification.Recipient{
+		{UserID: 7, Address: "a@mail.com", Locale: i18n.IdID, Data: data},
+		{UserID: 8, Address: "b@mail.com", Data: data},
+	})
+	assert.NoError(t, err)
+	tx.AssertExpectations(t)
+}
+
+func TestNotifier_EnqueueAccountLink(t *testing.T) {
+	templates, err := notification.ParseTemplates("")
+	assert.NoError(t, err)
+	notifier := notification.NewNotifier(templates)
+
+	for _, name := range []string{constant.TemplatePasswordReset, constant.TemplateEmailVerification} {
+		t.Run(name, func(t *testing.T) {
+			tx := new(mocks.NotificationStore)
+			tx.On("CreateNotifications", mock.Anything, mock.MatchedBy(func(notifications []domians.Notification) bool {
+				return len(notifications) == 1 &&
+					notifications[0].Template == name &&
+					strings.Contains(notifications[0].Body, "https://app/link?token=abc") &&
+					strings.Contains(notifications[0].Body, "01 Jan 2030 10:00 UTC")
+			})).Return(nil).Once()
+
+			err := notifier.Enqueue(context.Background(), tx, name, []notification.Recipient{
+				{UserID: 7, Address: "a@mail.com", Data: notification.AccountLink{
+					Name:      "budi",
+					Link:      "https://app/link?token=abc",
+					ExpiresAt: time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC),
+				}},
+			})
+			assert.NoError(t, err)
+			tx.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		attempts     uint
+		failures     int
+		mockBehavior func(repo *mocks.NotificationRepoInterface)
+		wantSent     int
+	}{
+		{
+			name: "Success - notification sent",
+			mockBehavior: func(repo *mocks.NotificationRepoInterface) {
+				repo.On("UpdateNotification", mock.Anything, &domians.Notification{ID: 1}, mock.MatchedBy(func(data map[string]any) bool {
+					return data["status"] == constant.NotificationSent &&
+						data["attempts"] == uint(1) &&
+						data["body"] == ""
+				})).Return(nil).Once()
+			},
+			wantSent: 1,
+		},
+		{
+			name:     "Retry - failed attempt is scheduled again",
+			attempts: 1,
+			failures: 1,
+			mockBehavior: func(repo *mocks.NotificationRepoInterface) {
+				repo.On("UpdateNotification", mock.Anything, &domians.Notification{ID: 1}, mock.MatchedBy(func(data map[string]any) bool {
+					next, _ := data["next_attempt_at"].(time.Time)
+					_, cleared := data["body"]
+					return data["status"] == constant.NotificationPending &&
+						!cleared &&
+						data["attempts"] == uint(2) &&
+						data["last_error"] == "smtp unavailable" &&
+						time.Until(next) > constant.NotificationBackoff
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name:     "Failed - last attempt gives up",
+			attempts: constant.NotificationMaxAttempts - 1,
+			failures: 1,
+			mockBehavior: func(repo *mocks.NotificationRepoInterface) {
+				repo.On("UpdateNotification", mock.Anything, &domians.Notification{ID: 1}, mock.MatchedBy(func(data map[string]any) bool {
+					return data["status"] == constant.NotificationFailed &&
+						data["attempts"] == uint(constant.NotificationMaxAttempts) &&
+						data["body"] == ""
+				})).Return(nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.NotificationRepoInterface)
+			repo.On("ClaimDueNotifications", mock.Anything, mock.Anything, mock.Anything, constant.NotificationLease).
+				Return([]domians.Notification{
+					{
+						ID:        1,
+						Recipient: "a@mail.com",
+						Subject:   "subject",
+						Body:      "body",
+						Status:    constant.NotificationSending,
+						Attempts:  tt.attempts,
+					},
+				}, nil).Once()
+			tt.mockBehavior(repo)
+			sender := &failingSender{failures: tt.failures}
+
+			sent, err := notification.NewDispatcher(repo, sender).Dispatch(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantSent, sent)
+			assert.Len(t, sender.sent, tt.wantSent)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDispatcher_Purge(t *testing.T) {
+	now := time.Now()
+	repo := new(mocks.NotificationRepoInterface)
+	repo.On("DeleteFinishedNotifications", mock.Anything, now.Add(-constant.NotificationRetention)).
+		Return(nil).Once()
+
+	err := notification.NewDispatcher(repo, &failingSender{}).Purge(context.Background(), now)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestOutboxSender_Send(t *testing.T) {
+	dir := t.TempDir()
+	sender := mailer.NewOutboxSender(dir, "no-reply@loanflow.id")
+
+	err := sender.Send(context.Background(), mailer.Message{
+		To:      "a@mail.com",
+		Subject: "hello\r\nBcc: someone@mail.com",
+		Body:    "body",
+	})
+	assert.NoError(t, err)
+
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	content, err := os.ReadFile(dir + "/" + files[0].Name())
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "To: a@mail.com\r\n")
+	assert.Contains(t, string(content), "Subject: helloBcc: someone@mail.com\r\n")
+}
@@ -0,0 +1,165 @@
+package notification
+
+import (
+	"context"
+	"embed"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/i18n"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"io/fs"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+type (
+	// NotificationStore stores the notifications to send, usually the
+	// running db transaction of the caller so the notifications are only
+	// sent when the transaction is committed.
+	NotificationStore interface {
+		CreateNotifications(
+			ctx context.Context,
+			notifications []domians.Notification,
+		) error
+	}
+
+	// Recipient is a user to notify with the data for the template, the
+	// email is written in Locale or in i18n.DefaultLocale when it is empty.
+	Recipient struct {
+		UserID  uint
+		Address string
+		Locale  string
+		Data    any
+	}
+
+	// LoanFunded is the data of the loan_funded template.
+	LoanFunded struct {
+		Name           string
+		LoanID         uint
+		Currency       money.Currency
+		AmountInvested money.Money
+		AgreementLink  string
+	}
+
+	// AccountLink is the data of the password_reset and email_verification
+	// templates, Link carries a single-use token.
+	AccountLink struct {
+		Name      string
+		Link      string
+		ExpiresAt time.Time
+	}
+
+	Notifier struct {
+		templates map[string]*template.Template
+	}
+
+	NotifierInterface interface {
+		Enqueue(
+			ctx context.Context,
+			tx NotificationStore,
+			name string,
+			recipients []Recipient,
+		) error
+	}
+)
+
+func NewNotifier(templates map[string]*template.Template) NotifierInterface {
+	return Notifier{
+		templates: templates,
+	}
+}
+
+// ParseTemplates reads every <name>.tmpl file in dir, the bundled templates
+// are used when dir is empty. A template defines a "subject" and a "body",
+// the texts come from the message catalogue with {{t "key" args...}}.
+func ParseTemplates(dir string) (map[string]*template.Template, error) {
+	var fsys fs.FS = os.DirFS(dir)
+	if dir == "" {
+		sub, err := fs.Sub(defaultTemplates, "templates")
+		if err != nil {
+			return nil, err
+		}
+		fsys = sub
+	}
+	files, err := fs.Glob(fsys, "*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	templates := make(map[string]*template.Template, len(files))
+	for _, file := range files {
+		tmpl, err := template.New(file).Funcs(translator(i18n.DefaultLocale)).ParseFS(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		templates[strings.TrimSuffix(file, ".tmpl")] = tmpl
+	}
+	return templates, nil
+}
+
+// Enqueue renders the template for every recipient and stores one pending
+// email notification per recipient.
+func (n Notifier) Enqueue(
+	ctx context.Context,
+	tx NotificationStore,
+	name string,
+	recipients []Recipient,
+) error {
+	tmpl, ok := n.templates[name]
+	if !ok {
+		return constant.ErrNotificationTemplate
+	}
+	now := time.Now()
+	notifications := make([]domians.Notification, 0, len(recipients))
+	for _, recipient := range recipients {
+		subject, body, err := render(tmpl, recipient.Locale, recipient.Data)
+		if err != nil {
+			return err
+		}
+		notifications = append(notifications, domians.Notification{
+			UserID:        recipient.UserID,
+			Channel:       constant.ChannelEmail,
+			Recipient:     recipient.Address,
+			Template:      name,
+			Subject:       subject,
+			Body:          body,
+			Status:        constant.NotificationPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+	return tx.CreateNotifications(ctx, notifications)
+}
+
+// translator is the "t" function of the templates for locale.
+func translator(locale string) template.FuncMap {
+	return template.FuncMap{
+		"t": func(key string, args ...any) string {
+			return i18n.Translate(locale, key, args...)
+		},
+	}
+}
+
+func render(tmpl *template.Template, locale string, data any) (string, string, error) {
+	tmpl, err := tmpl.Clone()
+	if err != nil {
+		return "", "", err
+	}
+	tmpl.Funcs(translator(locale))
+
+	var subject, body strings.Builder
+	err = tmpl.ExecuteTemplate(&subject, "subject", data)
+	if err != nil {
+		return "", "", err
+	}
+	err = tmpl.ExecuteTemplate(&body, "body", data)
+	if err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(subject.String()), strings.TrimSpace(body.String()), nil
+}
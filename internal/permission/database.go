@@ -0,0 +1,79 @@
+package permission
+
+import (
+	"context"
+	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	"slices"
+	"sync"
+	"time"
+)
+
+type (
+	// DatabaseResolver reads the grants from the role_permissions table and
+	// keeps them for ttl, so checking a route does not query the database
+	// on every request.
+	DatabaseResolver struct {
+		store    Repository.PermissionRepoInterface
+		ttl      time.Duration
+		mu       sync.RWMutex
+		grants   map[string][]string
+		loadedAt time.Time
+		// version changes on Invalidate, grants read before are not kept
+		version uint64
+	}
+)
+
+func NewDatabaseResolver(store Repository.PermissionRepoInterface, ttl time.Duration) Resolver {
+	return &DatabaseResolver{
+		store: store,
+		ttl:   ttl,
+	}
+}
+
+func (r *DatabaseResolver) Allowed(ctx context.Context, role, permission string) (bool, error) {
+	grants, err := r.load(ctx)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(grants[role], permission), nil
+}
+
+func (r *DatabaseResolver) Permissions(ctx context.Context, role string) ([]string, error) {
+	grants, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(grants[role]), nil
+}
+
+func (r *DatabaseResolver) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.grants = nil
+	r.version++
+}
+
+func (r *DatabaseResolver) load(ctx context.Context) (map[string][]string, error) {
+	r.mu.RLock()
+	grants, loadedAt, version := r.grants, r.loadedAt, r.version
+	r.mu.RUnlock()
+	if grants != nil && time.Since(loadedAt) < r.ttl {
+		return grants, nil
+	}
+
+	rows, err := r.store.GetRolePermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// rows are ordered by role and permission, so each list is sorted
+	grants = make(map[string][]string)
+	for _, row := range rows {
+		grants[row.Role] = append(grants[row.Role], row.Permission)
+	}
+	r.mu.Lock()
+	if r.version == version {
+		r.grants, r.loadedAt = grants, time.Now()
+	}
+	r.mu.Unlock()
+	return grants, nil
+}
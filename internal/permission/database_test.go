@@ -0,0 +1,99 @@
+package permission_test
+
+import (
+	"context"
+	"errors"
+	"github.com/bowoBp/LoanFlow/internal/adapter/mocks"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/permission"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestDatabaseResolver_Allowed(t *testing.T) {
+	ctx := context.Background()
+	errDB := errors.New("db down")
+
+	tests := []struct {
+		name         string
+		role         string
+		permission   string
+		mockBehavior func(store *mocks.PermissionRepoInterface)
+		want         bool
+		expectedErr  error
+	}{
+		{
+			name:       "Allowed - role has the permission",
+			role:       constant.RoleStaff,
+			permission: constant.PermLoanApprove,
+			mockBehavior: func(store *mocks.PermissionRepoInterface) {
+				store.On("GetRolePermissions", mock.Anything).Return([]domians.RolePermission{
+					{Role: constant.RoleStaff, Permission: constant.PermLoanApprove},
+				}, nil).Once()
+			},
+			want: true,
+		},
+		{
+			name:       "Denied - permission of another role",
+			role:       constant.RoleBorrower,
+			permission: constant.PermLoanApprove,
+			mockBehavior: func(store *mocks.PermissionRepoInterface) {
+				store.On("GetRolePermissions", mock.Anything).Return([]domians.RolePermission{
+					{Role: constant.RoleStaff, Permission: constant.PermLoanApprove},
+				}, nil).Once()
+			},
+		},
+		{
+			name:       "Error - grants cannot be read",
+			role:       constant.RoleStaff,
+			permission: constant.PermLoanApprove,
+			mockBehavior: func(store *mocks.PermissionRepoInterface) {
+				store.On("GetRolePermissions", mock.Anything).Return(nil, errDB).Once()
+			},
+			expectedErr: errDB,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := new(mocks.PermissionRepoInterface)
+			tt.mockBehavior(store)
+			resolver := permission.NewDatabaseResolver(store, time.Minute)
+
+			allowed, err := resolver.Allowed(ctx, tt.role, tt.permission)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.want, allowed)
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDatabaseResolver_Invalidate(t *testing.T) {
+	ctx := context.Background()
+	store := new(mocks.PermissionRepoInterface)
+	store.On("GetRolePermissions", mock.Anything).Return([]domians.RolePermission{
+		{Role: constant.RoleStaff, Permission: constant.PermLoanApprove},
+	}, nil).Once()
+	store.On("GetRolePermissions", mock.Anything).Return([]domians.RolePermission{
+		{Role: constant.RoleStaff, Permission: constant.PermLoanApprove},
+		{Role: constant.RoleStaff, Permission: constant.PermLoanDisburse},
+	}, nil).Once()
+	resolver := permission.NewDatabaseResolver(store, time.Hour)
+
+	// the second check is served from memory
+	for range 2 {
+		permissions, err := resolver.Permissions(ctx, constant.RoleStaff)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{constant.PermLoanApprove}, permissions)
+	}
+
+	resolver.Invalidate()
+	permissions, err := resolver.Permissions(ctx, constant.RoleStaff)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{constant.PermLoanApprove, constant.PermLoanDisburse}, permissions)
+	store.AssertExpectations(t)
+}
@@ -0,0 +1,61 @@
+package permission
+
+import (
+	"context"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+)
+
+type (
+	// Resolver tells which permissions the users of a role have been
+	// granted.
+	Resolver interface {
+		Allowed(ctx context.Context, role, permission string) (bool, error)
+		// Permissions returns the permissions of the role sorted by name.
+		Permissions(ctx context.Context, role string) ([]string, error)
+		// Invalidate drops the grants held in memory, the next check reads
+		// them again.
+		Invalidate()
+	}
+)
+
+// Defaults are the grants seeded by the role_permissions migration, the
+// roles each route allowed before permissions could be edited.
+var Defaults = map[string][]string{
+	constant.RoleAdmin: {
+		constant.PermLedgerRead,
+		constant.PermLedgerReadAll,
+		constant.PermLedgerTopUp,
+		constant.PermLoanApprove,
+		constant.PermLoanCreate,
+		constant.PermLoanDisburse,
+		constant.PermLoanRead,
+		constant.PermLoanReadAll,
+		constant.PermLoanReject,
+		constant.PermLoanRepay,
+		constant.PermUserManage,
+	},
+	constant.RoleStaff: {
+		constant.PermLedgerRead,
+		constant.PermLedgerReadAll,
+		constant.PermLedgerTopUp,
+		constant.PermLoanApprove,
+		constant.PermLoanDisburse,
+		constant.PermLoanRead,
+		constant.PermLoanReadAll,
+		constant.PermLoanReject,
+		constant.PermLoanRepay,
+	},
+	constant.RoleBorrower: {
+		constant.PermLedgerRead,
+		constant.PermLoanCancel,
+		constant.PermLoanCreate,
+		constant.PermLoanRead,
+		constant.PermLoanRepay,
+	},
+	constant.RoleInvestor: {
+		constant.PermInvestmentCreate,
+		constant.PermLedgerRead,
+		constant.PermLedgerTopUpRequest,
+		constant.PermLoanRead,
+	},
+}
@@ -0,0 +1,30 @@
+package permission
+
+import (
+	"context"
+	"slices"
+)
+
+type (
+	// Static grants a fixed set of permissions per role, it cannot be edited
+	// while running.
+	Static struct {
+		grants map[string][]string
+	}
+)
+
+func NewStatic(grants map[string][]string) Resolver {
+	return &Static{
+		grants: grants,
+	}
+}
+
+func (s Static) Allowed(_ context.Context, role, permission string) (bool, error) {
+	return slices.Contains(s.grants[role], permission), nil
+}
+
+func (s Static) Permissions(_ context.Context, role string) ([]string, error) {
+	return slices.Sorted(slices.Values(s.grants[role])), nil
+}
+
+func (s Static) Invalidate() {}
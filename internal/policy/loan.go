@@ -0,0 +1,106 @@
+// Package policy decides which records a user may see once the permission
+// check of the route passed. Policies work on plain values so they can be
+// tested without HTTP.
+package policy
+
+import (
+	"context"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/dto"
+	"github.com/bowoBp/LoanFlow/internal/permission"
+	"slices"
+)
+
+type (
+	// Subject is the authenticated user, the JWT id and role.
+	Subject struct {
+		ID   uint
+		Role string
+	}
+
+	// InvestmentStore finds the investments of a loan, implemented by the
+	// loan repository.
+	InvestmentStore interface {
+		GetLoanInvestors(
+			ctx context.Context,
+			loanID uint,
+		) ([]domians.LoanInvestor, error)
+	}
+
+	LoanPolicy struct {
+		Investments InvestmentStore
+		Permissions permission.Resolver
+	}
+)
+
+// OpenLoanStates are the states in which a loan is offered to investors.
+var OpenLoanStates = []string{constant.Approved}
+
+func NewLoanPolicy(investments InvestmentStore, permissions permission.Resolver) LoanPolicy {
+	return LoanPolicy{
+		Investments: investments,
+		Permissions: permissions,
+	}
+}
+
+// ListFilter narrows the loan list to what subject may see: roles with the
+// loan.read_all permission get every loan, borrowers their own loans and
+// investors the open loans and the loans they invested in.
+func (p LoanPolicy) ListFilter(ctx context.Context, subject Subject) (dto.LoanFilter, error) {
+	readAll, err := p.Permissions.Allowed(ctx, subject.Role, constant.PermLoanReadAll)
+	if err != nil {
+		return dto.LoanFilter{}, err
+	}
+	if readAll {
+		return dto.LoanFilter{}, nil
+	}
+	switch subject.Role {
+	case constant.RoleBorrower:
+		return dto.LoanFilter{BorrowerID: subject.ID}, nil
+	case constant.RoleInvestor:
+		return dto.LoanFilter{
+			InvestorID: subject.ID,
+			OpenStates: OpenLoanStates,
+		}, nil
+	}
+	return dto.LoanFilter{}, constant.ErrLoanForbidden
+}
+
+// CanView returns ErrLoanNotOwned when subject may not see loan, the rules
+// are the ones of ListFilter.
+func (p LoanPolicy) CanView(
+	ctx context.Context,
+	subject Subject,
+	loan *domians.Loan,
+) error {
+	readAll, err := p.Permissions.Allowed(ctx, subject.Role, constant.PermLoanReadAll)
+	if err != nil {
+		return err
+	}
+	if readAll {
+		return nil
+	}
+	switch subject.Role {
+	case constant.RoleBorrower:
+		if loan.BorrowerID == subject.ID {
+			return nil
+		}
+		return constant.ErrLoanNotOwned
+	case constant.RoleInvestor:
+		if slices.Contains(OpenLoanStates, loan.State) {
+			return nil
+		}
+		investments, err := p.Investments.GetLoanInvestors(ctx, loan.ID)
+		if err != nil {
+			return err
+		}
+		for _, investment := range investments {
+			if investment.InvestorID == subject.ID {
+				return nil
+			}
+		}
+		return constant.ErrLoanNotOwned
+	}
+	return constant.ErrLoanForbidden
+}
@@ -0,0 +1,181 @@
+package policy_test
+
+import (
+	"context"
+	"errors"
+	"github.com/bowoBp/LoanFlow/internal/adapter/mocks"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/dto"
+	"github.com/bowoBp/LoanFlow/internal/permission"
+	"github.com/bowoBp/LoanFlow/internal/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestLoanPolicy_ListFilter(t *testing.T) {
+	// a borrower granted loan.read_all by an admin sees every loan
+	grants := map[string][]string{
+		constant.RoleBorrower: {constant.PermLoanRead, constant.PermLoanReadAll},
+	}
+
+	tests := []struct {
+		name    string
+		grants  map[string][]string
+		subject policy.Subject
+		want    dto.LoanFilter
+		wantErr error
+	}{
+		{
+			name:    "admin sees every loan",
+			subject: policy.Subject{ID: 1, Role: constant.RoleAdmin},
+			want:    dto.LoanFilter{},
+		},
+		{
+			name:    "staff sees every loan",
+			subject: policy.Subject{ID: 2, Role: constant.RoleStaff},
+			want:    dto.LoanFilter{},
+		},
+		{
+			name:    "borrower sees own loans",
+			subject: policy.Subject{ID: 3, Role: constant.RoleBorrower},
+			want:    dto.LoanFilter{BorrowerID: 3},
+		},
+		{
+			name:    "borrower granted loan.read_all sees every loan",
+			grants:  grants,
+			subject: policy.Subject{ID: 3, Role: constant.RoleBorrower},
+			want:    dto.LoanFilter{},
+		},
+		{
+			name:    "investor sees open and invested loans",
+			subject: policy.Subject{ID: 4, Role: constant.RoleInvestor},
+			want:    dto.LoanFilter{InvestorID: 4, OpenStates: []string{constant.Approved}},
+		},
+		{
+			name:    "unknown role",
+			subject: policy.Subject{ID: 5, Role: "GUEST"},
+			wantErr: constant.ErrLoanForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grants := tt.grants
+			if grants == nil {
+				grants = permission.Defaults
+			}
+			got, err := policy.NewLoanPolicy(nil, permission.NewStatic(grants)).
+				ListFilter(context.Background(), tt.subject)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoanPolicy_CanView(t *testing.T) {
+	mockLoanRepo := new(mocks.LoanRepoInterface)
+
+	disbursed := &domians.Loan{ID: 1, BorrowerID: 3, State: constant.Disbursed}
+	approved := &domians.Loan{ID: 2, BorrowerID: 3, State: constant.Approved}
+	investments := []domians.LoanInvestor{
+		{ID: 10, LoanID: 1, InvestorID: 4, Status: constant.InvestmentActive},
+	}
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		subject      policy.Subject
+		loan         *domians.Loan
+		wantErr      error
+	}{
+		{
+			name:         "admin",
+			mockBehavior: func() {},
+			subject:      policy.Subject{ID: 1, Role: constant.RoleAdmin},
+			loan:         disbursed,
+		},
+		{
+			name:         "staff",
+			mockBehavior: func() {},
+			subject:      policy.Subject{ID: 2, Role: constant.RoleStaff},
+			loan:         disbursed,
+		},
+		{
+			name:         "borrower owns the loan",
+			mockBehavior: func() {},
+			subject:      policy.Subject{ID: 3, Role: constant.RoleBorrower},
+			loan:         disbursed,
+		},
+		{
+			name:         "borrower of another loan",
+			mockBehavior: func() {},
+			subject:      policy.Subject{ID: 9, Role: constant.RoleBorrower},
+			loan:         approved,
+			wantErr:      constant.ErrLoanNotOwned,
+		},
+		{
+			name:         "investor on an open loan",
+			mockBehavior: func() {},
+			subject:      policy.Subject{ID: 9, Role: constant.RoleInvestor},
+			loan:         approved,
+		},
+		{
+			name: "investor invested in the loan",
+			mockBehavior: func() {
+				mockLoanRepo.On("GetLoanInvestors", mock.Anything, uint(1)).
+					Return(investments, nil).Once()
+			},
+			subject: policy.Subject{ID: 4, Role: constant.RoleInvestor},
+			loan:    disbursed,
+		},
+		{
+			name: "investor did not invest in the loan",
+			mockBehavior: func() {
+				mockLoanRepo.On("GetLoanInvestors", mock.Anything, uint(1)).
+					Return(investments, nil).Once()
+			},
+			subject: policy.Subject{ID: 9, Role: constant.RoleInvestor},
+			loan:    disbursed,
+			wantErr: constant.ErrLoanNotOwned,
+		},
+		{
+			name: "investments cannot be read",
+			mockBehavior: func() {
+				mockLoanRepo.On("GetLoanInvestors", mock.Anything, uint(1)).
+					Return(nil, errors.New("db down")).Once()
+			},
+			subject: policy.Subject{ID: 4, Role: constant.RoleInvestor},
+			loan:    disbursed,
+			wantErr: errors.New("db down"),
+		},
+		{
+			name:         "unknown role",
+			mockBehavior: func() {},
+			subject:      policy.Subject{ID: 4, Role: "GUEST"},
+			loan:         approved,
+			wantErr:      constant.ErrLoanForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			err := policy.NewLoanPolicy(mockLoanRepo, permission.NewStatic(permission.Defaults)).
+				CanView(context.Background(), tt.subject, tt.loan)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockLoanRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package revocation
+
+import (
+	"context"
+	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"time"
+)
+
+type (
+	// DatabaseDenylist shares the denylist between every instance of the
+	// API through the revoked_tokens and user_revocations tables.
+	DatabaseDenylist struct {
+		store Repository.RevocationRepoInterface
+	}
+)
+
+func NewDatabaseDenylist(store Repository.RevocationRepoInterface) Denylist {
+	return &DatabaseDenylist{
+		store: store,
+	}
+}
+
+func (d DatabaseDenylist) Revoke(ctx context.Context, token Token) error {
+	return d.store.StoreRevokedToken(ctx, &domians.RevokedToken{
+		TokenID:   token.ID,
+		UserID:    token.UserID,
+		ExpiresAt: token.ExpiresAt,
+		CreatedAt: time.Now(),
+	})
+}
+
+func (d DatabaseDenylist) RevokeUser(ctx context.Context, userID uint, revokedAt time.Time) error {
+	return d.store.StoreUserRevocation(ctx, &domians.UserRevocation{
+		UserID:    userID,
+		RevokedAt: revokedAt,
+		ExpiresAt: userRevocationExpiry(revokedAt),
+	})
+}
+
+func (d DatabaseDenylist) IsRevoked(ctx context.Context, token Token) (bool, error) {
+	revoked, err := d.store.GetRevokedToken(ctx, token.ID)
+	if err != nil || revoked != nil {
+		return revoked != nil, err
+	}
+	revocation, err := d.store.GetUserRevocation(ctx, token.UserID)
+	if err != nil || revocation == nil {
+		return false, err
+	}
+	return issuedBefore(token, revocation.RevokedAt), nil
+}
+
+func (d DatabaseDenylist) Purge(ctx context.Context, now time.Time) error {
+	return d.store.DeleteExpiredRevocations(ctx, now)
+}
@@ -0,0 +1,61 @@
+package revocation
+
+import (
+	"context"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	"log/slog"
+	"time"
+)
+
+type (
+	// Token is the access token checked against the denylist, ID is its jti
+	// claim.
+	Token struct {
+		ID        string
+		UserID    uint
+		IssuedAt  time.Time
+		ExpiresAt time.Time
+	}
+
+	// Denylist rejects access tokens before their expiry. Entries are only
+	// kept as long as the tokens they reject could be used.
+	Denylist interface {
+		// Revoke rejects the token until it expires.
+		Revoke(ctx context.Context, token Token) error
+		// RevokeUser rejects every token of the user issued up to revokedAt.
+		RevokeUser(ctx context.Context, userID uint, revokedAt time.Time) error
+		IsRevoked(ctx context.Context, token Token) (bool, error)
+		// Purge drops the entries of tokens expired at now.
+		Purge(ctx context.Context, now time.Time) error
+	}
+)
+
+// Run purges the denylist every interval until ctx is done.
+func Run(ctx context.Context, denylist Denylist, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := denylist.Purge(ctx, time.Now())
+		if err != nil {
+			slog.ErrorContext(ctx, "revocation.Run", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// issuedBefore compares in microseconds like the iat claim and the
+// revoked_at column, a token issued in the same microsecond as the
+// revocation is rejected with it.
+func issuedBefore(token Token, revokedAt time.Time) bool {
+	return !token.IssuedAt.Truncate(time.Microsecond).After(revokedAt.Truncate(time.Microsecond))
+}
+
+// userRevocationExpiry is when every token issued before revokedAt has
+// expired.
+func userRevocationExpiry(revokedAt time.Time) time.Time {
+	return revokedAt.Add(constant.AccessTokenTTL)
+}
@@ -0,0 +1,64 @@
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type (
+	// MemoryDenylist keeps the denylist in the process, revocations are lost
+	// on restart and not shared between instances.
+	MemoryDenylist struct {
+		mu     sync.RWMutex
+		tokens map[string]time.Time
+		users  map[uint]time.Time
+	}
+)
+
+func NewMemoryDenylist() Denylist {
+	return &MemoryDenylist{
+		tokens: make(map[string]time.Time),
+		users:  make(map[uint]time.Time),
+	}
+}
+
+func (m *MemoryDenylist) Revoke(_ context.Context, token Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens[token.ID] = token.ExpiresAt
+	return nil
+}
+
+func (m *MemoryDenylist) RevokeUser(_ context.Context, userID uint, revokedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users[userID] = revokedAt
+	return nil
+}
+
+func (m *MemoryDenylist) IsRevoked(_ context.Context, token Token) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if _, ok := m.tokens[token.ID]; ok {
+		return true, nil
+	}
+	revokedAt, ok := m.users[token.UserID]
+	return ok && issuedBefore(token, revokedAt), nil
+}
+
+func (m *MemoryDenylist) Purge(_ context.Context, now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, expiresAt := range m.tokens {
+		if !expiresAt.After(now) {
+			delete(m.tokens, id)
+		}
+	}
+	for userID, revokedAt := range m.users {
+		if !userRevocationExpiry(revokedAt).After(now) {
+			delete(m.users, userID)
+		}
+	}
+	return nil
+}
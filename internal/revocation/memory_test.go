@@ -0,0 +1,80 @@
+package revocation_test
+
+import (
+	"context"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	"github.com/bowoBp/LoanFlow/internal/revocation"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMemoryDenylist_IsRevoked(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	denylist := revocation.NewMemoryDenylist()
+	_ = denylist.Revoke(ctx, revocation.Token{ID: "logged-out", UserID: 1, ExpiresAt: now.Add(time.Hour)})
+	_ = denylist.RevokeUser(ctx, 2, now)
+
+	tests := []struct {
+		name  string
+		token revocation.Token
+		want  bool
+	}{
+		{
+			name:  "Revoked - logged out token",
+			token: revocation.Token{ID: "logged-out", UserID: 1, IssuedAt: now.Add(-time.Minute)},
+			want:  true,
+		},
+		{
+			name:  "Revoked - issued before the user was revoked",
+			token: revocation.Token{ID: "old", UserID: 2, IssuedAt: now.Add(-time.Minute)},
+			want:  true,
+		},
+		{
+			name:  "Revoked - issued in the same second as the revocation",
+			token: revocation.Token{ID: "same-second", UserID: 2, IssuedAt: now},
+			want:  true,
+		},
+		{
+			name:  "Valid - issued after the user was revoked",
+			token: revocation.Token{ID: "new", UserID: 2, IssuedAt: now.Add(time.Second)},
+		},
+		{
+			name:  "Valid - issued later in the second of the revocation",
+			token: revocation.Token{ID: "next", UserID: 2, IssuedAt: now.Add(time.Millisecond)},
+		},
+		{
+			name:  "Valid - other token of the user",
+			token: revocation.Token{ID: "other", UserID: 1, IssuedAt: now.Add(-time.Minute)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := denylist.IsRevoked(ctx, tt.token)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMemoryDenylist_Purge(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	denylist := revocation.NewMemoryDenylist()
+	_ = denylist.Revoke(ctx, revocation.Token{ID: "logged-out", UserID: 1, ExpiresAt: now.Add(time.Hour)})
+	_ = denylist.RevokeUser(ctx, 2, now)
+	old := revocation.Token{ID: "old", UserID: 2, IssuedAt: now.Add(-time.Minute)}
+
+	assert.NoError(t, denylist.Purge(ctx, now.Add(2*time.Hour)))
+	revoked, _ := denylist.IsRevoked(ctx, revocation.Token{ID: "logged-out", UserID: 1})
+	assert.False(t, revoked)
+	revoked, _ = denylist.IsRevoked(ctx, old)
+	assert.True(t, revoked, "user revocation is kept until the tokens it rejects expire")
+
+	assert.NoError(t, denylist.Purge(ctx, now.Add(constant.AccessTokenTTL)))
+	revoked, _ = denylist.IsRevoked(ctx, old)
+	assert.False(t, revoked)
+}
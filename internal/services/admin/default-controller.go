@@ -0,0 +1,335 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/dto"
+	"github.com/bowoBp/LoanFlow/internal/i18n"
+	"time"
+)
+
+type (
+	Controller struct {
+		Uc UsecaseInterface
+	}
+
+	ControllerInterface interface {
+		CreateUser(
+			ctx context.Context,
+			actorID uint,
+			payload CreateUserRequest,
+		) (*dto.Response, error)
+		GetUsers(
+			ctx context.Context,
+		) (*dto.Response, error)
+		UpdateRole(
+			ctx context.Context,
+			userID, actorID uint,
+			payload UpdateRoleRequest,
+		) (*dto.Response, error)
+		GetRoleAudits(
+			ctx context.Context,
+			userID uint,
+		) (*dto.Response, error)
+		RevokeTokens(
+			ctx context.Context,
+			userID uint,
+		) (*dto.Response, error)
+		UnlockLogin(
+			ctx context.Context,
+			userID, actorID uint,
+		) (*dto.Response, error)
+		GetAuthEvents(
+			ctx context.Context,
+			userID uint,
+		) (*dto.Response, error)
+		CreateAPIKey(
+			ctx context.Context,
+			actorID uint,
+			payload CreateAPIKeyRequest,
+		) (*dto.Response, error)
+		GetAPIKeys(
+			ctx context.Context,
+		) (*dto.Response, error)
+		RevokeAPIKey(
+			ctx context.Context,
+			keyID uint,
+		) (*dto.Response, error)
+		GetRolePermissions(
+			ctx context.Context,
+		) (*dto.Response, error)
+		UpdateRolePermissions(
+			ctx context.Context,
+			role string,
+			actorID uint,
+			payload UpdateRolePermissionsRequest,
+		) (*dto.Response, error)
+	}
+)
+
+func (ctrl Controller) CreateUser(
+	ctx context.Context,
+	actorID uint,
+	payload CreateUserRequest,
+) (*dto.Response, error) {
+	start := time.Now()
+	user, err := ctrl.Uc.CreateUser(ctx, actorID, payload)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		userResponse(*user),
+		"success.user_created",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) GetUsers(
+	ctx context.Context,
+) (*dto.Response, error) {
+	start := time.Now()
+	users, err := ctrl.Uc.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]UserResponse, len(users))
+	for i := range users {
+		result[i] = userResponse(users[i])
+	}
+	return i18n.Success(
+		ctx,
+		result,
+		"success.users_listed",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) UpdateRole(
+	ctx context.Context,
+	userID, actorID uint,
+	payload UpdateRoleRequest,
+) (*dto.Response, error) {
+	start := time.Now()
+	user, err := ctrl.Uc.UpdateRole(ctx, userID, actorID, payload)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		userResponse(*user),
+		"success.role_updated",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) GetRoleAudits(
+	ctx context.Context,
+	userID uint,
+) (*dto.Response, error) {
+	start := time.Now()
+	audits, err := ctrl.Uc.GetRoleAudits(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]RoleAuditResponse, len(audits))
+	for i, audit := range audits {
+		result[i] = RoleAuditResponse{
+			ID:           audit.ID,
+			ActorID:      audit.ActorID,
+			PreviousRole: audit.PreviousRole,
+			NewRole:      audit.NewRole,
+			Reason:       audit.Reason,
+			CreatedAt:    audit.CreatedAt,
+		}
+	}
+	return i18n.Success(
+		ctx,
+		result,
+		"success.role_audits_listed",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) RevokeTokens(
+	ctx context.Context,
+	userID uint,
+) (*dto.Response, error) {
+	start := time.Now()
+	err := ctrl.Uc.RevokeTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		nil,
+		"success.tokens_revoked",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) UnlockLogin(
+	ctx context.Context,
+	userID, actorID uint,
+) (*dto.Response, error) {
+	start := time.Now()
+	err := ctrl.Uc.UnlockLogin(ctx, userID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		nil,
+		"success.login_unlocked",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) GetAuthEvents(
+	ctx context.Context,
+	userID uint,
+) (*dto.Response, error) {
+	start := time.Now()
+	events, err := ctrl.Uc.GetAuthEvents(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		events,
+		"success.auth_events_listed",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) CreateAPIKey(
+	ctx context.Context,
+	actorID uint,
+	payload CreateAPIKeyRequest,
+) (*dto.Response, error) {
+	start := time.Now()
+	apiKey, key, err := ctrl.Uc.CreateAPIKey(ctx, actorID, payload)
+	if err != nil {
+		return nil, err
+	}
+	result := apiKeyResponse(*apiKey)
+	result.Key = key
+	return i18n.Success(
+		ctx,
+		result,
+		"success.api_key_created",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) GetAPIKeys(
+	ctx context.Context,
+) (*dto.Response, error) {
+	start := time.Now()
+	apiKeys, err := ctrl.Uc.GetAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]APIKeyResponse, len(apiKeys))
+	for i := range apiKeys {
+		result[i] = apiKeyResponse(apiKeys[i])
+	}
+	return i18n.Success(
+		ctx,
+		result,
+		"success.api_keys_listed",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) RevokeAPIKey(
+	ctx context.Context,
+	keyID uint,
+) (*dto.Response, error) {
+	start := time.Now()
+	err := ctrl.Uc.RevokeAPIKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		nil,
+		"success.api_key_revoked",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+// GetRolePermissions lists every role, a role without permissions is listed
+// with an empty list.
+func (ctrl Controller) GetRolePermissions(
+	ctx context.Context,
+) (*dto.Response, error) {
+	start := time.Now()
+	rows, err := ctrl.Uc.GetRolePermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]RolePermissionsResponse, len(constant.Roles))
+	for i, role := range constant.Roles {
+		result[i] = RolePermissionsResponse{Role: role, Permissions: make([]string, 0)}
+		for _, row := range rows {
+			if row.Role == role {
+				result[i].Permissions = append(result[i].Permissions, row.Permission)
+			}
+		}
+	}
+	return i18n.Success(
+		ctx,
+		result,
+		"success.role_permissions_listed",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) UpdateRolePermissions(
+	ctx context.Context,
+	role string,
+	actorID uint,
+	payload UpdateRolePermissionsRequest,
+) (*dto.Response, error) {
+	start := time.Now()
+	permissions, err := ctrl.Uc.UpdateRolePermissions(ctx, role, actorID, payload)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		RolePermissionsResponse{Role: role, Permissions: permissions},
+		"success.role_permissions_updated",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func userResponse(user domians.User) UserResponse {
+	return UserResponse{
+		ID:        user.ID,
+		UserName:  user.Name,
+		Email:     user.Email,
+		Phone:     user.Phone,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+func apiKeyResponse(apiKey domians.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		UserID:     apiKey.UserID,
+		Prefix:     apiKey.Prefix,
+		Scopes:     apiKey.Scopes,
+		CreatedBy:  apiKey.CreatedBy,
+		ExpiresAt:  apiKey.ExpiresAt,
+		LastUsedAt: apiKey.LastUsedAt,
+		RevokedAt:  apiKey.RevokedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
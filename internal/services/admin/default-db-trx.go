@@ -0,0 +1,131 @@
+package admin
+
+import (
+	"context"
+	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"gorm.io/gorm"
+	"time"
+)
+
+type (
+	DefaultAdminTransaction struct {
+		db             *gorm.DB
+		userRepo       Repository.UserRepoInterface
+		sessionRepo    Repository.SessionRepoInterface
+		permissionRepo Repository.PermissionRepoInterface
+	}
+
+	DefaultAdminTransactionInterface interface {
+		Begin() (DefaultAdminTransactionInterface, error)
+		End(err error) error
+		GetUserByID(
+			ctx context.Context,
+			id uint,
+		) (*domians.User, error)
+		StoreUser(
+			ctx context.Context,
+			user *domians.User,
+		) (*domians.User, error)
+		UpdateUser(
+			ctx context.Context,
+			user *domians.User,
+			updateData map[string]any,
+		) error
+		CreateRoleAudit(
+			ctx context.Context,
+			audit *domians.RoleAudit,
+		) error
+		RevokeSessionsByUserID(
+			ctx context.Context,
+			userID uint,
+			revokedAt time.Time,
+		) error
+		DeleteRolePermissions(
+			ctx context.Context,
+			role string,
+		) error
+		StoreRolePermissions(
+			ctx context.Context,
+			permissions []domians.RolePermission,
+		) error
+	}
+)
+
+func NewAdminTransaction(db *gorm.DB) DefaultAdminTransaction {
+	return DefaultAdminTransaction{
+		db: db,
+	}
+}
+
+func (repo DefaultAdminTransaction) GetUserByID(
+	ctx context.Context,
+	id uint,
+) (*domians.User, error) {
+	return repo.userRepo.GetUserByID(ctx, id)
+}
+
+func (repo DefaultAdminTransaction) StoreUser(
+	ctx context.Context,
+	user *domians.User,
+) (*domians.User, error) {
+	return repo.userRepo.StoreUser(ctx, user)
+}
+
+func (repo DefaultAdminTransaction) UpdateUser(
+	ctx context.Context,
+	user *domians.User,
+	updateData map[string]any,
+) error {
+	return repo.userRepo.UpdateUser(ctx, user, updateData)
+}
+
+func (repo DefaultAdminTransaction) CreateRoleAudit(
+	ctx context.Context,
+	audit *domians.RoleAudit,
+) error {
+	return repo.userRepo.CreateRoleAudit(ctx, audit)
+}
+
+func (repo DefaultAdminTransaction) RevokeSessionsByUserID(
+	ctx context.Context,
+	userID uint,
+	revokedAt time.Time,
+) error {
+	return repo.sessionRepo.RevokeSessionsByUserID(ctx, userID, revokedAt)
+}
+
+func (repo DefaultAdminTransaction) DeleteRolePermissions(
+	ctx context.Context,
+	role string,
+) error {
+	return repo.permissionRepo.DeleteRolePermissions(ctx, role)
+}
+
+func (repo DefaultAdminTransaction) StoreRolePermissions(
+	ctx context.Context,
+	permissions []domians.RolePermission,
+) error {
+	return repo.permissionRepo.StoreRolePermissions(ctx, permissions)
+}
+
+func (repo DefaultAdminTransaction) Begin() (DefaultAdminTransactionInterface, error) {
+	evoTrx := repo.db.Begin()
+	if err := evoTrx.Error; err != nil {
+		return DefaultAdminTransaction{}, err
+	}
+	newAdminTrx := &DefaultAdminTransaction{
+		db:             evoTrx,
+		userRepo:       Repository.NewUserRepo(evoTrx),
+		sessionRepo:    Repository.NewSessionRepo(evoTrx),
+		permissionRepo: Repository.NewPermissionRepo(evoTrx),
+	}
+	return newAdminTrx, nil
+}
+
+func (repo DefaultAdminTransaction) End(err error) error {
+	if err != nil {
+		return repo.db.Rollback().Error
+	}
+	return repo.db.Commit().Error
+}
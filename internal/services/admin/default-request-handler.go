@@ -0,0 +1,181 @@
+package admin
+
+import (
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type (
+	RequestHandler struct {
+		ctrl ControllerInterface
+	}
+)
+
+func (rh RequestHandler) CreateUser(ctx *gin.Context) {
+	var payload = CreateUserRequest{}
+	err := ctx.ShouldBind(&payload)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	id, _ := ctx.Get("id")
+	res, err := rh.ctrl.CreateUser(ctx, id.(uint), payload)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) GetUsers(ctx *gin.Context) {
+	res, err := rh.ctrl.GetUsers(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) UpdateRole(ctx *gin.Context) {
+	var payload = UpdateRoleRequest{}
+	err := ctx.ShouldBind(&payload)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	userID, err := strconv.ParseUint(ctx.Param("userId"), 10, 32)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	id, _ := ctx.Get("id")
+	res, err := rh.ctrl.UpdateRole(ctx, uint(userID), id.(uint), payload)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) GetRoleAudits(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.Param("userId"), 10, 32)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	res, err := rh.ctrl.GetRoleAudits(ctx, uint(userID))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) RevokeTokens(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.Param("userId"), 10, 32)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	res, err := rh.ctrl.RevokeTokens(ctx, uint(userID))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) UnlockLogin(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.Param("userId"), 10, 32)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	id, _ := ctx.Get("id")
+	res, err := rh.ctrl.UnlockLogin(ctx, uint(userID), id.(uint))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) GetAuthEvents(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.Param("userId"), 10, 32)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	res, err := rh.ctrl.GetAuthEvents(ctx, uint(userID))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) CreateAPIKey(ctx *gin.Context) {
+	var payload = CreateAPIKeyRequest{}
+	err := ctx.ShouldBind(&payload)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	id, _ := ctx.Get("id")
+	res, err := rh.ctrl.CreateAPIKey(ctx, id.(uint), payload)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) GetAPIKeys(ctx *gin.Context) {
+	res, err := rh.ctrl.GetAPIKeys(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) RevokeAPIKey(ctx *gin.Context) {
+	keyID, err := strconv.ParseUint(ctx.Param("keyId"), 10, 32)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	res, err := rh.ctrl.RevokeAPIKey(ctx, uint(keyID))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) GetRolePermissions(ctx *gin.Context) {
+	res, err := rh.ctrl.GetRolePermissions(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) UpdateRolePermissions(ctx *gin.Context) {
+	var payload = UpdateRolePermissionsRequest{}
+	err := ctx.ShouldBind(&payload)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	id, _ := ctx.Get("id")
+	res, err := rh.ctrl.UpdateRolePermissions(ctx, ctx.Param("role"), id.(uint), payload)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
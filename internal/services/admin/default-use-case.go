@@ -0,0 +1,498 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/permission"
+	"github.com/bowoBp/LoanFlow/internal/revocation"
+	"github.com/bowoBp/LoanFlow/pkg/reporter"
+	"github.com/bowoBp/LoanFlow/utils/helper"
+	"slices"
+	"time"
+)
+
+type (
+	Usecase struct {
+		UserRepo         Repository.UserRepoInterface
+		LoginAttemptRepo Repository.LoginAttemptRepoInterface
+		APIKeyRepo       Repository.APIKeyRepoInterface
+		PermissionRepo   Repository.PermissionRepoInterface
+		DbTransaction    Repository.TransactionUnit[DefaultAdminTransactionInterface]
+		Bcrypt           helper.BcryptInterface
+		Denylist         revocation.Denylist
+		Permissions      permission.Resolver
+		Reporter         reporter.Reporter
+	}
+
+	UsecaseInterface interface {
+		CreateUser(
+			ctx context.Context,
+			actorID uint,
+			payload CreateUserRequest,
+		) (*domians.User, error)
+		GetUsers(
+			ctx context.Context,
+		) ([]domians.User, error)
+		UpdateRole(
+			ctx context.Context,
+			userID, actorID uint,
+			payload UpdateRoleRequest,
+		) (*domians.User, error)
+		GetRoleAudits(
+			ctx context.Context,
+			userID uint,
+		) ([]domians.RoleAudit, error)
+		RevokeTokens(
+			ctx context.Context,
+			userID uint,
+		) error
+		UnlockLogin(
+			ctx context.Context,
+			userID, actorID uint,
+		) error
+		GetAuthEvents(
+			ctx context.Context,
+			userID uint,
+		) ([]domians.AuthEvent, error)
+		CreateAPIKey(
+			ctx context.Context,
+			actorID uint,
+			payload CreateAPIKeyRequest,
+		) (*domians.APIKey, string, error)
+		GetAPIKeys(
+			ctx context.Context,
+		) ([]domians.APIKey, error)
+		RevokeAPIKey(
+			ctx context.Context,
+			keyID uint,
+		) error
+		GetRolePermissions(
+			ctx context.Context,
+		) ([]domians.RolePermission, error)
+		UpdateRolePermissions(
+			ctx context.Context,
+			role string,
+			actorID uint,
+			payload UpdateRolePermissionsRequest,
+		) ([]string, error)
+	}
+)
+
+// CreateUser creates a user with any role, the role given is audited. The
+// admin vouches for the email, so the user starts verified and can create or
+// fund loans without a verification link.
+func (uc Usecase) CreateUser(
+	ctx context.Context,
+	actorID uint,
+	payload CreateUserRequest,
+) (user *domians.User, err error) {
+	if payload.Password == "" || payload.UserName == "" {
+		return nil, constant.ErrRegister
+	}
+	if !slices.Contains(constant.Roles, payload.Role) {
+		return nil, constant.ErrRole
+	}
+	emailDuplicate, err := uc.UserRepo.CheckEmail(ctx, payload.Email)
+	if err != nil {
+		return nil, err
+	}
+	if emailDuplicate != nil {
+		return nil, constant.DuplicateEmail
+	}
+
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func(tx DefaultAdminTransactionInterface, err *error) {
+		// recover panic
+		if r := recover(); r != nil {
+			*err = uc.Reporter.Recover(ctx, r)
+		}
+		// end transaction (rollback or commit)
+		errTrx := tx.End(*err)
+		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
+		}
+	}(dbTrx, &err)
+
+	now := time.Now()
+	user, err = dbTrx.StoreUser(
+		ctx,
+		&domians.User{
+			Name:            payload.UserName,
+			PasswordHash:    uc.Bcrypt.HasPass(payload.Password),
+			Email:           payload.Email,
+			Phone:           payload.Phone,
+			Role:            payload.Role,
+			EmailVerifiedAt: &now,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	err = dbTrx.CreateRoleAudit(ctx, &domians.RoleAudit{
+		UserID:    user.ID,
+		ActorID:   actorID,
+		NewRole:   payload.Role,
+		Reason:    payload.Reason,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (uc Usecase) GetUsers(
+	ctx context.Context,
+) ([]domians.User, error) {
+	return uc.UserRepo.GetAllUser(ctx)
+}
+
+// UpdateRole promotes or demotes a user and audits the change. The sessions
+// and access tokens of the user are revoked so the new role is used from the
+// next login.
+func (uc Usecase) UpdateRole(
+	ctx context.Context,
+	userID, actorID uint,
+	payload UpdateRoleRequest,
+) (user *domians.User, err error) {
+	if !slices.Contains(constant.Roles, payload.Role) {
+		return nil, constant.ErrRole
+	}
+	if userID == actorID {
+		return nil, constant.ErrRoleSelf
+	}
+
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func(tx DefaultAdminTransactionInterface, err *error) {
+		// recover panic
+		if r := recover(); r != nil {
+			*err = uc.Reporter.Recover(ctx, r)
+		}
+		// end transaction (rollback or commit)
+		errTrx := tx.End(*err)
+		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
+		}
+	}(dbTrx, &err)
+
+	user, err = dbTrx.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, constant.ErrUserNotFound
+	}
+	if user.Role == payload.Role {
+		return nil, constant.ErrRoleUnchanged
+	}
+
+	now := time.Now()
+	previousRole := user.Role
+	err = dbTrx.UpdateUser(ctx, user, map[string]any{
+		"role":       payload.Role,
+		"updated_at": now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = dbTrx.CreateRoleAudit(ctx, &domians.RoleAudit{
+		UserID:       user.ID,
+		ActorID:      actorID,
+		PreviousRole: previousRole,
+		NewRole:      payload.Role,
+		Reason:       payload.Reason,
+		CreatedAt:    now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = dbTrx.RevokeSessionsByUserID(ctx, user.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.Denylist.RevokeUser(ctx, user.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	user.Role = payload.Role
+	user.UpdatedAt = now
+	return user, nil
+}
+
+func (uc Usecase) GetRoleAudits(
+	ctx context.Context,
+	userID uint,
+) ([]domians.RoleAudit, error) {
+	user, err := uc.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, constant.ErrUserNotFound
+	}
+	return uc.UserRepo.GetRoleAudits(ctx, userID)
+}
+
+// RevokeTokens logs the user out of every device, the sessions and the
+// access tokens issued so far are revoked.
+func (uc Usecase) RevokeTokens(
+	ctx context.Context,
+	userID uint,
+) (err error) {
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return err
+	}
+	defer func(tx DefaultAdminTransactionInterface, err *error) {
+		// recover panic
+		if r := recover(); r != nil {
+			*err = uc.Reporter.Recover(ctx, r)
+		}
+		// end transaction (rollback or commit)
+		errTrx := tx.End(*err)
+		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
+		}
+	}(dbTrx, &err)
+
+	user, err := dbTrx.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return constant.ErrUserNotFound
+	}
+	now := time.Now()
+	err = dbTrx.RevokeSessionsByUserID(ctx, user.ID, now)
+	if err != nil {
+		return err
+	}
+	return uc.Denylist.RevokeUser(ctx, user.ID, now)
+}
+
+// UnlockLogin lifts the lock of the account after failed logins before it
+// expires, with the locks of the IP addresses the user failed to login from
+// while a lock could still hold. The unlock is audited.
+func (uc Usecase) UnlockLogin(
+	ctx context.Context,
+	userID, actorID uint,
+) error {
+	user, err := uc.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return constant.ErrUserNotFound
+	}
+	now := time.Now()
+	err = uc.LoginAttemptRepo.DeleteLoginThrottle(ctx, constant.ThrottleAccount, helper.NormalizeEmail(user.Email))
+	if err != nil {
+		return err
+	}
+	ips, err := uc.LoginAttemptRepo.GetFailedLoginIPs(ctx, user.ID, now.Add(-constant.LoginLockMax))
+	if err != nil {
+		return err
+	}
+	for _, ip := range ips {
+		err = uc.LoginAttemptRepo.DeleteLoginThrottle(ctx, constant.ThrottleIP, ip)
+		if err != nil {
+			return err
+		}
+	}
+	return uc.LoginAttemptRepo.CreateAuthEvent(ctx, &domians.AuthEvent{
+		UserID:    &user.ID,
+		ActorID:   &actorID,
+		Event:     constant.AuthEventLoginUnlocked,
+		Email:     user.Email,
+		CreatedAt: now,
+	})
+}
+
+func (uc Usecase) GetAuthEvents(
+	ctx context.Context,
+	userID uint,
+) ([]domians.AuthEvent, error) {
+	user, err := uc.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, constant.ErrUserNotFound
+	}
+	return uc.LoginAttemptRepo.GetAuthEvents(ctx, userID)
+}
+
+// CreateAPIKey creates a key acting for the user of the payload within its
+// scopes. The key is returned once, only its hash is stored.
+func (uc Usecase) CreateAPIKey(
+	ctx context.Context,
+	actorID uint,
+	payload CreateAPIKeyRequest,
+) (*domians.APIKey, string, error) {
+	if payload.Name == "" || len(payload.Scopes) == 0 {
+		return nil, "", constant.ErrAPIKeyScopes
+	}
+	for _, scope := range payload.Scopes {
+		if !slices.Contains(constant.APIKeyScopes, scope) {
+			return nil, "", constant.ErrAPIKeyScopes
+		}
+	}
+	now := time.Now()
+	if payload.ExpiresAt != nil && !payload.ExpiresAt.After(now) {
+		return nil, "", constant.ErrAPIKeyExpiry
+	}
+	user, err := uc.UserRepo.GetUserByID(ctx, payload.UserID)
+	if err != nil {
+		return nil, "", err
+	}
+	if user == nil {
+		return nil, "", constant.ErrUserNotFound
+	}
+
+	key, prefix, err := helper.GenerateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+	apiKey := &domians.APIKey{
+		Name:      payload.Name,
+		UserID:    user.ID,
+		Prefix:    prefix,
+		KeyHash:   helper.HashAPIKey(key),
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(payload.Scopes))),
+		CreatedBy: actorID,
+		ExpiresAt: payload.ExpiresAt,
+		CreatedAt: now,
+	}
+	err = uc.APIKeyRepo.StoreAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return apiKey, key, nil
+}
+
+func (uc Usecase) GetAPIKeys(
+	ctx context.Context,
+) ([]domians.APIKey, error) {
+	return uc.APIKeyRepo.GetAPIKeys(ctx)
+}
+
+// RevokeAPIKey stops the key from being accepted, a revoked key is kept for
+// the record.
+func (uc Usecase) RevokeAPIKey(
+	ctx context.Context,
+	keyID uint,
+) error {
+	apiKey, err := uc.APIKeyRepo.GetAPIKeyByID(ctx, keyID)
+	if err != nil {
+		return err
+	}
+	if apiKey == nil {
+		return constant.ErrAPIKeyNotFound
+	}
+	if apiKey.RevokedAt != nil {
+		return nil
+	}
+	return uc.APIKeyRepo.UpdateAPIKey(ctx, apiKey, map[string]any{
+		"revoked_at": time.Now(),
+	})
+}
+
+func (uc Usecase) GetRolePermissions(
+	ctx context.Context,
+) ([]domians.RolePermission, error) {
+	return uc.PermissionRepo.GetRolePermissions(ctx)
+}
+
+// UpdateRolePermissions replaces the permissions of the role. Tokens are not
+// revoked, permissions are checked on every request and the new ones are
+// used as soon as the grants are read again.
+func (uc Usecase) UpdateRolePermissions(
+	ctx context.Context,
+	role string,
+	actorID uint,
+	payload UpdateRolePermissionsRequest,
+) ([]string, error) {
+	if !slices.Contains(constant.Roles, role) {
+		return nil, constant.ErrRole
+	}
+	for _, perm := range payload.Permissions {
+		if !slices.Contains(constant.Permissions, perm) {
+			return nil, constant.ErrPermission
+		}
+	}
+	// admin tidak boleh mengunci dirinya sendiri dari pengaturan permission
+	if role == constant.RoleAdmin && !slices.Contains(payload.Permissions, constant.PermUserManage) {
+		return nil, constant.ErrPermissionLockout
+	}
+
+	permissions := slices.Compact(slices.Sorted(slices.Values(payload.Permissions)))
+	err := uc.replaceRolePermissions(ctx, role, actorID, permissions)
+	if err != nil {
+		return nil, err
+	}
+	// the grants are read again once the transaction is committed
+	uc.Permissions.Invalidate()
+	return permissions, nil
+}
+
+func (uc Usecase) replaceRolePermissions(
+	ctx context.Context,
+	role string,
+	actorID uint,
+	permissions []string,
+) (err error) {
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return err
+	}
+	defer func(tx DefaultAdminTransactionInterface, err *error) {
+		// recover panic
+		if r := recover(); r != nil {
+			*err = uc.Reporter.Recover(ctx, r)
+		}
+		// end transaction (rollback or commit)
+		errTrx := tx.End(*err)
+		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
+		}
+	}(dbTrx, &err)
+
+	err = dbTrx.DeleteRolePermissions(ctx, role)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	rows := make([]domians.RolePermission, len(permissions))
+	for i, perm := range permissions {
+		rows[i] = domians.RolePermission{
+			Role:       role,
+			Permission: perm,
+			CreatedBy:  &actorID,
+			CreatedAt:  now,
+		}
+	}
+	return dbTrx.StoreRolePermissions(ctx, rows)
+}
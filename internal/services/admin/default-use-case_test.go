@@ -0,0 +1,521 @@
+package admin_test
+
+import (
+	"context"
+	"errors"
+	"github.com/bowoBp/LoanFlow/internal/adapter/mocks"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/services/admin"
+	"github.com/bowoBp/LoanFlow/utils/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUsecase_CreateUser(t *testing.T) {
+	mockTransaction := new(mocks.DefaultAdminTransactionInterface)
+	mockUserRepo := new(mocks.UserRepoInterface)
+	mockBcrypt := new(mocks.BcryptInterface)
+
+	actorID := uint(1)
+	payload := admin.CreateUserRequest{
+		UserName: "staff",
+		Password: "secret",
+		Email:    "staff@loanflow.id",
+		Role:     constant.RoleStaff,
+		Reason:   "new operations hire",
+	}
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		payload      admin.CreateUserRequest
+		wantErr      bool
+		expectedErr  error
+	}{
+		{
+			name: "Success - staff created and audited",
+			mockBehavior: func() {
+				mockUserRepo.On("CheckEmail", mock.Anything, payload.Email).Return(nil, nil).Once()
+				mockBcrypt.On("HasPass", payload.Password).Return("hashed").Once()
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("StoreUser", mock.Anything, mock.MatchedBy(func(user *domians.User) bool {
+					// created by an admin, the email does not need a verification link
+					return user.Role == constant.RoleStaff && user.PasswordHash == "hashed" &&
+						user.EmailVerifiedAt != nil
+				})).Return(&domians.User{ID: 7, Role: constant.RoleStaff}, nil).Once()
+				mockTransaction.On("CreateRoleAudit", mock.Anything, mock.MatchedBy(func(audit *domians.RoleAudit) bool {
+					return audit.UserID == 7 && audit.ActorID == actorID &&
+						audit.PreviousRole == "" && audit.NewRole == constant.RoleStaff &&
+						audit.Reason == payload.Reason
+				})).Return(nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+			},
+			payload: payload,
+		},
+		{
+			name: "Error - audit fails rolls back",
+			mockBehavior: func() {
+				mockUserRepo.On("CheckEmail", mock.Anything, payload.Email).Return(nil, nil).Once()
+				mockBcrypt.On("HasPass", payload.Password).Return("hashed").Once()
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("StoreUser", mock.Anything, mock.Anything).
+					Return(&domians.User{ID: 7, Role: constant.RoleStaff}, nil).Once()
+				mockTransaction.On("CreateRoleAudit", mock.Anything, mock.Anything).
+					Return(errors.New("audit failed")).Once()
+				mockTransaction.On("End", mock.MatchedBy(func(e error) bool {
+					return e != nil && e.Error() == "audit failed"
+				})).Return(nil).Once()
+			},
+			payload:     payload,
+			wantErr:     true,
+			expectedErr: errors.New("audit failed"),
+		},
+		{
+			name: "Error - duplicate email",
+			mockBehavior: func() {
+				mockUserRepo.On("CheckEmail", mock.Anything, payload.Email).
+					Return(&domians.User{ID: 3}, nil).Once()
+			},
+			payload:     payload,
+			wantErr:     true,
+			expectedErr: constant.DuplicateEmail,
+		},
+		{
+			name:         "Error - unknown role",
+			mockBehavior: func() {},
+			payload: admin.CreateUserRequest{
+				UserName: "root",
+				Password: "secret",
+				Role:     "ROOT",
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrRole,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := admin.Usecase{
+				UserRepo:      mockUserRepo,
+				DbTransaction: mockTransaction,
+				Bcrypt:        mockBcrypt,
+			}
+			got, err := uc.CreateUser(context.Background(), actorID, tt.payload)
+
+			if tt.wantErr {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, uint(7), got.ID)
+			}
+
+			mockTransaction.AssertExpectations(t)
+			mockUserRepo.AssertExpectations(t)
+			mockBcrypt.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsecase_UpdateRole(t *testing.T) {
+	mockTransaction := new(mocks.DefaultAdminTransactionInterface)
+	mockDenylist := new(mocks.Denylist)
+
+	actorID := uint(1)
+	userID := uint(7)
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		userID       uint
+		payload      admin.UpdateRoleRequest
+		wantErr      bool
+		expectedErr  error
+	}{
+		{
+			name: "Success - borrower promoted to staff",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetUserByID", mock.Anything, userID).
+					Return(&domians.User{ID: userID, Role: constant.RoleBorrower}, nil).Once()
+				mockTransaction.On("UpdateUser", mock.Anything, mock.Anything, mock.MatchedBy(func(data map[string]any) bool {
+					return data["role"] == constant.RoleStaff
+				})).Return(nil).Once()
+				mockTransaction.On("CreateRoleAudit", mock.Anything, mock.MatchedBy(func(audit *domians.RoleAudit) bool {
+					return audit.UserID == userID && audit.ActorID == actorID &&
+						audit.PreviousRole == constant.RoleBorrower && audit.NewRole == constant.RoleStaff
+				})).Return(nil).Once()
+				mockTransaction.On("RevokeSessionsByUserID", mock.Anything, userID, mock.Anything).Return(nil).Once()
+				mockDenylist.On("RevokeUser", mock.Anything, userID, mock.Anything).Return(nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+			},
+			userID:  userID,
+			payload: admin.UpdateRoleRequest{Role: constant.RoleStaff, Reason: "joined operations"},
+		},
+		{
+			name: "Error - user not found",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetUserByID", mock.Anything, userID).Return(nil, nil).Once()
+				mockTransaction.On("End", constant.ErrUserNotFound).Return(nil).Once()
+			},
+			userID:      userID,
+			payload:     admin.UpdateRoleRequest{Role: constant.RoleStaff},
+			wantErr:     true,
+			expectedErr: constant.ErrUserNotFound,
+		},
+		{
+			name: "Error - role unchanged",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetUserByID", mock.Anything, userID).
+					Return(&domians.User{ID: userID, Role: constant.RoleStaff}, nil).Once()
+				mockTransaction.On("End", constant.ErrRoleUnchanged).Return(nil).Once()
+			},
+			userID:      userID,
+			payload:     admin.UpdateRoleRequest{Role: constant.RoleStaff},
+			wantErr:     true,
+			expectedErr: constant.ErrRoleUnchanged,
+		},
+		{
+			name:         "Error - admin changes own role",
+			mockBehavior: func() {},
+			userID:       actorID,
+			payload:      admin.UpdateRoleRequest{Role: constant.RoleBorrower},
+			wantErr:      true,
+			expectedErr:  constant.ErrRoleSelf,
+		},
+		{
+			name:         "Error - unknown role",
+			mockBehavior: func() {},
+			userID:       userID,
+			payload:      admin.UpdateRoleRequest{Role: "ROOT"},
+			wantErr:      true,
+			expectedErr:  constant.ErrRole,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := admin.Usecase{
+				DbTransaction: mockTransaction,
+				Denylist:      mockDenylist,
+			}
+			got, err := uc.UpdateRole(context.Background(), tt.userID, actorID, tt.payload)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.payload.Role, got.Role)
+			}
+
+			mockTransaction.AssertExpectations(t)
+			mockDenylist.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsecase_RevokeTokens(t *testing.T) {
+	mockTransaction := new(mocks.DefaultAdminTransactionInterface)
+	mockDenylist := new(mocks.Denylist)
+
+	userID := uint(7)
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		wantErr      bool
+		expectedErr  error
+	}{
+		{
+			name: "Success - sessions and access tokens revoked",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetUserByID", mock.Anything, userID).
+					Return(&domians.User{ID: userID}, nil).Once()
+				mockTransaction.On("RevokeSessionsByUserID", mock.Anything, userID, mock.Anything).Return(nil).Once()
+				mockDenylist.On("RevokeUser", mock.Anything, userID, mock.Anything).Return(nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+			},
+		},
+		{
+			name: "Error - user not found",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetUserByID", mock.Anything, userID).Return(nil, nil).Once()
+				mockTransaction.On("End", constant.ErrUserNotFound).Return(nil).Once()
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := admin.Usecase{
+				DbTransaction: mockTransaction,
+				Denylist:      mockDenylist,
+			}
+			err := uc.RevokeTokens(context.Background(), userID)
+
+			if tt.wantErr {
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockTransaction.AssertExpectations(t)
+			mockDenylist.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsecase_UnlockLogin(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepoInterface)
+	mockLoginAttemptRepo := new(mocks.LoginAttemptRepoInterface)
+
+	actorID := uint(1)
+	userID := uint(7)
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		wantErr      bool
+		expectedErr  error
+	}{
+		{
+			name: "Success - account and IP throttles cleared and audited",
+			mockBehavior: func() {
+				mockUserRepo.On("GetUserByID", mock.Anything, userID).
+					Return(&domians.User{ID: userID, Email: "Budi@LoanFlow.id"}, nil).Once()
+				mockLoginAttemptRepo.On("DeleteLoginThrottle", mock.Anything, constant.ThrottleAccount, "budi@loanflow.id").
+					Return(nil).Once()
+				mockLoginAttemptRepo.On("GetFailedLoginIPs", mock.Anything, userID, mock.MatchedBy(func(since time.Time) bool {
+					return time.Since(since) >= constant.LoginLockMax
+				})).Return([]string{"203.0.113.7", "198.51.100.20"}, nil).Once()
+				mockLoginAttemptRepo.On("DeleteLoginThrottle", mock.Anything, constant.ThrottleIP, "203.0.113.7").
+					Return(nil).Once()
+				mockLoginAttemptRepo.On("DeleteLoginThrottle", mock.Anything, constant.ThrottleIP, "198.51.100.20").
+					Return(nil).Once()
+				mockLoginAttemptRepo.On("CreateAuthEvent", mock.Anything, mock.MatchedBy(func(event *domians.AuthEvent) bool {
+					return event.Event == constant.AuthEventLoginUnlocked &&
+						*event.UserID == userID && *event.ActorID == actorID
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name: "Error - user not found",
+			mockBehavior: func() {
+				mockUserRepo.On("GetUserByID", mock.Anything, userID).Return(nil, nil).Once()
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := admin.Usecase{
+				UserRepo:         mockUserRepo,
+				LoginAttemptRepo: mockLoginAttemptRepo,
+			}
+			err := uc.UnlockLogin(context.Background(), userID, actorID)
+
+			if tt.wantErr {
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockUserRepo.AssertExpectations(t)
+			mockLoginAttemptRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsecase_CreateAPIKey(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepoInterface)
+	mockAPIKeyRepo := new(mocks.APIKeyRepoInterface)
+
+	actorID := uint(1)
+	past := time.Now().Add(-time.Hour)
+	payload := admin.CreateAPIKeyRequest{
+		Name:   "field-agent app",
+		UserID: 3,
+		Scopes: []string{constant.ScopeLoansApprove, constant.ScopeLoansRead, constant.ScopeLoansApprove},
+	}
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		payload      admin.CreateAPIKeyRequest
+		wantErr      bool
+		expectedErr  error
+	}{
+		{
+			name: "Success - only the hash is stored",
+			mockBehavior: func() {
+				mockUserRepo.On("GetUserByID", mock.Anything, uint(3)).
+					Return(&domians.User{ID: 3, Role: constant.RoleStaff}, nil).Once()
+				mockAPIKeyRepo.On("StoreAPIKey", mock.Anything, mock.MatchedBy(func(key *domians.APIKey) bool {
+					return key.UserID == 3 && key.CreatedBy == actorID && len(key.KeyHash) == 64 &&
+						assert.ObjectsAreEqual([]string{constant.ScopeLoansApprove, constant.ScopeLoansRead}, key.Scopes)
+				})).Return(nil).Once()
+			},
+			payload: payload,
+		},
+		{
+			name:         "Error - unknown scope",
+			mockBehavior: func() {},
+			payload: admin.CreateAPIKeyRequest{
+				Name:   "field-agent app",
+				UserID: 3,
+				Scopes: []string{"users:delete"},
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrAPIKeyScopes,
+		},
+		{
+			name:         "Error - expiry in the past",
+			mockBehavior: func() {},
+			payload: admin.CreateAPIKeyRequest{
+				Name:      "field-agent app",
+				UserID:    3,
+				Scopes:    []string{constant.ScopeLoansRead},
+				ExpiresAt: &past,
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrAPIKeyExpiry,
+		},
+		{
+			name: "Error - user not found",
+			mockBehavior: func() {
+				mockUserRepo.On("GetUserByID", mock.Anything, uint(3)).Return(nil, nil).Once()
+			},
+			payload:     payload,
+			wantErr:     true,
+			expectedErr: constant.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := admin.Usecase{
+				UserRepo:   mockUserRepo,
+				APIKeyRepo: mockAPIKeyRepo,
+			}
+			got, key, err := uc.CreateAPIKey(context.Background(), actorID, tt.payload)
+
+			if tt.wantErr {
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, strings.HasPrefix(key, got.Prefix))
+				assert.Equal(t, helper.HashAPIKey(key), got.KeyHash)
+			}
+
+			mockUserRepo.AssertExpectations(t)
+			mockAPIKeyRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsecase_UpdateRolePermissions(t *testing.T) {
+	mockTransaction := new(mocks.DefaultAdminTransactionInterface)
+	mockPermissions := new(mocks.Resolver)
+
+	actorID := uint(1)
+
+	tests := []struct {
+		name         string
+		role         string
+		payload      admin.UpdateRolePermissionsRequest
+		mockBehavior func()
+		want         []string
+		wantErr      bool
+		expectedErr  error
+	}{
+		{
+			name: "Success - permissions replaced and reloaded",
+			role: constant.RoleStaff,
+			payload: admin.UpdateRolePermissionsRequest{
+				Permissions: []string{constant.PermLoanRead, constant.PermLoanApprove, constant.PermLoanRead},
+			},
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("DeleteRolePermissions", mock.Anything, constant.RoleStaff).Return(nil).Once()
+				mockTransaction.On("StoreRolePermissions", mock.Anything, mock.MatchedBy(func(rows []domians.RolePermission) bool {
+					return len(rows) == 2 &&
+						rows[0].Permission == constant.PermLoanApprove &&
+						rows[1].Permission == constant.PermLoanRead &&
+						rows[0].Role == constant.RoleStaff &&
+						*rows[0].CreatedBy == actorID
+				})).Return(nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+				mockPermissions.On("Invalidate").Return().Once()
+			},
+			want: []string{constant.PermLoanApprove, constant.PermLoanRead},
+		},
+		{
+			name:         "Error - unknown role",
+			role:         "AUDITOR",
+			payload:      admin.UpdateRolePermissionsRequest{Permissions: []string{constant.PermLoanRead}},
+			mockBehavior: func() {},
+			wantErr:      true,
+			expectedErr:  constant.ErrRole,
+		},
+		{
+			name:         "Error - unknown permission",
+			role:         constant.RoleStaff,
+			payload:      admin.UpdateRolePermissionsRequest{Permissions: []string{"loan.delete"}},
+			mockBehavior: func() {},
+			wantErr:      true,
+			expectedErr:  constant.ErrPermission,
+		},
+		{
+			name:         "Error - admin would lose user.manage",
+			role:         constant.RoleAdmin,
+			payload:      admin.UpdateRolePermissionsRequest{Permissions: []string{constant.PermLoanRead}},
+			mockBehavior: func() {},
+			wantErr:      true,
+			expectedErr:  constant.ErrPermissionLockout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := admin.Usecase{
+				DbTransaction: mockTransaction,
+				Permissions:   mockPermissions,
+			}
+			permissions, err := uc.UpdateRolePermissions(context.Background(), tt.role, actorID, tt.payload)
+
+			if tt.wantErr {
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, permissions)
+			}
+
+			mockTransaction.AssertExpectations(t)
+			mockPermissions.AssertExpectations(t)
+		})
+	}
+}
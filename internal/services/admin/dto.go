@@ -0,0 +1,72 @@
+package admin
+
+import (
+	"time"
+)
+
+type (
+	CreateUserRequest struct {
+		UserName string `json:"userName" validate:"required,max=100"`
+		Password string `json:"password" validate:"required,min=8,max=72"`
+		Email    string `json:"email" validate:"required,email"`
+		Phone    string `json:"phone" validate:"required,phone"`
+		Role     string `json:"role" validate:"required,role"`
+		Reason   string `json:"reason" validate:"max=500"`
+	}
+
+	UpdateRoleRequest struct {
+		Role   string `json:"role" validate:"required,role"`
+		Reason string `json:"reason" validate:"max=500"`
+	}
+
+	UserResponse struct {
+		ID        uint      `json:"id"`
+		UserName  string    `json:"userName"`
+		Email     string    `json:"email"`
+		Phone     string    `json:"phone"`
+		Role      string    `json:"role"`
+		CreatedAt time.Time `json:"createdAt"`
+		UpdatedAt time.Time `json:"updatedAt"`
+	}
+
+	RoleAuditResponse struct {
+		ID           uint      `json:"id"`
+		ActorID      uint      `json:"actorId"`
+		PreviousRole string    `json:"previousRole"`
+		NewRole      string    `json:"newRole"`
+		Reason       string    `json:"reason"`
+		CreatedAt    time.Time `json:"createdAt"`
+	}
+
+	CreateAPIKeyRequest struct {
+		Name      string     `json:"name" validate:"required,max=100"`
+		UserID    uint       `json:"userId" validate:"required"`
+		Scopes    []string   `json:"scopes" validate:"required,min=1,dive,api_key_scope"`
+		ExpiresAt *time.Time `json:"expiresAt"`
+	}
+
+	// APIKeyResponse carries the key only when it is created, it cannot be
+	// shown again.
+	APIKeyResponse struct {
+		ID         uint       `json:"id"`
+		Name       string     `json:"name"`
+		UserID     uint       `json:"userId"`
+		Prefix     string     `json:"prefix"`
+		Key        string     `json:"key,omitempty"`
+		Scopes     []string   `json:"scopes"`
+		CreatedBy  uint       `json:"createdBy"`
+		ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+		LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+		RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+		CreatedAt  time.Time  `json:"createdAt"`
+	}
+
+	UpdateRolePermissionsRequest struct {
+		Permissions []string `json:"permissions" validate:"required,dive,required"`
+	}
+
+	RolePermissionsResponse struct {
+		Role        string   `json:"role"`
+		Permissions []string `json:"permissions"`
+	}
+)
@@ -0,0 +1,135 @@
+package admin
+
+import (
+	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	"github.com/bowoBp/LoanFlow/internal/permission"
+	"github.com/bowoBp/LoanFlow/internal/revocation"
+	"github.com/bowoBp/LoanFlow/pkg/middleware"
+	"github.com/bowoBp/LoanFlow/pkg/reporter"
+	"github.com/bowoBp/LoanFlow/utils/helper"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type (
+	Router struct {
+		auth        middleware.AuthInterface
+		idempotency middleware.IdempotencyInterface
+		rh          *RequestHandler
+	}
+)
+
+func NewRoute(
+	db *gorm.DB,
+	auth middleware.AuthInterface,
+	bcrypt helper.BcryptInterface,
+	idempotency middleware.IdempotencyInterface,
+	denylist revocation.Denylist,
+	permissions permission.Resolver,
+	reporter reporter.Reporter,
+) *Router {
+	return &Router{
+		auth:        auth,
+		idempotency: idempotency,
+		rh: &RequestHandler{
+			ctrl: &Controller{
+				Uc: Usecase{
+					UserRepo:         Repository.NewUserRepo(db),
+					LoginAttemptRepo: Repository.NewLoginAttemptRepo(db),
+					APIKeyRepo:       Repository.NewAPIKeyRepo(db),
+					PermissionRepo:   Repository.NewPermissionRepo(db),
+					DbTransaction:    NewAdminTransaction(db),
+					Bcrypt:           bcrypt,
+					Denylist:         denylist,
+					Permissions:      permissions,
+					Reporter:         reporter,
+				},
+			},
+		},
+	}
+}
+
+func (r Router) Route(router *gin.RouterGroup) {
+	users := router.Group("admin/users")
+	apiKeys := router.Group("admin/api-keys")
+	roles := router.Group("admin/roles")
+
+	users.POST(
+		"/",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermUserManage),
+		r.idempotency.Handle(),
+		r.rh.CreateUser,
+	)
+	users.GET(
+		"/",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermUserManage),
+		r.rh.GetUsers,
+	)
+	users.PATCH(
+		"/:userId/role",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermUserManage),
+		r.idempotency.Handle(),
+		r.rh.UpdateRole,
+	)
+	users.GET(
+		"/:userId/role-audits",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermUserManage),
+		r.rh.GetRoleAudits,
+	)
+	users.POST(
+		"/:userId/revoke-tokens",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermUserManage),
+		r.rh.RevokeTokens,
+	)
+	users.POST(
+		"/:userId/unlock",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermUserManage),
+		r.rh.UnlockLogin,
+	)
+	users.GET(
+		"/:userId/auth-events",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermUserManage),
+		r.rh.GetAuthEvents,
+	)
+
+	apiKeys.POST(
+		"/",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermUserManage),
+		r.rh.CreateAPIKey,
+	)
+	apiKeys.GET(
+		"/",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermUserManage),
+		r.rh.GetAPIKeys,
+	)
+	apiKeys.DELETE(
+		"/:keyId",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermUserManage),
+		r.rh.RevokeAPIKey,
+	)
+
+	roles.GET(
+		"/",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermUserManage),
+		r.rh.GetRolePermissions,
+	)
+	roles.PUT(
+		"/:role/permissions",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermUserManage),
+		r.idempotency.Handle(),
+		r.rh.UpdateRolePermissions,
+	)
+}
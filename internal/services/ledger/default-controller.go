@@ -0,0 +1,243 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/dto"
+	"github.com/bowoBp/LoanFlow/internal/i18n"
+	"time"
+)
+
+type (
+	Controller struct {
+		Uc UsecaseInterface
+	}
+
+	ControllerInterface interface {
+		RequestTopUp(
+			ctx context.Context,
+			userID uint,
+			payload TopUpRequest,
+		) (*dto.Response, error)
+		GetTopUps(
+			ctx context.Context,
+			userID uint,
+			role, status string,
+			query dto.GetListQuery,
+		) (*dto.Response, error)
+		ConfirmTopUp(
+			ctx context.Context,
+			topUpID, userID uint,
+			payload ConfirmTopUpRequest,
+		) (*dto.Response, error)
+		RejectTopUp(
+			ctx context.Context,
+			topUpID, userID uint,
+			payload RejectTopUpRequest,
+		) (*dto.Response, error)
+		GetAccounts(
+			ctx context.Context,
+			userID uint,
+		) (*dto.Response, error)
+		GetStatement(
+			ctx context.Context,
+			accountID, userID uint,
+			role string,
+			query dto.GetListQuery,
+		) (*dto.Response, error)
+	}
+)
+
+func (ctrl Controller) RequestTopUp(
+	ctx context.Context,
+	userID uint,
+	payload TopUpRequest,
+) (*dto.Response, error) {
+	start := time.Now()
+	topUp, err := ctrl.Uc.RequestTopUp(ctx, userID, payload)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		topUpResponse(*topUp),
+		"success.top_up_requested",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) GetTopUps(
+	ctx context.Context,
+	userID uint,
+	role, status string,
+	query dto.GetListQuery,
+) (*dto.Response, error) {
+	start := time.Now()
+	if query.PerPage < 1 {
+		query.PerPage = 10
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	topUps, count, err := ctrl.Uc.GetTopUps(ctx, userID, role, status, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := TopUpListResponse{
+		Pagination: dto.PaginationResponse{
+			PerPage:     query.PerPage,
+			Total:       uint(count),
+			CurrentPage: query.Page,
+		},
+		TopUps: make([]TopUpResponse, len(topUps)),
+	}
+	result.Pagination.Evaluate()
+	for i := range topUps {
+		result.TopUps[i] = topUpResponse(topUps[i])
+	}
+
+	return i18n.Success(
+		ctx,
+		result,
+		"success.top_ups_listed",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) ConfirmTopUp(
+	ctx context.Context,
+	topUpID, userID uint,
+	payload ConfirmTopUpRequest,
+) (*dto.Response, error) {
+	start := time.Now()
+	res, err := ctrl.Uc.ConfirmTopUp(ctx, topUpID, userID, payload)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		res,
+		"success.wallet_topped_up",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) RejectTopUp(
+	ctx context.Context,
+	topUpID, userID uint,
+	payload RejectTopUpRequest,
+) (*dto.Response, error) {
+	start := time.Now()
+	topUp, err := ctrl.Uc.RejectTopUp(ctx, topUpID, userID, payload)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		topUpResponse(*topUp),
+		"success.top_up_rejected",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) GetAccounts(
+	ctx context.Context,
+	userID uint,
+) (*dto.Response, error) {
+	start := time.Now()
+	accounts, err := ctrl.Uc.GetAccounts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]AccountResponse, len(accounts))
+	for i := range accounts {
+		result[i] = accountResponse(accounts[i])
+	}
+	return i18n.Success(
+		ctx,
+		result,
+		"success.accounts_listed",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) GetStatement(
+	ctx context.Context,
+	accountID, userID uint,
+	role string,
+	query dto.GetListQuery,
+) (*dto.Response, error) {
+	start := time.Now()
+	if query.PerPage < 1 {
+		query.PerPage = 10
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	account, lines, count, err := ctrl.Uc.GetStatement(ctx, accountID, userID, role, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := StatementResponse{
+		Account: accountResponse(*account),
+		Pagination: dto.PaginationResponse{
+			PerPage:     query.PerPage,
+			Total:       uint(count),
+			CurrentPage: query.Page,
+		},
+		Lines: make([]StatementLineResponse, len(lines)),
+	}
+	result.Pagination.Evaluate()
+	for i, line := range lines {
+		result.Lines[i] = StatementLineResponse{
+			ID:           line.ID,
+			Debit:        line.Debit,
+			Credit:       line.Credit,
+			BalanceAfter: line.BalanceAfter,
+			CreatedAt:    line.CreatedAt,
+		}
+		if line.JournalEntry != nil {
+			result.Lines[i].EntryType = line.JournalEntry.Type
+			result.Lines[i].Reference = line.JournalEntry.Reference
+			result.Lines[i].Description = line.JournalEntry.Description
+		}
+	}
+
+	return i18n.Success(
+		ctx,
+		result,
+		"success.statement_listed",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func accountResponse(account domians.LedgerAccount) AccountResponse {
+	return AccountResponse{
+		ID:        account.ID,
+		Code:      account.Code,
+		Type:      account.Type,
+		LoanID:    account.LoanID,
+		Currency:  account.Currency,
+		Balance:   account.Balance,
+		UpdatedAt: account.UpdatedAt,
+	}
+}
+
+func topUpResponse(topUp domians.TopUp) TopUpResponse {
+	return TopUpResponse{
+		ID:               topUp.ID,
+		UserID:           topUp.UserID,
+		Currency:         topUp.Currency,
+		Amount:           topUp.Amount,
+		PaymentReference: topUp.PaymentReference,
+		Status:           topUp.Status,
+		JournalEntryID:   topUp.JournalEntryID,
+		ReviewedBy:       topUp.ReviewedBy,
+		ReviewedAt:       topUp.ReviewedAt,
+		Remarks:          topUp.Remarks,
+		CreatedAt:        topUp.CreatedAt,
+	}
+}
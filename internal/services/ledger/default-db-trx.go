@@ -0,0 +1,104 @@
+package ledger
+
+import (
+	"context"
+	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"gorm.io/gorm"
+)
+
+type (
+	DefaultLedgerTransaction struct {
+		db         *gorm.DB
+		ledgerRepo Repository.LedgerRepoInterface
+	}
+
+	DefaultLedgerTransactionInterface interface {
+		Begin() (DefaultLedgerTransactionInterface, error)
+		End(err error) error
+		GetOrCreateAccount(
+			ctx context.Context,
+			account *domians.LedgerAccount,
+		) (*domians.LedgerAccount, error)
+		UpdateLedgerAccount(
+			ctx context.Context,
+			account *domians.LedgerAccount,
+			updateData map[string]any,
+		) error
+		CreateJournalEntry(
+			ctx context.Context,
+			entry *domians.JournalEntry,
+		) error
+		GetTopUpByIDForUpdate(
+			ctx context.Context,
+			topUpID uint,
+		) (*domians.TopUp, error)
+		UpdateTopUp(
+			ctx context.Context,
+			topUp *domians.TopUp,
+			updateData map[string]any,
+		) error
+	}
+)
+
+func NewLedgerTransaction(db *gorm.DB) DefaultLedgerTransaction {
+	return DefaultLedgerTransaction{
+		db: db,
+	}
+}
+
+func (repo DefaultLedgerTransaction) GetOrCreateAccount(
+	ctx context.Context,
+	account *domians.LedgerAccount,
+) (*domians.LedgerAccount, error) {
+	return repo.ledgerRepo.GetOrCreateAccount(ctx, account)
+}
+
+func (repo DefaultLedgerTransaction) UpdateLedgerAccount(
+	ctx context.Context,
+	account *domians.LedgerAccount,
+	updateData map[string]any,
+) error {
+	return repo.ledgerRepo.UpdateLedgerAccount(ctx, account, updateData)
+}
+
+func (repo DefaultLedgerTransaction) CreateJournalEntry(
+	ctx context.Context,
+	entry *domians.JournalEntry,
+) error {
+	return repo.ledgerRepo.CreateJournalEntry(ctx, entry)
+}
+
+func (repo DefaultLedgerTransaction) GetTopUpByIDForUpdate(
+	ctx context.Context,
+	topUpID uint,
+) (*domians.TopUp, error) {
+	return repo.ledgerRepo.GetTopUpByIDForUpdate(ctx, topUpID)
+}
+
+func (repo DefaultLedgerTransaction) UpdateTopUp(
+	ctx context.Context,
+	topUp *domians.TopUp,
+	updateData map[string]any,
+) error {
+	return repo.ledgerRepo.UpdateTopUp(ctx, topUp, updateData)
+}
+
+func (repo DefaultLedgerTransaction) Begin() (DefaultLedgerTransactionInterface, error) {
+	evoTrx := repo.db.Begin()
+	if err := evoTrx.Error; err != nil {
+		return DefaultLedgerTransaction{}, err
+	}
+	newLedgerTrx := &DefaultLedgerTransaction{
+		db:         evoTrx,
+		ledgerRepo: Repository.NewLedgerRepo(evoTrx),
+	}
+	return newLedgerTrx, nil
+}
+
+func (repo DefaultLedgerTransaction) End(err error) error {
+	if err != nil {
+		return repo.db.Rollback().Error
+	}
+	return repo.db.Commit().Error
+}
@@ -0,0 +1,130 @@
+package ledger
+
+import (
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	"github.com/bowoBp/LoanFlow/internal/dto"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type (
+	RequestHandler struct {
+		ctrl ControllerInterface
+	}
+)
+
+func (rh RequestHandler) RequestTopUp(ctx *gin.Context) {
+	var payload = TopUpRequest{}
+	err := ctx.ShouldBind(&payload)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	id, _ := ctx.Get("id")
+	res, err := rh.ctrl.RequestTopUp(ctx, id.(uint), payload)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) GetTopUps(ctx *gin.Context) {
+	var query dto.GetListQuery
+	query.PerPage, _ = strconv.Atoi(ctx.Query("perPage"))
+	query.Page, _ = strconv.Atoi(ctx.Query("page"))
+
+	id, _ := ctx.Get("id")
+	role, _ := ctx.Get("userRole")
+	res, err := rh.ctrl.GetTopUps(
+		ctx,
+		id.(uint),
+		role.(string),
+		ctx.Query("status"),
+		query,
+	)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) ConfirmTopUp(ctx *gin.Context) {
+	var payload = ConfirmTopUpRequest{}
+	err := ctx.ShouldBind(&payload)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	topUpID, err := strconv.ParseUint(ctx.Param("topUpId"), 10, 32)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	id, _ := ctx.Get("id")
+	res, err := rh.ctrl.ConfirmTopUp(ctx, uint(topUpID), id.(uint), payload)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) RejectTopUp(ctx *gin.Context) {
+	var payload = RejectTopUpRequest{}
+	err := ctx.ShouldBind(&payload)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	topUpID, err := strconv.ParseUint(ctx.Param("topUpId"), 10, 32)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	id, _ := ctx.Get("id")
+	res, err := rh.ctrl.RejectTopUp(ctx, uint(topUpID), id.(uint), payload)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) GetAccounts(ctx *gin.Context) {
+	id, _ := ctx.Get("id")
+	res, err := rh.ctrl.GetAccounts(ctx, id.(uint))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) GetStatement(ctx *gin.Context) {
+	accountID, err := strconv.ParseUint(ctx.Param("accountId"), 10, 32)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	var query dto.GetListQuery
+	query.PerPage, _ = strconv.Atoi(ctx.Query("perPage"))
+	query.Page, _ = strconv.Atoi(ctx.Query("page"))
+
+	id, _ := ctx.Get("id")
+	role, _ := ctx.Get("userRole")
+	res, err := rh.ctrl.GetStatement(
+		ctx,
+		uint(accountID),
+		id.(uint),
+		role.(string),
+		query,
+	)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,287 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/dto"
+	"github.com/bowoBp/LoanFlow/internal/ledger"
+	"github.com/bowoBp/LoanFlow/internal/permission"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"github.com/bowoBp/LoanFlow/pkg/reporter"
+	"time"
+)
+
+type (
+	Usecase struct {
+		LedgerRepo    Repository.LedgerRepoInterface
+		DbTransaction Repository.TransactionUnit[DefaultLedgerTransactionInterface]
+		Book          ledger.BookInterface
+		Permissions   permission.Resolver
+		Reporter      reporter.Reporter
+	}
+
+	UsecaseInterface interface {
+		RequestTopUp(
+			ctx context.Context,
+			userID uint,
+			payload TopUpRequest,
+		) (*domians.TopUp, error)
+		GetTopUps(
+			ctx context.Context,
+			userID uint,
+			role, status string,
+			query dto.GetListQuery,
+		) ([]domians.TopUp, int64, error)
+		ConfirmTopUp(
+			ctx context.Context,
+			topUpID, userID uint,
+			payload ConfirmTopUpRequest,
+		) (ConfirmTopUpResponse, error)
+		RejectTopUp(
+			ctx context.Context,
+			topUpID, userID uint,
+			payload RejectTopUpRequest,
+		) (*domians.TopUp, error)
+		GetAccounts(
+			ctx context.Context,
+			userID uint,
+		) ([]domians.LedgerAccount, error)
+		GetStatement(
+			ctx context.Context,
+			accountID, userID uint,
+			role string,
+			query dto.GetListQuery,
+		) (*domians.LedgerAccount, []domians.JournalLine, int64, error)
+	}
+)
+
+// RequestTopUp records a top-up the investor paid for, the wallet is not
+// credited until staff confirmed the payment. A payment reference can only
+// be used once.
+func (uc Usecase) RequestTopUp(
+	ctx context.Context,
+	userID uint,
+	payload TopUpRequest,
+) (*domians.TopUp, error) {
+	currency := payload.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+	if !currency.Valid() {
+		return nil, constant.ErrCurrency
+	}
+	now := time.Now()
+	topUp := &domians.TopUp{
+		UserID:           userID,
+		Currency:         currency,
+		Amount:           payload.Amount.In(currency),
+		PaymentReference: payload.PaymentReference,
+		Status:           constant.TopUpPending,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	created, err := uc.LedgerRepo.CreateTopUp(ctx, topUp)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, constant.ErrTopUpReference
+	}
+	return topUp, nil
+}
+
+// GetTopUps lists the top-ups with status, every status when it is empty.
+// Only the roles reviewing top-ups see the top-ups of other users.
+func (uc Usecase) GetTopUps(
+	ctx context.Context,
+	userID uint,
+	role, status string,
+	query dto.GetListQuery,
+) ([]domians.TopUp, int64, error) {
+	reviewer, err := uc.Permissions.Allowed(ctx, role, constant.PermLedgerTopUp)
+	if err != nil {
+		return nil, 0, err
+	}
+	filter := dto.TopUpFilter{Status: status}
+	if !reviewer {
+		filter.UserID = userID
+	}
+	return uc.LedgerRepo.GetTopUps(ctx, query, filter)
+}
+
+// ConfirmTopUp credits the wallet once staff found the payment, the amount
+// and payment reference received must match the pending top-up.
+func (uc Usecase) ConfirmTopUp(
+	ctx context.Context,
+	topUpID, userID uint,
+	payload ConfirmTopUpRequest,
+) (result ConfirmTopUpResponse, err error) {
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return result, err
+	}
+	defer func(tx DefaultLedgerTransactionInterface, err *error) {
+		// recover panic
+		if r := recover(); r != nil {
+			*err = uc.Reporter.Recover(ctx, r)
+		}
+		// end transaction (rollback or commit)
+		errTrx := tx.End(*err)
+		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
+		}
+	}(dbTrx, &err)
+
+	topUp, err := uc.pendingTopUp(ctx, dbTrx, topUpID, userID)
+	if err != nil {
+		return result, err
+	}
+	if topUp.PaymentReference != payload.PaymentReference || topUp.Amount.Cmp(payload.Amount) != 0 {
+		return result, constant.ErrTopUpMismatch
+	}
+
+	entry, err := uc.Book.TopUp(ctx, dbTrx, topUp, userID)
+	if err != nil {
+		return result, err
+	}
+	now := time.Now()
+	err = dbTrx.UpdateTopUp(ctx, topUp, map[string]any{
+		"status":           constant.TopUpConfirmed,
+		"journal_entry_id": entry.ID,
+		"reviewed_by":      userID,
+		"reviewed_at":      now,
+		"updated_at":       now,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result = ConfirmTopUpResponse{
+		TopUpID:        topUp.ID,
+		JournalEntryID: entry.ID,
+		Currency:       entry.Currency,
+		Amount:         topUp.Amount,
+	}
+	// the wallet line is the credit side of the top-up
+	for _, line := range entry.Lines {
+		if line.Credit.IsPositive() {
+			result.Balance = line.BalanceAfter
+		}
+	}
+	return result, nil
+}
+
+// RejectTopUp closes a top-up whose payment never arrived, the wallet is
+// left as it is.
+func (uc Usecase) RejectTopUp(
+	ctx context.Context,
+	topUpID, userID uint,
+	payload RejectTopUpRequest,
+) (topUp *domians.TopUp, err error) {
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func(tx DefaultLedgerTransactionInterface, err *error) {
+		// recover panic
+		if r := recover(); r != nil {
+			*err = uc.Reporter.Recover(ctx, r)
+		}
+		// end transaction (rollback or commit)
+		errTrx := tx.End(*err)
+		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
+		}
+	}(dbTrx, &err)
+
+	topUp, err = uc.pendingTopUp(ctx, dbTrx, topUpID, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	err = dbTrx.UpdateTopUp(ctx, topUp, map[string]any{
+		"status":      constant.TopUpRejected,
+		"reviewed_by": userID,
+		"reviewed_at": now,
+		"remarks":     payload.Reason,
+		"updated_at":  now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	topUp.Status = constant.TopUpRejected
+	topUp.ReviewedBy = &userID
+	topUp.ReviewedAt = &now
+	topUp.Remarks = payload.Reason
+	return topUp, nil
+}
+
+// pendingTopUp locks the top-up that userID reviews, it must still be
+// pending and asked for by someone else.
+func (uc Usecase) pendingTopUp(
+	ctx context.Context,
+	tx DefaultLedgerTransactionInterface,
+	topUpID, userID uint,
+) (*domians.TopUp, error) {
+	topUp, err := tx.GetTopUpByIDForUpdate(ctx, topUpID)
+	if err != nil {
+		return nil, err
+	}
+	if topUp == nil {
+		return nil, constant.ErrTopUpNotFound
+	}
+	if topUp.UserID == userID {
+		return nil, constant.ErrTopUpSelfReview
+	}
+	if topUp.Status != constant.TopUpPending {
+		return nil, constant.ErrTopUpReviewed
+	}
+	return topUp, nil
+}
+
+func (uc Usecase) GetAccounts(
+	ctx context.Context,
+	userID uint,
+) ([]domians.LedgerAccount, error) {
+	return uc.LedgerRepo.GetAccountsByUserID(ctx, userID)
+}
+
+// GetStatement returns the lines of an account, an account that belongs to
+// another user or to the platform needs the ledger.read_all permission.
+func (uc Usecase) GetStatement(
+	ctx context.Context,
+	accountID, userID uint,
+	role string,
+	query dto.GetListQuery,
+) (*domians.LedgerAccount, []domians.JournalLine, int64, error) {
+	account, err := uc.LedgerRepo.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if account == nil {
+		return nil, nil, 0, constant.ErrAccountNotFound
+	}
+	if account.UserID == nil || *account.UserID != userID {
+		readAll, err := uc.Permissions.Allowed(ctx, role, constant.PermLedgerReadAll)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if !readAll {
+			return nil, nil, 0, constant.ErrAccountNotOwned
+		}
+	}
+	lines, count, err := uc.LedgerRepo.GetAccountLines(ctx, accountID, query)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return account, lines, count, nil
+}
@@ -0,0 +1,322 @@
+package ledger_test
+
+import (
+	"context"
+	"github.com/bowoBp/LoanFlow/internal/adapter/mocks"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/dto"
+	"github.com/bowoBp/LoanFlow/internal/services/ledger"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestUsecase_RequestTopUp(t *testing.T) {
+	investorID := uint(7)
+	payload := ledger.TopUpRequest{
+		Amount:           money.New(250000),
+		PaymentReference: "TRF-20261018-001",
+	}
+
+	tests := []struct {
+		name        string
+		created     bool
+		expectedErr error
+	}{
+		{
+			name:    "Success - top-up waits for confirmation",
+			created: true,
+		},
+		{
+			name:        "Error - payment reference already used",
+			created:     false,
+			expectedErr: constant.ErrTopUpReference,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLedgerRepo := new(mocks.LedgerRepoInterface)
+			mockLedgerRepo.On("CreateTopUp", mock.Anything, mock.MatchedBy(func(topUp *domians.TopUp) bool {
+				return topUp.UserID == investorID &&
+					topUp.Currency == money.IDR &&
+					topUp.Amount == payload.Amount.In(money.IDR) &&
+					topUp.PaymentReference == payload.PaymentReference &&
+					topUp.Status == constant.TopUpPending
+			})).Return(tt.created, nil).Once()
+
+			// the wallet is not touched before staff confirmed the payment
+			uc := ledger.Usecase{
+				LedgerRepo: mockLedgerRepo,
+				Book:       new(mocks.BookInterface),
+			}
+			topUp, err := uc.RequestTopUp(context.Background(), investorID, payload)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, topUp)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, constant.TopUpPending, topUp.Status)
+			}
+			mockLedgerRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsecase_GetTopUps(t *testing.T) {
+	query := dto.GetListQuery{PerPage: 10, Page: 1}
+
+	tests := []struct {
+		name     string
+		role     string
+		reviewer bool
+		filter   dto.TopUpFilter
+	}{
+		{
+			name:     "Reviewer - lists the top-ups of every user",
+			role:     constant.RoleStaff,
+			reviewer: true,
+			filter:   dto.TopUpFilter{Status: constant.TopUpPending},
+		},
+		{
+			name:   "Investor - lists only own top-ups",
+			role:   constant.RoleInvestor,
+			filter: dto.TopUpFilter{UserID: 7, Status: constant.TopUpPending},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLedgerRepo := new(mocks.LedgerRepoInterface)
+			mockLedgerRepo.On("GetTopUps", mock.Anything, query, tt.filter).
+				Return([]domians.TopUp{}, int64(0), nil).Once()
+			mockPermissions := new(mocks.Resolver)
+			mockPermissions.On("Allowed", mock.Anything, tt.role, constant.PermLedgerTopUp).
+				Return(tt.reviewer, nil).Once()
+
+			uc := ledger.Usecase{LedgerRepo: mockLedgerRepo, Permissions: mockPermissions}
+			_, _, err := uc.GetTopUps(context.Background(), 7, tt.role, constant.TopUpPending, query)
+
+			assert.NoError(t, err)
+			mockLedgerRepo.AssertExpectations(t)
+			mockPermissions.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsecase_ConfirmTopUp(t *testing.T) {
+	mockTransaction := new(mocks.DefaultLedgerTransactionInterface)
+	mockBook := new(mocks.BookInterface)
+
+	topUpID := uint(4)
+	investorID := uint(7)
+	staffID := uint(2)
+	pending := func() *domians.TopUp {
+		return &domians.TopUp{
+			ID:               topUpID,
+			UserID:           investorID,
+			Amount:           money.New(250000),
+			PaymentReference: "TRF-20261018-001",
+			Status:           constant.TopUpPending,
+		}
+	}
+	payload := ledger.ConfirmTopUpRequest{
+		Amount:           money.New(250000),
+		PaymentReference: "TRF-20261018-001",
+	}
+
+	tests := []struct {
+		name         string
+		userID       uint
+		payload      ledger.ConfirmTopUpRequest
+		mockBehavior func()
+		want         ledger.ConfirmTopUpResponse
+		expectedErr  error
+	}{
+		{
+			name:    "Error - top-up not found",
+			userID:  staffID,
+			payload: payload,
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetTopUpByIDForUpdate", mock.Anything, topUpID).Return(nil, nil).Once()
+				mockTransaction.On("End", constant.ErrTopUpNotFound).Return(nil).Once()
+			},
+			expectedErr: constant.ErrTopUpNotFound,
+		},
+		{
+			name:    "Error - top-up already reviewed",
+			userID:  staffID,
+			payload: payload,
+			mockBehavior: func() {
+				confirmed := pending()
+				confirmed.Status = constant.TopUpConfirmed
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetTopUpByIDForUpdate", mock.Anything, topUpID).Return(confirmed, nil).Once()
+				mockTransaction.On("End", constant.ErrTopUpReviewed).Return(nil).Once()
+			},
+			expectedErr: constant.ErrTopUpReviewed,
+		},
+		{
+			name:    "Error - user confirms own top-up",
+			userID:  investorID,
+			payload: payload,
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetTopUpByIDForUpdate", mock.Anything, topUpID).Return(pending(), nil).Once()
+				mockTransaction.On("End", constant.ErrTopUpSelfReview).Return(nil).Once()
+			},
+			expectedErr: constant.ErrTopUpSelfReview,
+		},
+		{
+			name:   "Error - payment received differs from the top-up",
+			userID: staffID,
+			payload: ledger.ConfirmTopUpRequest{
+				Amount:           money.New(25000),
+				PaymentReference: payload.PaymentReference,
+			},
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetTopUpByIDForUpdate", mock.Anything, topUpID).Return(pending(), nil).Once()
+				mockTransaction.On("End", constant.ErrTopUpMismatch).Return(nil).Once()
+			},
+			expectedErr: constant.ErrTopUpMismatch,
+		},
+		{
+			name:    "Success - wallet is credited",
+			userID:  staffID,
+			payload: payload,
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetTopUpByIDForUpdate", mock.Anything, topUpID).Return(pending(), nil).Once()
+				mockBook.On("TopUp", mock.Anything, mockTransaction, pending(), staffID).
+					Return(&domians.JournalEntry{
+						ID:       11,
+						Currency: money.IDR,
+						Lines: []domians.JournalLine{
+							{Debit: money.New(250000), BalanceAfter: money.New(900000)},
+							{Credit: money.New(250000), BalanceAfter: money.New(300000)},
+						},
+					}, nil).Once()
+				mockTransaction.On("UpdateTopUp", mock.Anything, pending(), mock.MatchedBy(func(data map[string]any) bool {
+					return data["status"] == constant.TopUpConfirmed &&
+						data["journal_entry_id"] == uint(11) &&
+						data["reviewed_by"] == staffID
+				})).Return(nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+			},
+			want: ledger.ConfirmTopUpResponse{
+				TopUpID:        topUpID,
+				JournalEntryID: 11,
+				Currency:       money.IDR,
+				Amount:         money.New(250000),
+				Balance:        money.New(300000),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := ledger.Usecase{
+				DbTransaction: mockTransaction,
+				Book:          mockBook,
+			}
+			got, err := uc.ConfirmTopUp(context.Background(), topUpID, tt.userID, tt.payload)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			mockTransaction.AssertExpectations(t)
+			mockBook.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsecase_RejectTopUp(t *testing.T) {
+	mockTransaction := new(mocks.DefaultLedgerTransactionInterface)
+	topUpID := uint(4)
+	staffID := uint(2)
+	payload := ledger.RejectTopUpRequest{Reason: "no transfer with this reference"}
+
+	mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+	mockTransaction.On("GetTopUpByIDForUpdate", mock.Anything, topUpID).
+		Return(&domians.TopUp{ID: topUpID, UserID: 7, Status: constant.TopUpPending}, nil).Once()
+	mockTransaction.On("UpdateTopUp", mock.Anything, mock.Anything, mock.MatchedBy(func(data map[string]any) bool {
+		return data["status"] == constant.TopUpRejected &&
+			data["remarks"] == payload.Reason &&
+			data["reviewed_by"] == staffID
+	})).Return(nil).Once()
+	mockTransaction.On("End", nil).Return(nil).Once()
+
+	uc := ledger.Usecase{DbTransaction: mockTransaction}
+	topUp, err := uc.RejectTopUp(context.Background(), topUpID, staffID, payload)
+
+	assert.NoError(t, err)
+	assert.Equal(t, constant.TopUpRejected, topUp.Status)
+	mockTransaction.AssertExpectations(t)
+}
+
+func TestUsecase_GetStatement(t *testing.T) {
+	ownerID := uint(7)
+	query := dto.GetListQuery{PerPage: 10, Page: 1}
+	wallet := &domians.LedgerAccount{ID: 3, UserID: &ownerID, Currency: money.IDR}
+
+	tests := []struct {
+		name         string
+		userID       uint
+		role         string
+		mockBehavior func(*mocks.LedgerRepoInterface, *mocks.Resolver)
+		expectedErr  error
+	}{
+		{
+			name:   "Success - owner reads own account",
+			userID: ownerID,
+			role:   constant.RoleInvestor,
+			mockBehavior: func(repo *mocks.LedgerRepoInterface, _ *mocks.Resolver) {
+				repo.On("GetAccountLines", mock.Anything, wallet.ID, query).
+					Return([]domians.JournalLine{}, int64(0), nil).Once()
+			},
+		},
+		{
+			name:   "Success - role with ledger.read_all reads another account",
+			userID: 2,
+			role:   constant.RoleStaff,
+			mockBehavior: func(repo *mocks.LedgerRepoInterface, permissions *mocks.Resolver) {
+				permissions.On("Allowed", mock.Anything, constant.RoleStaff, constant.PermLedgerReadAll).
+					Return(true, nil).Once()
+				repo.On("GetAccountLines", mock.Anything, wallet.ID, query).
+					Return([]domians.JournalLine{}, int64(0), nil).Once()
+			},
+		},
+		{
+			name:   "Error - account of another user",
+			userID: 9,
+			role:   constant.RoleInvestor,
+			mockBehavior: func(_ *mocks.LedgerRepoInterface, permissions *mocks.Resolver) {
+				permissions.On("Allowed", mock.Anything, constant.RoleInvestor, constant.PermLedgerReadAll).
+					Return(false, nil).Once()
+			},
+			expectedErr: constant.ErrAccountNotOwned,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLedgerRepo := new(mocks.LedgerRepoInterface)
+			mockLedgerRepo.On("GetAccountByID", mock.Anything, wallet.ID).Return(wallet, nil).Once()
+			mockPermissions := new(mocks.Resolver)
+			tt.mockBehavior(mockLedgerRepo, mockPermissions)
+
+			uc := ledger.Usecase{LedgerRepo: mockLedgerRepo, Permissions: mockPermissions}
+			_, _, _, err := uc.GetStatement(context.Background(), wallet.ID, tt.userID, tt.role, query)
+
+			assert.Equal(t, tt.expectedErr, err)
+			mockLedgerRepo.AssertExpectations(t)
+			mockPermissions.AssertExpectations(t)
+		})
+	}
+}
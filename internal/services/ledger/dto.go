@@ -0,0 +1,82 @@
+package ledger
+
+import (
+	"github.com/bowoBp/LoanFlow/internal/dto"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"time"
+)
+
+type (
+	// TopUpRequest is sent by the investor after paying to the platform,
+	// PaymentReference is the reference of the bank transfer.
+	TopUpRequest struct {
+		Currency         money.Currency `json:"currency" validate:"omitempty,currency"`
+		Amount           money.Money    `json:"amount" validate:"amount_min=10000,amount_max=100000000"`
+		PaymentReference string         `json:"paymentReference" validate:"required,max=100"`
+	}
+
+	// ConfirmTopUpRequest repeats the payment as staff found it on the bank
+	// statement, it must match the top-up.
+	ConfirmTopUpRequest struct {
+		Amount           money.Money `json:"amount" validate:"amount_min=0.01"`
+		PaymentReference string      `json:"paymentReference" validate:"required,max=100"`
+	}
+
+	RejectTopUpRequest struct {
+		Reason string `json:"reason" validate:"required,max=500"`
+	}
+
+	TopUpResponse struct {
+		ID               uint           `json:"id"`
+		UserID           uint           `json:"userId"`
+		Currency         money.Currency `json:"currency"`
+		Amount           money.Money    `json:"amount"`
+		PaymentReference string         `json:"paymentReference"`
+		Status           string         `json:"status"`
+		JournalEntryID   *uint          `json:"journalEntryId"`
+		ReviewedBy       *uint          `json:"reviewedBy"`
+		ReviewedAt       *time.Time     `json:"reviewedAt"`
+		Remarks          string         `json:"remarks"`
+		CreatedAt        time.Time      `json:"createdAt"`
+	}
+
+	ConfirmTopUpResponse struct {
+		TopUpID        uint           `json:"topUpId"`
+		JournalEntryID uint           `json:"journalEntryId"`
+		Currency       money.Currency `json:"currency"`
+		Amount         money.Money    `json:"amount"`
+		Balance        money.Money    `json:"balance"`
+	}
+
+	TopUpListResponse struct {
+		Pagination dto.PaginationResponse `json:"pagination"`
+		TopUps     []TopUpResponse        `json:"topUps"`
+	}
+
+	AccountResponse struct {
+		ID        uint           `json:"id"`
+		Code      string         `json:"code"`
+		Type      string         `json:"type"`
+		LoanID    *uint          `json:"loanId"`
+		Currency  money.Currency `json:"currency"`
+		Balance   money.Money    `json:"balance"`
+		UpdatedAt time.Time      `json:"updatedAt"`
+	}
+
+	StatementLineResponse struct {
+		ID           uint        `json:"id"`
+		EntryType    string      `json:"entryType"`
+		Reference    string      `json:"reference"`
+		Description  string      `json:"description"`
+		Debit        money.Money `json:"debit"`
+		Credit       money.Money `json:"credit"`
+		BalanceAfter money.Money `json:"balanceAfter"`
+		CreatedAt    time.Time   `json:"createdAt"`
+	}
+
+	StatementResponse struct {
+		Account    AccountResponse         `json:"account"`
+		Pagination dto.PaginationResponse  `json:"pagination"`
+		Lines      []StatementLineResponse `json:"lines"`
+	}
+)
@@ -0,0 +1,88 @@
+package ledger
+
+import (
+	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	"github.com/bowoBp/LoanFlow/internal/ledger"
+	"github.com/bowoBp/LoanFlow/internal/permission"
+	"github.com/bowoBp/LoanFlow/pkg/middleware"
+	"github.com/bowoBp/LoanFlow/pkg/reporter"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type (
+	Router struct {
+		auth        middleware.AuthInterface
+		idempotency middleware.IdempotencyInterface
+		rh          *RequestHandler
+	}
+)
+
+func NewRoute(
+	db *gorm.DB,
+	auth middleware.AuthInterface,
+	book ledger.BookInterface,
+	idempotency middleware.IdempotencyInterface,
+	permissions permission.Resolver,
+	reporter reporter.Reporter,
+) *Router {
+	return &Router{
+		auth:        auth,
+		idempotency: idempotency,
+		rh: &RequestHandler{
+			ctrl: &Controller{
+				Uc: Usecase{
+					LedgerRepo:    Repository.NewLedgerRepo(db),
+					DbTransaction: NewLedgerTransaction(db),
+					Book:          book,
+					Permissions:   permissions,
+					Reporter:      reporter,
+				},
+			},
+		},
+	}
+}
+
+func (r Router) Route(router *gin.RouterGroup) {
+	ledgers := router.Group("ledger")
+
+	ledgers.POST(
+		"/top-ups",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermLedgerTopUpRequest),
+		r.idempotency.Handle(),
+		r.rh.RequestTopUp,
+	)
+	ledgers.GET(
+		"/top-ups",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermLedgerTopUpRequest, constant.PermLedgerTopUp),
+		r.rh.GetTopUps,
+	)
+	ledgers.POST(
+		"/top-ups/:topUpId/confirm",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermLedgerTopUp),
+		r.idempotency.Handle(),
+		r.rh.ConfirmTopUp,
+	)
+	ledgers.POST(
+		"/top-ups/:topUpId/reject",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermLedgerTopUp),
+		r.rh.RejectTopUp,
+	)
+	ledgers.GET(
+		"/accounts",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermLedgerRead),
+		r.rh.GetAccounts,
+	)
+	ledgers.GET(
+		"/accounts/:accountId/statement",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermLedgerRead),
+		r.rh.GetStatement,
+	)
+}
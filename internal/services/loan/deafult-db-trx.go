@@ -5,12 +5,16 @@ import (
 	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
 	domians "github.com/bowoBp/LoanFlow/internal/domain"
 	"gorm.io/gorm"
+	"time"
 )
 
 type (
 	DefaultLoanTransaction struct {
-		db       *gorm.DB
-		loanRepo Repository.LoanRepoInterface
+		db               *gorm.DB
+		loanRepo         Repository.LoanRepoInterface
+		repaymentRepo    Repository.RepaymentRepoInterface
+		notificationRepo Repository.NotificationRepoInterface
+		ledgerRepo       Repository.LedgerRepoInterface
 	}
 
 	DefaultLoanTransactionInterface interface {
@@ -20,6 +24,10 @@ type (
 			ctx context.Context,
 			loan *domians.Loan,
 		) (*domians.Loan, error)
+		GetLoanByIDForUpdate(
+			ctx context.Context,
+			loanID uint,
+		) (*domians.Loan, error)
 		ApproveDetail(
 			ctx context.Context,
 			detail *domians.LoanApprovalDetail,
@@ -41,6 +49,41 @@ type (
 			ctx context.Context,
 			investLoan *domians.LoanInvestor,
 		) error
+		CreateInstallments(
+			ctx context.Context,
+			installments []domians.LoanInstallment,
+		) error
+		ReleaseInvestments(
+			ctx context.Context,
+			loanID uint,
+			releasedAt time.Time,
+		) error
+		GetLoanInvestors(
+			ctx context.Context,
+			loanID uint,
+		) ([]domians.LoanInvestor, error)
+		UpdateLoanInvestor(
+			ctx context.Context,
+			investor *domians.LoanInvestor,
+			updateData map[string]any,
+		) error
+		CreateNotifications(
+			ctx context.Context,
+			notifications []domians.Notification,
+		) error
+		GetOrCreateAccount(
+			ctx context.Context,
+			account *domians.LedgerAccount,
+		) (*domians.LedgerAccount, error)
+		UpdateLedgerAccount(
+			ctx context.Context,
+			account *domians.LedgerAccount,
+			updateData map[string]any,
+		) error
+		CreateJournalEntry(
+			ctx context.Context,
+			entry *domians.JournalEntry,
+		) error
 	}
 )
 
@@ -50,6 +93,13 @@ func NewLoanTransaction(db *gorm.DB) DefaultLoanTransaction {
 	}
 }
 
+func (repo DefaultLoanTransaction) GetLoanByIDForUpdate(
+	ctx context.Context,
+	loanID uint,
+) (*domians.Loan, error) {
+	return repo.loanRepo.GetLoanByIDForUpdate(ctx, loanID)
+}
+
 func (repo DefaultLoanTransaction) UpdateLoan(
 	ctx context.Context,
 	loan *domians.Loan,
@@ -93,28 +143,85 @@ func (repo DefaultLoanTransaction) InvestLoan(
 	return repo.loanRepo.InvestLoan(ctx, investLoan)
 }
 
+func (repo DefaultLoanTransaction) CreateInstallments(
+	ctx context.Context,
+	installments []domians.LoanInstallment,
+) error {
+	return repo.repaymentRepo.CreateInstallments(ctx, installments)
+}
+
+func (repo DefaultLoanTransaction) ReleaseInvestments(
+	ctx context.Context,
+	loanID uint,
+	releasedAt time.Time,
+) error {
+	return repo.loanRepo.ReleaseInvestments(ctx, loanID, releasedAt)
+}
+
+func (repo DefaultLoanTransaction) GetLoanInvestors(
+	ctx context.Context,
+	loanID uint,
+) ([]domians.LoanInvestor, error) {
+	return repo.loanRepo.GetLoanInvestors(ctx, loanID)
+}
+
+func (repo DefaultLoanTransaction) UpdateLoanInvestor(
+	ctx context.Context,
+	investor *domians.LoanInvestor,
+	updateData map[string]any,
+) error {
+	return repo.loanRepo.UpdateLoanInvestor(ctx, investor, updateData)
+}
+
+func (repo DefaultLoanTransaction) CreateNotifications(
+	ctx context.Context,
+	notifications []domians.Notification,
+) error {
+	return repo.notificationRepo.CreateNotifications(ctx, notifications)
+}
+
+func (repo DefaultLoanTransaction) GetOrCreateAccount(
+	ctx context.Context,
+	account *domians.LedgerAccount,
+) (*domians.LedgerAccount, error) {
+	return repo.ledgerRepo.GetOrCreateAccount(ctx, account)
+}
+
+func (repo DefaultLoanTransaction) UpdateLedgerAccount(
+	ctx context.Context,
+	account *domians.LedgerAccount,
+	updateData map[string]any,
+) error {
+	return repo.ledgerRepo.UpdateLedgerAccount(ctx, account, updateData)
+}
+
+func (repo DefaultLoanTransaction) CreateJournalEntry(
+	ctx context.Context,
+	entry *domians.JournalEntry,
+) error {
+	return repo.ledgerRepo.CreateJournalEntry(ctx, entry)
+}
+
 func (repo DefaultLoanTransaction) Begin() (DefaultLoanTransactionInterface, error) {
 	evoTrx := repo.db.Begin()
 	if err := evoTrx.Error; err != nil {
 		return DefaultLoanTransaction{}, err
 	}
 	newLoanTrx := &DefaultLoanTransaction{
-		db:       evoTrx,
-		loanRepo: Repository.NewLoanRepo(evoTrx),
+		db:               evoTrx,
+		loanRepo:         Repository.NewLoanRepo(evoTrx),
+		repaymentRepo:    Repository.NewRepaymentRepo(evoTrx),
+		notificationRepo: Repository.NewNotificationRepo(evoTrx),
+		ledgerRepo:       Repository.NewLedgerRepo(evoTrx),
 	}
 	return newLoanTrx, nil
 }
 
+// End rolls the transaction back when err is not nil and commits it
+// otherwise, the failure of either is returned for the usecase to report.
 func (repo DefaultLoanTransaction) End(err error) error {
 	if err != nil {
-		errTrx := repo.db.Rollback().Error
-		if errTrx != nil {
-			return nil
-		}
-	}
-	errTrx := repo.db.Commit().Error
-	if errTrx != nil {
-		return errTrx
+		return repo.db.Rollback().Error
 	}
-	return nil
+	return repo.db.Commit().Error
 }
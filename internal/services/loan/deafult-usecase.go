@@ -2,10 +2,22 @@ package loan
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	"github.com/bowoBp/LoanFlow/internal/agreement"
 	"github.com/bowoBp/LoanFlow/internal/constant"
 	domians "github.com/bowoBp/LoanFlow/internal/domain"
 	"github.com/bowoBp/LoanFlow/internal/dto"
+	"github.com/bowoBp/LoanFlow/internal/ledger"
+	"github.com/bowoBp/LoanFlow/internal/notification"
+	"github.com/bowoBp/LoanFlow/internal/policy"
+	"github.com/bowoBp/LoanFlow/internal/services/repayment"
+	"github.com/bowoBp/LoanFlow/internal/statemachine"
+	"github.com/bowoBp/LoanFlow/pkg/metrics"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"github.com/bowoBp/LoanFlow/pkg/reporter"
+	"slices"
 	"time"
 )
 
@@ -13,6 +25,13 @@ type (
 	Usecase struct {
 		LoanRepo      Repository.LoanRepoInterface
 		DbTransaction Repository.TransactionUnit[DefaultLoanTransactionInterface]
+		StateMachine  *statemachine.Machine
+		Agreement     agreement.GeneratorInterface
+		Notifier      notification.NotifierInterface
+		Ledger        ledger.BookInterface
+		Policy        policy.LoanPolicy
+		Reporter      reporter.Reporter
+		Metrics       metrics.LoanRecorder
 	}
 
 	UsecaseInterface interface {
@@ -23,53 +42,91 @@ type (
 
 		GetLoan(
 			ctx context.Context,
-			loanID uint,
+			loanID, userID uint,
+			role string,
 		) (*domians.Loan, error)
 		ApproveLoan(
 			ctx context.Context,
 			loanID, userID uint,
+			role string,
 			payload ApproveLoanRequest,
 		) error
 		DisburseLoan(
 			ctx context.Context,
 			loanID, userID uint,
+			role string,
 			payload DisburseLoanRequest,
 		) error
 		StoreInvest(
 			ctx context.Context,
 			loanID, userID uint,
+			role string,
 			payload InvestLoanRequest,
 		) error
 		GetLoans(
 			ctx context.Context,
+			userID uint,
+			role string,
 			query dto.GetListQuery,
 		) ([]domians.Loan, int64, error)
+		RejectLoan(
+			ctx context.Context,
+			loanID, userID uint,
+			role string,
+			payload RejectLoanRequest,
+		) error
+		CancelLoan(
+			ctx context.Context,
+			loanID, userID uint,
+			role string,
+			payload CancelLoanRequest,
+		) error
 	}
 )
 
 func (uc Usecase) CreateLoan(
 	ctx context.Context,
 	payload CreateLoanRequest,
-) error {
+) (err error) {
+	defer uc.record(&err, countEvent(constant.EventPropose))
 	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return err
+	}
 	defer func(tx DefaultLoanTransactionInterface, err *error) {
 		// recover panic
 		if r := recover(); r != nil {
-			// TODO: catch error and pass to log/sentry soon
+			*err = uc.Reporter.Recover(ctx, r)
 		}
 		// end transaction (rollback or commit)
 		errTrx := tx.End(*err)
 		if errTrx != nil {
-			// TODO: catch error and pass to log/sentry soon
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
 		}
 	}(dbTrx, &err)
+	tenor := payload.Tenor
+	if tenor == 0 {
+		tenor = constant.DefaultTenor
+	}
+	currency := payload.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+	if !currency.Valid() {
+		return constant.ErrCurrency
+	}
 	loan, err := dbTrx.CreateLoan(
 		ctx,
 		&domians.Loan{
 			BorrowerID:      uint(payload.ID),
-			PrincipalAmount: payload.PrincipalAmount,
+			Currency:        currency,
+			PrincipalAmount: payload.PrincipalAmount.In(currency),
 			Rate:            payload.Rate,
-			ROI:             payload.PrincipalAmount * (payload.Rate / 100),
+			ROI:             payload.Rate.Of(payload.PrincipalAmount.In(currency)),
+			Tenor:           tenor,
 			State:           constant.Proposed,
 		},
 	)
@@ -77,71 +134,82 @@ func (uc Usecase) CreateLoan(
 		return err
 	}
 
-	err = dbTrx.CreateLoanState(
+	return uc.StateMachine.Start(
 		ctx,
-		&domians.LoanStateHistory{
-			LoanID:        loan.ID,
-			PreviousState: "",
-			NewState:      constant.Proposed,
-			ActionBy:      payload.ID,
-			ActionAt:      time.Now(),
-			Remarks:       "proposed loan",
+		dbTrx,
+		loan,
+		statemachine.Trigger{
+			Event: constant.EventPropose,
+			Actor: statemachine.Actor{ID: payload.ID, Role: payload.Role},
 		},
 	)
-	if err != nil {
-		return err
-	}
-	return nil
 }
 
 func (uc Usecase) GetLoan(
 	ctx context.Context,
-	loanID uint,
+	loanID, userID uint,
+	role string,
 ) (*domians.Loan, error) {
-	return uc.LoanRepo.GetLoanByID(ctx, loanID)
+	loan, err := uc.LoanRepo.GetLoanByID(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	if loan == nil {
+		return nil, constant.LoanNotFound
+	}
+	err = uc.Policy.CanView(ctx, policy.Subject{ID: userID, Role: role}, loan)
+	if err != nil {
+		return nil, err
+	}
+	return loan, nil
 }
 
 func (uc Usecase) ApproveLoan(
 	ctx context.Context,
 	loanID, userID uint,
+	role string,
 	payload ApproveLoanRequest,
-) error {
+) (err error) {
+	defer uc.record(&err, countEvent(constant.EventApprove))
 	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return err
+	}
 	defer func(tx DefaultLoanTransactionInterface, err *error) {
 		// recover panic
 		if r := recover(); r != nil {
-			// TODO: catch error and pass to log/sentry soon
+			*err = uc.Reporter.Recover(ctx, r)
 		}
 		// end transaction (rollback or commit)
 		errTrx := tx.End(*err)
 		if errTrx != nil {
-			// TODO: catch error and pass to log/sentry soon
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
 		}
 	}(dbTrx, &err)
 
 	loan, err := uc.LoanRepo.GetLoanByID(ctx, loanID)
-	if err != nil {
+	if err != nil || loan == nil {
 		return constant.LoanNotFound
 	}
-	if loan.State != constant.Proposed {
-		return constant.ErrStateApprove
-	}
-	err = dbTrx.UpdateLoan(
+	err = uc.StateMachine.Fire(
 		ctx,
-		&domians.Loan{
-			ID: loanID,
-		},
-		map[string]any{
-			"state":                 constant.Approved,
-			"agreement_letter_link": payload.Proof,
-			"updated_at":            time.Now(),
+		dbTrx,
+		loan,
+		statemachine.Trigger{
+			Event: constant.EventApprove,
+			Actor: statemachine.Actor{ID: userID, Role: role},
+			Changes: map[string]any{
+				"agreement_letter_link": payload.Proof,
+			},
 		},
 	)
 	if err != nil {
-
 		return err
 	}
-	err = dbTrx.ApproveDetail(
+	return dbTrx.ApproveDetail(
 		ctx,
 		&domians.LoanApprovalDetail{
 			LoanID:       loanID,
@@ -151,74 +219,88 @@ func (uc Usecase) ApproveLoan(
 			CreatedAt:    time.Now(),
 			UpdatedAt:    time.Now(),
 		})
-	if err != nil {
-		return err
-	}
-	err = dbTrx.CreateLoanState(
-		ctx,
-		&domians.LoanStateHistory{
-			LoanID:        loanID,
-			PreviousState: loan.State,
-			NewState:      constant.Approved,
-			ActionBy:      userID,
-			ActionAt:      time.Now(),
-			Remarks:       "Approved loan",
-		},
-	)
-	if err != nil {
-		return err
-	}
-	return nil
 }
 
+// StoreInvest retries the investment when the loan is changed by another
+// request at the same time, ErrLoanConflict is returned when every attempt
+// conflicted.
 func (uc Usecase) StoreInvest(
 	ctx context.Context,
 	loanID, userID uint,
+	role string,
 	payload InvestLoanRequest,
-) error {
-	dbTrx, err := uc.DbTransaction.Begin()
-	nextState := ""
-
-	loan, err := uc.LoanRepo.GetLoanByID(ctx, loanID)
-	if err != nil {
-		return constant.LoanNotFound
-	}
-	if loan.State != constant.Approved {
-		return constant.ErrStateInvest
+) (err error) {
+	for attempt := 1; ; attempt++ {
+		err = uc.storeInvest(ctx, loanID, userID, role, payload)
+		if !errors.Is(err, constant.ErrLoanConflict) || attempt == constant.InvestMaxAttempts {
+			return err
+		}
 	}
-	if loan.PrincipalAmount < payload.Amount {
-		return constant.ErrInvestAmount
+}
+
+func (uc Usecase) storeInvest(
+	ctx context.Context,
+	loanID, userID uint,
+	role string,
+	payload InvestLoanRequest,
+) (err error) {
+	var loan *domians.Loan
+	trigger := statemachine.Trigger{
+		Event: constant.EventFund,
+		Actor: statemachine.Actor{ID: userID, Role: role},
 	}
-	loan.PrincipalAmount -= payload.Amount
-	if loan.PrincipalAmount == 0 {
-		loan.State = constant.Invested
-		nextState = constant.Invested
+	defer uc.record(&err, func(recorder metrics.LoanRecorder) {
+		recorder.LoanEvent(trigger.Event)
+		recorder.LoanInvested(loan.Currency, payload.Amount)
+	})
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return err
 	}
-
 	defer func(tx DefaultLoanTransactionInterface, err *error) {
 		// recover panic
 		if r := recover(); r != nil {
-
+			*err = uc.Reporter.Recover(ctx, r)
 		}
 		// end transaction (rollback or commit)
 		errTrx := tx.End(*err)
 		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
 		}
 	}(dbTrx, &err)
 
-	err = dbTrx.UpdateLoan(
-		ctx,
-		&domians.Loan{
-			ID: loanID,
-		},
-		map[string]any{
-			"state":            loan.State,
-			"principal_amount": loan.PrincipalAmount,
-			"updated_at":       time.Now(),
-		},
-	)
+	// the loan row stays locked until the transaction ends so concurrent
+	// investments are checked against the latest funded amount
+	loan, err = dbTrx.GetLoanByIDForUpdate(ctx, loanID)
+	if err != nil || loan == nil {
+		return constant.LoanNotFound
+	}
+	_, err = uc.StateMachine.Can(ctx, loan, trigger)
+	if err != nil {
+		return err
+	}
+	if loan.PrincipalAmount.Sub(loan.FundedAmount).Cmp(payload.Amount) < 0 {
+		return constant.ErrInvestAmount
+	}
+	// the investment is paid from the investor wallet
+	err = uc.Ledger.Invest(ctx, dbTrx, loan, userID, payload.Amount)
 	if err != nil {
+		return err
+	}
 
+	// a partial investment keeps the loan open, the last one closes it
+	funded := loan.FundedAmount.Add(payload.Amount)
+	if funded.Cmp(loan.PrincipalAmount) == 0 {
+		trigger.Event = constant.EventInvest
+	}
+	trigger.Changes = map[string]any{
+		"funded_amount": funded,
+	}
+	err = uc.StateMachine.Fire(ctx, dbTrx, loan, trigger)
+	if err != nil {
 		return err
 	}
 	err = dbTrx.InvestLoan(
@@ -227,69 +309,81 @@ func (uc Usecase) StoreInvest(
 			LoanID:         loanID,
 			InvestorID:     userID,
 			AmountInvested: payload.Amount,
+			Status:         constant.InvestmentActive,
 			CreatedAt:      time.Now(),
 			UpdatedAt:      time.Now(),
 		})
-	if err != nil {
+	if err != nil || loan.State != constant.Invested {
 		return err
 	}
-
-	err = dbTrx.CreateLoanState(
-		ctx,
-		&domians.LoanStateHistory{
-			LoanID:        loanID,
-			PreviousState: constant.Approved,
-			NewState:      nextState,
-			ActionBy:      userID,
-			ActionAt:      time.Now(),
-			Remarks:       "user invested",
-		},
-	)
+	investors, err := uc.Agreement.Generate(ctx, dbTrx, loan)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	// investors are emailed once the transaction is committed
+	recipients := make([]notification.Recipient, 0, len(investors))
+	for _, investor := range investors {
+		recipients = append(recipients, notification.Recipient{
+			UserID:  investor.InvestorID,
+			Address: investor.Investor.Email,
+			Locale:  investor.Investor.Locale,
+			Data: notification.LoanFunded{
+				Name:           investor.Investor.Name,
+				LoanID:         loan.ID,
+				Currency:       loan.Currency,
+				AmountInvested: investor.AmountInvested,
+				AgreementLink:  investor.AgreementLetterLink,
+			},
+		})
+	}
+	return uc.Notifier.Enqueue(ctx, dbTrx, constant.TemplateLoanFunded, recipients)
 }
 
 func (uc Usecase) DisburseLoan(
 	ctx context.Context,
 	loanID, userID uint,
+	role string,
 	payload DisburseLoanRequest,
-) error {
+) (err error) {
+	defer uc.record(&err, countEvent(constant.EventDisburse))
 	dbTrx, err := uc.DbTransaction.Begin()
-
-	loan, err := uc.LoanRepo.GetLoanByID(ctx, loanID)
 	if err != nil {
-		return constant.LoanNotFound
-	}
-	if loan.State != constant.Invested {
-		return constant.ErrStateDisburse
+		return err
 	}
-
 	defer func(tx DefaultLoanTransactionInterface, err *error) {
 		// recover panic
 		if r := recover(); r != nil {
-
+			*err = uc.Reporter.Recover(ctx, r)
 		}
 		// end transaction (rollback or commit)
 		errTrx := tx.End(*err)
 		if errTrx != nil {
-			// TODO: catch error and pass to log/sentry soon
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
 		}
 	}(dbTrx, &err)
 
-	err = dbTrx.UpdateLoan(
+	loan, err := uc.LoanRepo.GetLoanByID(ctx, loanID)
+	if err != nil || loan == nil {
+		return constant.LoanNotFound
+	}
+	err = uc.StateMachine.Fire(
 		ctx,
-		&domians.Loan{
-			ID: loanID,
-		},
-		map[string]any{
-			"state":      constant.Disbursed,
-			"updated_at": time.Now(),
+		dbTrx,
+		loan,
+		statemachine.Trigger{
+			Event: constant.EventDisburse,
+			Actor: statemachine.Actor{ID: userID, Role: role},
 		},
 	)
 	if err != nil {
-
+		return err
+	}
+	err = uc.Ledger.Disburse(ctx, dbTrx, loan, userID)
+	if err != nil {
 		return err
 	}
 	err = dbTrx.DisburseDetail(
@@ -304,26 +398,161 @@ func (uc Usecase) DisburseLoan(
 	if err != nil {
 		return err
 	}
-	err = dbTrx.CreateLoanState(
+
+	return dbTrx.CreateInstallments(
 		ctx,
-		&domians.LoanStateHistory{
-			LoanID:        loanID,
-			PreviousState: loan.State,
-			NewState:      constant.Disbursed,
-			ActionBy:      userID,
-			ActionAt:      time.Now(),
-			Remarks:       "Disbursed loan",
-		},
+		repayment.BuildSchedule(
+			loanID,
+			loan.PrincipalAmount,
+			loan.ROI,
+			loan.Tenor,
+			time.Now(),
+		),
 	)
-	if err != nil {
-		return err
-	}
-	return nil
 }
 
 func (uc Usecase) GetLoans(
 	ctx context.Context,
+	userID uint,
+	role string,
 	query dto.GetListQuery,
 ) ([]domians.Loan, int64, error) {
-	return uc.LoanRepo.GetLoans(ctx, query)
+	filter, err := uc.Policy.ListFilter(ctx, policy.Subject{ID: userID, Role: role})
+	if err != nil {
+		return nil, 0, err
+	}
+	return uc.LoanRepo.GetLoans(ctx, query, filter)
+}
+
+func (uc Usecase) RejectLoan(
+	ctx context.Context,
+	loanID, userID uint,
+	role string,
+	payload RejectLoanRequest,
+) (err error) {
+	if !slices.Contains(constant.RejectReasonCodes, payload.ReasonCode) {
+		return constant.ErrReasonCode
+	}
+	defer uc.record(&err, countEvent(constant.EventReject))
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return err
+	}
+	defer func(tx DefaultLoanTransactionInterface, err *error) {
+		// recover panic
+		if r := recover(); r != nil {
+			*err = uc.Reporter.Recover(ctx, r)
+		}
+		// end transaction (rollback or commit)
+		errTrx := tx.End(*err)
+		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
+		}
+	}(dbTrx, &err)
+
+	loan, err := uc.LoanRepo.GetLoanByID(ctx, loanID)
+	if err != nil || loan == nil {
+		return constant.LoanNotFound
+	}
+	return uc.StateMachine.Fire(
+		ctx,
+		dbTrx,
+		loan,
+		statemachine.Trigger{
+			Event:      constant.EventReject,
+			Actor:      statemachine.Actor{ID: userID, Role: role},
+			ReasonCode: payload.ReasonCode,
+			Remarks:    payload.Reason,
+		},
+	)
+}
+
+func (uc Usecase) CancelLoan(
+	ctx context.Context,
+	loanID, userID uint,
+	role string,
+	payload CancelLoanRequest,
+) (err error) {
+	if !slices.Contains(constant.CancelReasonCodes, payload.ReasonCode) {
+		return constant.ErrReasonCode
+	}
+	defer uc.record(&err, countEvent(constant.EventCancel))
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return err
+	}
+	defer func(tx DefaultLoanTransactionInterface, err *error) {
+		// recover panic
+		if r := recover(); r != nil {
+			*err = uc.Reporter.Recover(ctx, r)
+		}
+		// end transaction (rollback or commit)
+		errTrx := tx.End(*err)
+		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
+		}
+	}(dbTrx, &err)
+
+	loan, err := uc.LoanRepo.GetLoanByID(ctx, loanID)
+	if err != nil || loan == nil {
+		return constant.LoanNotFound
+	}
+	trigger := statemachine.Trigger{
+		Event:      constant.EventCancel,
+		Actor:      statemachine.Actor{ID: userID, Role: role},
+		ReasonCode: payload.ReasonCode,
+		Remarks:    payload.Reason,
+	}
+	_, err = uc.StateMachine.Can(ctx, loan, trigger)
+	if err != nil {
+		return err
+	}
+
+	// an approved loan may be partly funded, the investors are released
+	// and nothing stays funded on the cancelled loan
+	funded := loan.FundedAmount
+	if funded.IsPositive() {
+		trigger.Changes = map[string]any{
+			"funded_amount": money.Zero,
+		}
+	}
+
+	err = uc.StateMachine.Fire(ctx, dbTrx, loan, trigger)
+	if err != nil {
+		return err
+	}
+	if funded.IsZero() {
+		return nil
+	}
+	investors, err := dbTrx.GetLoanInvestors(ctx, loanID)
+	if err != nil {
+		return err
+	}
+	err = uc.Ledger.Release(ctx, dbTrx, loan, investors, userID)
+	if err != nil {
+		return err
+	}
+	return dbTrx.ReleaseInvestments(ctx, loanID, time.Now())
+}
+
+// record counts the loan events of the usecase once its transaction is
+// committed, it is deferred before the transaction begins so it runs after
+// End. Nothing is counted without Metrics.
+func (uc Usecase) record(err *error, count func(recorder metrics.LoanRecorder)) {
+	if *err != nil || uc.Metrics == nil {
+		return
+	}
+	count(uc.Metrics)
+}
+
+func countEvent(event string) func(recorder metrics.LoanRecorder) {
+	return func(recorder metrics.LoanRecorder) {
+		recorder.LoanEvent(event)
+	}
 }
@@ -7,7 +7,13 @@ import (
 	"github.com/bowoBp/LoanFlow/internal/constant"
 	domians "github.com/bowoBp/LoanFlow/internal/domain"
 	"github.com/bowoBp/LoanFlow/internal/dto"
+	"github.com/bowoBp/LoanFlow/internal/notification"
+	"github.com/bowoBp/LoanFlow/internal/permission"
+	"github.com/bowoBp/LoanFlow/internal/policy"
 	"github.com/bowoBp/LoanFlow/internal/services/loan"
+	"github.com/bowoBp/LoanFlow/internal/statemachine"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"github.com/bowoBp/LoanFlow/pkg/reporter"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
@@ -22,14 +28,16 @@ func TestUseCase_GetLoan(t *testing.T) {
 	mockLoan := &domians.Loan{
 		ID:              1,
 		BorrowerID:      42,
-		PrincipalAmount: 1000000,
-		Rate:            5.5,
+		PrincipalAmount: money.New(1000000),
+		Rate:            money.MustParseRate("5.5"),
 		State:           "proposed",
 	}
 
 	type args struct {
 		ctx    context.Context
 		loanID uint
+		userID uint
+		role   string
 	}
 	tests := []struct {
 		name          string
@@ -48,10 +56,44 @@ func TestUseCase_GetLoan(t *testing.T) {
 			args: args{
 				ctx:    context.Background(),
 				loanID: 1,
+				userID: 42,
+				role:   constant.RoleBorrower,
 			},
 			want:    mockLoan,
 			wantErr: false,
 		},
+		{
+			name: "Error - Loan Owned By Another Borrower",
+			mockBehavior: func() {
+				mockLoanRepo.On("GetLoanByID", mock.Anything, uint(1)).
+					Return(mockLoan, nil).Once()
+			},
+			args: args{
+				ctx:    context.Background(),
+				loanID: 1,
+				userID: 7,
+				role:   constant.RoleBorrower,
+			},
+			want:          nil,
+			wantErr:       true,
+			expectedError: constant.ErrLoanNotOwned,
+		},
+		{
+			name: "Error - Loan Does Not Exist",
+			mockBehavior: func() {
+				mockLoanRepo.On("GetLoanByID", mock.Anything, uint(4)).
+					Return(nil, nil).Once()
+			},
+			args: args{
+				ctx:    context.Background(),
+				loanID: 4,
+				userID: 42,
+				role:   constant.RoleStaff,
+			},
+			want:          nil,
+			wantErr:       true,
+			expectedError: constant.LoanNotFound,
+		},
 		{
 			name: "Error - Loan Not Found",
 			mockBehavior: func() {
@@ -61,6 +103,8 @@ func TestUseCase_GetLoan(t *testing.T) {
 			args: args{
 				ctx:    context.Background(),
 				loanID: 2,
+				userID: 42,
+				role:   constant.RoleBorrower,
 			},
 			want:          nil,
 			wantErr:       true,
@@ -75,6 +119,8 @@ func TestUseCase_GetLoan(t *testing.T) {
 			args: args{
 				ctx:    context.Background(),
 				loanID: 3,
+				userID: 42,
+				role:   constant.RoleBorrower,
 			},
 			want:          nil,
 			wantErr:       true,
@@ -90,10 +136,11 @@ func TestUseCase_GetLoan(t *testing.T) {
 			// Buat instance Usecase
 			uc := loan.Usecase{
 				LoanRepo: mockLoanRepo,
+				Policy:   policy.NewLoanPolicy(mockLoanRepo, permission.NewStatic(permission.Defaults)),
 			}
 
 			// Panggil fungsi GetLoan
-			got, err := uc.GetLoan(tt.args.ctx, tt.args.loanID)
+			got, err := uc.GetLoan(tt.args.ctx, tt.args.loanID, tt.args.userID, tt.args.role)
 
 			// Assert hasil
 			if tt.wantErr {
@@ -114,19 +161,22 @@ func TestUsecase_CreateLoan(t *testing.T) {
 
 	// Mock dependencies
 	mockTransaction := new(mocks.DefaultLoanTransactionInterface)
+	mockReporter := new(mocks.Reporter)
+	mockRecorder := new(mocks.LoanRecorder)
 
 	// Mock data
 	loanPayload := loan.CreateLoanRequest{
 		ID:              1,
-		PrincipalAmount: 1000000,
-		Rate:            5.5,
+		Role:            constant.RoleBorrower,
+		PrincipalAmount: money.New(1000000),
+		Rate:            money.MustParseRate("5.5"),
 	}
 	mockLoan := &domians.Loan{
 		ID:              1,
 		BorrowerID:      1,
-		PrincipalAmount: 1000000,
-		Rate:            5.5,
-		ROI:             55000,
+		PrincipalAmount: money.New(1000000),
+		Rate:            money.MustParseRate("5.5"),
+		ROI:             money.New(55000),
 		State:           "proposed",
 	}
 
@@ -159,6 +209,9 @@ func TestUsecase_CreateLoan(t *testing.T) {
 
 				// Mock End
 				mockTransaction.On("End", nil).Return(nil).Once()
+
+				// the created loan is counted after the commit
+				mockRecorder.On("LoanEvent", constant.EventPropose).Once()
 			},
 			args: args{
 				ctx:     context.Background(),
@@ -188,6 +241,52 @@ func TestUsecase_CreateLoan(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Error - Panic Reported and Rolled Back",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+
+				// Mock CreateLoan panicking
+				mockTransaction.On("CreateLoan", mock.Anything, mock.Anything).
+					Run(func(args mock.Arguments) {
+						panic("nil repository")
+					}).Once()
+
+				mockReporter.On("Recover", mock.Anything, "nil repository").
+					Return(&reporter.PanicError{Value: "nil repository"}).Once()
+
+				// Mock End rolling back the panic
+				mockTransaction.On("End", mock.MatchedBy(func(e error) bool {
+					var panicErr *reporter.PanicError
+					return errors.As(e, &panicErr)
+				})).Return(nil).Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				payload: loanPayload,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error - Commit Failure Reported",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("CreateLoan", mock.Anything, mock.Anything).Return(mockLoan, nil).Once()
+				mockTransaction.On("CreateLoanState", mock.Anything, mock.Anything).Return(nil).Once()
+
+				// Mock End failing to commit
+				mockTransaction.On("End", nil).Return(errors.New("commit failed")).Once()
+
+				mockReporter.On("Report", mock.Anything, mock.MatchedBy(func(e error) bool {
+					return e.Error() == "end transaction: commit failed"
+				})).Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				payload: loanPayload,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -198,6 +297,9 @@ func TestUsecase_CreateLoan(t *testing.T) {
 			// Create use case
 			uc := loan.Usecase{
 				DbTransaction: mockTransaction,
+				StateMachine:  statemachine.NewLoanMachine(permission.NewStatic(permission.Defaults)),
+				Reporter:      mockReporter,
+				Metrics:       mockRecorder,
 			}
 
 			// Call CreateLoan
@@ -212,6 +314,8 @@ func TestUsecase_CreateLoan(t *testing.T) {
 
 			// Verify all mocks
 			mockTransaction.AssertExpectations(t)
+			mockReporter.AssertExpectations(t)
+			mockRecorder.AssertExpectations(t)
 		})
 	}
 }
@@ -345,10 +449,11 @@ func TestUsecase_ApproveLoan(t *testing.T) {
 			uc := loan.Usecase{
 				LoanRepo:      mockLoanRepo,
 				DbTransaction: mockTransaction,
+				StateMachine:  statemachine.NewLoanMachine(permission.NewStatic(permission.Defaults)),
 			}
 
 			// Call ApproveLoan
-			err := uc.ApproveLoan(tt.args.ctx, tt.args.loanID, tt.args.userID, tt.args.payload)
+			err := uc.ApproveLoan(tt.args.ctx, tt.args.loanID, tt.args.userID, constant.RoleStaff, tt.args.payload)
 
 			// Assertions
 			if tt.wantErr {
@@ -369,12 +474,16 @@ func TestUsecase_StoreInvest(t *testing.T) {
 	// Mock dependencies
 	mockTransaction := new(mocks.DefaultLoanTransactionInterface)
 	mockLoanRepo := new(mocks.LoanRepoInterface)
+	mockAgreement := new(mocks.GeneratorInterface)
+	mockNotifier := new(mocks.NotifierInterface)
+	mockLedger := new(mocks.BookInterface)
+	mockRecorder := new(mocks.LoanRecorder)
 
 	// Mock data
 	loanID := uint(1)
 	userID := uint(2)
 	payload := loan.InvestLoanRequest{
-		Amount: 150000,
+		Amount: money.New(150000),
 	}
 
 	type args struct {
@@ -397,19 +506,23 @@ func TestUsecase_StoreInvest(t *testing.T) {
 				// Mock Begin
 				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
 
-				// Mock GetLoanByID
-				mockLoanRepo.On("GetLoanByID", mock.Anything, loanID).
+				// Mock GetLoanByIDForUpdate
+				mockTransaction.On("GetLoanByIDForUpdate", mock.Anything, loanID).
 					Return(&domians.Loan{
 						ID:              loanID,
 						State:           constant.Approved,
-						PrincipalAmount: 150000,
+						PrincipalAmount: money.New(150000),
 					}, nil).Once()
 
+				// Mock Invest
+				mockLedger.On("Invest", mock.Anything, mockTransaction, mock.Anything, userID, payload.Amount).
+					Return(nil).Once()
+
 				// Mock UpdateLoan
 				mockTransaction.On("UpdateLoan", mock.Anything, mock.MatchedBy(func(loan *domians.Loan) bool {
 					return loan.ID == loanID
 				}), mock.MatchedBy(func(data map[string]any) bool {
-					return data["principal_amount"] == float64(0) && // Sesuaikan nilai aktual
+					return data["funded_amount"] == money.New(150000) &&
 						data["state"] == constant.Invested
 				})).Return(nil).Once()
 
@@ -428,10 +541,42 @@ func TestUsecase_StoreInvest(t *testing.T) {
 						state.ActionBy == userID
 				})).Return(nil).Once()
 
+				// Mock Generate
+				mockAgreement.On("Generate", mock.Anything, mockTransaction, mock.MatchedBy(func(loan *domians.Loan) bool {
+					return loan.ID == loanID && loan.State == constant.Invested
+				})).Return([]domians.LoanInvestor{
+					{
+						ID:                  5,
+						LoanID:              loanID,
+						InvestorID:          userID,
+						AmountInvested:      payload.Amount,
+						AgreementLetterLink: "agreements/loan-1/investment-5.html",
+						Investor:            domians.User{ID: userID, Email: "investor@mail.com", Name: "Investor"},
+					},
+				}, nil).Once()
+
+				// Mock Enqueue
+				mockNotifier.On("Enqueue", mock.Anything, mockTransaction, constant.TemplateLoanFunded,
+					[]notification.Recipient{
+						{
+							UserID:  userID,
+							Address: "investor@mail.com",
+							Data: notification.LoanFunded{
+								Name:           "Investor",
+								LoanID:         loanID,
+								AmountInvested: payload.Amount,
+								AgreementLink:  "agreements/loan-1/investment-5.html",
+							},
+						},
+					}).Return(nil).Once()
+
 				// Mock End
 				mockTransaction.On("End", nil).
 					Return(nil).Once()
 
+				// the investment closing the loan is counted after the commit
+				mockRecorder.On("LoanEvent", constant.EventInvest).Once()
+				mockRecorder.On("LoanInvested", money.Currency(""), payload.Amount).Once()
 			},
 			args: args{
 				ctx:     context.Background(),
@@ -441,16 +586,102 @@ func TestUsecase_StoreInvest(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Success - Partially funded",
+			mockBehavior: func() {
+				// Mock Begin
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+
+				// Mock GetLoanByIDForUpdate
+				mockTransaction.On("GetLoanByIDForUpdate", mock.Anything, loanID).
+					Return(&domians.Loan{
+						ID:              loanID,
+						State:           constant.Approved,
+						PrincipalAmount: money.New(300000),
+						FundedAmount:    money.New(100000),
+						Currency:        money.IDR,
+					}, nil).Once()
+
+				// Mock Invest
+				mockLedger.On("Invest", mock.Anything, mockTransaction, mock.Anything, userID, payload.Amount).
+					Return(nil).Once()
+
+				// Mock UpdateLoan, principal is left untouched
+				mockTransaction.On("UpdateLoan", mock.Anything, &domians.Loan{ID: loanID}, mock.MatchedBy(func(data map[string]any) bool {
+					_, principalChanged := data["principal_amount"]
+					return data["funded_amount"] == money.New(250000) &&
+						data["state"] == constant.Approved &&
+						!principalChanged
+				})).Return(nil).Once()
+
+				// Mock CreateLoanState
+				mockTransaction.On("CreateLoanState", mock.Anything, mock.MatchedBy(func(state *domians.LoanStateHistory) bool {
+					return state.PreviousState == constant.Approved &&
+						state.NewState == constant.Approved
+				})).Return(nil).Once()
+
+				// Mock InvestLoan
+				mockTransaction.On("InvestLoan", mock.Anything, mock.Anything).Return(nil).Once()
+
+				// Mock End
+				mockTransaction.On("End", nil).Return(nil).Once()
+
+				// a partial investment is counted as a fund event
+				mockRecorder.On("LoanEvent", constant.EventFund).Once()
+				mockRecorder.On("LoanInvested", money.IDR, payload.Amount).Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				loanID:  loanID,
+				userID:  userID,
+				payload: payload,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Error - Loan changed by every attempt",
+			mockBehavior: func() {
+				// Mock Begin, GetLoanByIDForUpdate and UpdateLoan for every attempt
+				mockTransaction.On("Begin").Return(mockTransaction, nil).
+					Times(constant.InvestMaxAttempts)
+				mockTransaction.On("GetLoanByIDForUpdate", mock.Anything, loanID).
+					Return(&domians.Loan{
+						ID:              loanID,
+						State:           constant.Approved,
+						PrincipalAmount: money.New(300000),
+						Version:         4,
+					}, nil).Times(constant.InvestMaxAttempts)
+				mockLedger.On("Invest", mock.Anything, mockTransaction, mock.Anything, userID, payload.Amount).
+					Return(nil).Times(constant.InvestMaxAttempts)
+				mockTransaction.On("UpdateLoan", mock.Anything, &domians.Loan{ID: loanID, Version: 4}, mock.MatchedBy(func(data map[string]any) bool {
+					return data["version"] == uint(5)
+				})).Return(constant.ErrLoanConflict).Times(constant.InvestMaxAttempts)
+
+				// Mock End
+				mockTransaction.On("End", constant.ErrLoanConflict).Return(nil).
+					Times(constant.InvestMaxAttempts)
+			},
+			args: args{
+				ctx:     context.Background(),
+				loanID:  loanID,
+				userID:  userID,
+				payload: payload,
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrLoanConflict,
+		},
 		{
 			name: "Error - Loan not found",
 			mockBehavior: func() {
 				// Mock Begin
 				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
 
-				// Mock GetLoanByID
-				mockLoanRepo.On("GetLoanByID", mock.Anything, loanID).
+				// Mock GetLoanByIDForUpdate
+				mockTransaction.On("GetLoanByIDForUpdate", mock.Anything, loanID).
 					Return(nil, errors.New("loan not found")).Once()
 
+				// Mock End
+				mockTransaction.On("End", constant.LoanNotFound).Return(nil).Once()
 			},
 			args: args{
 				ctx:     context.Background(),
@@ -467,12 +698,14 @@ func TestUsecase_StoreInvest(t *testing.T) {
 				// Mock Begin
 				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
 
-				// Mock GetLoanByID
-				mockLoanRepo.On("GetLoanByID", mock.Anything, loanID).
+				// Mock GetLoanByIDForUpdate
+				mockTransaction.On("GetLoanByIDForUpdate", mock.Anything, loanID).
 					Return(&domians.Loan{
 						State: constant.Proposed,
 					}, nil).Once()
 
+				// Mock End
+				mockTransaction.On("End", constant.ErrStateInvest).Return(nil).Once()
 			},
 			args: args{
 				ctx:     context.Background(),
@@ -483,6 +716,36 @@ func TestUsecase_StoreInvest(t *testing.T) {
 			wantErr:     true,
 			expectedErr: constant.ErrStateInvest,
 		},
+		{
+			name: "Error - Insufficient wallet balance",
+			mockBehavior: func() {
+				// Mock Begin
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+
+				// Mock GetLoanByIDForUpdate
+				mockTransaction.On("GetLoanByIDForUpdate", mock.Anything, loanID).
+					Return(&domians.Loan{
+						ID:              loanID,
+						State:           constant.Approved,
+						PrincipalAmount: money.New(300000),
+					}, nil).Once()
+
+				// Mock Invest
+				mockLedger.On("Invest", mock.Anything, mockTransaction, mock.Anything, userID, payload.Amount).
+					Return(constant.ErrInsufficientBalance).Once()
+
+				// Mock End
+				mockTransaction.On("End", constant.ErrInsufficientBalance).Return(nil).Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				loanID:  loanID,
+				userID:  userID,
+				payload: payload,
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrInsufficientBalance,
+		},
 		{
 
 			name: "Error - Invalid amount",
@@ -490,13 +753,15 @@ func TestUsecase_StoreInvest(t *testing.T) {
 				// Mock Begin
 				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
 
-				// Mock GetLoanByID
-				mockLoanRepo.On("GetLoanByID", mock.Anything, loanID).
+				// Mock GetLoanByIDForUpdate
+				mockTransaction.On("GetLoanByIDForUpdate", mock.Anything, loanID).
 					Return(&domians.Loan{
 						State:           constant.Approved,
-						PrincipalAmount: 100000,
+						PrincipalAmount: money.New(100000),
 					}, nil).Once()
 
+				// Mock End
+				mockTransaction.On("End", constant.ErrInvestAmount).Return(nil).Once()
 			},
 			args: args{
 				ctx:     context.Background(),
@@ -518,10 +783,15 @@ func TestUsecase_StoreInvest(t *testing.T) {
 			uc := loan.Usecase{
 				LoanRepo:      mockLoanRepo,
 				DbTransaction: mockTransaction,
+				StateMachine:  statemachine.NewLoanMachine(permission.NewStatic(permission.Defaults)),
+				Agreement:     mockAgreement,
+				Notifier:      mockNotifier,
+				Ledger:        mockLedger,
+				Metrics:       mockRecorder,
 			}
 
 			// Call StoreInvest
-			err := uc.StoreInvest(tt.args.ctx, tt.args.loanID, tt.args.userID, tt.args.payload)
+			err := uc.StoreInvest(tt.args.ctx, tt.args.loanID, tt.args.userID, constant.RoleInvestor, tt.args.payload)
 
 			// Assertions
 			if tt.wantErr {
@@ -534,6 +804,10 @@ func TestUsecase_StoreInvest(t *testing.T) {
 			// Verify all mocks
 			mockTransaction.AssertExpectations(t)
 			mockLoanRepo.AssertExpectations(t)
+			mockAgreement.AssertExpectations(t)
+			mockNotifier.AssertExpectations(t)
+			mockLedger.AssertExpectations(t)
+			mockRecorder.AssertExpectations(t)
 		})
 	}
 }
@@ -542,6 +816,7 @@ func TestUsecase_DisburseLoan(t *testing.T) {
 	// Mock dependencies
 	mockTransaction := new(mocks.DefaultLoanTransactionInterface)
 	mockLoanRepo := new(mocks.LoanRepoInterface)
+	mockLedger := new(mocks.BookInterface)
 
 	// Mock data
 	loanID := uint(2)
@@ -552,7 +827,7 @@ func TestUsecase_DisburseLoan(t *testing.T) {
 	//loanData := &domians.Loan{
 	//	ID:              loanID,
 	//	State:           constant.Proposed, // State tidak sesuai ekspektasi
-	//	PrincipalAmount: 1000000,
+	//	PrincipalAmount: money.New(1000000),
 	//}
 
 	type args struct {
@@ -577,6 +852,8 @@ func TestUsecase_DisburseLoan(t *testing.T) {
 				mockLoanRepo.On("GetLoanByID", mock.Anything, loanID).
 					Return(nil, errors.New("loan not found")).Once()
 
+				// Mock End
+				mockTransaction.On("End", constant.LoanNotFound).Return(nil).Once()
 			},
 			args: args{
 				ctx:     context.Background(),
@@ -596,9 +873,12 @@ func TestUsecase_DisburseLoan(t *testing.T) {
 				mockLoanRepo.On("GetLoanByID", mock.Anything, loanID).
 					Return(&domians.Loan{
 						ID:              loanID,
-						PrincipalAmount: 1000000,
+						PrincipalAmount: money.New(1000000),
 						State:           constant.Proposed,
 					}, nil).Once()
+
+				// Mock End
+				mockTransaction.On("End", constant.ErrStateDisburse).Return(nil).Once()
 			},
 			args: args{
 				ctx:     context.Background(),
@@ -618,8 +898,12 @@ func TestUsecase_DisburseLoan(t *testing.T) {
 				// Mock GetLoanByID
 				mockLoanRepo.On("GetLoanByID", mock.Anything, loanID).
 					Return(&domians.Loan{
-						ID:    loanID,
-						State: constant.Invested,
+						ID:              loanID,
+						State:           constant.Invested,
+						PrincipalAmount: money.New(100000),
+						FundedAmount:    money.New(100000),
+						ROI:             money.New(12000),
+						Tenor:           12,
 					}, nil).Once()
 
 				// Mock UpdateLoan
@@ -629,7 +913,12 @@ func TestUsecase_DisburseLoan(t *testing.T) {
 					return data["state"] == constant.Disbursed
 				})).Return(nil).Once()
 
-				// Mock InvestLoan
+				// Mock Disburse
+				mockLedger.On("Disburse", mock.Anything, mockTransaction, mock.MatchedBy(func(loan *domians.Loan) bool {
+					return loan.ID == loanID && loan.State == constant.Disbursed
+				}), userID).Return(nil).Once()
+
+				// Mock DisburseDetail
 				mockTransaction.On("DisburseDetail", mock.Anything,
 					mock.MatchedBy(func(disbursed *domians.LoanDisbursementDetail) bool {
 						return disbursed.LoanID == loanID &&
@@ -637,6 +926,21 @@ func TestUsecase_DisburseLoan(t *testing.T) {
 							disbursed.SignedAgreementDoc == payload.AgreementLin
 					})).Return(nil).Once()
 
+				// Mock CreateInstallments
+				mockTransaction.On("CreateInstallments", mock.Anything,
+					mock.MatchedBy(func(installments []domians.LoanInstallment) bool {
+						var principal, interest money.Money
+						for _, installment := range installments {
+							principal = principal.Add(installment.PrincipalDue)
+							interest = interest.Add(installment.InterestDue)
+						}
+						return len(installments) == 12 &&
+							installments[0].LoanID == loanID &&
+							installments[0].Status == constant.InstallmentPending &&
+							principal == money.New(100000) &&
+							interest == money.New(12000)
+					})).Return(nil).Once()
+
 				// Mock CreateLoanState
 				mockTransaction.On("CreateLoanState", mock.Anything, mock.MatchedBy(func(state *domians.LoanStateHistory) bool {
 					return state.LoanID == loanID &&
@@ -668,8 +972,253 @@ func TestUsecase_DisburseLoan(t *testing.T) {
 			uc := loan.Usecase{
 				LoanRepo:      mockLoanRepo,
 				DbTransaction: mockTransaction,
+				StateMachine:  statemachine.NewLoanMachine(permission.NewStatic(permission.Defaults)),
+				Ledger:        mockLedger,
 			}
-			err := uc.DisburseLoan(tt.args.ctx, tt.args.loanID, tt.args.userID, tt.args.payload)
+			err := uc.DisburseLoan(tt.args.ctx, tt.args.loanID, tt.args.userID, constant.RoleStaff, tt.args.payload)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockTransaction.AssertExpectations(t)
+			mockLoanRepo.AssertExpectations(t)
+			mockLedger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsecase_CancelLoan(t *testing.T) {
+	// Mock dependencies
+	mockTransaction := new(mocks.DefaultLoanTransactionInterface)
+	mockLoanRepo := new(mocks.LoanRepoInterface)
+	mockLedger := new(mocks.BookInterface)
+
+	// Mock data
+	loanID := uint(3)
+	borrowerID := uint(4)
+	payload := loan.CancelLoanRequest{
+		ReasonCode: constant.CancelFundingTooSlow,
+		Reason:     "need the money sooner",
+	}
+
+	type args struct {
+		ctx     context.Context
+		userID  uint
+		payload loan.CancelLoanRequest
+	}
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		args         args
+		wantErr      bool
+		expectedErr  error
+	}{
+		{
+			name:         "Error - unknown reason code",
+			mockBehavior: func() {},
+			args: args{
+				ctx:     context.Background(),
+				userID:  borrowerID,
+				payload: loan.CancelLoanRequest{ReasonCode: "BORED"},
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrReasonCode,
+		},
+		{
+			name: "Error - loan owned by another borrower",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockLoanRepo.On("GetLoanByID", mock.Anything, loanID).
+					Return(&domians.Loan{ID: loanID, BorrowerID: borrowerID, State: constant.Proposed}, nil).Once()
+				mockTransaction.On("End", constant.ErrLoanNotOwned).Return(nil).Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				userID:  borrowerID + 1,
+				payload: payload,
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrLoanNotOwned,
+		},
+		{
+			name: "Success - partly funded loan releases investments",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockLoanRepo.On("GetLoanByID", mock.Anything, loanID).
+					Return(&domians.Loan{
+						ID:              loanID,
+						BorrowerID:      borrowerID,
+						PrincipalAmount: money.New(100000),
+						FundedAmount:    money.New(60000),
+						State:           constant.Approved,
+					}, nil).Once()
+
+				// nothing stays funded on the cancelled loan
+				mockTransaction.On("UpdateLoan", mock.Anything, &domians.Loan{ID: loanID}, mock.MatchedBy(func(data map[string]any) bool {
+					return data["state"] == constant.Cancelled &&
+						data["funded_amount"] == money.Zero
+				})).Return(nil).Once()
+				mockTransaction.On("CreateLoanState", mock.Anything, mock.MatchedBy(func(state *domians.LoanStateHistory) bool {
+					return state.PreviousState == constant.Approved &&
+						state.NewState == constant.Cancelled &&
+						state.ReasonCode == payload.ReasonCode &&
+						state.Remarks == payload.Reason &&
+						state.ActionBy == borrowerID
+				})).Return(nil).Once()
+				// investors get their money back in their wallets
+				investors := []domians.LoanInvestor{
+					{ID: 7, LoanID: loanID, InvestorID: 9, AmountInvested: money.New(60000), Status: constant.InvestmentActive},
+				}
+				mockTransaction.On("GetLoanInvestors", mock.Anything, loanID).Return(investors, nil).Once()
+				mockLedger.On("Release", mock.Anything, mockTransaction, mock.Anything, investors, borrowerID).
+					Return(nil).Once()
+				mockTransaction.On("ReleaseInvestments", mock.Anything, loanID, mock.Anything).
+					Return(nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				userID:  borrowerID,
+				payload: payload,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := loan.Usecase{
+				LoanRepo:      mockLoanRepo,
+				DbTransaction: mockTransaction,
+				StateMachine:  statemachine.NewLoanMachine(permission.NewStatic(permission.Defaults)),
+				Ledger:        mockLedger,
+			}
+			err := uc.CancelLoan(tt.args.ctx, loanID, tt.args.userID, constant.RoleBorrower, tt.args.payload)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockTransaction.AssertExpectations(t)
+			mockLoanRepo.AssertExpectations(t)
+			mockLedger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsecase_RejectLoan(t *testing.T) {
+	// Mock dependencies
+	mockTransaction := new(mocks.DefaultLoanTransactionInterface)
+	mockLoanRepo := new(mocks.LoanRepoInterface)
+
+	// Mock data
+	loanID := uint(3)
+	staffID := uint(2)
+	payload := loan.RejectLoanRequest{
+		ReasonCode: constant.RejectIncompleteDocuments,
+		Reason:     "missing the business permit",
+	}
+
+	type args struct {
+		ctx     context.Context
+		role    string
+		payload loan.RejectLoanRequest
+	}
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		args         args
+		wantErr      bool
+		expectedErr  error
+	}{
+		{
+			name:         "Error - unknown reason code",
+			mockBehavior: func() {},
+			args: args{
+				ctx:     context.Background(),
+				role:    constant.RoleStaff,
+				payload: loan.RejectLoanRequest{ReasonCode: "TOO_RICH"},
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrReasonCode,
+		},
+		{
+			name: "Error - loan is not proposed",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockLoanRepo.On("GetLoanByID", mock.Anything, loanID).
+					Return(&domians.Loan{ID: loanID, State: constant.Approved}, nil).Once()
+				mockTransaction.On("End", constant.ErrStateReject).Return(nil).Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				role:    constant.RoleStaff,
+				payload: payload,
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrStateReject,
+		},
+		{
+			name: "Error - borrower cannot reject",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockLoanRepo.On("GetLoanByID", mock.Anything, loanID).
+					Return(&domians.Loan{ID: loanID, State: constant.Proposed}, nil).Once()
+				mockTransaction.On("End", constant.ErrTransitionForbidden).Return(nil).Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				role:    constant.RoleBorrower,
+				payload: payload,
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrTransitionForbidden,
+		},
+		{
+			name: "Success - reject proposed loan",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockLoanRepo.On("GetLoanByID", mock.Anything, loanID).
+					Return(&domians.Loan{ID: loanID, State: constant.Proposed}, nil).Once()
+				mockTransaction.On("UpdateLoan", mock.Anything, &domians.Loan{ID: loanID}, mock.MatchedBy(func(data map[string]any) bool {
+					return data["state"] == constant.Rejected
+				})).Return(nil).Once()
+				// the history keeps why the loan was rejected
+				mockTransaction.On("CreateLoanState", mock.Anything, mock.MatchedBy(func(state *domians.LoanStateHistory) bool {
+					return state.PreviousState == constant.Proposed &&
+						state.NewState == constant.Rejected &&
+						state.ReasonCode == payload.ReasonCode &&
+						state.Remarks == payload.Reason &&
+						state.ActionBy == staffID
+				})).Return(nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				role:    constant.RoleStaff,
+				payload: payload,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := loan.Usecase{
+				LoanRepo:      mockLoanRepo,
+				DbTransaction: mockTransaction,
+				StateMachine:  statemachine.NewLoanMachine(permission.NewStatic(permission.Defaults)),
+			}
+			err := uc.RejectLoan(tt.args.ctx, loanID, staffID, tt.args.role, tt.args.payload)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -687,8 +1236,10 @@ func TestUsecase_DisburseLoan(t *testing.T) {
 func TestUsecase_GetLoans(t *testing.T) {
 	loanRepo := new(mocks.LoanRepoInterface)
 	type args struct {
-		ctx   context.Context
-		query dto.GetListQuery
+		ctx    context.Context
+		userID uint
+		role   string
+		query  dto.GetListQuery
 	}
 	reqParam := dto.GetListQuery{
 		PerPage: 10,
@@ -699,9 +1250,9 @@ func TestUsecase_GetLoans(t *testing.T) {
 		{
 			ID:                  1,
 			BorrowerID:          101,
-			PrincipalAmount:     500000,
-			Rate:                7.5,
-			ROI:                 37500,
+			PrincipalAmount:     money.New(500000),
+			Rate:                money.MustParseRate("7.5"),
+			ROI:                 money.New(37500),
 			State:               "proposed",
 			AgreementLetterLink: "",
 			CreatedAt:           time.Now(),
@@ -709,9 +1260,9 @@ func TestUsecase_GetLoans(t *testing.T) {
 		{
 			ID:                  2,
 			BorrowerID:          102,
-			PrincipalAmount:     1000000,
-			Rate:                5.5,
-			ROI:                 55000,
+			PrincipalAmount:     money.New(1000000),
+			Rate:                money.MustParseRate("5.5"),
+			ROI:                 money.New(55000),
 			State:               "approved",
 			AgreementLetterLink: "http://example.com/agreement_2.pdf",
 			CreatedAt:           time.Now(),
@@ -729,18 +1280,64 @@ func TestUsecase_GetLoans(t *testing.T) {
 		{
 			name: "success - get list loan",
 			mockBehavior: func() {
-				loanRepo.On("GetLoans", mock.Anything, reqParam).
+				loanRepo.On("GetLoans", mock.Anything, reqParam, dto.LoanFilter{}).
 					Return(LoanList, int64(50), nil).Once()
 			},
 			args: args{
-				ctx:   context.Background(),
-				query: reqParam,
+				ctx:    context.Background(),
+				userID: 1,
+				role:   constant.RoleStaff,
+				query:  reqParam,
 			},
 			want:        LoanList,
 			want1:       50,
 			wantErr:     false,
 			expectedErr: nil,
 		},
+		{
+			name: "success - borrower only lists own loans",
+			mockBehavior: func() {
+				loanRepo.On("GetLoans", mock.Anything, reqParam, dto.LoanFilter{BorrowerID: 101}).
+					Return(LoanList[:1], int64(1), nil).Once()
+			},
+			args: args{
+				ctx:    context.Background(),
+				userID: 101,
+				role:   constant.RoleBorrower,
+				query:  reqParam,
+			},
+			want:  LoanList[:1],
+			want1: 1,
+		},
+		{
+			name: "success - investor lists open and invested loans",
+			mockBehavior: func() {
+				loanRepo.On("GetLoans", mock.Anything, reqParam, dto.LoanFilter{
+					InvestorID: 5,
+					OpenStates: []string{constant.Approved},
+				}).Return(LoanList[1:], int64(1), nil).Once()
+			},
+			args: args{
+				ctx:    context.Background(),
+				userID: 5,
+				role:   constant.RoleInvestor,
+				query:  reqParam,
+			},
+			want:  LoanList[1:],
+			want1: 1,
+		},
+		{
+			name:         "error - unknown role",
+			mockBehavior: func() {},
+			args: args{
+				ctx:    context.Background(),
+				userID: 5,
+				role:   "GUEST",
+				query:  reqParam,
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrLoanForbidden,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -748,14 +1345,17 @@ func TestUsecase_GetLoans(t *testing.T) {
 
 			uc := loan.Usecase{
 				LoanRepo: loanRepo,
+				Policy:   policy.NewLoanPolicy(loanRepo, permission.NewStatic(permission.Defaults)),
 			}
-			_, _, err := uc.GetLoans(tt.args.ctx, tt.args.query)
+			got, got1, err := uc.GetLoans(tt.args.ctx, tt.args.userID, tt.args.role, tt.args.query)
 
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.Equal(t, tt.expectedErr, err)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+				assert.Equal(t, tt.want1, got1)
 			}
 
 			loanRepo.AssertExpectations(t)
@@ -3,8 +3,11 @@ package loan
 import (
 	"context"
 	"fmt"
-	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
 	"github.com/bowoBp/LoanFlow/internal/dto"
+	"github.com/bowoBp/LoanFlow/internal/i18n"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"math"
 	"time"
 )
 
@@ -20,27 +23,45 @@ type (
 		) (*dto.Response, error)
 		GetLoan(
 			ctx context.Context,
-			loanID uint,
+			loanID, userID uint,
+			role string,
 		) (*dto.Response, error)
 		ApproveLoan(
 			ctx context.Context,
 			loanID, userID uint,
+			role string,
 			payload ApproveLoanRequest,
 		) (*dto.Response, error)
 		DisburseLoan(
 			ctx context.Context,
 			loanID, userID uint,
+			role string,
 			payload DisburseLoanRequest,
 		) (*dto.Response, error)
 		StoreInvest(
 			ctx context.Context,
 			loanID, userID uint,
+			role string,
 			payload InvestLoanRequest,
 		) (*dto.Response, error)
 		GetLoans(
 			ctx context.Context,
+			userID uint,
+			role string,
 			query dto.GetListQuery,
 		) (*dto.Response, error)
+		RejectLoan(
+			ctx context.Context,
+			loanID, userID uint,
+			role string,
+			payload RejectLoanRequest,
+		) (*dto.Response, error)
+		CancelLoan(
+			ctx context.Context,
+			loanID, userID uint,
+			role string,
+			payload CancelLoanRequest,
+		) (*dto.Response, error)
 	}
 )
 
@@ -53,9 +74,10 @@ func (ctrl Controller) CreateLoan(
 	if err != nil {
 		return nil, err
 	}
-	return dto.NewSuccessResponse(
+	return i18n.Success(
+		ctx,
 		payload,
-		"success create loan",
+		"success.loan_created",
 		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
 	), nil
 }
@@ -63,16 +85,18 @@ func (ctrl Controller) CreateLoan(
 func (ctrl Controller) ApproveLoan(
 	ctx context.Context,
 	loanID, userID uint,
+	role string,
 	payload ApproveLoanRequest,
 ) (*dto.Response, error) {
 	start := time.Now()
-	err := ctrl.Uc.ApproveLoan(ctx, loanID, userID, payload)
+	err := ctrl.Uc.ApproveLoan(ctx, loanID, userID, role, payload)
 	if err != nil {
 		return nil, err
 	}
-	return dto.NewSuccessResponse(
+	return i18n.Success(
+		ctx,
 		nil,
-		"loan success approved",
+		"success.loan_approved",
 		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
 	), nil
 }
@@ -80,16 +104,18 @@ func (ctrl Controller) ApproveLoan(
 func (ctrl Controller) StoreInvest(
 	ctx context.Context,
 	loanID, userID uint,
+	role string,
 	payload InvestLoanRequest,
 ) (*dto.Response, error) {
 	start := time.Now()
-	err := ctrl.Uc.StoreInvest(ctx, loanID, userID, payload)
+	err := ctrl.Uc.StoreInvest(ctx, loanID, userID, role, payload)
 	if err != nil {
 		return nil, err
 	}
-	return dto.NewSuccessResponse(
+	return i18n.Success(
+		ctx,
 		nil,
-		"success invested",
+		"success.loan_invested",
 		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
 	), nil
 }
@@ -97,50 +123,99 @@ func (ctrl Controller) StoreInvest(
 func (ctrl Controller) DisburseLoan(
 	ctx context.Context,
 	loanID, userID uint,
+	role string,
 	payload DisburseLoanRequest,
 ) (*dto.Response, error) {
 	start := time.Now()
-	err := ctrl.Uc.DisburseLoan(ctx, loanID, userID, payload)
+	err := ctrl.Uc.DisburseLoan(ctx, loanID, userID, role, payload)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		nil,
+		"success.loan_disbursed",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) RejectLoan(
+	ctx context.Context,
+	loanID, userID uint,
+	role string,
+	payload RejectLoanRequest,
+) (*dto.Response, error) {
+	start := time.Now()
+	err := ctrl.Uc.RejectLoan(ctx, loanID, userID, role, payload)
 	if err != nil {
 		return nil, err
 	}
-	return dto.NewSuccessResponse(
+	return i18n.Success(
+		ctx,
 		nil,
-		"loan success Disbursed",
+		"success.loan_rejected",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) CancelLoan(
+	ctx context.Context,
+	loanID, userID uint,
+	role string,
+	payload CancelLoanRequest,
+) (*dto.Response, error) {
+	start := time.Now()
+	err := ctrl.Uc.CancelLoan(ctx, loanID, userID, role, payload)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		nil,
+		"success.loan_cancelled",
 		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
 	), nil
 }
 
 func (ctrl Controller) GetLoan(
 	ctx context.Context,
-	loanID uint,
+	loanID, userID uint,
+	role string,
 ) (*dto.Response, error) {
 	start := time.Now()
-	res, err := ctrl.Uc.GetLoan(ctx, loanID)
+	res, err := ctrl.Uc.GetLoan(ctx, loanID, userID, role)
 	if err != nil {
-		return nil, constant.LoanNotFound
+		return nil, err
 	}
 	result := &ResponseLoan{
-		ID:              res.ID,
-		BorrowerID:      res.BorrowerID,
-		PrincipalAmount: res.PrincipalAmount,
-		Rate:            res.Rate,
-		Roi:             res.ROI,
-		State:           res.State,
-		AgreementLetter: res.AgreementLetterLink,
-		CreatedAt:       res.CreatedAt,
-		UpdatedAt:       res.UpdatedAt,
-	}
-
-	return dto.NewSuccessResponse(
+		ID:               res.ID,
+		BorrowerID:       res.BorrowerID,
+		Currency:         res.Currency,
+		PrincipalAmount:  res.PrincipalAmount,
+		FundedAmount:     res.FundedAmount,
+		RemainingAmount:  remainingAmount(*res),
+		FundedPercentage: fundedPercentage(*res),
+		Rate:             res.Rate,
+		Roi:              res.ROI,
+		Tenor:            res.Tenor,
+		State:            res.State,
+		AgreementLetter:  res.AgreementLetterLink,
+		CreatedAt:        res.CreatedAt,
+		UpdatedAt:        res.UpdatedAt,
+	}
+
+	return i18n.Success(
+		ctx,
 		result,
-		"success get loan",
+		"success.loan_detail",
 		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
 	), nil
 }
 
 func (ctrl Controller) GetLoans(
 	ctx context.Context,
+	userID uint,
+	role string,
 	query dto.GetListQuery,
 ) (*dto.Response, error) {
 	start := time.Now()
@@ -150,20 +225,24 @@ func (ctrl Controller) GetLoans(
 	if query.Page < 1 {
 		query.Page = 1
 	}
-	loans, count, err := ctrl.Uc.GetLoans(ctx, query)
+	loans, count, err := ctrl.Uc.GetLoans(ctx, userID, role, query)
 	if err != nil {
 		return nil, err
 	}
 	result := make([]ListsLoanResponse, len(loans))
 	for i, _ := range loans {
 		result[i] = ListsLoanResponse{
-			ID:              loans[i].ID,
-			BorrowerID:      loans[i].BorrowerID,
-			PrincipalAmount: loans[i].PrincipalAmount,
-			Rate:            loans[i].Rate,
-			Roi:             loans[i].ROI,
-			State:           loans[i].State,
-			CreatedAt:       loans[i].CreatedAt,
+			ID:               loans[i].ID,
+			BorrowerID:       loans[i].BorrowerID,
+			Currency:         loans[i].Currency,
+			PrincipalAmount:  loans[i].PrincipalAmount,
+			FundedAmount:     loans[i].FundedAmount,
+			RemainingAmount:  remainingAmount(loans[i]),
+			FundedPercentage: fundedPercentage(loans[i]),
+			Rate:             loans[i].Rate,
+			Roi:              loans[i].ROI,
+			State:            loans[i].State,
+			CreatedAt:        loans[i].CreatedAt,
 		}
 	}
 
@@ -178,10 +257,20 @@ func (ctrl Controller) GetLoans(
 		Data:       result,
 	}
 
-	return dto.NewSuccessResponse(
+	return i18n.Success(
+		ctx,
 		listPaginated,
-		"success get loan list",
+		"success.loans_listed",
 		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
 	), nil
 
 }
+
+func remainingAmount(loan domians.Loan) money.Money {
+	return loan.PrincipalAmount.Sub(loan.FundedAmount)
+}
+
+// fundedPercentage is rounded to two decimals.
+func fundedPercentage(loan domians.Loan) float64 {
+	return math.Round(loan.FundedAmount.Ratio(loan.PrincipalAmount)*10000) / 100
+}
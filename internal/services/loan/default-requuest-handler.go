@@ -1,6 +1,7 @@
 package loan
 
 import (
+	"github.com/bowoBp/LoanFlow/internal/constant"
 	"github.com/bowoBp/LoanFlow/internal/dto"
 	"github.com/gin-gonic/gin"
 	"net/http"
@@ -17,14 +18,16 @@ func (rh RequestHandler) CreateLoan(ctx *gin.Context) {
 	var payload = CreateLoanRequest{}
 	err := ctx.ShouldBind(&payload)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, dto.DefaultErrorResponseWithMessage(err.Error()))
+		ctx.Error(constant.InvalidRequest(err))
 		return
 	}
 	id, _ := ctx.Get("id")
+	role, _ := ctx.Get("userRole")
 	payload.ID = id.(uint)
+	payload.Role = role.(string)
 	res, err := rh.ctrl.CreateLoan(ctx, payload)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.DefaultErrorResponseWithMessage(err.Error()))
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusOK, res)
@@ -34,19 +37,26 @@ func (rh RequestHandler) ApproveLoan(ctx *gin.Context) {
 	var payload = ApproveLoanRequest{}
 	err := ctx.ShouldBind(&payload)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, dto.DefaultErrorResponseWithMessage(err.Error()))
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	loanID, err := strconv.ParseUint(ctx.Param("loanId"), 10, 32)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
 	}
 	id, _ := ctx.Get("id")
-	loanIdParam := ctx.Param("loanId")
-	loanID, err := strconv.Atoi(loanIdParam)
+	role, _ := ctx.Get("userRole")
 	res, err := rh.ctrl.ApproveLoan(
 		ctx,
 		uint(loanID),
 		id.(uint),
+		role.(string),
 		payload,
 	)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.DefaultErrorResponseWithMessage(err.Error()))
+		ctx.Error(err)
+		return
 	}
 	ctx.JSON(http.StatusOK, res)
 }
@@ -55,19 +65,26 @@ func (rh RequestHandler) StoreInvest(ctx *gin.Context) {
 	var payload = InvestLoanRequest{}
 	err := ctx.ShouldBind(&payload)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, dto.DefaultErrorResponseWithMessage(err.Error()))
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	loanID, err := strconv.ParseUint(ctx.Param("loanId"), 10, 32)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
 	}
 	id, _ := ctx.Get("id")
-	loanIdParam := ctx.Param("loanId")
-	loanID, err := strconv.Atoi(loanIdParam)
+	role, _ := ctx.Get("userRole")
 	res, err := rh.ctrl.StoreInvest(
 		ctx,
 		uint(loanID),
 		id.(uint),
+		role.(string),
 		payload,
 	)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.DefaultErrorResponseWithMessage(err.Error()))
+		ctx.Error(err)
+		return
 	}
 	ctx.JSON(http.StatusOK, res)
 }
@@ -76,33 +93,97 @@ func (rh RequestHandler) DisburseLoan(ctx *gin.Context) {
 	var payload = DisburseLoanRequest{}
 	err := ctx.ShouldBind(&payload)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, dto.DefaultErrorResponseWithMessage(err.Error()))
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	loanID, err := strconv.ParseUint(ctx.Param("loanId"), 10, 32)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
 	}
 	id, _ := ctx.Get("id")
-	loanIdParam := ctx.Param("loanId")
-	loanID, err := strconv.Atoi(loanIdParam)
+	role, _ := ctx.Get("userRole")
 	res, err := rh.ctrl.DisburseLoan(
 		ctx,
 		uint(loanID),
 		id.(uint),
+		role.(string),
 		payload,
 	)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.DefaultErrorResponseWithMessage(err.Error()))
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) RejectLoan(ctx *gin.Context) {
+	var payload = RejectLoanRequest{}
+	err := ctx.ShouldBind(&payload)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	loanID, err := strconv.ParseUint(ctx.Param("loanId"), 10, 32)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	id, _ := ctx.Get("id")
+	role, _ := ctx.Get("userRole")
+	res, err := rh.ctrl.RejectLoan(
+		ctx,
+		uint(loanID),
+		id.(uint),
+		role.(string),
+		payload,
+	)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) CancelLoan(ctx *gin.Context) {
+	var payload = CancelLoanRequest{}
+	err := ctx.ShouldBind(&payload)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	loanID, err := strconv.ParseUint(ctx.Param("loanId"), 10, 32)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	id, _ := ctx.Get("id")
+	role, _ := ctx.Get("userRole")
+	res, err := rh.ctrl.CancelLoan(
+		ctx,
+		uint(loanID),
+		id.(uint),
+		role.(string),
+		payload,
+	)
+	if err != nil {
+		ctx.Error(err)
+		return
 	}
 	ctx.JSON(http.StatusOK, res)
 }
 
 func (rh RequestHandler) GetLoan(ctx *gin.Context) {
-	loanIDParam := ctx.Param("loanId")
-	loanID, err := strconv.ParseUint(loanIDParam, 10, 32)
+	loanID, err := strconv.ParseUint(ctx.Param("loanId"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, dto.DefaultErrorResponseWithMessage(err.Error()))
+		ctx.Error(constant.InvalidRequest(err))
 		return
 	}
-	res, err := rh.ctrl.GetLoan(ctx, uint(loanID))
+	id, _ := ctx.Get("id")
+	role, _ := ctx.Get("userRole")
+	res, err := rh.ctrl.GetLoan(ctx, uint(loanID), id.(uint), role.(string))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.DefaultErrorResponseWithMessage(err.Error()))
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusOK, res)
@@ -111,12 +192,14 @@ func (rh RequestHandler) GetLoan(ctx *gin.Context) {
 func (rh RequestHandler) GetLoans(ctx *gin.Context) {
 	query, err := rh.getLoansQuery(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.DefaultErrorResponseWithMessage(err.Error()))
+		ctx.Error(constant.InvalidRequest(err))
 		return
 	}
-	res, err := rh.ctrl.GetLoans(ctx, query)
+	id, _ := ctx.Get("id")
+	role, _ := ctx.Get("userRole")
+	res, err := rh.ctrl.GetLoans(ctx, id.(uint), role.(string), query)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.DefaultErrorResponseWithMessage(err.Error()))
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusOK, res)
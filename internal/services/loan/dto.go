@@ -2,36 +2,49 @@ package loan
 
 import (
 	"github.com/bowoBp/LoanFlow/internal/dto"
+	"github.com/bowoBp/LoanFlow/pkg/money"
 	"time"
 )
 
 type (
 	CreateLoanRequest struct {
-		ID              uint    `json:"id"`
-		PrincipalAmount float64 `json:"principalAmount"`
-		Rate            float64 `json:"rate"`
+		ID              uint           `json:"id"`
+		Role            string         `json:"-"`
+		Currency        money.Currency `json:"currency" validate:"omitempty,currency"`
+		PrincipalAmount money.Money    `json:"principalAmount" validate:"amount_min=100000,amount_max=2000000000"`
+		Rate            money.Rate     `json:"rate" validate:"rate_min=0,rate_max=100"`
+		Tenor           uint           `json:"tenor" validate:"omitempty,max=60"`
 	}
 
 	ResponseLoan struct {
-		ID              uint      `json:"id"`
-		BorrowerID      uint      `json:"borrowerId"`
-		PrincipalAmount float64   `json:"principalAmount"`
-		Rate            float64   `json:"rate"`
-		Roi             float64   `json:"roi"`
-		State           string    `json:"state"`
-		AgreementLetter string    `json:"agreementLetter"`
-		CreatedAt       time.Time `json:"createdAt"`
-		UpdatedAt       time.Time `json:"updatedAt"`
+		ID               uint           `json:"id"`
+		BorrowerID       uint           `json:"borrowerId"`
+		Currency         money.Currency `json:"currency"`
+		PrincipalAmount  money.Money    `json:"principalAmount"`
+		FundedAmount     money.Money    `json:"fundedAmount"`
+		RemainingAmount  money.Money    `json:"remainingAmount"`
+		FundedPercentage float64        `json:"fundedPercentage"`
+		Rate             money.Rate     `json:"rate"`
+		Roi              money.Money    `json:"roi"`
+		Tenor            uint           `json:"tenor"`
+		State            string         `json:"state"`
+		AgreementLetter  string         `json:"agreementLetter"`
+		CreatedAt        time.Time      `json:"createdAt"`
+		UpdatedAt        time.Time      `json:"updatedAt"`
 	}
 
 	ListsLoanResponse struct {
-		ID              uint      `json:"id"`
-		BorrowerID      uint      `json:"borrowerId"`
-		PrincipalAmount float64   `json:"principalAmount"`
-		Rate            float64   `json:"rate"`
-		Roi             float64   `json:"roi"`
-		State           string    `json:"state"`
-		CreatedAt       time.Time `json:"createdAt"`
+		ID               uint           `json:"id"`
+		BorrowerID       uint           `json:"borrowerId"`
+		Currency         money.Currency `json:"currency"`
+		PrincipalAmount  money.Money    `json:"principalAmount"`
+		FundedAmount     money.Money    `json:"fundedAmount"`
+		RemainingAmount  money.Money    `json:"remainingAmount"`
+		FundedPercentage float64        `json:"fundedPercentage"`
+		Rate             money.Rate     `json:"rate"`
+		Roi              money.Money    `json:"roi"`
+		State            string         `json:"state"`
+		CreatedAt        time.Time      `json:"createdAt"`
 	}
 	ListLoanPaginate struct {
 		Pagination dto.PaginationResponse
@@ -39,14 +52,24 @@ type (
 	}
 
 	ApproveLoanRequest struct {
-		Proof string `json:"proof"`
+		Proof string `json:"proof" validate:"required,max=2048"`
 	}
 
 	DisburseLoanRequest struct {
-		AgreementLin string `json:"agreementLink"`
+		AgreementLin string `json:"agreementLink" validate:"required,max=2048"`
 	}
 
 	InvestLoanRequest struct {
-		Amount float64 `json:"amount"`
+		Amount money.Money `json:"amount" validate:"amount_min=0.01"`
+	}
+
+	RejectLoanRequest struct {
+		ReasonCode string `json:"reasonCode" validate:"required,reject_reason"`
+		Reason     string `json:"reason" validate:"max=500"`
+	}
+
+	CancelLoanRequest struct {
+		ReasonCode string `json:"reasonCode" validate:"required,cancel_reason"`
+		Reason     string `json:"reason" validate:"max=500"`
 	}
 )
@@ -2,32 +2,63 @@ package loan
 
 import (
 	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	"github.com/bowoBp/LoanFlow/internal/agreement"
 	"github.com/bowoBp/LoanFlow/internal/constant"
+	"github.com/bowoBp/LoanFlow/internal/ledger"
+	"github.com/bowoBp/LoanFlow/internal/notification"
+	"github.com/bowoBp/LoanFlow/internal/permission"
+	"github.com/bowoBp/LoanFlow/internal/policy"
+	"github.com/bowoBp/LoanFlow/internal/statemachine"
+	"github.com/bowoBp/LoanFlow/pkg/metrics"
 	"github.com/bowoBp/LoanFlow/pkg/middleware"
+	"github.com/bowoBp/LoanFlow/pkg/reporter"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
-	"log"
+	"log/slog"
 	"net/http"
 )
 
 type (
 	Router struct {
-		auth middleware.AuthInterface
-		rh   *RequestHandler
+		auth         middleware.AuthInterface
+		idempotency  middleware.IdempotencyInterface
+		verification middleware.EmailVerificationInterface
+		machine      *statemachine.Machine
+		rh           *RequestHandler
 	}
 )
 
 func NewRoute(
 	db *gorm.DB,
 	auth middleware.AuthInterface,
+	machine *statemachine.Machine,
+	agreement agreement.GeneratorInterface,
+	notifier notification.NotifierInterface,
+	book ledger.BookInterface,
+	idempotency middleware.IdempotencyInterface,
+	verification middleware.EmailVerificationInterface,
+	permissions permission.Resolver,
+	reporter reporter.Reporter,
+	recorder metrics.LoanRecorder,
 ) *Router {
+	loanRepo := Repository.NewLoanRepo(db)
 	return &Router{
-		auth: auth,
+		auth:         auth,
+		idempotency:  idempotency,
+		verification: verification,
+		machine:      machine,
 		rh: &RequestHandler{
 			ctrl: &Controller{
 				Uc: Usecase{
-					LoanRepo:      Repository.NewLoanRepo(db),
+					LoanRepo:      loanRepo,
 					DbTransaction: NewLoanTransaction(db),
+					StateMachine:  machine,
+					Agreement:     agreement,
+					Notifier:      notifier,
+					Ledger:        book,
+					Policy:        policy.NewLoanPolicy(loanRepo, permissions),
+					Reporter:      reporter,
+					Metrics:       recorder,
 				},
 			},
 		},
@@ -37,7 +68,7 @@ func NewRoute(
 // Minimal handler to return 200 OK with "Not Implemented" message
 func notImplementedHandler(c *gin.Context) {
 	loanId := c.Param("loanId")
-	log.Println("Loan ID:", loanId)
+	slog.InfoContext(c, "loan route not implemented", "loan_id", loanId)
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Not Implemented",
 		"loanId":  loanId,
@@ -49,59 +80,58 @@ func (r Router) Route(router *gin.RouterGroup) {
 
 	loans.GET(
 		"/",
-		r.auth.Authentication(),
-		r.auth.Authorize(
-			constant.RoleAdmin,
-			constant.RoleBorrower,
-			constant.RoleStaff,
-			constant.RoleInvestor,
-		),
+		r.auth.Authentication(constant.ScopeLoansRead),
+		r.auth.AuthorizePermission(constant.PermLoanRead),
 		r.rh.GetLoans,
 	)
 	loans.POST(
 		"/",
-		r.auth.Authentication(),
-		r.auth.Authorize(
-			constant.RoleAdmin,
-			constant.RoleBorrower,
-		),
+		r.auth.Authentication(constant.ScopeLoansCreate),
+		r.auth.AuthorizePermission(r.machine.Permission(constant.EventPropose)),
+		r.verification.Handle(),
+		r.idempotency.Handle(),
 		r.rh.CreateLoan,
 	)
 	loans.POST(
 		"/:loanId/approve",
-		r.auth.Authentication(),
-		r.auth.Authorize(
-			constant.RoleAdmin,
-			constant.RoleStaff,
-		),
+		r.auth.Authentication(constant.ScopeLoansApprove),
+		r.auth.AuthorizePermission(r.machine.Permission(constant.EventApprove)),
+		r.idempotency.Handle(),
 		r.rh.ApproveLoan,
 	)
 	loans.POST(
 		"/:loanId/invest",
-		r.auth.Authentication(),
-		r.auth.Authorize(
-			constant.RoleInvestor,
-		),
+		r.auth.Authentication(constant.ScopeLoansInvest),
+		r.auth.AuthorizePermission(r.machine.Permission(constant.EventInvest)),
+		r.verification.Handle(),
+		r.idempotency.Handle(),
 		r.rh.StoreInvest,
 	)
 	loans.POST(
 		"/:loanId/disburse",
-		r.auth.Authentication(),
-		r.auth.Authorize(
-			constant.RoleAdmin,
-			constant.RoleStaff,
-		),
+		r.auth.Authentication(constant.ScopeLoansDisburse),
+		r.auth.AuthorizePermission(r.machine.Permission(constant.EventDisburse)),
+		r.idempotency.Handle(),
 		r.rh.DisburseLoan,
 	)
+	loans.POST(
+		"/:loanId/reject",
+		r.auth.Authentication(constant.ScopeLoansReject),
+		r.auth.AuthorizePermission(r.machine.Permission(constant.EventReject)),
+		r.idempotency.Handle(),
+		r.rh.RejectLoan,
+	)
+	loans.POST(
+		"/:loanId/cancel",
+		r.auth.Authentication(constant.ScopeLoansCancel),
+		r.auth.AuthorizePermission(r.machine.Permission(constant.EventCancel)),
+		r.idempotency.Handle(),
+		r.rh.CancelLoan,
+	)
 	loans.GET(
 		"/:loanId",
-		r.auth.Authentication(),
-		r.auth.Authorize(
-			constant.RoleAdmin,
-			constant.RoleBorrower,
-			constant.RoleStaff,
-			constant.RoleInvestor,
-		),
+		r.auth.Authentication(constant.ScopeLoansRead),
+		r.auth.AuthorizePermission(constant.PermLoanRead),
 		r.rh.GetLoan,
 	)
 }
@@ -0,0 +1,204 @@
+package loan_test
+
+import (
+	"context"
+	"errors"
+	"github.com/bowoBp/LoanFlow/internal/adapter/mocks"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/permission"
+	"github.com/bowoBp/LoanFlow/internal/services/loan"
+	"github.com/bowoBp/LoanFlow/internal/statemachine"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryLoanDB keeps a single loan with the postgres row semantics used by
+// StoreInvest: a locked row blocks other lockers until the transaction
+// ends, and writes are only visible after commit.
+type memoryLoanDB struct {
+	// forUpdate false turns GetLoanByIDForUpdate into a plain read, so
+	// only the version check protects the loan
+	forUpdate bool
+
+	row       sync.Mutex
+	mu        sync.Mutex
+	loan      domians.Loan
+	investors []domians.LoanInvestor
+}
+
+func (db *memoryLoanDB) Begin() (loan.DefaultLoanTransactionInterface, error) {
+	return &memoryLoanTx{db: db}, nil
+}
+
+func (db *memoryLoanDB) End(err error) error {
+	return nil
+}
+
+func (db *memoryLoanDB) committed() domians.Loan {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.loan
+}
+
+type memoryLoanTx struct {
+	// methods StoreInvest does not call are left nil
+	loan.DefaultLoanTransactionInterface
+
+	db        *memoryLoanDB
+	locked    bool
+	changes   map[string]any
+	investors []domians.LoanInvestor
+}
+
+func (tx *memoryLoanTx) lock() {
+	if !tx.locked {
+		tx.db.row.Lock()
+		tx.locked = true
+	}
+}
+
+func (tx *memoryLoanTx) GetLoanByIDForUpdate(ctx context.Context, loanID uint) (*domians.Loan, error) {
+	if tx.db.forUpdate {
+		tx.lock()
+	}
+	current := tx.db.committed()
+	if !tx.db.forUpdate {
+		// let the other investors read the same version
+		time.Sleep(time.Millisecond)
+	}
+	return &current, nil
+}
+
+func (tx *memoryLoanTx) UpdateLoan(ctx context.Context, loan *domians.Loan, updateData map[string]any) error {
+	// an UPDATE waits for the row lock, then sees the committed version
+	tx.lock()
+	if tx.db.committed().Version != loan.Version {
+		return constant.ErrLoanConflict
+	}
+	tx.changes = updateData
+	return nil
+}
+
+func (tx *memoryLoanTx) CreateLoanState(ctx context.Context, state *domians.LoanStateHistory) error {
+	return nil
+}
+
+func (tx *memoryLoanTx) InvestLoan(ctx context.Context, investLoan *domians.LoanInvestor) error {
+	tx.investors = append(tx.investors, *investLoan)
+	return nil
+}
+
+func (tx *memoryLoanTx) End(err error) error {
+	if err == nil && tx.changes != nil {
+		tx.db.mu.Lock()
+		tx.db.loan.FundedAmount = tx.changes["funded_amount"].(money.Money)
+		tx.db.loan.State = tx.changes["state"].(string)
+		tx.db.loan.Version = tx.changes["version"].(uint)
+		tx.db.investors = append(tx.db.investors, tx.investors...)
+		tx.db.mu.Unlock()
+	}
+	if tx.locked {
+		tx.db.row.Unlock()
+	}
+	return nil
+}
+
+func TestUsecase_StoreInvest_Concurrent(t *testing.T) {
+	const (
+		investors = 20
+		amount    = 10000
+		principal = 100000
+	)
+
+	tests := []struct {
+		name      string
+		forUpdate bool
+	}{
+		{name: "row lock", forUpdate: true},
+		{name: "version check only", forUpdate: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &memoryLoanDB{
+				forUpdate: tt.forUpdate,
+				loan: domians.Loan{
+					ID:              1,
+					State:           constant.Approved,
+					PrincipalAmount: money.New(principal),
+				},
+			}
+			mockAgreement := new(mocks.GeneratorInterface)
+			mockAgreement.On("Generate", mock.Anything, mock.Anything, mock.Anything).
+				Return([]domians.LoanInvestor{}, nil)
+			mockNotifier := new(mocks.NotifierInterface)
+			mockNotifier.On("Enqueue", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return(nil)
+			mockLedger := new(mocks.BookInterface)
+			mockLedger.On("Invest", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return(nil)
+
+			uc := loan.Usecase{
+				DbTransaction: db,
+				StateMachine:  statemachine.NewLoanMachine(permission.NewStatic(permission.Defaults)),
+				Agreement:     mockAgreement,
+				Notifier:      mockNotifier,
+				Ledger:        mockLedger,
+			}
+
+			var (
+				wg        sync.WaitGroup
+				start     = make(chan struct{})
+				errs      = make([]error, investors)
+				succeeded int
+			)
+			for i := 0; i < investors; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					<-start
+					errs[i] = uc.StoreInvest(
+						context.Background(),
+						1,
+						uint(100+i),
+						constant.RoleInvestor,
+						loan.InvestLoanRequest{Amount: money.New(amount)},
+					)
+				}(i)
+			}
+			close(start)
+			wg.Wait()
+
+			for _, err := range errs {
+				switch {
+				case err == nil:
+					succeeded++
+				case errors.Is(err, constant.ErrInvestAmount),
+					errors.Is(err, constant.ErrStateInvest),
+					errors.Is(err, constant.ErrLoanConflict):
+				default:
+					t.Errorf("unexpected error: %v", err)
+				}
+			}
+
+			var invested money.Money
+			for _, investor := range db.investors {
+				invested = invested.Add(investor.AmountInvested)
+			}
+			final := db.committed()
+			assert.LessOrEqual(t, final.FundedAmount.Cmp(money.New(principal)), 0)
+			assert.Equal(t, final.FundedAmount, invested)
+			assert.Equal(t, money.New(int64(succeeded*amount)), invested)
+			if tt.forUpdate {
+				// nobody conflicts while the row is locked, the loan is filled
+				assert.Equal(t, principal/amount, succeeded)
+				assert.Equal(t, constant.Invested, final.State)
+			}
+		})
+	}
+}
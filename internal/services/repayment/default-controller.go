@@ -0,0 +1,99 @@
+package repayment
+
+import (
+	"context"
+	"fmt"
+	"github.com/bowoBp/LoanFlow/internal/dto"
+	"github.com/bowoBp/LoanFlow/internal/i18n"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"time"
+)
+
+type (
+	Controller struct {
+		Uc UsecaseInterface
+	}
+
+	ControllerInterface interface {
+		GetSchedule(
+			ctx context.Context,
+			loanID, userID uint,
+			role string,
+		) (*dto.Response, error)
+		Repay(
+			ctx context.Context,
+			loanID, userID uint,
+			role string,
+			payload RepayLoanRequest,
+		) (*dto.Response, error)
+	}
+)
+
+func (ctrl Controller) GetSchedule(
+	ctx context.Context,
+	loanID, userID uint,
+	role string,
+) (*dto.Response, error) {
+	start := time.Now()
+	loan, installments, err := ctrl.Uc.GetSchedule(ctx, loanID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ScheduleResponse{
+		LoanID:       loan.ID,
+		State:        loan.State,
+		Tenor:        loan.Tenor,
+		Outstanding:  Outstanding(installments),
+		Installments: make([]InstallmentResponse, len(installments)),
+	}
+	var totalDue, totalPaid money.Money
+	for i, installment := range installments {
+		totalDue = totalDue.Add(installment.AmountDue)
+		totalPaid = totalPaid.Add(installment.AmountPaid)
+		result.Installments[i] = InstallmentResponse{
+			Sequence:     installment.Sequence,
+			DueDate:      installment.DueDate,
+			PrincipalDue: installment.PrincipalDue,
+			InterestDue:  installment.InterestDue,
+			AmountDue:    installment.AmountDue,
+			AmountPaid:   installment.AmountPaid,
+			Status:       installment.Status,
+			PaidAt:       installment.PaidAt,
+		}
+	}
+	result.TotalDue = totalDue
+	result.TotalPaid = totalPaid
+
+	return i18n.Success(
+		ctx,
+		result,
+		"success.schedule_detail",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) Repay(
+	ctx context.Context,
+	loanID, userID uint,
+	role string,
+	payload RepayLoanRequest,
+) (*dto.Response, error) {
+	start := time.Now()
+	res, err := ctrl.Uc.Repay(ctx, loanID, userID, role, payload)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		RepayLoanResponse{
+			RepaymentID: res.RepaymentID,
+			LoanID:      loanID,
+			Amount:      res.Amount,
+			Outstanding: res.Outstanding,
+			State:       res.State,
+		},
+		"success.loan_repaid",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
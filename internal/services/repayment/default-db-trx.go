@@ -0,0 +1,165 @@
+package repayment
+
+import (
+	"context"
+	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"gorm.io/gorm"
+)
+
+type (
+	DefaultRepaymentTransaction struct {
+		db            *gorm.DB
+		loanRepo      Repository.LoanRepoInterface
+		repaymentRepo Repository.RepaymentRepoInterface
+		ledgerRepo    Repository.LedgerRepoInterface
+	}
+
+	DefaultRepaymentTransactionInterface interface {
+		Begin() (DefaultRepaymentTransactionInterface, error)
+		End(err error) error
+		GetLoanByIDForUpdate(
+			ctx context.Context,
+			loanID uint,
+		) (*domians.Loan, error)
+		UpdateLoan(
+			ctx context.Context,
+			loan *domians.Loan,
+			updateData map[string]any,
+		) error
+		CreateLoanState(
+			ctx context.Context,
+			state *domians.LoanStateHistory,
+		) error
+		GetInstallmentsByLoanID(
+			ctx context.Context,
+			loanID uint,
+		) ([]domians.LoanInstallment, error)
+		UpdateInstallment(
+			ctx context.Context,
+			installment *domians.LoanInstallment,
+			updateData map[string]any,
+		) error
+		CreateRepayment(
+			ctx context.Context,
+			repayment *domians.LoanRepayment,
+		) error
+		GetLoanInvestors(
+			ctx context.Context,
+			loanID uint,
+		) ([]domians.LoanInvestor, error)
+		GetOrCreateAccount(
+			ctx context.Context,
+			account *domians.LedgerAccount,
+		) (*domians.LedgerAccount, error)
+		UpdateLedgerAccount(
+			ctx context.Context,
+			account *domians.LedgerAccount,
+			updateData map[string]any,
+		) error
+		CreateJournalEntry(
+			ctx context.Context,
+			entry *domians.JournalEntry,
+		) error
+	}
+)
+
+func NewRepaymentTransaction(db *gorm.DB) DefaultRepaymentTransaction {
+	return DefaultRepaymentTransaction{
+		db: db,
+	}
+}
+
+func (repo DefaultRepaymentTransaction) GetLoanByIDForUpdate(
+	ctx context.Context,
+	loanID uint,
+) (*domians.Loan, error) {
+	return repo.loanRepo.GetLoanByIDForUpdate(ctx, loanID)
+}
+
+func (repo DefaultRepaymentTransaction) UpdateLoan(
+	ctx context.Context,
+	loan *domians.Loan,
+	updateData map[string]any,
+) error {
+	return repo.loanRepo.UpdateLoan(ctx, loan, updateData)
+}
+
+func (repo DefaultRepaymentTransaction) CreateLoanState(
+	ctx context.Context,
+	state *domians.LoanStateHistory,
+) error {
+	return repo.loanRepo.CreateLoanState(ctx, state)
+}
+
+func (repo DefaultRepaymentTransaction) GetInstallmentsByLoanID(
+	ctx context.Context,
+	loanID uint,
+) ([]domians.LoanInstallment, error) {
+	return repo.repaymentRepo.GetInstallmentsByLoanID(ctx, loanID)
+}
+
+func (repo DefaultRepaymentTransaction) UpdateInstallment(
+	ctx context.Context,
+	installment *domians.LoanInstallment,
+	updateData map[string]any,
+) error {
+	return repo.repaymentRepo.UpdateInstallment(ctx, installment, updateData)
+}
+
+func (repo DefaultRepaymentTransaction) CreateRepayment(
+	ctx context.Context,
+	repayment *domians.LoanRepayment,
+) error {
+	return repo.repaymentRepo.CreateRepayment(ctx, repayment)
+}
+
+func (repo DefaultRepaymentTransaction) GetLoanInvestors(
+	ctx context.Context,
+	loanID uint,
+) ([]domians.LoanInvestor, error) {
+	return repo.loanRepo.GetLoanInvestors(ctx, loanID)
+}
+
+func (repo DefaultRepaymentTransaction) GetOrCreateAccount(
+	ctx context.Context,
+	account *domians.LedgerAccount,
+) (*domians.LedgerAccount, error) {
+	return repo.ledgerRepo.GetOrCreateAccount(ctx, account)
+}
+
+func (repo DefaultRepaymentTransaction) UpdateLedgerAccount(
+	ctx context.Context,
+	account *domians.LedgerAccount,
+	updateData map[string]any,
+) error {
+	return repo.ledgerRepo.UpdateLedgerAccount(ctx, account, updateData)
+}
+
+func (repo DefaultRepaymentTransaction) CreateJournalEntry(
+	ctx context.Context,
+	entry *domians.JournalEntry,
+) error {
+	return repo.ledgerRepo.CreateJournalEntry(ctx, entry)
+}
+
+func (repo DefaultRepaymentTransaction) Begin() (DefaultRepaymentTransactionInterface, error) {
+	evoTrx := repo.db.Begin()
+	if err := evoTrx.Error; err != nil {
+		return DefaultRepaymentTransaction{}, err
+	}
+	newRepaymentTrx := &DefaultRepaymentTransaction{
+		db:            evoTrx,
+		loanRepo:      Repository.NewLoanRepo(evoTrx),
+		repaymentRepo: Repository.NewRepaymentRepo(evoTrx),
+		ledgerRepo:    Repository.NewLedgerRepo(evoTrx),
+	}
+	return newRepaymentTrx, nil
+}
+
+func (repo DefaultRepaymentTransaction) End(err error) error {
+	if err != nil {
+		return repo.db.Rollback().Error
+	}
+	return repo.db.Commit().Error
+}
@@ -0,0 +1,58 @@
+package repayment
+
+import (
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type (
+	RequestHandler struct {
+		ctrl ControllerInterface
+	}
+)
+
+func (rh RequestHandler) GetSchedule(ctx *gin.Context) {
+	loanID, err := strconv.ParseUint(ctx.Param("loanId"), 10, 32)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	id, _ := ctx.Get("id")
+	role, _ := ctx.Get("userRole")
+	res, err := rh.ctrl.GetSchedule(ctx, uint(loanID), id.(uint), role.(string))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) Repay(ctx *gin.Context) {
+	var payload = RepayLoanRequest{}
+	err := ctx.ShouldBind(&payload)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	loanID, err := strconv.ParseUint(ctx.Param("loanId"), 10, 32)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	id, _ := ctx.Get("id")
+	role, _ := ctx.Get("userRole")
+	res, err := rh.ctrl.Repay(
+		ctx,
+		uint(loanID),
+		id.(uint),
+		role.(string),
+		payload,
+	)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,193 @@
+package repayment
+
+import (
+	"context"
+	"fmt"
+	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/ledger"
+	"github.com/bowoBp/LoanFlow/internal/policy"
+	"github.com/bowoBp/LoanFlow/internal/statemachine"
+	"github.com/bowoBp/LoanFlow/pkg/reporter"
+	"time"
+)
+
+type (
+	Usecase struct {
+		LoanRepo      Repository.LoanRepoInterface
+		RepaymentRepo Repository.RepaymentRepoInterface
+		DbTransaction Repository.TransactionUnit[DefaultRepaymentTransactionInterface]
+		StateMachine  *statemachine.Machine
+		Ledger        ledger.BookInterface
+		Policy        policy.LoanPolicy
+		Reporter      reporter.Reporter
+	}
+
+	UsecaseInterface interface {
+		GetSchedule(
+			ctx context.Context,
+			loanID, userID uint,
+			role string,
+		) (*domians.Loan, []domians.LoanInstallment, error)
+		Repay(
+			ctx context.Context,
+			loanID, userID uint,
+			role string,
+			payload RepayLoanRequest,
+		) (RepaymentResult, error)
+	}
+)
+
+func (uc Usecase) GetSchedule(
+	ctx context.Context,
+	loanID, userID uint,
+	role string,
+) (*domians.Loan, []domians.LoanInstallment, error) {
+	loan, err := uc.LoanRepo.GetLoanByID(ctx, loanID)
+	if err != nil || loan == nil {
+		return nil, nil, constant.LoanNotFound
+	}
+	err = uc.Policy.CanView(ctx, policy.Subject{ID: userID, Role: role}, loan)
+	if err != nil {
+		return nil, nil, err
+	}
+	installments, err := uc.RepaymentRepo.GetInstallmentsByLoanID(ctx, loanID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(installments) == 0 {
+		return nil, nil, constant.ErrScheduleNotFound
+	}
+	return loan, installments, nil
+}
+
+func (uc Usecase) Repay(
+	ctx context.Context,
+	loanID, userID uint,
+	role string,
+	payload RepayLoanRequest,
+) (result RepaymentResult, err error) {
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return result, err
+	}
+	defer func(tx DefaultRepaymentTransactionInterface, err *error) {
+		// recover panic
+		if r := recover(); r != nil {
+			*err = uc.Reporter.Recover(ctx, r)
+		}
+		// end transaction (rollback or commit)
+		errTrx := tx.End(*err)
+		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
+		}
+	}(dbTrx, &err)
+
+	// the loan row stays locked until the transaction ends so concurrent
+	// repayments are allocated against the latest paid amounts
+	loan, err := dbTrx.GetLoanByIDForUpdate(ctx, loanID)
+	if err != nil || loan == nil {
+		return result, constant.LoanNotFound
+	}
+	// pay_off accepts every state a loan can be repaid in
+	trigger := statemachine.Trigger{
+		Event: constant.EventPayOff,
+		Actor: statemachine.Actor{ID: userID, Role: role},
+	}
+	_, err = uc.StateMachine.Can(ctx, loan, trigger)
+	if err != nil {
+		return result, err
+	}
+
+	installments, err := dbTrx.GetInstallmentsByLoanID(ctx, loanID)
+	if err != nil {
+		return result, err
+	}
+	if len(installments) == 0 {
+		return result, constant.ErrScheduleNotFound
+	}
+
+	paidAt := time.Now()
+	changed, err := AllocateRepayment(installments, payload.Amount, paidAt)
+	if err != nil {
+		return result, err
+	}
+	for i := range changed {
+		err = dbTrx.UpdateInstallment(
+			ctx,
+			&domians.LoanInstallment{
+				ID: changed[i].ID,
+			},
+			map[string]any{
+				"amount_paid": changed[i].AmountPaid,
+				"status":      changed[i].Status,
+				"paid_at":     changed[i].PaidAt,
+				"updated_at":  paidAt,
+			},
+		)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	repayment := &domians.LoanRepayment{
+		LoanID:    loanID,
+		PaidBy:    userID,
+		Amount:    payload.Amount,
+		PaidAt:    paidAt,
+		Remarks:   payload.Remarks,
+		CreatedAt: paidAt,
+		UpdatedAt: paidAt,
+	}
+	err = dbTrx.CreateRepayment(ctx, repayment)
+	if err != nil {
+		return result, err
+	}
+	investors, err := dbTrx.GetLoanInvestors(ctx, loanID)
+	if err != nil {
+		return result, err
+	}
+	err = uc.Ledger.Repay(ctx, dbTrx, loan, investors, payload.Amount, userID)
+	if err != nil {
+		return result, err
+	}
+
+	outstanding := Outstanding(mergeInstallments(installments, changed))
+	switch {
+	case outstanding.IsZero():
+		err = uc.StateMachine.Fire(ctx, dbTrx, loan, trigger)
+	case loan.State == constant.Disbursed:
+		trigger.Event = constant.EventRepay
+		err = uc.StateMachine.Fire(ctx, dbTrx, loan, trigger)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	return RepaymentResult{
+		RepaymentID: repayment.ID,
+		Amount:      repayment.Amount,
+		Outstanding: outstanding,
+		State:       loan.State,
+	}, nil
+}
+
+// mergeInstallments returns installments with the changed rows applied.
+func mergeInstallments(
+	installments, changed []domians.LoanInstallment,
+) []domians.LoanInstallment {
+	merged := make([]domians.LoanInstallment, len(installments))
+	copy(merged, installments)
+	for _, c := range changed {
+		for i := range merged {
+			if merged[i].Sequence == c.Sequence {
+				merged[i] = c
+			}
+		}
+	}
+	return merged
+}
@@ -0,0 +1,235 @@
+package repayment_test
+
+import (
+	"context"
+	"github.com/bowoBp/LoanFlow/internal/adapter/mocks"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/permission"
+	"github.com/bowoBp/LoanFlow/internal/services/repayment"
+	"github.com/bowoBp/LoanFlow/internal/statemachine"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestBuildSchedule(t *testing.T) {
+	start := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	installments := repayment.BuildSchedule(1, money.New(1000000), money.New(100000), 3, start)
+
+	assert.Len(t, installments, 3)
+	assert.Equal(t, money.MustParse("333333.33"), installments[0].PrincipalDue)
+	assert.Equal(t, money.MustParse("33333.33"), installments[0].InterestDue)
+	// rounding leftovers go to the last installment
+	assert.Equal(t, money.MustParse("333333.34"), installments[2].PrincipalDue)
+	assert.Equal(t, money.MustParse("33333.34"), installments[2].InterestDue)
+	assert.Equal(t, time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC), installments[0].DueDate)
+	assert.Equal(t, money.MustParse("1100000.0"), repayment.Outstanding(installments))
+}
+
+func TestUsecase_Repay(t *testing.T) {
+	mockTransaction := new(mocks.DefaultRepaymentTransactionInterface)
+	mockLedger := new(mocks.BookInterface)
+
+	loanID := uint(1)
+	userID := uint(3)
+	investors := []domians.LoanInvestor{
+		{ID: 20, LoanID: loanID, InvestorID: 5, AmountInvested: money.New(100000), Status: constant.InvestmentActive},
+	}
+	schedule := func() []domians.LoanInstallment {
+		return []domians.LoanInstallment{
+			{ID: 10, LoanID: loanID, Sequence: 1, AmountDue: money.New(55000), Status: constant.InstallmentPending},
+			{ID: 11, LoanID: loanID, Sequence: 2, AmountDue: money.New(55000), Status: constant.InstallmentPending},
+		}
+	}
+
+	type args struct {
+		ctx     context.Context
+		payload repayment.RepayLoanRequest
+	}
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		args         args
+		want         repayment.RepaymentResult
+		wantErr      bool
+		expectedErr  error
+	}{
+		{
+			name: "Success - first partial repayment moves loan to repaying",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetLoanByIDForUpdate", mock.Anything, loanID).
+					Return(&domians.Loan{ID: loanID, BorrowerID: userID, State: constant.Disbursed}, nil).Once()
+				mockTransaction.On("GetInstallmentsByLoanID", mock.Anything, loanID).
+					Return(schedule(), nil).Once()
+
+				mockTransaction.On("UpdateInstallment", mock.Anything, mock.MatchedBy(func(installment *domians.LoanInstallment) bool {
+					return installment.ID == 10
+				}), mock.MatchedBy(func(data map[string]any) bool {
+					return data["amount_paid"] == money.New(55000) && data["status"] == constant.InstallmentPaid
+				})).Return(nil).Once()
+				mockTransaction.On("UpdateInstallment", mock.Anything, mock.MatchedBy(func(installment *domians.LoanInstallment) bool {
+					return installment.ID == 11
+				}), mock.MatchedBy(func(data map[string]any) bool {
+					return data["amount_paid"] == money.New(5000) && data["status"] == constant.InstallmentPartial
+				})).Return(nil).Once()
+
+				mockTransaction.On("CreateRepayment", mock.Anything, mock.MatchedBy(func(repayment *domians.LoanRepayment) bool {
+					return repayment.LoanID == loanID && repayment.PaidBy == userID && repayment.Amount == money.New(60000)
+				})).Return(nil).Once()
+				mockTransaction.On("GetLoanInvestors", mock.Anything, loanID).Return(investors, nil).Once()
+				mockLedger.On("Repay", mock.Anything, mockTransaction, mock.Anything, investors, money.New(60000), userID).
+					Return(nil).Once()
+				mockTransaction.On("UpdateLoan", mock.Anything, mock.Anything, mock.MatchedBy(func(data map[string]any) bool {
+					return data["state"] == constant.Repaying
+				})).Return(nil).Once()
+				mockTransaction.On("CreateLoanState", mock.Anything, mock.MatchedBy(func(state *domians.LoanStateHistory) bool {
+					return state.PreviousState == constant.Disbursed && state.NewState == constant.Repaying
+				})).Return(nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				payload: repayment.RepayLoanRequest{Amount: money.New(60000)},
+			},
+			want: repayment.RepaymentResult{
+				Amount:      money.New(60000),
+				Outstanding: money.New(50000),
+				State:       constant.Repaying,
+			},
+		},
+		{
+			name: "Success - last repayment pays off the loan",
+			mockBehavior: func() {
+				installments := schedule()
+				installments[0].AmountPaid = money.New(55000)
+				installments[0].Status = constant.InstallmentPaid
+
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetLoanByIDForUpdate", mock.Anything, loanID).
+					Return(&domians.Loan{ID: loanID, BorrowerID: userID, State: constant.Repaying}, nil).Once()
+				mockTransaction.On("GetInstallmentsByLoanID", mock.Anything, loanID).
+					Return(installments, nil).Once()
+				mockTransaction.On("UpdateInstallment", mock.Anything, mock.Anything, mock.MatchedBy(func(data map[string]any) bool {
+					return data["status"] == constant.InstallmentPaid
+				})).Return(nil).Once()
+				mockTransaction.On("CreateRepayment", mock.Anything, mock.Anything).Return(nil).Once()
+				mockTransaction.On("GetLoanInvestors", mock.Anything, loanID).Return(investors, nil).Once()
+				mockLedger.On("Repay", mock.Anything, mockTransaction, mock.Anything, investors, money.New(55000), userID).
+					Return(nil).Once()
+				mockTransaction.On("UpdateLoan", mock.Anything, mock.Anything, mock.MatchedBy(func(data map[string]any) bool {
+					return data["state"] == constant.PaidOff
+				})).Return(nil).Once()
+				mockTransaction.On("CreateLoanState", mock.Anything, mock.MatchedBy(func(state *domians.LoanStateHistory) bool {
+					return state.PreviousState == constant.Repaying && state.NewState == constant.PaidOff
+				})).Return(nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				payload: repayment.RepayLoanRequest{Amount: money.New(55000)},
+			},
+			want: repayment.RepaymentResult{
+				Amount:      money.New(55000),
+				Outstanding: money.New(0),
+				State:       constant.PaidOff,
+			},
+		},
+		{
+			name: "Error - ledger posting fails",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetLoanByIDForUpdate", mock.Anything, loanID).
+					Return(&domians.Loan{ID: loanID, BorrowerID: userID, State: constant.Disbursed}, nil).Once()
+				mockTransaction.On("GetInstallmentsByLoanID", mock.Anything, loanID).
+					Return(schedule(), nil).Once()
+				mockTransaction.On("UpdateInstallment", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				mockTransaction.On("CreateRepayment", mock.Anything, mock.Anything).Return(nil).Once()
+				mockTransaction.On("GetLoanInvestors", mock.Anything, loanID).Return(investors, nil).Once()
+				mockLedger.On("Repay", mock.Anything, mockTransaction, mock.Anything, investors, money.New(1000), userID).
+					Return(constant.ErrUnbalancedEntry).Once()
+				mockTransaction.On("End", constant.ErrUnbalancedEntry).Return(nil).Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				payload: repayment.RepayLoanRequest{Amount: money.New(1000)},
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrUnbalancedEntry,
+		},
+		{
+			name: "Error - amount exceeds outstanding",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetLoanByIDForUpdate", mock.Anything, loanID).
+					Return(&domians.Loan{ID: loanID, BorrowerID: userID, State: constant.Disbursed}, nil).Once()
+				mockTransaction.On("GetInstallmentsByLoanID", mock.Anything, loanID).
+					Return(schedule(), nil).Once()
+				mockTransaction.On("End", constant.ErrRepaymentAmount).Return(nil).Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				payload: repayment.RepayLoanRequest{Amount: money.New(200000)},
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrRepaymentAmount,
+		},
+		{
+			name: "Error - loan of another borrower",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetLoanByIDForUpdate", mock.Anything, loanID).
+					Return(&domians.Loan{ID: loanID, BorrowerID: userID + 1, State: constant.Disbursed}, nil).Once()
+				mockTransaction.On("End", constant.ErrLoanNotOwned).Return(nil).Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				payload: repayment.RepayLoanRequest{Amount: money.New(1000)},
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrLoanNotOwned,
+		},
+		{
+			name: "Error - loan not disbursed",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetLoanByIDForUpdate", mock.Anything, loanID).
+					Return(&domians.Loan{ID: loanID, BorrowerID: userID, State: constant.Invested}, nil).Once()
+				mockTransaction.On("End", constant.ErrStateRepay).Return(nil).Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				payload: repayment.RepayLoanRequest{Amount: money.New(1000)},
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrStateRepay,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := repayment.Usecase{
+				DbTransaction: mockTransaction,
+				StateMachine:  statemachine.NewLoanMachine(permission.NewStatic(permission.Defaults)),
+				Ledger:        mockLedger,
+			}
+			got, err := uc.Repay(tt.args.ctx, loanID, userID, constant.RoleBorrower, tt.args.payload)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			mockTransaction.AssertExpectations(t)
+			mockLedger.AssertExpectations(t)
+		})
+	}
+}
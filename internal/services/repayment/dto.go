@@ -0,0 +1,49 @@
+package repayment
+
+import (
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"time"
+)
+
+type (
+	RepayLoanRequest struct {
+		Amount  money.Money `json:"amount" validate:"amount_min=0.01"`
+		Remarks string      `json:"remarks" validate:"max=500"`
+	}
+
+	RepaymentResult struct {
+		RepaymentID uint
+		Amount      money.Money
+		Outstanding money.Money
+		State       string
+	}
+
+	RepayLoanResponse struct {
+		RepaymentID uint        `json:"repaymentId"`
+		LoanID      uint        `json:"loanId"`
+		Amount      money.Money `json:"amount"`
+		Outstanding money.Money `json:"outstanding"`
+		State       string      `json:"state"`
+	}
+
+	InstallmentResponse struct {
+		Sequence     uint        `json:"sequence"`
+		DueDate      time.Time   `json:"dueDate"`
+		PrincipalDue money.Money `json:"principalDue"`
+		InterestDue  money.Money `json:"interestDue"`
+		AmountDue    money.Money `json:"amountDue"`
+		AmountPaid   money.Money `json:"amountPaid"`
+		Status       string      `json:"status"`
+		PaidAt       *time.Time  `json:"paidAt"`
+	}
+
+	ScheduleResponse struct {
+		LoanID       uint                  `json:"loanId"`
+		State        string                `json:"state"`
+		Tenor        uint                  `json:"tenor"`
+		TotalDue     money.Money           `json:"totalDue"`
+		TotalPaid    money.Money           `json:"totalPaid"`
+		Outstanding  money.Money           `json:"outstanding"`
+		Installments []InstallmentResponse `json:"installments"`
+	}
+)
@@ -0,0 +1,71 @@
+package repayment
+
+import (
+	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	"github.com/bowoBp/LoanFlow/internal/ledger"
+	"github.com/bowoBp/LoanFlow/internal/permission"
+	"github.com/bowoBp/LoanFlow/internal/policy"
+	"github.com/bowoBp/LoanFlow/internal/statemachine"
+	"github.com/bowoBp/LoanFlow/pkg/middleware"
+	"github.com/bowoBp/LoanFlow/pkg/reporter"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type (
+	Router struct {
+		auth        middleware.AuthInterface
+		idempotency middleware.IdempotencyInterface
+		machine     *statemachine.Machine
+		rh          *RequestHandler
+	}
+)
+
+func NewRoute(
+	db *gorm.DB,
+	auth middleware.AuthInterface,
+	machine *statemachine.Machine,
+	book ledger.BookInterface,
+	idempotency middleware.IdempotencyInterface,
+	permissions permission.Resolver,
+	reporter reporter.Reporter,
+) *Router {
+	loanRepo := Repository.NewLoanRepo(db)
+	return &Router{
+		auth:        auth,
+		idempotency: idempotency,
+		machine:     machine,
+		rh: &RequestHandler{
+			ctrl: &Controller{
+				Uc: Usecase{
+					LoanRepo:      loanRepo,
+					RepaymentRepo: Repository.NewRepaymentRepo(db),
+					DbTransaction: NewRepaymentTransaction(db),
+					StateMachine:  machine,
+					Ledger:        book,
+					Policy:        policy.NewLoanPolicy(loanRepo, permissions),
+					Reporter:      reporter,
+				},
+			},
+		},
+	}
+}
+
+func (r Router) Route(router *gin.RouterGroup) {
+	loans := router.Group("loans")
+
+	loans.GET(
+		"/:loanId/schedule",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermLoanRead),
+		r.rh.GetSchedule,
+	)
+	loans.POST(
+		"/:loanId/repayments",
+		r.auth.Authentication(),
+		r.auth.AuthorizePermission(r.machine.Permission(constant.EventRepay)),
+		r.idempotency.Handle(),
+		r.rh.Repay,
+	)
+}
@@ -0,0 +1,88 @@
+package repayment
+
+import (
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"time"
+)
+
+// BuildSchedule splits the disbursed principal and the loan interest (ROI)
+// into equal monthly installments starting one month after startAt.
+// Rounding leftovers are put on the last installment so the schedule
+// always sums up to the exact principal and interest.
+func BuildSchedule(
+	loanID uint,
+	principal, interest money.Money,
+	tenor uint,
+	startAt time.Time,
+) []domians.LoanInstallment {
+	if tenor == 0 {
+		tenor = constant.DefaultTenor
+	}
+
+	var (
+		principalDue = principal.Split(int(tenor))
+		interestDue  = interest.Split(int(tenor))
+		installments = make([]domians.LoanInstallment, tenor)
+		now          = time.Now()
+	)
+	for i := range installments {
+		installments[i] = domians.LoanInstallment{
+			LoanID:       loanID,
+			Sequence:     uint(i + 1),
+			DueDate:      startAt.AddDate(0, i+1, 0),
+			PrincipalDue: principalDue[i],
+			InterestDue:  interestDue[i],
+			AmountDue:    principalDue[i].Add(interestDue[i]),
+			Status:       constant.InstallmentPending,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+	}
+	return installments
+}
+
+// AllocateRepayment applies amount to the unpaid installments ordered by
+// sequence (oldest first) and returns only the installments that changed.
+func AllocateRepayment(
+	installments []domians.LoanInstallment,
+	amount money.Money,
+	paidAt time.Time,
+) ([]domians.LoanInstallment, error) {
+	remaining := amount
+	if !remaining.IsPositive() || remaining.Cmp(Outstanding(installments)) > 0 {
+		return nil, constant.ErrRepaymentAmount
+	}
+
+	changed := make([]domians.LoanInstallment, 0)
+	for _, installment := range installments {
+		if remaining.IsZero() {
+			break
+		}
+		due := installment.AmountDue.Sub(installment.AmountPaid)
+		if !due.IsPositive() {
+			continue
+		}
+		pay := due.Min(remaining)
+		remaining = remaining.Sub(pay)
+
+		installment.AmountPaid = installment.AmountPaid.Add(pay)
+		installment.Status = constant.InstallmentPartial
+		if pay == due {
+			installment.Status = constant.InstallmentPaid
+			installment.PaidAt = &paidAt
+		}
+		changed = append(changed, installment)
+	}
+	return changed, nil
+}
+
+// Outstanding returns the unpaid balance of the given installments.
+func Outstanding(installments []domians.LoanInstallment) money.Money {
+	var total money.Money
+	for _, installment := range installments {
+		total = total.Add(installment.AmountDue.Sub(installment.AmountPaid))
+	}
+	return total
+}
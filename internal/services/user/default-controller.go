@@ -3,8 +3,10 @@ package user
 import (
 	"context"
 	"fmt"
+	"github.com/bowoBp/LoanFlow/internal/constant"
 	"github.com/bowoBp/LoanFlow/internal/dto"
-	"log"
+	"github.com/bowoBp/LoanFlow/internal/i18n"
+	"github.com/bowoBp/LoanFlow/internal/revocation"
 	"strconv"
 	"time"
 )
@@ -25,24 +27,91 @@ type (
 		) (*dto.Response, error)
 
 		GetCurrent(
+			ctx context.Context,
 			id, userName, created interface{},
 		) (*dto.Response, error)
 
 		Login(
 			ctx context.Context,
 			userName, password string,
+			client Client,
+		) (SuccessLoginUser, error)
+
+		VerifyLogin(
+			ctx context.Context,
+			challengeToken, code string,
+			client Client,
 		) (SuccessLoginUser, error)
 
 		RefreshToken(
 			ctx context.Context,
-			id uint,
-			createdAt time.Time,
-			token, role, name string,
+			token string,
+			client Client,
 		) (SuccessLoginUser, error)
 
 		RevokeToken(
 			ctx context.Context,
 			id uint,
+			access revocation.Token,
+			token string,
+		) (*dto.Response, error)
+
+		GetSessions(
+			ctx context.Context,
+			id uint,
+		) (*dto.Response, error)
+
+		RevokeSession(
+			ctx context.Context,
+			id, sessionID uint,
+		) (*dto.Response, error)
+
+		ForgotPassword(
+			ctx context.Context,
+			email string,
+		) (*dto.Response, error)
+
+		ResetPassword(
+			ctx context.Context,
+			token, password string,
+		) (*dto.Response, error)
+
+		VerifyEmail(
+			ctx context.Context,
+			token string,
+		) (*dto.Response, error)
+
+		ResendVerification(
+			ctx context.Context,
+			id uint,
+		) (*dto.Response, error)
+
+		EnrollTOTP(
+			ctx context.Context,
+			id uint,
+		) (*dto.Response, error)
+
+		ConfirmTOTP(
+			ctx context.Context,
+			id uint,
+			code string,
+		) (*dto.Response, error)
+
+		DisableTOTP(
+			ctx context.Context,
+			id uint,
+			code string,
+		) (*dto.Response, error)
+
+		GetPermissions(
+			ctx context.Context,
+			role string,
+		) (*dto.Response, error)
+
+		UpdateLocale(
+			ctx context.Context,
+			id uint,
+			locale string,
 		) (*dto.Response, error)
 	}
 )
@@ -56,9 +125,10 @@ func (ctrl Controller) Register(
 	if err != nil {
 		return nil, err
 	}
-	return dto.NewSuccessResponse(
+	return i18n.Success(
+		ctx,
 		result,
-		"Register is success",
+		"success.registered",
 		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
 	), nil
 }
@@ -70,7 +140,6 @@ func (ctrl Controller) GetAll(
 	var res = make([]Users, 0)
 	users, err := ctrl.Uc.GetAll(ctx)
 	if err != nil {
-		log.Println(err)
 		return nil, err
 	}
 
@@ -81,41 +150,44 @@ func (ctrl Controller) GetAll(
 			CreatedAt: user.CreatedAt,
 		})
 	}
-	return dto.NewSuccessResponse(
+	return i18n.Success(
+		ctx,
 		res,
-		"success get all ",
+		"success.users_listed",
 		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
 	), nil
 
 }
 
 func (ctrl Controller) GetCurrent(
+	ctx context.Context,
 	id, userName, created interface{},
 ) (*dto.Response, error) {
 	start := time.Now()
-	userId, ok := id.(string)
+	userId, ok := id.(uint)
 	if !ok {
-		return nil, fmt.Errorf("ID must be a string")
+		return nil, constant.ErrInvalidToken
 	}
 
 	userNm, ok := userName.(string)
 	if !ok {
-		return nil, fmt.Errorf("userName must be a string")
+		return nil, constant.ErrInvalidToken
 	}
 
 	createdUsr, ok := created.(time.Time)
 	if !ok {
-		return nil, fmt.Errorf("created must be a time.Time")
+		return nil, constant.ErrInvalidToken
 	}
 	var res = Users{
-		ID:        userId,
+		ID:        strconv.Itoa(int(userId)),
 		UserName:  userNm,
 		CreatedAt: createdUsr,
 	}
 
-	return dto.NewSuccessResponse(
+	return i18n.Success(
+		ctx,
 		res,
-		"success current user",
+		"success.current_user",
 		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
 	), nil
 
@@ -124,45 +196,72 @@ func (ctrl Controller) GetCurrent(
 func (ctrl Controller) Login(
 	ctx context.Context,
 	email, password string,
+	client Client,
 ) (SuccessLoginUser, error) {
-	user, tokenString, refreshToken, err := ctrl.Uc.LoginUser(ctx, email, password)
+	result, err := ctrl.Uc.LoginUser(ctx, email, password, client)
 	if err != nil {
 		return SuccessLoginUser{}, err
 	}
-	response := SuccessLoginUser{
+	if result.ChallengeToken != "" {
+		return SuccessLoginUser{
+			Response: dto.ResponseMeta{
+				Success:      true,
+				MessageTitle: i18n.T(ctx, i18n.TitleMFARequired),
+				Message:      i18n.T(ctx, "success.mfa_required"),
+				ResponseTime: "",
+			},
+			UserName:       result.User.Name,
+			MFARequired:    true,
+			ChallengeToken: result.ChallengeToken,
+		}, nil
+	}
+	return loginResponse(ctx, result), nil
+}
+
+func (ctrl Controller) VerifyLogin(
+	ctx context.Context,
+	challengeToken, code string,
+	client Client,
+) (SuccessLoginUser, error) {
+	result, err := ctrl.Uc.VerifyLogin(ctx, challengeToken, code, client)
+	if err != nil {
+		return SuccessLoginUser{}, err
+	}
+	return loginResponse(ctx, result), nil
+}
+
+func loginResponse(ctx context.Context, result LoginResult) SuccessLoginUser {
+	return SuccessLoginUser{
 		Response: dto.ResponseMeta{
 			Success:      true,
-			MessageTitle: "login successful",
-			Message:      "success",
+			MessageTitle: i18n.T(ctx, i18n.TitleLogin),
+			Message:      i18n.T(ctx, "success.login"),
 			ResponseTime: "",
 		},
-		UserName:     user.Name,
-		AccessToken:  tokenString,
-		RefreshToken: refreshToken,
+		UserName:     result.User.Name,
+		AccessToken:  result.AccessToken,
+		RefreshToken: result.RefreshToken,
 	}
-
-	return response, nil
 }
 
 func (ctrl Controller) RefreshToken(
 	ctx context.Context,
-	id uint,
-	createdAt time.Time,
-	token, role, name string,
+	token string,
+	client Client,
 ) (SuccessLoginUser, error) {
 
-	tokenString, refreshToken, err := ctrl.Uc.RefreshToken(ctx, id, createdAt, token, role, name)
+	user, tokenString, refreshToken, err := ctrl.Uc.RefreshToken(ctx, token, client)
 	if err != nil {
 		return SuccessLoginUser{}, err
 	}
 	response := SuccessLoginUser{
 		Response: dto.ResponseMeta{
 			Success:      true,
-			MessageTitle: "login successful",
-			Message:      "success",
+			MessageTitle: i18n.T(ctx, i18n.TitleLogin),
+			Message:      i18n.T(ctx, "success.login"),
 			ResponseTime: "",
 		},
-		UserName:     name,
+		UserName:     user.Name,
 		AccessToken:  tokenString,
 		RefreshToken: refreshToken,
 	}
@@ -173,15 +272,222 @@ func (ctrl Controller) RefreshToken(
 func (ctrl Controller) RevokeToken(
 	ctx context.Context,
 	id uint,
+	access revocation.Token,
+	token string,
+) (*dto.Response, error) {
+	start := time.Now()
+	err := ctrl.Uc.RevokeToken(ctx, id, access, token)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		nil,
+		"success.logged_out",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) GetSessions(
+	ctx context.Context,
+	id uint,
+) (*dto.Response, error) {
+	start := time.Now()
+	sessions, err := ctrl.Uc.GetSessions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]SessionResponse, len(sessions))
+	for i, session := range sessions {
+		result[i] = SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+		}
+	}
+	return i18n.Success(
+		ctx,
+		result,
+		"success.sessions_listed",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) RevokeSession(
+	ctx context.Context,
+	id, sessionID uint,
+) (*dto.Response, error) {
+	start := time.Now()
+	err := ctrl.Uc.RevokeSession(ctx, id, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		nil,
+		"success.session_revoked",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) ForgotPassword(
+	ctx context.Context,
+	email string,
+) (*dto.Response, error) {
+	start := time.Now()
+	err := ctrl.Uc.ForgotPassword(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		nil,
+		"success.password_reset_sent",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) ResetPassword(
+	ctx context.Context,
+	token, password string,
+) (*dto.Response, error) {
+	start := time.Now()
+	err := ctrl.Uc.ResetPassword(ctx, token, password)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		nil,
+		"success.password_reset",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) VerifyEmail(
+	ctx context.Context,
+	token string,
+) (*dto.Response, error) {
+	start := time.Now()
+	err := ctrl.Uc.VerifyEmail(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		nil,
+		"success.email_verified",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) ResendVerification(
+	ctx context.Context,
+	id uint,
+) (*dto.Response, error) {
+	start := time.Now()
+	err := ctrl.Uc.ResendVerification(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		nil,
+		"success.verification_sent",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) EnrollTOTP(
+	ctx context.Context,
+	id uint,
+) (*dto.Response, error) {
+	start := time.Now()
+	enrolment, err := ctrl.Uc.EnrollTOTP(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		enrolment,
+		"success.totp_enrolled",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) ConfirmTOTP(
+	ctx context.Context,
+	id uint,
+	code string,
 ) (*dto.Response, error) {
 	start := time.Now()
-	err := ctrl.Uc.RevokeToken(ctx, id)
+	codes, err := ctrl.Uc.ConfirmTOTP(ctx, id, code)
 	if err != nil {
 		return nil, err
 	}
-	return dto.NewSuccessResponse(
+	return i18n.Success(
+		ctx,
+		RecoveryCodesResponse{RecoveryCodes: codes},
+		"success.totp_enabled",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) DisableTOTP(
+	ctx context.Context,
+	id uint,
+	code string,
+) (*dto.Response, error) {
+	start := time.Now()
+	err := ctrl.Uc.DisableTOTP(ctx, id, code)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
 		nil,
-		"Logout successful",
+		"success.totp_disabled",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) GetPermissions(
+	ctx context.Context,
+	role string,
+) (*dto.Response, error) {
+	start := time.Now()
+	permissions, err := ctrl.Uc.GetPermissions(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+	if permissions == nil {
+		permissions = make([]string, 0)
+	}
+	return i18n.Success(
+		ctx,
+		PermissionsResponse{Role: role, Permissions: permissions},
+		"success.permissions_listed",
+		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
+	), nil
+}
+
+func (ctrl Controller) UpdateLocale(
+	ctx context.Context,
+	id uint,
+	locale string,
+) (*dto.Response, error) {
+	start := time.Now()
+	err := ctrl.Uc.UpdateLocale(ctx, id, locale)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.Success(
+		ctx,
+		LocaleParam{Locale: locale},
+		"success.locale_updated",
 		fmt.Sprint(time.Since(start).Milliseconds(), " ms."),
 	), nil
 }
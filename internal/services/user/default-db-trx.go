@@ -0,0 +1,268 @@
+package user
+
+import (
+	"context"
+	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"gorm.io/gorm"
+	"time"
+)
+
+type (
+	DefaultUserTransaction struct {
+		db               *gorm.DB
+		userRepo         Repository.UserRepoInterface
+		sessionRepo      Repository.SessionRepoInterface
+		notificationRepo Repository.NotificationRepoInterface
+	}
+
+	DefaultUserTransactionInterface interface {
+		Begin() (DefaultUserTransactionInterface, error)
+		End(err error) error
+		StoreUser(
+			ctx context.Context,
+			user *domians.User,
+		) (*domians.User, error)
+		GetUserByID(
+			ctx context.Context,
+			id uint,
+		) (*domians.User, error)
+		UpdateUser(
+			ctx context.Context,
+			user *domians.User,
+			updateData map[string]any,
+		) error
+		CreateSession(
+			ctx context.Context,
+			session *domians.Session,
+		) error
+		UpdateSession(
+			ctx context.Context,
+			session *domians.Session,
+			updateData map[string]any,
+		) error
+		StoreRefreshToken(
+			ctx context.Context,
+			token *domians.RefreshToken,
+		) error
+		GetRefreshTokenByHash(
+			ctx context.Context,
+			tokenHash string,
+		) (*domians.RefreshToken, error)
+		UpdateRefreshToken(
+			ctx context.Context,
+			token *domians.RefreshToken,
+			updateData map[string]any,
+		) error
+		RevokeSessionsByUserID(
+			ctx context.Context,
+			userID uint,
+			revokedAt time.Time,
+		) error
+		StoreUserToken(
+			ctx context.Context,
+			token *domians.UserToken,
+		) error
+		GetUserTokenByHash(
+			ctx context.Context,
+			purpose, tokenHash string,
+		) (*domians.UserToken, error)
+		UseUserTokens(
+			ctx context.Context,
+			userID uint,
+			purpose string,
+			usedAt time.Time,
+		) error
+		CreateNotifications(
+			ctx context.Context,
+			notifications []domians.Notification,
+		) error
+		UpdateUserToken(
+			ctx context.Context,
+			token *domians.UserToken,
+			updateData map[string]any,
+		) error
+		UpdateTOTPStep(
+			ctx context.Context,
+			userID uint,
+			step int64,
+		) (bool, error)
+		StoreRecoveryCodes(
+			ctx context.Context,
+			codes []domians.RecoveryCode,
+		) error
+		DeleteRecoveryCodes(
+			ctx context.Context,
+			userID uint,
+		) error
+		UseRecoveryCode(
+			ctx context.Context,
+			userID uint,
+			codeHash string,
+			usedAt time.Time,
+		) (bool, error)
+	}
+)
+
+func NewUserTransaction(db *gorm.DB) DefaultUserTransaction {
+	return DefaultUserTransaction{
+		db: db,
+	}
+}
+
+func (repo DefaultUserTransaction) StoreUser(
+	ctx context.Context,
+	user *domians.User,
+) (*domians.User, error) {
+	return repo.userRepo.StoreUser(ctx, user)
+}
+
+func (repo DefaultUserTransaction) GetUserByID(
+	ctx context.Context,
+	id uint,
+) (*domians.User, error) {
+	return repo.userRepo.GetUserByID(ctx, id)
+}
+
+func (repo DefaultUserTransaction) UpdateUser(
+	ctx context.Context,
+	user *domians.User,
+	updateData map[string]any,
+) error {
+	return repo.userRepo.UpdateUser(ctx, user, updateData)
+}
+
+func (repo DefaultUserTransaction) CreateSession(
+	ctx context.Context,
+	session *domians.Session,
+) error {
+	return repo.sessionRepo.CreateSession(ctx, session)
+}
+
+func (repo DefaultUserTransaction) UpdateSession(
+	ctx context.Context,
+	session *domians.Session,
+	updateData map[string]any,
+) error {
+	return repo.sessionRepo.UpdateSession(ctx, session, updateData)
+}
+
+func (repo DefaultUserTransaction) StoreRefreshToken(
+	ctx context.Context,
+	token *domians.RefreshToken,
+) error {
+	return repo.sessionRepo.StoreRefreshToken(ctx, token)
+}
+
+func (repo DefaultUserTransaction) GetRefreshTokenByHash(
+	ctx context.Context,
+	tokenHash string,
+) (*domians.RefreshToken, error) {
+	return repo.sessionRepo.GetRefreshTokenByHash(ctx, tokenHash)
+}
+
+func (repo DefaultUserTransaction) UpdateRefreshToken(
+	ctx context.Context,
+	token *domians.RefreshToken,
+	updateData map[string]any,
+) error {
+	return repo.sessionRepo.UpdateRefreshToken(ctx, token, updateData)
+}
+
+func (repo DefaultUserTransaction) RevokeSessionsByUserID(
+	ctx context.Context,
+	userID uint,
+	revokedAt time.Time,
+) error {
+	return repo.sessionRepo.RevokeSessionsByUserID(ctx, userID, revokedAt)
+}
+
+func (repo DefaultUserTransaction) StoreUserToken(
+	ctx context.Context,
+	token *domians.UserToken,
+) error {
+	return repo.userRepo.StoreUserToken(ctx, token)
+}
+
+func (repo DefaultUserTransaction) GetUserTokenByHash(
+	ctx context.Context,
+	purpose, tokenHash string,
+) (*domians.UserToken, error) {
+	return repo.userRepo.GetUserTokenByHash(ctx, purpose, tokenHash)
+}
+
+func (repo DefaultUserTransaction) UseUserTokens(
+	ctx context.Context,
+	userID uint,
+	purpose string,
+	usedAt time.Time,
+) error {
+	return repo.userRepo.UseUserTokens(ctx, userID, purpose, usedAt)
+}
+
+func (repo DefaultUserTransaction) CreateNotifications(
+	ctx context.Context,
+	notifications []domians.Notification,
+) error {
+	return repo.notificationRepo.CreateNotifications(ctx, notifications)
+}
+
+func (repo DefaultUserTransaction) UpdateUserToken(
+	ctx context.Context,
+	token *domians.UserToken,
+	updateData map[string]any,
+) error {
+	return repo.userRepo.UpdateUserToken(ctx, token, updateData)
+}
+
+func (repo DefaultUserTransaction) UpdateTOTPStep(
+	ctx context.Context,
+	userID uint,
+	step int64,
+) (bool, error) {
+	return repo.userRepo.UpdateTOTPStep(ctx, userID, step)
+}
+
+func (repo DefaultUserTransaction) StoreRecoveryCodes(
+	ctx context.Context,
+	codes []domians.RecoveryCode,
+) error {
+	return repo.userRepo.StoreRecoveryCodes(ctx, codes)
+}
+
+func (repo DefaultUserTransaction) DeleteRecoveryCodes(
+	ctx context.Context,
+	userID uint,
+) error {
+	return repo.userRepo.DeleteRecoveryCodes(ctx, userID)
+}
+
+func (repo DefaultUserTransaction) UseRecoveryCode(
+	ctx context.Context,
+	userID uint,
+	codeHash string,
+	usedAt time.Time,
+) (bool, error) {
+	return repo.userRepo.UseRecoveryCode(ctx, userID, codeHash, usedAt)
+}
+
+func (repo DefaultUserTransaction) Begin() (DefaultUserTransactionInterface, error) {
+	evoTrx := repo.db.Begin()
+	if err := evoTrx.Error; err != nil {
+		return DefaultUserTransaction{}, err
+	}
+	newUserTrx := &DefaultUserTransaction{
+		db:               evoTrx,
+		userRepo:         Repository.NewUserRepo(evoTrx),
+		sessionRepo:      Repository.NewSessionRepo(evoTrx),
+		notificationRepo: Repository.NewNotificationRepo(evoTrx),
+	}
+	return newUserTrx, nil
+}
+
+func (repo DefaultUserTransaction) End(err error) error {
+	if err != nil {
+		return repo.db.Rollback().Error
+	}
+	return repo.db.Commit().Error
+}
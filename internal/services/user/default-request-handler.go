@@ -2,10 +2,10 @@ package user
 
 import (
 	"github.com/bowoBp/LoanFlow/internal/constant"
-	"github.com/bowoBp/LoanFlow/internal/dto"
+	"github.com/bowoBp/LoanFlow/internal/revocation"
 	"github.com/gin-gonic/gin"
 	"net/http"
-	"time"
+	"strconv"
 )
 
 type (
@@ -16,20 +16,18 @@ type (
 
 func (rh RequestHandler) Register(ctx *gin.Context) {
 	var payload = RegisterUser{}
-	err := ctx.Bind(&payload)
-	role := ctx.GetHeader("X-User-Role")
-	payload.Role = role
-	if payload.Password == "" || payload.UserName == "" {
-		ctx.JSON(http.StatusBadRequest, dto.DefaultErrorInvalidDataWithMessage(constant.ErrRegister.Error()))
+	err := ctx.ShouldBind(&payload)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
 		return
 	}
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, dto.DefaultErrorInvalidDataWithMessage(err.Error()))
+	if payload.Password == "" || payload.UserName == "" {
+		ctx.Error(constant.ErrRegister)
 		return
 	}
 	res, err := rh.ctrl.Register(ctx, payload)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.DefaultErrorResponseWithMessage(err.Error()))
+		ctx.Error(err)
 		return
 	}
 
@@ -39,116 +37,259 @@ func (rh RequestHandler) Register(ctx *gin.Context) {
 func (rh RequestHandler) GetAll(ctx *gin.Context) {
 	res, err := rh.ctrl.GetAll(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.DefaultErrorResponseWithMessage(err.Error()))
+		ctx.Error(err)
+		return
 	}
 	ctx.JSON(http.StatusOK, res)
-
 }
 
 func (rh RequestHandler) GetCurrent(ctx *gin.Context) {
 	id, _ := ctx.Get("id")
 	userName, _ := ctx.Get("userName")
 	created, _ := ctx.Get("createdAt")
-	res, err := rh.ctrl.GetCurrent(id, userName, created)
+	res, err := rh.ctrl.GetCurrent(ctx, id, userName, created)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.DefaultErrorResponseWithMessage(constant.ErrUserNotFound.Error()))
+		ctx.Error(err)
+		return
 	}
 	ctx.JSON(http.StatusOK, res)
+}
 
+func (rh RequestHandler) GetPermissions(ctx *gin.Context) {
+	role := ctx.GetString("userRole")
+	res, err := rh.ctrl.GetPermissions(ctx, role)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) UpdateLocale(ctx *gin.Context) {
+	var payload = LocaleParam{}
+	err := ctx.ShouldBind(&payload)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	id, _ := ctx.Get("id")
+	res, err := rh.ctrl.UpdateLocale(ctx, id.(uint), payload.Locale)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
 }
 
 func (rh RequestHandler) Login(ctx *gin.Context) {
 	var payload = LoginParam{}
-	err := ctx.Bind(&payload)
+	err := ctx.ShouldBind(&payload)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, dto.DefaultErrorInvalidDataWithMessage(err.Error()))
+		ctx.Error(constant.InvalidRequest(err))
 		return
 	}
-	res, err := rh.ctrl.Login(ctx, payload.Email, payload.Password)
+	res, err := rh.ctrl.Login(ctx, payload.Email, payload.Password, client(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.DefaultErrorResponseWithMessage(err.Error()))
+		ctx.Error(err)
+		return
 	}
 
 	ctx.JSON(http.StatusOK, res)
 }
 
+// Logout revokes the access token and the session of the refresh token in
+// the body, or every session of the user when the body has no refresh token.
 func (rh RequestHandler) Logout(ctx *gin.Context) {
 	id, ok := ctx.Get("id")
 	if !ok {
-		ctx.JSON(http.StatusBadRequest, dto.DefaultErrorResponseWithMessage("Invalid User ID type"))
+		ctx.Error(constant.ErrInvalidToken)
+		return
+	}
+	var payload = RefreshTokenParam{}
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBind(&payload); err != nil {
+			ctx.Error(constant.InvalidRequest(err))
+			return
+		}
+	}
+	access := revocation.Token{
+		ID:        ctx.GetString("jti"),
+		UserID:    id.(uint),
+		ExpiresAt: ctx.GetTime("exp"),
 	}
-	res, err := rh.ctrl.RevokeToken(ctx, id.(uint))
+	res, err := rh.ctrl.RevokeToken(ctx, id.(uint), access, payload.RefreshToken)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.DefaultErrorResponseWithMessage(err.Error()))
+		ctx.Error(err)
+		return
 	}
 	ctx.JSON(http.StatusOK, res)
 }
 
+// RefreshToken does not need the access token, it may already be expired.
 func (rh RequestHandler) RefreshToken(ctx *gin.Context) {
-	// Ambil data dari context
+	var payload = RefreshTokenParam{}
+	err := ctx.ShouldBind(&payload)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	if payload.RefreshToken == "" {
+		ctx.Error(constant.InvalidRequest(constant.ErrRefreshToken))
+		return
+	}
+
+	res, err := rh.ctrl.RefreshToken(ctx, payload.RefreshToken, client(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// Berikan respons sukses
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) GetSessions(ctx *gin.Context) {
 	id, _ := ctx.Get("id")
+	res, err := rh.ctrl.GetSessions(ctx, id.(uint))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
 
-	idUint, ok := id.(uint)
-	if !ok {
-		ctx.JSON(http.StatusUnauthorized, dto.DefaultErrorResponseWithMessage("Invalid User ID type"))
+func (rh RequestHandler) RevokeSession(ctx *gin.Context) {
+	sessionID, err := strconv.ParseUint(ctx.Param("sessionId"), 10, 32)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	id, _ := ctx.Get("id")
+	res, err := rh.ctrl.RevokeSession(ctx, id.(uint), uint(sessionID))
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
+	ctx.JSON(http.StatusOK, res)
+}
 
-	userName, exists := ctx.Get("userName")
-	if !exists {
-		ctx.JSON(http.StatusUnauthorized, dto.DefaultErrorResponseWithMessage("User name not found in context"))
+func (rh RequestHandler) ForgotPassword(ctx *gin.Context) {
+	var payload = ForgotPasswordParam{}
+	err := ctx.ShouldBind(&payload)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
 		return
 	}
-	userNameStr, ok := userName.(string)
-	if !ok {
-		ctx.JSON(http.StatusUnauthorized, dto.DefaultErrorResponseWithMessage("Invalid UserName type"))
+	res, err := rh.ctrl.ForgotPassword(ctx, payload.Email)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
+	ctx.JSON(http.StatusOK, res)
+}
 
-	created, exists := ctx.Get("createdAt")
-	if !exists {
-		ctx.JSON(http.StatusUnauthorized, dto.DefaultErrorResponseWithMessage("Token creation time not found in context"))
+func (rh RequestHandler) ResetPassword(ctx *gin.Context) {
+	var payload = ResetPasswordParam{}
+	err := ctx.ShouldBind(&payload)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
 		return
 	}
-	createdAt, ok := created.(time.Time)
-	if !ok {
-		ctx.JSON(http.StatusUnauthorized, dto.DefaultErrorResponseWithMessage("Invalid CreatedAt type"))
+	res, err := rh.ctrl.ResetPassword(ctx, payload.Token, payload.Password)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
+	ctx.JSON(http.StatusOK, res)
+}
 
-	role, exists := ctx.Get("userRole")
-	if !exists {
-		ctx.JSON(http.StatusUnauthorized, dto.DefaultErrorResponseWithMessage("User role not found in context"))
+func (rh RequestHandler) VerifyEmail(ctx *gin.Context) {
+	var payload = VerifyEmailParam{}
+	err := ctx.ShouldBind(&payload)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
 		return
 	}
-	roleStr, ok := role.(string)
-	if !ok {
-		ctx.JSON(http.StatusUnauthorized, dto.DefaultErrorResponseWithMessage("Invalid UserRole type"))
+	res, err := rh.ctrl.VerifyEmail(ctx, payload.Token)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
+	ctx.JSON(http.StatusOK, res)
+}
 
-	// Bind payload dari request body
-	var payload = RefreshTokenParam{}
-	err := ctx.Bind(&payload)
+func (rh RequestHandler) ResendVerification(ctx *gin.Context) {
+	id, _ := ctx.Get("id")
+	res, err := rh.ctrl.ResendVerification(ctx, id.(uint))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, dto.DefaultErrorInvalidDataWithMessage(err.Error()))
+		ctx.Error(err)
 		return
 	}
+	ctx.JSON(http.StatusOK, res)
+}
 
-	// Panggil controller untuk memproses refresh token
-	res, err := rh.ctrl.RefreshToken(
-		ctx,
-		idUint,
-		createdAt,
-		payload.RefreshToken,
-		roleStr,
-		userNameStr,
-	)
+// VerifyLogin is the second login step of a user with two-factor
+// authentication.
+func (rh RequestHandler) VerifyLogin(ctx *gin.Context) {
+	var payload = VerifyLoginParam{}
+	err := ctx.ShouldBind(&payload)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.DefaultErrorResponseWithMessage(err.Error()))
+		ctx.Error(constant.InvalidRequest(err))
 		return
 	}
+	res, err := rh.ctrl.VerifyLogin(ctx, payload.ChallengeToken, payload.Code, client(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
 
-	// Berikan respons sukses
+func (rh RequestHandler) EnrollTOTP(ctx *gin.Context) {
+	id, _ := ctx.Get("id")
+	res, err := rh.ctrl.EnrollTOTP(ctx, id.(uint))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (rh RequestHandler) ConfirmTOTP(ctx *gin.Context) {
+	var payload = TOTPCodeParam{}
+	err := ctx.ShouldBind(&payload)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	id, _ := ctx.Get("id")
+	res, err := rh.ctrl.ConfirmTOTP(ctx, id.(uint), payload.Code)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
 	ctx.JSON(http.StatusOK, res)
 }
+
+func (rh RequestHandler) DisableTOTP(ctx *gin.Context) {
+	var payload = TOTPCodeParam{}
+	err := ctx.ShouldBind(&payload)
+	if err != nil {
+		ctx.Error(constant.InvalidRequest(err))
+		return
+	}
+	id, _ := ctx.Get("id")
+	res, err := rh.ctrl.DisableTOTP(ctx, id.(uint), payload.Code)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func client(ctx *gin.Context) Client {
+	return Client{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+}
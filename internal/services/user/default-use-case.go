@@ -6,18 +6,31 @@ import (
 	"github.com/bowoBp/LoanFlow/internal/adapter/repository"
 	"github.com/bowoBp/LoanFlow/internal/constant"
 	"github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/i18n"
+	"github.com/bowoBp/LoanFlow/internal/notification"
+	"github.com/bowoBp/LoanFlow/internal/permission"
+	"github.com/bowoBp/LoanFlow/internal/revocation"
 	"github.com/bowoBp/LoanFlow/pkg/environment"
+	"github.com/bowoBp/LoanFlow/pkg/reporter"
 	"github.com/bowoBp/LoanFlow/utils/helper"
+	"slices"
 	"strconv"
 	"time"
 )
 
 type (
 	UsaCase struct {
-		userRepo Repository.UserRepoInterface
-		jwt      helper.JwtInterface
-		bcrypt   helper.BcryptInterface
-		env      environment.Environment
+		UserRepo         Repository.UserRepoInterface
+		SessionRepo      Repository.SessionRepoInterface
+		LoginAttemptRepo Repository.LoginAttemptRepoInterface
+		DbTransaction    Repository.TransactionUnit[DefaultUserTransactionInterface]
+		Jwt              helper.JwtInterface
+		Bcrypt           helper.BcryptInterface
+		Env              environment.Environment
+		Notifier         notification.NotifierInterface
+		Denylist         revocation.Denylist
+		Permissions      permission.Resolver
+		Reporter         reporter.Reporter
 	}
 
 	UsecaseInterface interface {
@@ -33,36 +46,129 @@ type (
 		LoginUser(
 			ctx context.Context,
 			email, password string,
-		) (*domians.User, string, string, error)
+			client Client,
+		) (LoginResult, error)
+
+		VerifyLogin(
+			ctx context.Context,
+			challengeToken, code string,
+			client Client,
+		) (LoginResult, error)
 
 		RefreshToken(
 			ctx context.Context,
-			id uint,
-			createdAt time.Time,
-			token, role, name string,
-		) (string, string, error)
+			token string,
+			client Client,
+		) (*domians.User, string, string, error)
 
 		RevokeToken(
 			ctx context.Context,
 			id uint,
+			access revocation.Token,
+			token string,
+		) error
+
+		GetSessions(
+			ctx context.Context,
+			id uint,
+		) ([]domians.Session, error)
+
+		RevokeSession(
+			ctx context.Context,
+			id, sessionID uint,
+		) error
+
+		ForgotPassword(
+			ctx context.Context,
+			email string,
+		) error
+
+		ResetPassword(
+			ctx context.Context,
+			token, password string,
+		) error
+
+		VerifyEmail(
+			ctx context.Context,
+			token string,
+		) error
+
+		ResendVerification(
+			ctx context.Context,
+			id uint,
+		) error
+
+		EnrollTOTP(
+			ctx context.Context,
+			id uint,
+		) (TOTPEnrolment, error)
+
+		ConfirmTOTP(
+			ctx context.Context,
+			id uint,
+			code string,
+		) ([]string, error)
+
+		DisableTOTP(
+			ctx context.Context,
+			id uint,
+			code string,
+		) error
+
+		GetPermissions(
+			ctx context.Context,
+			role string,
+		) ([]string, error)
+
+		UpdateLocale(
+			ctx context.Context,
+			id uint,
+			locale string,
 		) error
 	}
 )
 
+// RegisterUser creates the user and emails the link to verify the email,
+// loans cannot be created or funded before it is verified.
 func (uc UsaCase) RegisterUser(
 	ctx context.Context,
 	payload RegisterUser,
 ) (result UseCaseRegisterResult, err error) {
+	// STAFF and ADMIN are provisioned through the admin endpoints
+	if !slices.Contains(constant.PublicRoles, payload.Role) {
+		return result, constant.ErrRegisterRole
+	}
 
-	emailDuplicate, err := uc.userRepo.CheckEmail(ctx, payload.Email)
+	emailDuplicate, err := uc.UserRepo.CheckEmail(ctx, payload.Email)
 	if err != nil {
 		return result, err
 	}
 	if emailDuplicate != nil {
 		return result, constant.DuplicateEmail
 	}
-	hashPass := uc.bcrypt.HasPass(payload.Password)
-	user, err := uc.userRepo.StoreUser(
+
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return result, err
+	}
+	defer func(tx DefaultUserTransactionInterface, err *error) {
+		// recover panic
+		if r := recover(); r != nil {
+			*err = uc.Reporter.Recover(ctx, r)
+		}
+		// end transaction (rollback or commit)
+		errTrx := tx.End(*err)
+		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
+		}
+	}(dbTrx, &err)
+
+	now := time.Now()
+	hashPass := uc.Bcrypt.HasPass(payload.Password)
+	user, err := dbTrx.StoreUser(
 		ctx,
 		&domians.User{
 			Name:         payload.UserName,
@@ -70,9 +176,17 @@ func (uc UsaCase) RegisterUser(
 			Email:        payload.Email,
 			Phone:        payload.Phone,
 			Role:         payload.Role,
-			CreatedAt:    time.Now(),
-			UpdatedAt:    time.Now(),
+			Locale:       payload.Locale,
+			CreatedAt:    now,
+			UpdatedAt:    now,
 		})
+	if err != nil {
+		return result, err
+	}
+	err = uc.sendUserToken(ctx, dbTrx, user, constant.TokenEmailVerification, now)
+	if err != nil {
+		return result, err
+	}
 	result.User = RegisterUser{
 		ID:        strconv.Itoa(int(user.ID)),
 		UserName:  user.Name,
@@ -81,122 +195,492 @@ func (uc UsaCase) RegisterUser(
 		Role:      user.Role,
 		CreatedAt: user.CreatedAt,
 	}
-	return result, err
+	return result, nil
 }
 
 func (uc UsaCase) GetAll(
 	ctx context.Context,
 ) ([]domians.User, error) {
-	return uc.userRepo.GetAllUser(ctx)
+	return uc.UserRepo.GetAllUser(ctx)
 }
 
+// LoginUser checks the password and starts a session. A user with
+// two-factor authentication gets a challenge token instead, the session is
+// started by VerifyLogin with the code. Failed logins lock the account and
+// the IP address for a while, an unknown email fails like a wrong password.
 func (uc UsaCase) LoginUser(
 	ctx context.Context,
 	email, password string,
-) (*domians.User, string, string, error) {
-	user, err := uc.userRepo.CheckEmail(ctx, email)
+	client Client,
+) (result LoginResult, err error) {
+	now := time.Now()
+	err = uc.checkLoginThrottle(ctx, email, client, now)
 	if err != nil {
-		return nil, "", "", err
+		return LoginResult{}, err
+	}
+	user, err := uc.UserRepo.CheckEmail(ctx, email)
+	if err != nil {
+		return LoginResult{}, err
 	}
 
-	// verify hashed password
-	comparePass := uc.bcrypt.ComparePass([]byte(user.PasswordHash), []byte(password))
-	if !comparePass {
-		return nil, "", "", err
+	// verify hashed password, the dummy hash keeps an unknown email as slow
+	passwordHash := dummyPasswordHash
+	if user != nil {
+		passwordHash = user.PasswordHash
+	}
+	if !uc.Bcrypt.ComparePass([]byte(passwordHash), []byte(password)) || user == nil {
+		err = uc.recordLoginFailure(ctx, user, email, client, now)
+		if err != nil {
+			return LoginResult{}, err
+		}
+		return LoginResult{}, constant.ErrLogin
+	}
+	err = uc.LoginAttemptRepo.DeleteLoginThrottle(ctx, constant.ThrottleAccount, helper.NormalizeEmail(email))
+	if err != nil {
+		return LoginResult{}, err
 	}
 
-	//Generate token JWT
-	tokenString, errToken := uc.jwt.GenerateToken(user.ID, user.Role, user.Name, user.CreatedAt)
-	if errToken != nil {
-		return nil, "", "", err
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return LoginResult{}, err
 	}
+	defer func(tx DefaultUserTransactionInterface, err *error) {
+		// recover panic
+		if r := recover(); r != nil {
+			*err = uc.Reporter.Recover(ctx, r)
+		}
+		// end transaction (rollback or commit)
+		errTrx := tx.End(*err)
+		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
+		}
+	}(dbTrx, &err)
 
-	//Generate refresh token
-	refreshToken, err := uc._generateRefreshToken(user.ID, ctx)
+	if user.TOTPEnabledAt != nil {
+		result.ChallengeToken, err = uc.issueChallenge(ctx, dbTrx, user, now)
+		if err != nil {
+			return LoginResult{}, err
+		}
+		result.User = user
+		return result, nil
+	}
+	return uc.startSession(ctx, dbTrx, user, client, false, now)
+}
 
-	return user, tokenString, refreshToken, nil
+// startSession opens a session (token family) for the device and returns
+// its first refresh token with an access token.
+func (uc UsaCase) startSession(
+	ctx context.Context,
+	tx DefaultUserTransactionInterface,
+	user *domians.User,
+	client Client,
+	mfa bool,
+	now time.Time,
+) (LoginResult, error) {
+	session := &domians.Session{
+		UserID:     user.ID,
+		UserAgent:  client.UserAgent,
+		IPAddress:  client.IPAddress,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(constant.RefreshTokenTTL),
+		MFA:        mfa,
+	}
+	err := tx.CreateSession(ctx, session)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	refreshToken, err := uc.issueRefreshToken(ctx, tx, session, now)
+	if err != nil {
+		return LoginResult{}, err
+	}
 
+	//Generate token JWT
+	accessToken, err := uc.Jwt.GenerateToken(user.ID, user.Role, user.Name, user.Locale, user.CreatedAt, mfa)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	return LoginResult{
+		User:         user,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
 }
 
+// RefreshToken rotates the refresh token: the token is used up and a new
+// one of the same session is returned with a new access token. A token that
+// was already used means it leaked, the whole session is revoked and
+// ErrRefreshTokenReused is returned.
 func (uc UsaCase) RefreshToken(
 	ctx context.Context,
-	id uint,
-	createdAt time.Time,
-	token, role, name string,
-) (string, string, error) {
+	token string,
+	client Client,
+) (*domians.User, string, string, error) {
+	user, tokenString, refreshToken, reused, err := uc.rotateRefreshToken(ctx, token, client)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if reused {
+		return nil, "", "", constant.ErrRefreshTokenReused
+	}
+	return user, tokenString, refreshToken, nil
+}
 
-	//Generate token JWT
-	tokenString, err := uc.jwt.GenerateToken(id, role, name, createdAt)
+// rotateRefreshToken returns reused without an error when a used token is
+// replayed, so the revocation of the session is committed.
+func (uc UsaCase) rotateRefreshToken(
+	ctx context.Context,
+	token string,
+	client Client,
+) (user *domians.User, tokenString, refreshToken string, reused bool, err error) {
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return nil, "", "", false, err
+	}
+	defer func(tx DefaultUserTransactionInterface, err *error) {
+		// recover panic
+		if r := recover(); r != nil {
+			*err = uc.Reporter.Recover(ctx, r)
+		}
+		// end transaction (rollback or commit)
+		errTrx := tx.End(*err)
+		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
+		}
+	}(dbTrx, &err)
+
+	now := time.Now()
+	current, err := dbTrx.GetRefreshTokenByHash(ctx, HashToken(token))
 	if err != nil {
-		return "", "", err
+		return nil, "", "", false, err
+	}
+	if current == nil || current.Session == nil || current.Session.RevokedAt != nil {
+		return nil, "", "", false, constant.ErrRefreshToken
+	}
+	session := current.Session
+	if current.UsedAt != nil {
+		err = dbTrx.UpdateSession(ctx, session, map[string]any{
+			"revoked_at": now,
+		})
+		return nil, "", "", true, err
+	}
+	if !current.ExpiresAt.After(now) {
+		return nil, "", "", false, constant.ErrRefreshToken
 	}
 
-	//Generate refresh token
-	refreshToken, err := uc._generateRefreshToken(id, ctx)
+	// the role is read again so a role change applies from the next refresh
+	user, err = dbTrx.GetUserByID(ctx, current.UserID)
+	if err != nil {
+		return nil, "", "", false, err
+	}
+	if user == nil {
+		return nil, "", "", false, constant.ErrRefreshToken
+	}
 
-	return tokenString, refreshToken, nil
+	err = dbTrx.UpdateRefreshToken(ctx, current, map[string]any{
+		"used_at": now,
+	})
+	if err != nil {
+		return nil, "", "", false, err
+	}
+	refreshToken, err = uc.issueRefreshToken(ctx, dbTrx, session, now)
+	if err != nil {
+		return nil, "", "", false, err
+	}
+	err = dbTrx.UpdateSession(ctx, session, map[string]any{
+		"user_agent":   client.UserAgent,
+		"ip_address":   client.IPAddress,
+		"last_used_at": now,
+		"expires_at":   now.Add(constant.RefreshTokenTTL),
+	})
+	if err != nil {
+		return nil, "", "", false, err
+	}
 
+	tokenString, err = uc.Jwt.GenerateToken(user.ID, user.Role, user.Name, user.Locale, user.CreatedAt, session.MFA)
+	if err != nil {
+		return nil, "", "", false, err
+	}
+	return user, tokenString, refreshToken, false, nil
 }
 
-func (uc UsaCase) _generateRefreshToken(
-	id uint,
+// issueRefreshToken stores a new token of session, only its hash is kept.
+func (uc UsaCase) issueRefreshToken(
 	ctx context.Context,
+	tx DefaultUserTransactionInterface,
+	session *domians.Session,
+	now time.Time,
 ) (string, error) {
-	// Periksa apakah refresh token sudah ada
-	existingRefreshToken, err := uc.userRepo.CheckRefreshToken(ctx, id)
+	token, hash, err := newRefreshToken()
+	if err != nil {
+		return "", err
+	}
+	err = tx.StoreRefreshToken(ctx, &domians.RefreshToken{
+		UserID:    session.UserID,
+		SessionID: session.ID,
+		TokenHash: hash,
+		ExpiresAt: now.Add(constant.RefreshTokenTTL),
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
 	if err != nil {
-		return "", err // Error dari query ke database
+		return "", err
 	}
+	return token, nil
+}
 
-	// Generate nilai refresh token baru
-	refresh, err := uc.bcrypt.GenerateHashValue(
-		uc.env.Get("DEFAULT_SECRET_FORGET_PASSWORD"),
-		fmt.Sprintf("%d", id),
-		17,
-	)
+// RevokeToken logs out the access token and the session of token, every
+// session and access token of the user when no token is given.
+func (uc UsaCase) RevokeToken(
+	ctx context.Context,
+	id uint,
+	access revocation.Token,
+	token string,
+) error {
+	now := time.Now()
+	if token == "" {
+		err := uc.SessionRepo.RevokeSessionsByUserID(ctx, id, now)
+		if err != nil {
+			return err
+		}
+		return uc.Denylist.RevokeUser(ctx, id, now)
+	}
+	current, err := uc.SessionRepo.GetRefreshTokenByHash(ctx, HashToken(token))
 	if err != nil {
-		return "", fmt.Errorf("failed to generate refresh token hash: %w", err)
+		return err
 	}
+	if current == nil || current.UserID != id {
+		return constant.ErrRefreshToken
+	}
+	err = uc.SessionRepo.UpdateSession(ctx, &domians.Session{ID: current.SessionID}, map[string]any{
+		"revoked_at": now,
+	})
+	if err != nil {
+		return err
+	}
+	return uc.Denylist.Revoke(ctx, access)
+}
 
-	// Set waktu kedaluwarsa
-	expireAt := time.Now().Add(7 * 24 * time.Hour)
+func (uc UsaCase) GetSessions(
+	ctx context.Context,
+	id uint,
+) ([]domians.Session, error) {
+	return uc.SessionRepo.GetActiveSessions(ctx, id, time.Now())
+}
 
-	// Jika refresh token sudah ada, update
-	if existingRefreshToken != nil {
-		existingRefreshToken.RefreshToken = refresh
-		existingRefreshToken.ExpiresAt = expireAt
-		existingRefreshToken.UpdatedAt = time.Now()
+// RevokeSession revokes a session of the user, the session of another user
+// is reported as not found.
+func (uc UsaCase) RevokeSession(
+	ctx context.Context,
+	id, sessionID uint,
+) error {
+	session, err := uc.SessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.UserID != id {
+		return constant.ErrSessionNotFound
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+	return uc.SessionRepo.UpdateSession(ctx, session, map[string]any{
+		"revoked_at": time.Now(),
+	})
+}
 
-		// Update refresh token di database
-		err = uc.userRepo.UpdateRefreshToken(ctx, existingRefreshToken)
-		if err != nil {
-			return "", fmt.Errorf("failed to update refresh token: %w", err)
+// ForgotPassword emails a password reset link. An unknown email is not
+// reported so the endpoint cannot be used to find registered accounts.
+func (uc UsaCase) ForgotPassword(
+	ctx context.Context,
+	email string,
+) (err error) {
+	user, err := uc.UserRepo.CheckEmail(ctx, email)
+	if err != nil || user == nil {
+		return err
+	}
+
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return err
+	}
+	defer func(tx DefaultUserTransactionInterface, err *error) {
+		// recover panic
+		if r := recover(); r != nil {
+			*err = uc.Reporter.Recover(ctx, r)
 		}
+		// end transaction (rollback or commit)
+		errTrx := tx.End(*err)
+		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
+		}
+	}(dbTrx, &err)
+
+	return uc.sendUserToken(ctx, dbTrx, user, constant.TokenPasswordReset, time.Now())
+}
 
-		return existingRefreshToken.RefreshToken, nil
+// ResetPassword sets a new password with a reset token and logs out every
+// session of the user.
+func (uc UsaCase) ResetPassword(
+	ctx context.Context,
+	token, password string,
+) (err error) {
+	if password == "" {
+		return constant.ErrPassword
 	}
 
-	// Jika tidak ada, buat refresh token baru
-	newRefreshToken := &domians.RefreshToken{
-		UserID:       id,
-		RefreshToken: refresh,
-		ExpiresAt:    expireAt,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return err
 	}
+	defer func(tx DefaultUserTransactionInterface, err *error) {
+		// recover panic
+		if r := recover(); r != nil {
+			*err = uc.Reporter.Recover(ctx, r)
+		}
+		// end transaction (rollback or commit)
+		errTrx := tx.End(*err)
+		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
+		}
+	}(dbTrx, &err)
 
-	// Simpan refresh token baru ke database
-	_, err = uc.userRepo.StoreRefreshToken(ctx, newRefreshToken)
+	now := time.Now()
+	current, err := uc.consumeUserToken(ctx, dbTrx, constant.TokenPasswordReset, token, now)
+	if err != nil {
+		return err
+	}
+	user, err := dbTrx.GetUserByID(ctx, current.UserID)
 	if err != nil {
-		return "", fmt.Errorf("failed to store refresh token: %w", err)
+		return err
 	}
+	if user == nil {
+		return constant.ErrUserToken
+	}
+	updateData := map[string]any{
+		"password_hash": uc.Bcrypt.HasPass(password),
+		"updated_at":    now,
+	}
+	// the link was opened from the inbox, so the email is verified as well
+	if user.EmailVerifiedAt == nil {
+		updateData["email_verified_at"] = now
+	}
+	err = dbTrx.UpdateUser(ctx, user, updateData)
+	if err != nil {
+		return err
+	}
+	err = dbTrx.RevokeSessionsByUserID(ctx, user.ID, now)
+	if err != nil {
+		return err
+	}
+	return uc.Denylist.RevokeUser(ctx, user.ID, now)
+}
 
-	return newRefreshToken.RefreshToken, nil
+func (uc UsaCase) VerifyEmail(
+	ctx context.Context,
+	token string,
+) (err error) {
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return err
+	}
+	defer func(tx DefaultUserTransactionInterface, err *error) {
+		// recover panic
+		if r := recover(); r != nil {
+			*err = uc.Reporter.Recover(ctx, r)
+		}
+		// end transaction (rollback or commit)
+		errTrx := tx.End(*err)
+		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
+		}
+	}(dbTrx, &err)
+
+	now := time.Now()
+	current, err := uc.consumeUserToken(ctx, dbTrx, constant.TokenEmailVerification, token, now)
+	if err != nil {
+		return err
+	}
+	return dbTrx.UpdateUser(ctx, &domians.User{ID: current.UserID}, map[string]any{
+		"email_verified_at": now,
+		"updated_at":        now,
+	})
 }
 
-func (uc UsaCase) RevokeToken(
+// ResendVerification emails a new verification link, the previous links
+// stop working.
+func (uc UsaCase) ResendVerification(
 	ctx context.Context,
 	id uint,
+) (err error) {
+	user, err := uc.UserRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return constant.ErrUserNotFound
+	}
+	if user.EmailVerifiedAt != nil {
+		return constant.ErrEmailVerified
+	}
+
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return err
+	}
+	defer func(tx DefaultUserTransactionInterface, err *error) {
+		// recover panic
+		if r := recover(); r != nil {
+			*err = uc.Reporter.Recover(ctx, r)
+		}
+		// end transaction (rollback or commit)
+		errTrx := tx.End(*err)
+		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
+		}
+	}(dbTrx, &err)
+
+	return uc.sendUserToken(ctx, dbTrx, user, constant.TokenEmailVerification, time.Now())
+}
+
+// GetPermissions returns what the users of the role may do, so front ends
+// can hide the actions they cannot take.
+func (uc UsaCase) GetPermissions(
+	ctx context.Context,
+	role string,
+) ([]string, error) {
+	return uc.Permissions.Permissions(ctx, role)
+}
+
+// UpdateLocale saves the language preference of the user, it is carried by
+// the access tokens issued from the next login or refresh.
+func (uc UsaCase) UpdateLocale(
+	ctx context.Context,
+	id uint,
+	locale string,
 ) error {
-	return uc.userRepo.DeleteRefreshTokenByUserID(ctx, id)
+	if !i18n.Supported(locale) {
+		return constant.ErrLocale
+	}
+	return uc.UserRepo.UpdateUser(ctx, &domians.User{ID: id}, map[string]any{
+		"locale":     locale,
+		"updated_at": time.Now(),
+	})
 }
@@ -0,0 +1,925 @@
+package user_test
+
+import (
+	"context"
+	"github.com/bowoBp/LoanFlow/internal/adapter/mocks"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/i18n"
+	"github.com/bowoBp/LoanFlow/internal/notification"
+	"github.com/bowoBp/LoanFlow/internal/revocation"
+	"github.com/bowoBp/LoanFlow/internal/services/user"
+	"github.com/bowoBp/LoanFlow/pkg/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUsaCase_LoginUser(t *testing.T) {
+	mockTransaction := new(mocks.DefaultUserTransactionInterface)
+	mockUserRepo := new(mocks.UserRepoInterface)
+	mockLoginAttemptRepo := new(mocks.LoginAttemptRepoInterface)
+	mockJwt := new(mocks.JwtInterface)
+	mockBcrypt := new(mocks.BcryptInterface)
+
+	account := &domians.User{ID: 5, Name: "budi", Role: constant.RoleBorrower, PasswordHash: "hashed"}
+	enabledAt := time.Now()
+	mfaAccount := &domians.User{ID: 5, Name: "budi", Role: constant.RoleStaff, PasswordHash: "hashed", TOTPEnabledAt: &enabledAt}
+	client := user.Client{UserAgent: "curl/8.0", IPAddress: "10.0.0.1"}
+	lockedUntil := time.Now().Add(time.Minute)
+
+	notLocked := func() {
+		mockLoginAttemptRepo.On("GetLoginThrottle", mock.Anything, constant.ThrottleAccount, "budi@loanflow.id").
+			Return(nil, nil).Once()
+		mockLoginAttemptRepo.On("GetLoginThrottle", mock.Anything, constant.ThrottleIP, client.IPAddress).
+			Return(nil, nil).Once()
+	}
+	failure := func(accountFailures int) {
+		mockLoginAttemptRepo.On("IncrementLoginFailures", mock.Anything, constant.ThrottleAccount, "budi@loanflow.id", mock.Anything, mock.Anything).
+			Return(&domians.LoginThrottle{Failures: accountFailures}, nil).Once()
+		mockLoginAttemptRepo.On("IncrementLoginFailures", mock.Anything, constant.ThrottleIP, client.IPAddress, mock.Anything, mock.Anything).
+			Return(&domians.LoginThrottle{Failures: 1}, nil).Once()
+	}
+
+	tests := []struct {
+		name          string
+		mockBehavior  func()
+		email         string
+		wantChallenge bool
+		wantErr       bool
+		expectedErr   error
+	}{
+		{
+			name: "Success - new session and hashed refresh token stored",
+			mockBehavior: func() {
+				notLocked()
+				mockUserRepo.On("CheckEmail", mock.Anything, "budi@loanflow.id").Return(account, nil).Once()
+				mockBcrypt.On("ComparePass", []byte("hashed"), []byte("secret")).Return(true).Once()
+				mockLoginAttemptRepo.On("DeleteLoginThrottle", mock.Anything, constant.ThrottleAccount, "budi@loanflow.id").
+					Return(nil).Once()
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("CreateSession", mock.Anything, mock.MatchedBy(func(session *domians.Session) bool {
+					return session.UserID == account.ID && session.UserAgent == client.UserAgent &&
+						session.IPAddress == client.IPAddress
+				})).Run(func(args mock.Arguments) {
+					args.Get(1).(*domians.Session).ID = 9
+				}).Return(nil).Once()
+				mockTransaction.On("StoreRefreshToken", mock.Anything, mock.MatchedBy(func(token *domians.RefreshToken) bool {
+					return token.SessionID == 9 && token.UserID == account.ID && len(token.TokenHash) == 64
+				})).Return(nil).Once()
+				mockJwt.On("GenerateToken", account.ID, account.Role, account.Name, account.Locale, account.CreatedAt, false).
+					Return("access", nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+			},
+			email: "budi@loanflow.id",
+		},
+		{
+			name: "Success - two-factor user gets a challenge instead of a session",
+			mockBehavior: func() {
+				notLocked()
+				mockUserRepo.On("CheckEmail", mock.Anything, "budi@loanflow.id").Return(mfaAccount, nil).Once()
+				mockBcrypt.On("ComparePass", []byte("hashed"), []byte("secret")).Return(true).Once()
+				mockLoginAttemptRepo.On("DeleteLoginThrottle", mock.Anything, constant.ThrottleAccount, "budi@loanflow.id").
+					Return(nil).Once()
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("StoreUserToken", mock.Anything, mock.MatchedBy(func(token *domians.UserToken) bool {
+					return token.UserID == account.ID && token.Purpose == constant.TokenMFAChallenge &&
+						token.ExpiresAt.Sub(token.CreatedAt) == constant.MFAChallengeTTL
+				})).Return(nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+			},
+			email:         "budi@loanflow.id",
+			wantChallenge: true,
+		},
+		{
+			name: "Error - wrong password is counted and audited",
+			mockBehavior: func() {
+				notLocked()
+				mockUserRepo.On("CheckEmail", mock.Anything, "budi@loanflow.id").Return(account, nil).Once()
+				mockBcrypt.On("ComparePass", []byte("hashed"), []byte("secret")).Return(false).Once()
+				mockLoginAttemptRepo.On("CreateAuthEvent", mock.Anything, mock.MatchedBy(func(event *domians.AuthEvent) bool {
+					return event.Event == constant.AuthEventLoginFailed && event.UserID != nil && *event.UserID == account.ID
+				})).Return(nil).Once()
+				failure(1)
+			},
+			email:       "budi@loanflow.id",
+			wantErr:     true,
+			expectedErr: constant.ErrLogin,
+		},
+		{
+			name: "Error - unknown email is compared with the dummy hash",
+			mockBehavior: func() {
+				notLocked()
+				mockUserRepo.On("CheckEmail", mock.Anything, "budi@loanflow.id").Return(nil, nil).Once()
+				mockBcrypt.On("ComparePass", mock.MatchedBy(func(hash []byte) bool {
+					return len(hash) == 60
+				}), []byte("secret")).Return(false).Once()
+				mockLoginAttemptRepo.On("CreateAuthEvent", mock.Anything, mock.MatchedBy(func(event *domians.AuthEvent) bool {
+					return event.Event == constant.AuthEventLoginFailed && event.UserID == nil
+				})).Return(nil).Once()
+				failure(1)
+			},
+			email:       "budi@loanflow.id",
+			wantErr:     true,
+			expectedErr: constant.ErrLogin,
+		},
+		{
+			name: "Error - fifth failure locks the account",
+			mockBehavior: func() {
+				notLocked()
+				mockUserRepo.On("CheckEmail", mock.Anything, "Budi@LoanFlow.id").Return(account, nil).Once()
+				mockBcrypt.On("ComparePass", []byte("hashed"), []byte("secret")).Return(false).Once()
+				mockLoginAttemptRepo.On("CreateAuthEvent", mock.Anything, mock.MatchedBy(func(event *domians.AuthEvent) bool {
+					return event.Event == constant.AuthEventLoginFailed
+				})).Return(nil).Once()
+				failure(constant.LoginAccountFreeAttempts)
+				mockLoginAttemptRepo.On("LockLogin", mock.Anything, constant.ThrottleAccount, "budi@loanflow.id", mock.MatchedBy(func(until time.Time) bool {
+					lock := time.Until(until)
+					return lock > constant.LoginLockBase-time.Second && lock <= constant.LoginLockBase
+				})).Return(nil).Once()
+				mockLoginAttemptRepo.On("CreateAuthEvent", mock.Anything, mock.MatchedBy(func(event *domians.AuthEvent) bool {
+					return event.Event == constant.AuthEventLoginLocked
+				})).Return(nil).Once()
+			},
+			email:       "Budi@LoanFlow.id",
+			wantErr:     true,
+			expectedErr: constant.ErrLogin,
+		},
+		{
+			name: "Error - locked account is refused before the password check",
+			mockBehavior: func() {
+				mockLoginAttemptRepo.On("GetLoginThrottle", mock.Anything, constant.ThrottleAccount, "budi@loanflow.id").
+					Return(&domians.LoginThrottle{Failures: 5, LockedUntil: &lockedUntil}, nil).Once()
+			},
+			email:       "budi@loanflow.id",
+			wantErr:     true,
+			expectedErr: constant.ErrLoginLocked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := user.UsaCase{
+				UserRepo:         mockUserRepo,
+				LoginAttemptRepo: mockLoginAttemptRepo,
+				DbTransaction:    mockTransaction,
+				Jwt:              mockJwt,
+				Bcrypt:           mockBcrypt,
+			}
+			got, err := uc.LoginUser(context.Background(), tt.email, "secret", client)
+
+			switch {
+			case tt.wantErr:
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedErr, err)
+			case tt.wantChallenge:
+				assert.NoError(t, err)
+				assert.NotEmpty(t, got.ChallengeToken)
+				assert.Empty(t, got.AccessToken)
+				assert.Empty(t, got.RefreshToken)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, account.ID, got.User.ID)
+				assert.Equal(t, "access", got.AccessToken)
+				assert.NotEmpty(t, got.RefreshToken)
+			}
+
+			mockTransaction.AssertExpectations(t)
+			mockUserRepo.AssertExpectations(t)
+			mockLoginAttemptRepo.AssertExpectations(t)
+			mockJwt.AssertExpectations(t)
+			mockBcrypt.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsaCase_RefreshToken(t *testing.T) {
+	mockTransaction := new(mocks.DefaultUserTransactionInterface)
+	mockJwt := new(mocks.JwtInterface)
+
+	token := "refresh-token"
+	hash := user.HashToken(token)
+	client := user.Client{UserAgent: "curl/8.0", IPAddress: "10.0.0.1"}
+	account := &domians.User{ID: 5, Name: "budi", Role: constant.RoleStaff}
+	usedAt := time.Now().Add(-time.Minute)
+	revokedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		wantErr      bool
+		expectedErr  error
+	}{
+		{
+			name: "Success - token rotated with the role read again and the MFA of the session kept",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetRefreshTokenByHash", mock.Anything, hash).Return(&domians.RefreshToken{
+					ID:        1,
+					UserID:    account.ID,
+					SessionID: 9,
+					ExpiresAt: time.Now().Add(time.Hour),
+					Session:   &domians.Session{ID: 9, UserID: account.ID, MFA: true},
+				}, nil).Once()
+				mockTransaction.On("GetUserByID", mock.Anything, account.ID).Return(account, nil).Once()
+				mockTransaction.On("UpdateRefreshToken", mock.Anything, mock.Anything, mock.MatchedBy(func(data map[string]any) bool {
+					_, ok := data["used_at"]
+					return ok
+				})).Return(nil).Once()
+				mockTransaction.On("StoreRefreshToken", mock.Anything, mock.MatchedBy(func(token *domians.RefreshToken) bool {
+					return token.SessionID == 9 && token.TokenHash != hash
+				})).Return(nil).Once()
+				mockTransaction.On("UpdateSession", mock.Anything, mock.Anything, mock.MatchedBy(func(data map[string]any) bool {
+					return data["user_agent"] == client.UserAgent && data["ip_address"] == client.IPAddress
+				})).Return(nil).Once()
+				mockJwt.On("GenerateToken", account.ID, constant.RoleStaff, account.Name, account.Locale, account.CreatedAt, true).
+					Return("access", nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+			},
+		},
+		{
+			name: "Error - reused token revokes the session and commits",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetRefreshTokenByHash", mock.Anything, hash).Return(&domians.RefreshToken{
+					ID:        1,
+					UserID:    account.ID,
+					SessionID: 9,
+					ExpiresAt: time.Now().Add(time.Hour),
+					UsedAt:    &usedAt,
+					Session:   &domians.Session{ID: 9, UserID: account.ID},
+				}, nil).Once()
+				mockTransaction.On("UpdateSession", mock.Anything, mock.Anything, mock.MatchedBy(func(data map[string]any) bool {
+					_, ok := data["revoked_at"]
+					return ok
+				})).Return(nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrRefreshTokenReused,
+		},
+		{
+			name: "Error - token of a revoked session",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetRefreshTokenByHash", mock.Anything, hash).Return(&domians.RefreshToken{
+					ID:        1,
+					UserID:    account.ID,
+					SessionID: 9,
+					ExpiresAt: time.Now().Add(time.Hour),
+					Session:   &domians.Session{ID: 9, UserID: account.ID, RevokedAt: &revokedAt},
+				}, nil).Once()
+				mockTransaction.On("End", constant.ErrRefreshToken).Return(nil).Once()
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrRefreshToken,
+		},
+		{
+			name: "Error - expired token",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetRefreshTokenByHash", mock.Anything, hash).Return(&domians.RefreshToken{
+					ID:        1,
+					UserID:    account.ID,
+					SessionID: 9,
+					ExpiresAt: time.Now().Add(-time.Hour),
+					Session:   &domians.Session{ID: 9, UserID: account.ID},
+				}, nil).Once()
+				mockTransaction.On("End", constant.ErrRefreshToken).Return(nil).Once()
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrRefreshToken,
+		},
+		{
+			name: "Error - unknown token",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetRefreshTokenByHash", mock.Anything, hash).Return(nil, nil).Once()
+				mockTransaction.On("End", constant.ErrRefreshToken).Return(nil).Once()
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrRefreshToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := user.UsaCase{
+				DbTransaction: mockTransaction,
+				Jwt:           mockJwt,
+			}
+			got, access, refresh, err := uc.RefreshToken(context.Background(), token, client)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, constant.RoleStaff, got.Role)
+				assert.Equal(t, "access", access)
+				assert.NotEqual(t, token, refresh)
+			}
+
+			mockTransaction.AssertExpectations(t)
+			mockJwt.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsaCase_RevokeSession(t *testing.T) {
+	mockSessionRepo := new(mocks.SessionRepoInterface)
+
+	userID := uint(5)
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		wantErr      bool
+		expectedErr  error
+	}{
+		{
+			name: "Success - own session revoked",
+			mockBehavior: func() {
+				mockSessionRepo.On("GetSessionByID", mock.Anything, uint(9)).
+					Return(&domians.Session{ID: 9, UserID: userID}, nil).Once()
+				mockSessionRepo.On("UpdateSession", mock.Anything, mock.Anything, mock.MatchedBy(func(data map[string]any) bool {
+					_, ok := data["revoked_at"]
+					return ok
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name: "Error - session of another user",
+			mockBehavior: func() {
+				mockSessionRepo.On("GetSessionByID", mock.Anything, uint(9)).
+					Return(&domians.Session{ID: 9, UserID: 6}, nil).Once()
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := user.UsaCase{
+				SessionRepo: mockSessionRepo,
+			}
+			err := uc.RevokeSession(context.Background(), userID, 9)
+
+			if tt.wantErr {
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockSessionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsaCase_RevokeToken(t *testing.T) {
+	mockSessionRepo := new(mocks.SessionRepoInterface)
+	mockDenylist := new(mocks.Denylist)
+
+	userID := uint(5)
+	access := revocation.Token{ID: "jti-1", UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		token        string
+		wantErr      bool
+		expectedErr  error
+	}{
+		{
+			name: "Success - session and access token revoked",
+			mockBehavior: func() {
+				mockSessionRepo.On("GetRefreshTokenByHash", mock.Anything, user.HashToken("refresh")).
+					Return(&domians.RefreshToken{UserID: userID, SessionID: 9}, nil).Once()
+				mockSessionRepo.On("UpdateSession", mock.Anything, &domians.Session{ID: 9}, mock.Anything).
+					Return(nil).Once()
+				mockDenylist.On("Revoke", mock.Anything, access).Return(nil).Once()
+			},
+			token: "refresh",
+		},
+		{
+			name: "Success - every session and access token revoked",
+			mockBehavior: func() {
+				mockSessionRepo.On("RevokeSessionsByUserID", mock.Anything, userID, mock.Anything).Return(nil).Once()
+				mockDenylist.On("RevokeUser", mock.Anything, userID, mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name: "Error - refresh token of another user",
+			mockBehavior: func() {
+				mockSessionRepo.On("GetRefreshTokenByHash", mock.Anything, user.HashToken("refresh")).
+					Return(&domians.RefreshToken{UserID: 6, SessionID: 9}, nil).Once()
+			},
+			token:       "refresh",
+			wantErr:     true,
+			expectedErr: constant.ErrRefreshToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := user.UsaCase{
+				SessionRepo: mockSessionRepo,
+				Denylist:    mockDenylist,
+			}
+			err := uc.RevokeToken(context.Background(), userID, access, tt.token)
+
+			if tt.wantErr {
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockSessionRepo.AssertExpectations(t)
+			mockDenylist.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsaCase_UpdateLocale(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepoInterface)
+
+	userID := uint(5)
+
+	tests := []struct {
+		name         string
+		locale       string
+		mockBehavior func()
+		wantErr      bool
+		expectedErr  error
+	}{
+		{
+			name:   "Success - preference saved",
+			locale: i18n.IdID,
+			mockBehavior: func() {
+				mockUserRepo.On("UpdateUser", mock.Anything, &domians.User{ID: userID}, mock.MatchedBy(func(data map[string]any) bool {
+					return data["locale"] == i18n.IdID
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name:         "Error - unsupported locale",
+			locale:       "fr-FR",
+			mockBehavior: func() {},
+			wantErr:      true,
+			expectedErr:  constant.ErrLocale,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := user.UsaCase{
+				UserRepo: mockUserRepo,
+			}
+			err := uc.UpdateLocale(context.Background(), userID, tt.locale)
+
+			if tt.wantErr {
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockUserRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsaCase_ForgotPassword(t *testing.T) {
+	mockTransaction := new(mocks.DefaultUserTransactionInterface)
+	mockUserRepo := new(mocks.UserRepoInterface)
+	mockBcrypt := new(mocks.BcryptInterface)
+	mockEnv := new(mocks.Environment)
+	mockNotifier := new(mocks.NotifierInterface)
+
+	account := &domians.User{ID: 5, Name: "budi", Email: "budi@loanflow.id"}
+	mockEnv.On("Get", "DEFAULT_SECRET_FORGET_PASSWORD").Return("MI2CKT3TMRTGYZJSMRWGGMRU")
+	mockEnv.On("Get", "PASSWORD_RESET_URL").Return("https://app.loanflow.id/reset-password")
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+	}{
+		{
+			name: "Success - previous links replaced and a new link emailed",
+			mockBehavior: func() {
+				mockUserRepo.On("CheckEmail", mock.Anything, account.Email).Return(account, nil).Once()
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("UseUserTokens", mock.Anything, account.ID, constant.TokenPasswordReset, mock.Anything).
+					Return(nil).Once()
+				mockBcrypt.On("GenerateHashValue", "MI2CKT3TMRTGYZJSMRWGGMRU", mock.Anything, 0).
+					Return("hashed-token", nil).Once()
+				mockTransaction.On("StoreUserToken", mock.Anything, mock.MatchedBy(func(token *domians.UserToken) bool {
+					return token.UserID == account.ID && token.Purpose == constant.TokenPasswordReset &&
+						token.TokenHash == "hashed-token" &&
+						token.ExpiresAt.Sub(token.CreatedAt) == constant.PasswordResetTTL
+				})).Return(nil).Once()
+				mockNotifier.On("Enqueue", mock.Anything, mockTransaction, constant.TemplatePasswordReset,
+					mock.MatchedBy(func(recipients []notification.Recipient) bool {
+						if len(recipients) != 1 {
+							return false
+						}
+						link := recipients[0].Data.(notification.AccountLink).Link
+						// without a preference the email follows the request
+						return recipients[0].Address == account.Email &&
+							recipients[0].Locale == i18n.IdID &&
+							strings.HasPrefix(link, "https://app.loanflow.id/reset-password?token=")
+					})).Return(nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+			},
+		},
+		{
+			name: "Success - unknown email is not reported",
+			mockBehavior: func() {
+				mockUserRepo.On("CheckEmail", mock.Anything, account.Email).Return(nil, nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := user.UsaCase{
+				UserRepo:      mockUserRepo,
+				DbTransaction: mockTransaction,
+				Bcrypt:        mockBcrypt,
+				Env:           mockEnv,
+				Notifier:      mockNotifier,
+			}
+			ctx := context.WithValue(context.Background(), i18n.LocaleKey, i18n.IdID)
+			err := uc.ForgotPassword(ctx, account.Email)
+
+			assert.NoError(t, err)
+			mockTransaction.AssertExpectations(t)
+			mockUserRepo.AssertExpectations(t)
+			mockBcrypt.AssertExpectations(t)
+			mockNotifier.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsaCase_ResetPassword(t *testing.T) {
+	mockTransaction := new(mocks.DefaultUserTransactionInterface)
+	mockBcrypt := new(mocks.BcryptInterface)
+	mockEnv := new(mocks.Environment)
+	mockDenylist := new(mocks.Denylist)
+
+	userID := uint(5)
+	usedAt := time.Now().Add(-time.Minute)
+	mockEnv.On("Get", "DEFAULT_SECRET_FORGET_PASSWORD").Return("MI2CKT3TMRTGYZJSMRWGGMRU")
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		password     string
+		wantErr      bool
+		expectedErr  error
+	}{
+		{
+			name: "Success - password changed and sessions and tokens revoked",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockBcrypt.On("GenerateHashValue", "MI2CKT3TMRTGYZJSMRWGGMRU", "reset-token", 0).
+					Return("hashed-token", nil).Once()
+				mockTransaction.On("GetUserTokenByHash", mock.Anything, constant.TokenPasswordReset, "hashed-token").
+					Return(&domians.UserToken{ID: 1, UserID: userID, ExpiresAt: time.Now().Add(time.Minute)}, nil).Once()
+				mockTransaction.On("UseUserTokens", mock.Anything, userID, constant.TokenPasswordReset, mock.Anything).
+					Return(nil).Once()
+				mockTransaction.On("GetUserByID", mock.Anything, userID).
+					Return(&domians.User{ID: userID}, nil).Once()
+				mockBcrypt.On("HasPass", "new-secret").Return("new-hash").Once()
+				mockTransaction.On("UpdateUser", mock.Anything, mock.Anything, mock.MatchedBy(func(data map[string]any) bool {
+					_, verified := data["email_verified_at"]
+					return data["password_hash"] == "new-hash" && verified
+				})).Return(nil).Once()
+				mockTransaction.On("RevokeSessionsByUserID", mock.Anything, userID, mock.Anything).Return(nil).Once()
+				mockDenylist.On("RevokeUser", mock.Anything, userID, mock.Anything).Return(nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+			},
+			password: "new-secret",
+		},
+		{
+			name: "Error - token already used",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockBcrypt.On("GenerateHashValue", "MI2CKT3TMRTGYZJSMRWGGMRU", "reset-token", 0).
+					Return("hashed-token", nil).Once()
+				mockTransaction.On("GetUserTokenByHash", mock.Anything, constant.TokenPasswordReset, "hashed-token").
+					Return(&domians.UserToken{ID: 1, UserID: userID, ExpiresAt: time.Now().Add(time.Minute), UsedAt: &usedAt}, nil).Once()
+				mockTransaction.On("End", constant.ErrUserToken).Return(nil).Once()
+			},
+			password:    "new-secret",
+			wantErr:     true,
+			expectedErr: constant.ErrUserToken,
+		},
+		{
+			name: "Error - token expired",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockBcrypt.On("GenerateHashValue", "MI2CKT3TMRTGYZJSMRWGGMRU", "reset-token", 0).
+					Return("hashed-token", nil).Once()
+				mockTransaction.On("GetUserTokenByHash", mock.Anything, constant.TokenPasswordReset, "hashed-token").
+					Return(&domians.UserToken{ID: 1, UserID: userID, ExpiresAt: time.Now().Add(-time.Minute)}, nil).Once()
+				mockTransaction.On("End", constant.ErrUserToken).Return(nil).Once()
+			},
+			password:    "new-secret",
+			wantErr:     true,
+			expectedErr: constant.ErrUserToken,
+		},
+		{
+			name:         "Error - empty password",
+			mockBehavior: func() {},
+			wantErr:      true,
+			expectedErr:  constant.ErrPassword,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := user.UsaCase{
+				DbTransaction: mockTransaction,
+				Bcrypt:        mockBcrypt,
+				Env:           mockEnv,
+				Denylist:      mockDenylist,
+			}
+			err := uc.ResetPassword(context.Background(), "reset-token", tt.password)
+
+			if tt.wantErr {
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockTransaction.AssertExpectations(t)
+			mockBcrypt.AssertExpectations(t)
+			mockDenylist.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsaCase_VerifyEmail(t *testing.T) {
+	mockTransaction := new(mocks.DefaultUserTransactionInterface)
+	mockBcrypt := new(mocks.BcryptInterface)
+	mockEnv := new(mocks.Environment)
+
+	userID := uint(5)
+	mockEnv.On("Get", "DEFAULT_SECRET_VERIFY_EMAIL").Return("KZCVESKGLFCU2QKJ")
+	mockBcrypt.On("GenerateHashValue", "KZCVESKGLFCU2QKJ", "verify-token", 0).Return("hashed-token", nil)
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		wantErr      bool
+		expectedErr  error
+	}{
+		{
+			name: "Success - email verified",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetUserTokenByHash", mock.Anything, constant.TokenEmailVerification, "hashed-token").
+					Return(&domians.UserToken{ID: 1, UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}, nil).Once()
+				mockTransaction.On("UseUserTokens", mock.Anything, userID, constant.TokenEmailVerification, mock.Anything).
+					Return(nil).Once()
+				mockTransaction.On("UpdateUser", mock.Anything, &domians.User{ID: userID}, mock.MatchedBy(func(data map[string]any) bool {
+					_, ok := data["email_verified_at"]
+					return ok
+				})).Return(nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+			},
+		},
+		{
+			name: "Error - unknown token",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetUserTokenByHash", mock.Anything, constant.TokenEmailVerification, "hashed-token").
+					Return(nil, nil).Once()
+				mockTransaction.On("End", constant.ErrUserToken).Return(nil).Once()
+			},
+			wantErr:     true,
+			expectedErr: constant.ErrUserToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := user.UsaCase{
+				DbTransaction: mockTransaction,
+				Bcrypt:        mockBcrypt,
+				Env:           mockEnv,
+			}
+			err := uc.VerifyEmail(context.Background(), "verify-token")
+
+			if tt.wantErr {
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockTransaction.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsaCase_VerifyLogin(t *testing.T) {
+	mockTransaction := new(mocks.DefaultUserTransactionInterface)
+	mockJwt := new(mocks.JwtInterface)
+
+	secret, _ := totp.GenerateSecret()
+	enabledAt := time.Now()
+	account := &domians.User{ID: 5, Name: "budi", Role: constant.RoleStaff, TOTPSecret: secret, TOTPEnabledAt: &enabledAt}
+	challengeToken := "challenge-token"
+	hash := user.HashToken(challengeToken)
+	client := user.Client{UserAgent: "curl/8.0", IPAddress: "10.0.0.1"}
+	code, _ := totp.Code(secret, totp.Step(time.Now()))
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		code         string
+		wantErr      bool
+		expectedErr  error
+	}{
+		{
+			name: "Success - TOTP code starts an MFA session",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetUserTokenByHash", mock.Anything, constant.TokenMFAChallenge, hash).
+					Return(&domians.UserToken{ID: 1, UserID: account.ID, ExpiresAt: time.Now().Add(time.Minute)}, nil).Once()
+				mockTransaction.On("GetUserByID", mock.Anything, account.ID).Return(account, nil).Once()
+				mockTransaction.On("UpdateTOTPStep", mock.Anything, account.ID, mock.Anything).Return(true, nil).Once()
+				mockTransaction.On("UseUserTokens", mock.Anything, account.ID, constant.TokenMFAChallenge, mock.Anything).
+					Return(nil).Once()
+				mockTransaction.On("CreateSession", mock.Anything, mock.MatchedBy(func(session *domians.Session) bool {
+					return session.MFA
+				})).Return(nil).Once()
+				mockTransaction.On("StoreRefreshToken", mock.Anything, mock.Anything).Return(nil).Once()
+				mockJwt.On("GenerateToken", account.ID, account.Role, account.Name, account.Locale, account.CreatedAt, true).
+					Return("access", nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+			},
+			code: code,
+		},
+		{
+			name: "Error - replayed TOTP code counts as a wrong attempt",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetUserTokenByHash", mock.Anything, constant.TokenMFAChallenge, hash).
+					Return(&domians.UserToken{ID: 1, UserID: account.ID, ExpiresAt: time.Now().Add(time.Minute), Attempts: 1}, nil).Once()
+				mockTransaction.On("GetUserByID", mock.Anything, account.ID).Return(account, nil).Once()
+				mockTransaction.On("UpdateTOTPStep", mock.Anything, account.ID, mock.Anything).Return(false, nil).Once()
+				mockTransaction.On("UpdateUserToken", mock.Anything, mock.Anything, map[string]any{"attempts": uint(2)}).
+					Return(nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+			},
+			code:        code,
+			wantErr:     true,
+			expectedErr: constant.ErrTOTPCode,
+		},
+		{
+			name: "Success - recovery code is used up",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetUserTokenByHash", mock.Anything, constant.TokenMFAChallenge, hash).
+					Return(&domians.UserToken{ID: 1, UserID: account.ID, ExpiresAt: time.Now().Add(time.Minute)}, nil).Once()
+				mockTransaction.On("GetUserByID", mock.Anything, account.ID).Return(account, nil).Once()
+				mockTransaction.On("UseRecoveryCode", mock.Anything, account.ID, user.HashToken("abcdefghij"), mock.Anything).
+					Return(true, nil).Once()
+				mockTransaction.On("UseUserTokens", mock.Anything, account.ID, constant.TokenMFAChallenge, mock.Anything).
+					Return(nil).Once()
+				mockTransaction.On("CreateSession", mock.Anything, mock.Anything).Return(nil).Once()
+				mockTransaction.On("StoreRefreshToken", mock.Anything, mock.Anything).Return(nil).Once()
+				mockJwt.On("GenerateToken", account.ID, account.Role, account.Name, account.Locale, account.CreatedAt, true).
+					Return("access", nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+			},
+			code: "ABCDE-fghij",
+		},
+		{
+			name: "Error - challenge out of attempts",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetUserTokenByHash", mock.Anything, constant.TokenMFAChallenge, hash).
+					Return(&domians.UserToken{ID: 1, UserID: account.ID, ExpiresAt: time.Now().Add(time.Minute), Attempts: constant.MFAMaxAttempts}, nil).Once()
+				mockTransaction.On("End", constant.ErrMFAChallenge).Return(nil).Once()
+			},
+			code:        code,
+			wantErr:     true,
+			expectedErr: constant.ErrMFAChallenge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := user.UsaCase{
+				DbTransaction: mockTransaction,
+				Jwt:           mockJwt,
+			}
+			got, err := uc.VerifyLogin(context.Background(), challengeToken, tt.code, client)
+
+			if tt.wantErr {
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "access", got.AccessToken)
+				assert.NotEmpty(t, got.RefreshToken)
+			}
+
+			mockTransaction.AssertExpectations(t)
+			mockJwt.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsaCase_ConfirmTOTP(t *testing.T) {
+	mockTransaction := new(mocks.DefaultUserTransactionInterface)
+	mockDenylist := new(mocks.Denylist)
+
+	secret, _ := totp.GenerateSecret()
+	account := &domians.User{ID: 5, TOTPSecret: secret}
+	code, _ := totp.Code(secret, totp.Step(time.Now()))
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		code         string
+		wantErr      bool
+		expectedErr  error
+	}{
+		{
+			name: "Success - enabled with recovery codes and sessions revoked",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetUserByID", mock.Anything, account.ID).Return(account, nil).Once()
+				mockTransaction.On("UpdateUser", mock.Anything, account, mock.MatchedBy(func(data map[string]any) bool {
+					_, ok := data["totp_enabled_at"]
+					return ok
+				})).Return(nil).Once()
+				mockTransaction.On("DeleteRecoveryCodes", mock.Anything, account.ID).Return(nil).Once()
+				mockTransaction.On("StoreRecoveryCodes", mock.Anything, mock.MatchedBy(func(codes []domians.RecoveryCode) bool {
+					return len(codes) == constant.RecoveryCodeCount && len(codes[0].CodeHash) == 64
+				})).Return(nil).Once()
+				mockTransaction.On("RevokeSessionsByUserID", mock.Anything, account.ID, mock.Anything).Return(nil).Once()
+				mockDenylist.On("RevokeUser", mock.Anything, account.ID, mock.Anything).Return(nil).Once()
+				mockTransaction.On("End", nil).Return(nil).Once()
+			},
+			code: code,
+		},
+		{
+			name: "Error - wrong code",
+			mockBehavior: func() {
+				mockTransaction.On("Begin").Return(mockTransaction, nil).Once()
+				mockTransaction.On("GetUserByID", mock.Anything, account.ID).Return(account, nil).Once()
+				mockTransaction.On("End", constant.ErrTOTPCode).Return(nil).Once()
+			},
+			code:        "000000x",
+			wantErr:     true,
+			expectedErr: constant.ErrTOTPCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			uc := user.UsaCase{
+				DbTransaction: mockTransaction,
+				Denylist:      mockDenylist,
+			}
+			codes, err := uc.ConfirmTOTP(context.Background(), account.ID, tt.code)
+
+			if tt.wantErr {
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, codes, constant.RecoveryCodeCount)
+				assert.Regexp(t, "^[a-z2-7]{5}-[a-z2-7]{5}$", codes[0])
+			}
+
+			mockTransaction.AssertExpectations(t)
+			mockDenylist.AssertExpectations(t)
+		})
+	}
+}
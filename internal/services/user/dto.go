@@ -1,6 +1,7 @@
 package user
 
 import (
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
 	"github.com/bowoBp/LoanFlow/internal/dto"
 	"time"
 )
@@ -8,11 +9,12 @@ import (
 type (
 	RegisterUser struct {
 		ID        string    `json:"id"`
-		UserName  string    `validate:"required" json:"userName"`
-		Password  string    `validate:"required" json:"password"`
-		Email     string    `validate:"required" json:"email"`
-		Phone     string    `validate:"required" json:"phone"`
-		Role      string    `validate:"required" json:"role"`
+		UserName  string    `validate:"required,max=100" json:"userName"`
+		Password  string    `validate:"required,min=8,max=72" json:"password"`
+		Email     string    `validate:"required,email" json:"email"`
+		Phone     string    `validate:"required,phone" json:"phone"`
+		Role      string    `validate:"required,public_role" json:"role"`
+		Locale    string    `validate:"omitempty,locale" json:"locale"`
 		CreatedAt time.Time `json:"createdAt"`
 	}
 
@@ -21,10 +23,21 @@ type (
 	}
 
 	SuccessLoginUser struct {
-		Response     dto.ResponseMeta
-		UserName     string `json:"userName"`
-		AccessToken  string `json:"accessToken"`
-		RefreshToken string `json:"refreshToken"`
+		Response       dto.ResponseMeta
+		UserName       string `json:"userName"`
+		AccessToken    string `json:"accessToken,omitempty"`
+		RefreshToken   string `json:"refreshToken,omitempty"`
+		MFARequired    bool   `json:"mfaRequired,omitempty"`
+		ChallengeToken string `json:"challengeToken,omitempty"`
+	}
+
+	// LoginResult has either the tokens of the new session or, when the user
+	// has two-factor authentication, the challenge token for the code.
+	LoginResult struct {
+		User           *domians.User
+		AccessToken    string
+		RefreshToken   string
+		ChallengeToken string
 	}
 
 	Users struct {
@@ -34,11 +47,68 @@ type (
 	}
 
 	LoginParam struct {
-		Email    string `validate:"required" json:"email"`
+		Email    string `validate:"required,email" json:"email"`
 		Password string `validate:"required" json:"password"`
 	}
 
 	RefreshTokenParam struct {
 		RefreshToken string `json:"refreshToken"`
 	}
+
+	ForgotPasswordParam struct {
+		Email string `validate:"required,email" json:"email"`
+	}
+
+	ResetPasswordParam struct {
+		Token    string `validate:"required" json:"token"`
+		Password string `validate:"required,min=8,max=72" json:"password"`
+	}
+
+	VerifyEmailParam struct {
+		Token string `validate:"required" json:"token"`
+	}
+
+	VerifyLoginParam struct {
+		ChallengeToken string `validate:"required" json:"challengeToken"`
+		Code           string `validate:"required" json:"code"`
+	}
+
+	TOTPCodeParam struct {
+		Code string `validate:"required" json:"code"`
+	}
+
+	// TOTPEnrolment is shown once, URI is rendered as a QR code for the
+	// authenticator app.
+	TOTPEnrolment struct {
+		Secret string `json:"secret"`
+		URI    string `json:"uri"`
+	}
+
+	RecoveryCodesResponse struct {
+		RecoveryCodes []string `json:"recoveryCodes"`
+	}
+
+	// Client is the device a session is opened from.
+	Client struct {
+		UserAgent string
+		IPAddress string
+	}
+
+	SessionResponse struct {
+		ID         uint      `json:"id"`
+		UserAgent  string    `json:"userAgent"`
+		IPAddress  string    `json:"ipAddress"`
+		CreatedAt  time.Time `json:"createdAt"`
+		LastUsedAt time.Time `json:"lastUsedAt"`
+		ExpiresAt  time.Time `json:"expiresAt"`
+	}
+
+	LocaleParam struct {
+		Locale string `validate:"required,locale" json:"locale"`
+	}
+
+	PermissionsResponse struct {
+		Role        string   `json:"role"`
+		Permissions []string `json:"permissions"`
+	}
 )
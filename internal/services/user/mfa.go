@@ -0,0 +1,346 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/pkg/totp"
+	"strings"
+	"time"
+)
+
+// VerifyLogin completes the login of a user with two-factor authentication
+// with a TOTP or a recovery code, the session is marked as MFA so the roles
+// that require it are authorized.
+func (uc UsaCase) VerifyLogin(
+	ctx context.Context,
+	challengeToken, code string,
+	client Client,
+) (LoginResult, error) {
+	result, failed, err := uc.verifyLogin(ctx, challengeToken, code, client)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	if failed {
+		return LoginResult{}, constant.ErrTOTPCode
+	}
+	return result, nil
+}
+
+// verifyLogin returns failed without an error for a wrong code, so the
+// attempt counted on the challenge is committed.
+func (uc UsaCase) verifyLogin(
+	ctx context.Context,
+	challengeToken, code string,
+	client Client,
+) (result LoginResult, failed bool, err error) {
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return LoginResult{}, false, err
+	}
+	defer func(tx DefaultUserTransactionInterface, err *error) {
+		// recover panic
+		if r := recover(); r != nil {
+			*err = uc.Reporter.Recover(ctx, r)
+		}
+		// end transaction (rollback or commit)
+		errTrx := tx.End(*err)
+		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
+		}
+	}(dbTrx, &err)
+
+	now := time.Now()
+	challenge, err := dbTrx.GetUserTokenByHash(ctx, constant.TokenMFAChallenge, HashToken(challengeToken))
+	if err != nil {
+		return LoginResult{}, false, err
+	}
+	if challenge == nil || challenge.UsedAt != nil || !challenge.ExpiresAt.After(now) ||
+		challenge.Attempts >= constant.MFAMaxAttempts {
+		return LoginResult{}, false, constant.ErrMFAChallenge
+	}
+	user, err := dbTrx.GetUserByID(ctx, challenge.UserID)
+	if err != nil {
+		return LoginResult{}, false, err
+	}
+	if user == nil || user.TOTPEnabledAt == nil {
+		return LoginResult{}, false, constant.ErrMFAChallenge
+	}
+
+	ok, err := uc.checkSecondFactor(ctx, dbTrx, user, code, now)
+	if err != nil {
+		return LoginResult{}, false, err
+	}
+	if !ok {
+		err = dbTrx.UpdateUserToken(ctx, challenge, map[string]any{
+			"attempts": challenge.Attempts + 1,
+		})
+		return LoginResult{}, true, err
+	}
+	err = dbTrx.UseUserTokens(ctx, user.ID, constant.TokenMFAChallenge, now)
+	if err != nil {
+		return LoginResult{}, false, err
+	}
+	result, err = uc.startSession(ctx, dbTrx, user, client, true, now)
+	return result, false, err
+}
+
+// EnrollTOTP gives the user a new TOTP secret, two-factor authentication is
+// enabled once a code of it is confirmed with ConfirmTOTP.
+func (uc UsaCase) EnrollTOTP(
+	ctx context.Context,
+	id uint,
+) (TOTPEnrolment, error) {
+	user, err := uc.UserRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return TOTPEnrolment{}, err
+	}
+	if user == nil {
+		return TOTPEnrolment{}, constant.ErrUserNotFound
+	}
+	if user.TOTPEnabledAt != nil {
+		return TOTPEnrolment{}, constant.ErrTOTPEnabled
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return TOTPEnrolment{}, err
+	}
+	err = uc.UserRepo.UpdateUser(ctx, user, map[string]any{
+		"totp_secret":    secret,
+		"totp_last_step": 0,
+		"updated_at":     time.Now(),
+	})
+	if err != nil {
+		return TOTPEnrolment{}, err
+	}
+	return TOTPEnrolment{
+		Secret: secret,
+		URI:    totp.URI(constant.TOTPIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication with the first code of the
+// enrolled secret and returns the recovery codes, they are only shown once.
+// Every session and access token is revoked so the next login is made with
+// the code.
+func (uc UsaCase) ConfirmTOTP(
+	ctx context.Context,
+	id uint,
+	code string,
+) (codes []string, err error) {
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func(tx DefaultUserTransactionInterface, err *error) {
+		// recover panic
+		if r := recover(); r != nil {
+			*err = uc.Reporter.Recover(ctx, r)
+		}
+		// end transaction (rollback or commit)
+		errTrx := tx.End(*err)
+		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
+		}
+	}(dbTrx, &err)
+
+	user, err := dbTrx.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, constant.ErrUserNotFound
+	}
+	if user.TOTPEnabledAt != nil {
+		return nil, constant.ErrTOTPEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, constant.ErrTOTPNotEnrolled
+	}
+	now := time.Now()
+	step, ok, err := totp.Validate(user.TOTPSecret, code, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, constant.ErrTOTPCode
+	}
+	err = dbTrx.UpdateUser(ctx, user, map[string]any{
+		"totp_enabled_at": now,
+		"totp_last_step":  step,
+		"updated_at":      now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	codes, err = uc.replaceRecoveryCodes(ctx, dbTrx, user.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	err = dbTrx.RevokeSessionsByUserID(ctx, user.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.Denylist.RevokeUser(ctx, user.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// DisableTOTP turns two-factor authentication off, a TOTP or recovery code
+// is needed so a stolen access token cannot do it.
+func (uc UsaCase) DisableTOTP(
+	ctx context.Context,
+	id uint,
+	code string,
+) (err error) {
+	dbTrx, err := uc.DbTransaction.Begin()
+	if err != nil {
+		return err
+	}
+	defer func(tx DefaultUserTransactionInterface, err *error) {
+		// recover panic
+		if r := recover(); r != nil {
+			*err = uc.Reporter.Recover(ctx, r)
+		}
+		// end transaction (rollback or commit)
+		errTrx := tx.End(*err)
+		if errTrx != nil {
+			uc.Reporter.Report(ctx, fmt.Errorf("end transaction: %w", errTrx))
+			if *err == nil {
+				*err = errTrx
+			}
+		}
+	}(dbTrx, &err)
+
+	user, err := dbTrx.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return constant.ErrUserNotFound
+	}
+	if user.TOTPEnabledAt == nil {
+		return constant.ErrTOTPNotEnabled
+	}
+	now := time.Now()
+	ok, err := uc.checkSecondFactor(ctx, dbTrx, user, code, now)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return constant.ErrTOTPCode
+	}
+	err = dbTrx.UpdateUser(ctx, user, map[string]any{
+		"totp_secret":     nil,
+		"totp_enabled_at": nil,
+		"totp_last_step":  0,
+		"updated_at":      now,
+	})
+	if err != nil {
+		return err
+	}
+	return dbTrx.DeleteRecoveryCodes(ctx, user.ID)
+}
+
+// issueChallenge stores the token that links the second login step to the
+// checked password.
+func (uc UsaCase) issueChallenge(
+	ctx context.Context,
+	tx DefaultUserTransactionInterface,
+	user *domians.User,
+	now time.Time,
+) (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	err = tx.StoreUserToken(ctx, &domians.UserToken{
+		UserID:    user.ID,
+		Purpose:   constant.TokenMFAChallenge,
+		TokenHash: HashToken(token),
+		ExpiresAt: now.Add(constant.MFAChallengeTTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// checkSecondFactor accepts a TOTP code that was not used before or an
+// unused recovery code, which is used up.
+func (uc UsaCase) checkSecondFactor(
+	ctx context.Context,
+	tx DefaultUserTransactionInterface,
+	user *domians.User,
+	code string,
+	now time.Time,
+) (bool, error) {
+	step, ok, err := totp.Validate(user.TOTPSecret, code, now)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		return tx.UpdateTOTPStep(ctx, user.ID, step)
+	}
+	return tx.UseRecoveryCode(ctx, user.ID, hashRecoveryCode(code), now)
+}
+
+// replaceRecoveryCodes drops the recovery codes of the user and stores new
+// ones, only their hash is kept.
+func (uc UsaCase) replaceRecoveryCodes(
+	ctx context.Context,
+	tx DefaultUserTransactionInterface,
+	userID uint,
+	now time.Time,
+) ([]string, error) {
+	err := tx.DeleteRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]string, constant.RecoveryCodeCount)
+	records := make([]domians.RecoveryCode, constant.RecoveryCodeCount)
+	for i := range codes {
+		codes[i], err = newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		records[i] = domians.RecoveryCode{
+			UserID:    userID,
+			CodeHash:  hashRecoveryCode(codes[i]),
+			CreatedAt: now,
+		}
+	}
+	err = tx.StoreRecoveryCodes(ctx, records)
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// newRecoveryCode is 50 random bits written as xxxxx-xxxxx.
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+	return code[:5] + "-" + code[5:10], nil
+}
+
+// hashRecoveryCode ignores the case and the dash, codes are often typed.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	return HashToken(code)
+}
@@ -2,8 +2,13 @@ package user
 
 import (
 	"github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	"github.com/bowoBp/LoanFlow/internal/notification"
+	"github.com/bowoBp/LoanFlow/internal/permission"
+	"github.com/bowoBp/LoanFlow/internal/revocation"
 	"github.com/bowoBp/LoanFlow/pkg/environment"
 	"github.com/bowoBp/LoanFlow/pkg/middleware"
+	"github.com/bowoBp/LoanFlow/pkg/reporter"
 	"github.com/bowoBp/LoanFlow/utils/helper"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -22,15 +27,26 @@ func NewRoute(
 	bcrypt helper.BcryptInterface,
 	env environment.Environment,
 	auth middleware.AuthInterface,
+	notifier notification.NotifierInterface,
+	denylist revocation.Denylist,
+	permissions permission.Resolver,
+	reporter reporter.Reporter,
 ) *Router {
 	return &Router{
 		rq: &RequestHandler{
 			ctrl: &Controller{
 				Uc: UsaCase{
-					userRepo: Repository.NewUserRepo(db),
-					jwt:      jwt,
-					bcrypt:   bcrypt,
-					env:      env,
+					UserRepo:         Repository.NewUserRepo(db),
+					SessionRepo:      Repository.NewSessionRepo(db),
+					LoginAttemptRepo: Repository.NewLoginAttemptRepo(db),
+					DbTransaction:    NewUserTransaction(db),
+					Jwt:              jwt,
+					Bcrypt:           bcrypt,
+					Env:              env,
+					Notifier:         notifier,
+					Denylist:         denylist,
+					Permissions:      permissions,
+					Reporter:         reporter,
 				},
 			},
 		},
@@ -42,6 +58,7 @@ func NewRoute(
 func (r Router) Route(router *gin.RouterGroup) {
 	employee := router.Group("/user")
 	auth := router.Group("/auth")
+	me := router.Group("/me")
 	employee.POST(
 		"/register",
 		r.rq.Register,
@@ -49,6 +66,7 @@ func (r Router) Route(router *gin.RouterGroup) {
 	employee.GET(
 		"/all",
 		r.auth.Authentication(),
+		r.auth.AuthorizePermission(constant.PermUserManage),
 		r.rq.GetAll,
 	)
 
@@ -58,14 +76,29 @@ func (r Router) Route(router *gin.RouterGroup) {
 		r.rq.GetCurrent,
 	)
 
+	me.GET(
+		"/permissions",
+		r.auth.Authentication(),
+		r.rq.GetPermissions,
+	)
+	me.PUT(
+		"/locale",
+		r.auth.Authentication(),
+		r.rq.UpdateLocale,
+	)
+
 	auth.POST(
 		"/login",
 		r.rq.Login,
 	)
 
+	auth.POST(
+		"/2fa/verify",
+		r.rq.VerifyLogin,
+	)
+
 	auth.POST(
 		"/refresh-token",
-		r.auth.Authentication(),
 		r.rq.RefreshToken,
 	)
 
@@ -75,4 +108,55 @@ func (r Router) Route(router *gin.RouterGroup) {
 		r.rq.Logout,
 	)
 
+	auth.GET(
+		"/sessions",
+		r.auth.Authentication(),
+		r.rq.GetSessions,
+	)
+
+	auth.DELETE(
+		"/sessions/:sessionId",
+		r.auth.Authentication(),
+		r.rq.RevokeSession,
+	)
+
+	auth.POST(
+		"/forgot-password",
+		r.rq.ForgotPassword,
+	)
+
+	auth.POST(
+		"/reset-password",
+		r.rq.ResetPassword,
+	)
+
+	auth.POST(
+		"/verify-email",
+		r.rq.VerifyEmail,
+	)
+
+	auth.POST(
+		"/verify-email/resend",
+		r.auth.Authentication(),
+		r.rq.ResendVerification,
+	)
+
+	auth.POST(
+		"/2fa/enroll",
+		r.auth.Authentication(),
+		r.rq.EnrollTOTP,
+	)
+
+	auth.POST(
+		"/2fa/confirm",
+		r.auth.Authentication(),
+		r.rq.ConfirmTOTP,
+	)
+
+	auth.DELETE(
+		"/2fa",
+		r.auth.Authentication(),
+		r.rq.DisableTOTP,
+	)
+
 }
@@ -0,0 +1,125 @@
+package user
+
+import (
+	"context"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/utils/helper"
+	"time"
+)
+
+// dummyPasswordHash is compared when the email is unknown so the login takes
+// as long as one with a wrong password. It is the bcrypt hash (cost 12) of a
+// discarded random password.
+const dummyPasswordHash = "$2a$12$GzWmfX2NwnqHksNTBnQOT.1B.Kpo6zLlXlUmTuIkrnRl9zJT.m90."
+
+type loginScope struct {
+	scope        string
+	key          string
+	freeAttempts int
+}
+
+// loginScopes are the account, by its lowercase email so the case cannot be
+// used to get more attempts, and the IP address of the client.
+func loginScopes(email string, client Client) []loginScope {
+	scopes := []loginScope{{
+		scope:        constant.ThrottleAccount,
+		key:          helper.NormalizeEmail(email),
+		freeAttempts: constant.LoginAccountFreeAttempts,
+	}}
+	if client.IPAddress != "" {
+		scopes = append(scopes, loginScope{
+			scope:        constant.ThrottleIP,
+			key:          client.IPAddress,
+			freeAttempts: constant.LoginIPFreeAttempts,
+		})
+	}
+	return scopes
+}
+
+// checkLoginThrottle refuses the login while the account or the IP address
+// is locked. An unknown email is throttled like an account so a lock does
+// not tell the email is registered.
+func (uc UsaCase) checkLoginThrottle(
+	ctx context.Context,
+	email string,
+	client Client,
+	now time.Time,
+) error {
+	for _, scope := range loginScopes(email, client) {
+		throttle, err := uc.LoginAttemptRepo.GetLoginThrottle(ctx, scope.scope, scope.key)
+		if err != nil {
+			return err
+		}
+		if throttle != nil && throttle.LockedUntil != nil && throttle.LockedUntil.After(now) {
+			return constant.ErrLoginLocked
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure counts the failed login for the account and the IP
+// address, locks them once the free attempts are used and audits both.
+func (uc UsaCase) recordLoginFailure(
+	ctx context.Context,
+	user *domians.User,
+	email string,
+	client Client,
+	now time.Time,
+) error {
+	event := func(name string) error {
+		authEvent := &domians.AuthEvent{
+			Event:     name,
+			Email:     email,
+			IPAddress: client.IPAddress,
+			CreatedAt: now,
+		}
+		if user != nil {
+			authEvent.UserID = &user.ID
+		}
+		return uc.LoginAttemptRepo.CreateAuthEvent(ctx, authEvent)
+	}
+
+	err := event(constant.AuthEventLoginFailed)
+	if err != nil {
+		return err
+	}
+	for _, scope := range loginScopes(email, client) {
+		throttle, err := uc.LoginAttemptRepo.IncrementLoginFailures(
+			ctx,
+			scope.scope,
+			scope.key,
+			now,
+			now.Add(-constant.LoginFailureWindow),
+		)
+		if err != nil {
+			return err
+		}
+		lock := loginLockDuration(throttle.Failures, scope.freeAttempts)
+		if lock == 0 {
+			continue
+		}
+		err = uc.LoginAttemptRepo.LockLogin(ctx, scope.scope, scope.key, now.Add(lock))
+		if err != nil {
+			return err
+		}
+		err = event(constant.AuthEventLoginLocked)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loginLockDuration is LoginLockBase once the free attempts are used up and
+// doubles with every further failure, up to LoginLockMax.
+func loginLockDuration(failures, freeAttempts int) time.Duration {
+	if failures < freeAttempts {
+		return 0
+	}
+	lock := constant.LoginLockBase
+	for i := freeAttempts; i < failures && lock < constant.LoginLockMax; i++ {
+		lock *= 2
+	}
+	return min(lock, constant.LoginLockMax)
+}
@@ -0,0 +1,127 @@
+package user
+
+import (
+	"context"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/i18n"
+	"github.com/bowoBp/LoanFlow/internal/notification"
+	"net/url"
+	"time"
+)
+
+// userTokenPurpose tells how the tokens of a purpose are hashed, which page
+// their link opens and how long they can be used.
+type userTokenPurpose struct {
+	secretKey string // env key of the base32 HMAC secret
+	linkKey   string // env key of the page url, the token is added as ?token=
+	template  string
+	ttl       time.Duration
+}
+
+var userTokenPurposes = map[string]userTokenPurpose{
+	constant.TokenPasswordReset: {
+		secretKey: "DEFAULT_SECRET_FORGET_PASSWORD",
+		linkKey:   "PASSWORD_RESET_URL",
+		template:  constant.TemplatePasswordReset,
+		ttl:       constant.PasswordResetTTL,
+	},
+	constant.TokenEmailVerification: {
+		secretKey: "DEFAULT_SECRET_VERIFY_EMAIL",
+		linkKey:   "EMAIL_VERIFICATION_URL",
+		template:  constant.TemplateEmailVerification,
+		ttl:       constant.EmailVerificationTTL,
+	},
+}
+
+// sendUserToken replaces the unused tokens of purpose with a new one, its
+// link is emailed to the user once the transaction is committed.
+func (uc UsaCase) sendUserToken(
+	ctx context.Context,
+	tx DefaultUserTransactionInterface,
+	user *domians.User,
+	purpose string,
+	now time.Time,
+) error {
+	err := tx.UseUserTokens(ctx, user.ID, purpose, now)
+	if err != nil {
+		return err
+	}
+	token, err := randomToken()
+	if err != nil {
+		return err
+	}
+	hash, err := uc.hashUserToken(purpose, token)
+	if err != nil {
+		return err
+	}
+	link, err := url.Parse(uc.Env.Get(userTokenPurposes[purpose].linkKey))
+	if err != nil {
+		return err
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	expiresAt := now.Add(userTokenPurposes[purpose].ttl)
+	err = tx.StoreUserToken(ctx, &domians.UserToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: hash,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+	// without a preference the email follows the language of the request
+	locale := user.Locale
+	if locale == "" {
+		locale = i18n.FromContext(ctx)
+	}
+	return uc.Notifier.Enqueue(ctx, tx, userTokenPurposes[purpose].template, []notification.Recipient{
+		{
+			UserID:  user.ID,
+			Address: user.Email,
+			Locale:  locale,
+			Data: notification.AccountLink{
+				Name:      user.Name,
+				Link:      link.String(),
+				ExpiresAt: expiresAt,
+			},
+		},
+	})
+}
+
+// consumeUserToken uses up a token of purpose together with the other
+// unused tokens of the user, ErrUserToken is returned when the token is
+// unknown, used or expired.
+func (uc UsaCase) consumeUserToken(
+	ctx context.Context,
+	tx DefaultUserTransactionInterface,
+	purpose, token string,
+	now time.Time,
+) (*domians.UserToken, error) {
+	hash, err := uc.hashUserToken(purpose, token)
+	if err != nil {
+		return nil, err
+	}
+	current, err := tx.GetUserTokenByHash(ctx, purpose, hash)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil || current.UsedAt != nil || !current.ExpiresAt.After(now) {
+		return nil, constant.ErrUserToken
+	}
+	err = tx.UseUserTokens(ctx, current.UserID, purpose, now)
+	if err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// hashUserToken keys the hash with the secret of purpose, a leaked table
+// cannot be used to forge a link without it.
+func (uc UsaCase) hashUserToken(purpose, token string) (string, error) {
+	return uc.Bcrypt.GenerateHashValue(uc.Env.Get(userTokenPurposes[purpose].secretKey), token, 0)
+}
@@ -0,0 +1,121 @@
+package statemachine
+
+import (
+	"context"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+)
+
+// NewLoanMachine returns the loan lifecycle:
+// proposed -> approved -> invested -> disbursed -> repaying -> paid_off
+// a proposed loan can be rejected by staff, and the borrower can cancel
+// the request until it is fully invested. A loan is repaid by its borrower
+// or by staff. The actor needs the permission of the transition,
+// permissions tells which roles have it.
+func NewLoanMachine(permissions Permissions) *Machine {
+	return New(
+		permissions,
+		Transition{
+			Event:      constant.EventPropose,
+			From:       []string{""},
+			To:         constant.Proposed,
+			Permission: constant.PermLoanCreate,
+			Remarks:    "proposed loan",
+		},
+		Transition{
+			Event:      constant.EventApprove,
+			From:       []string{constant.Proposed},
+			To:         constant.Approved,
+			Permission: constant.PermLoanApprove,
+			Remarks:    "Approved loan",
+			Err:        constant.ErrStateApprove,
+		},
+		Transition{
+			// partial investment, the loan stays open for other investors
+			Event:      constant.EventFund,
+			From:       []string{constant.Approved},
+			To:         constant.Approved,
+			Permission: constant.PermInvestmentCreate,
+			Remarks:    "user invested",
+			Err:        constant.ErrStateInvest,
+		},
+		Transition{
+			Event:      constant.EventInvest,
+			From:       []string{constant.Approved},
+			To:         constant.Invested,
+			Permission: constant.PermInvestmentCreate,
+			Remarks:    "user invested",
+			Err:        constant.ErrStateInvest,
+		},
+		Transition{
+			Event:      constant.EventDisburse,
+			From:       []string{constant.Invested},
+			To:         constant.Disbursed,
+			Permission: constant.PermLoanDisburse,
+			Remarks:    "Disbursed loan",
+			Err:        constant.ErrStateDisburse,
+		},
+		Transition{
+			Event:      constant.EventRepay,
+			From:       []string{constant.Disbursed},
+			To:         constant.Repaying,
+			Permission: constant.PermLoanRepay,
+			Guards:     []Guard{IsBorrowerOrStaff},
+			Remarks:    "loan repayment",
+			Err:        constant.ErrStateRepay,
+		},
+		Transition{
+			Event:      constant.EventPayOff,
+			From:       []string{constant.Disbursed, constant.Repaying},
+			To:         constant.PaidOff,
+			Permission: constant.PermLoanRepay,
+			Guards:     []Guard{IsBorrowerOrStaff},
+			Remarks:    "loan paid off",
+			Err:        constant.ErrStateRepay,
+		},
+		Transition{
+			Event:      constant.EventReject,
+			From:       []string{constant.Proposed},
+			To:         constant.Rejected,
+			Permission: constant.PermLoanReject,
+			Remarks:    "Rejected loan",
+			Err:        constant.ErrStateReject,
+		},
+		Transition{
+			Event:      constant.EventCancel,
+			From:       []string{constant.Proposed, constant.Approved},
+			To:         constant.Cancelled,
+			Permission: constant.PermLoanCancel,
+			Guards:     []Guard{IsBorrower},
+			Remarks:    "Cancelled loan",
+			Err:        constant.ErrStateCancel,
+		},
+	)
+}
+
+// IsBorrower only lets the borrower who owns the loan fire the transition.
+func IsBorrower(
+	ctx context.Context,
+	loan *domians.Loan,
+	trigger Trigger,
+) error {
+	if loan.BorrowerID != trigger.Actor.ID {
+		return constant.ErrLoanNotOwned
+	}
+	return nil
+}
+
+// IsBorrowerOrStaff lets staff and admins fire the transition on any loan,
+// every other role only on the loans it borrowed, whatever permissions
+// the role was granted.
+func IsBorrowerOrStaff(
+	ctx context.Context,
+	loan *domians.Loan,
+	trigger Trigger,
+) error {
+	switch trigger.Actor.Role {
+	case constant.RoleAdmin, constant.RoleStaff:
+		return nil
+	}
+	return IsBorrower(ctx, loan, trigger)
+}
@@ -0,0 +1,242 @@
+package statemachine
+
+import (
+	"context"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"maps"
+	"slices"
+	"time"
+)
+
+type (
+	// Actor is the user who fires a transition.
+	Actor struct {
+		ID   uint
+		Role string
+	}
+
+	// Store persists the loan state and its history, usually the running
+	// db transaction of the usecase firing the transition.
+	Store interface {
+		UpdateLoan(
+			ctx context.Context,
+			loan *domians.Loan,
+			updateData map[string]any,
+		) error
+		CreateLoanState(
+			ctx context.Context,
+			state *domians.LoanStateHistory,
+		) error
+	}
+
+	// Permissions tells whether the role of the actor has the permission a
+	// transition requires.
+	Permissions interface {
+		Allowed(ctx context.Context, role, permission string) (bool, error)
+	}
+
+	// Guard rejects a transition by returning an error.
+	Guard func(
+		ctx context.Context,
+		loan *domians.Loan,
+		trigger Trigger,
+	) error
+
+	// Hook runs after the state and its history row have been written,
+	// inside the same transaction.
+	Hook func(
+		ctx context.Context,
+		tx Store,
+		loan *domians.Loan,
+		transition Transition,
+		trigger Trigger,
+	) error
+
+	Transition struct {
+		Event string
+		From  []string
+		To    string
+		// Permission is required from the actor to fire the transition.
+		Permission string
+		Guards     []Guard
+		Hooks      []Hook
+		Remarks    string
+		// Err is returned instead of ErrTransitionNotAllowed when the loan
+		// is not in one of From states.
+		Err error
+	}
+
+	// Trigger describes a single request to fire a transition.
+	Trigger struct {
+		Event      string
+		Actor      Actor
+		ReasonCode string
+		Remarks    string
+		// Changes are extra loan columns updated together with the state.
+		Changes map[string]any
+	}
+
+	Machine struct {
+		transitions map[string]*Transition
+		permissions Permissions
+	}
+)
+
+func New(permissions Permissions, transitions ...Transition) *Machine {
+	m := &Machine{
+		transitions: make(map[string]*Transition, len(transitions)),
+		permissions: permissions,
+	}
+	for i := range transitions {
+		m.transitions[transitions[i].Event] = &transitions[i]
+	}
+	return m
+}
+
+// Use registers hooks that run after the given event.
+func (m *Machine) Use(event string, hooks ...Hook) {
+	if t, ok := m.transitions[event]; ok {
+		t.Hooks = append(t.Hooks, hooks...)
+	}
+}
+
+// Permission returns the permission required to fire the given event.
+func (m *Machine) Permission(event string) string {
+	if t, ok := m.transitions[event]; ok {
+		return t.Permission
+	}
+	return ""
+}
+
+// Can checks whether the trigger may fire on the loan without writing anything.
+func (m *Machine) Can(
+	ctx context.Context,
+	loan *domians.Loan,
+	trigger Trigger,
+) (*Transition, error) {
+	t, ok := m.transitions[trigger.Event]
+	if !ok {
+		return nil, constant.ErrUnknownTransition
+	}
+	if !slices.Contains(t.From, loan.State) {
+		if t.Err != nil {
+			return nil, t.Err
+		}
+		return nil, constant.ErrTransitionNotAllowed
+	}
+	if t.Permission != "" {
+		if m.permissions == nil {
+			return nil, constant.ErrTransitionForbidden
+		}
+		allowed, err := m.permissions.Allowed(ctx, trigger.Actor.Role, t.Permission)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, constant.ErrTransitionForbidden
+		}
+	}
+	for _, guard := range t.Guards {
+		if err := guard(ctx, loan, trigger); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Fire moves the loan to the transition target state, writes the history
+// row and runs the transition hooks.
+func (m *Machine) Fire(
+	ctx context.Context,
+	tx Store,
+	loan *domians.Loan,
+	trigger Trigger,
+) error {
+	t, err := m.Can(ctx, loan, trigger)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	updateData := make(map[string]any, len(trigger.Changes)+3)
+	maps.Copy(updateData, trigger.Changes)
+	updateData["state"] = t.To
+	updateData["version"] = loan.Version + 1
+	updateData["updated_at"] = now
+
+	// the update fails with ErrLoanConflict when the loan version
+	// changed since it was read
+	err = tx.UpdateLoan(
+		ctx,
+		&domians.Loan{
+			ID:      loan.ID,
+			Version: loan.Version,
+		},
+		updateData,
+	)
+	if err != nil {
+		return err
+	}
+
+	previous := loan.State
+	err = m.record(ctx, tx, loan.ID, previous, *t, trigger, now)
+	if err != nil {
+		return err
+	}
+	loan.State = t.To
+	loan.Version++
+
+	for _, hook := range t.Hooks {
+		if err = hook(ctx, tx, loan, *t, trigger); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start writes the history row of a newly created loan. The trigger event
+// must be a transition from the empty state, the loan row itself is
+// expected to be stored already with the target state.
+func (m *Machine) Start(
+	ctx context.Context,
+	tx Store,
+	loan *domians.Loan,
+	trigger Trigger,
+) error {
+	t, err := m.Can(ctx, &domians.Loan{ID: loan.ID, BorrowerID: loan.BorrowerID}, trigger)
+	if err != nil {
+		return err
+	}
+	if loan.State != t.To {
+		return constant.ErrTransitionNotAllowed
+	}
+	return m.record(ctx, tx, loan.ID, "", *t, trigger, time.Now())
+}
+
+func (m *Machine) record(
+	ctx context.Context,
+	tx Store,
+	loanID uint,
+	previous string,
+	t Transition,
+	trigger Trigger,
+	at time.Time,
+) error {
+	remarks := trigger.Remarks
+	if remarks == "" {
+		remarks = t.Remarks
+	}
+	return tx.CreateLoanState(
+		ctx,
+		&domians.LoanStateHistory{
+			LoanID:        loanID,
+			PreviousState: previous,
+			NewState:      t.To,
+			ReasonCode:    trigger.ReasonCode,
+			ActionBy:      trigger.Actor.ID,
+			ActionAt:      at,
+			Remarks:       remarks,
+		},
+	)
+}
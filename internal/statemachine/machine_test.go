@@ -0,0 +1,178 @@
+package statemachine_test
+
+import (
+	"context"
+	"errors"
+	"github.com/bowoBp/LoanFlow/internal/adapter/mocks"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/permission"
+	"github.com/bowoBp/LoanFlow/internal/statemachine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestMachine_Fire(t *testing.T) {
+	staff := statemachine.Actor{ID: 9, Role: constant.RoleStaff}
+	errGuard := errors.New("guard rejected")
+
+	tests := []struct {
+		name         string
+		loan         *domians.Loan
+		trigger      statemachine.Trigger
+		grants       map[string][]string
+		guard        statemachine.Guard
+		mockBehavior func(tx *mocks.DefaultLoanTransactionInterface)
+		wantState    string
+		wantHook     bool
+		expectedErr  error
+	}{
+		{
+			name:    "Success - writes state, history and runs hooks",
+			loan:    &domians.Loan{ID: 1, State: constant.Proposed},
+			trigger: statemachine.Trigger{Event: constant.EventApprove, Actor: staff},
+			mockBehavior: func(tx *mocks.DefaultLoanTransactionInterface) {
+				tx.On("UpdateLoan", mock.Anything, &domians.Loan{ID: 1}, mock.MatchedBy(func(data map[string]any) bool {
+					return data["state"] == constant.Approved
+				})).Return(nil).Once()
+				tx.On("CreateLoanState", mock.Anything, mock.MatchedBy(func(state *domians.LoanStateHistory) bool {
+					return state.LoanID == 1 &&
+						state.PreviousState == constant.Proposed &&
+						state.NewState == constant.Approved &&
+						state.ActionBy == staff.ID &&
+						state.Remarks == "Approved loan"
+				})).Return(nil).Once()
+			},
+			wantState: constant.Approved,
+			wantHook:  true,
+		},
+		{
+			name:         "Error - loan in wrong state",
+			loan:         &domians.Loan{ID: 1, State: constant.Invested},
+			trigger:      statemachine.Trigger{Event: constant.EventApprove, Actor: staff},
+			mockBehavior: func(tx *mocks.DefaultLoanTransactionInterface) {},
+			wantState:    constant.Invested,
+			expectedErr:  constant.ErrStateApprove,
+		},
+		{
+			name: "Error - role not allowed",
+			loan: &domians.Loan{ID: 1, State: constant.Proposed},
+			trigger: statemachine.Trigger{
+				Event: constant.EventApprove,
+				Actor: statemachine.Actor{ID: 2, Role: constant.RoleInvestor},
+			},
+			mockBehavior: func(tx *mocks.DefaultLoanTransactionInterface) {},
+			wantState:    constant.Proposed,
+			expectedErr:  constant.ErrTransitionForbidden,
+		},
+		{
+			name: "Success - permission granted to another role",
+			loan: &domians.Loan{ID: 1, State: constant.Proposed},
+			trigger: statemachine.Trigger{
+				Event: constant.EventApprove,
+				Actor: statemachine.Actor{ID: 2, Role: constant.RoleInvestor},
+			},
+			grants: map[string][]string{constant.RoleInvestor: {constant.PermLoanApprove}},
+			mockBehavior: func(tx *mocks.DefaultLoanTransactionInterface) {
+				tx.On("UpdateLoan", mock.Anything, &domians.Loan{ID: 1}, mock.Anything).Return(nil).Once()
+				tx.On("CreateLoanState", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			wantState: constant.Approved,
+			wantHook:  true,
+		},
+		{
+			name: "Error - guard rejects",
+			loan: &domians.Loan{ID: 1, State: constant.Proposed},
+			guard: func(ctx context.Context, loan *domians.Loan, trigger statemachine.Trigger) error {
+				return errGuard
+			},
+			trigger:      statemachine.Trigger{Event: constant.EventApprove, Actor: staff},
+			mockBehavior: func(tx *mocks.DefaultLoanTransactionInterface) {},
+			wantState:    constant.Proposed,
+			expectedErr:  errGuard,
+		},
+		{
+			name: "Error - borrower repays a loan of another borrower",
+			loan: &domians.Loan{ID: 1, BorrowerID: 4, State: constant.Disbursed},
+			trigger: statemachine.Trigger{
+				Event: constant.EventRepay,
+				Actor: statemachine.Actor{ID: 5, Role: constant.RoleBorrower},
+			},
+			mockBehavior: func(tx *mocks.DefaultLoanTransactionInterface) {},
+			wantState:    constant.Disbursed,
+			expectedErr:  constant.ErrLoanNotOwned,
+		},
+		{
+			name: "Error - repay granted to investor on a loan not borrowed",
+			loan: &domians.Loan{ID: 1, BorrowerID: 4, State: constant.Disbursed},
+			trigger: statemachine.Trigger{
+				Event: constant.EventPayOff,
+				Actor: statemachine.Actor{ID: 5, Role: constant.RoleInvestor},
+			},
+			grants:       map[string][]string{constant.RoleInvestor: {constant.PermLoanRepay}},
+			mockBehavior: func(tx *mocks.DefaultLoanTransactionInterface) {},
+			wantState:    constant.Disbursed,
+			expectedErr:  constant.ErrLoanNotOwned,
+		},
+		{
+			name:    "Success - staff repays on behalf of the borrower",
+			loan:    &domians.Loan{ID: 1, BorrowerID: 4, State: constant.Disbursed},
+			trigger: statemachine.Trigger{Event: constant.EventRepay, Actor: staff},
+			mockBehavior: func(tx *mocks.DefaultLoanTransactionInterface) {
+				tx.On("UpdateLoan", mock.Anything, &domians.Loan{ID: 1}, mock.MatchedBy(func(data map[string]any) bool {
+					return data["state"] == constant.Repaying
+				})).Return(nil).Once()
+				tx.On("CreateLoanState", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			wantState: constant.Repaying,
+		},
+		{
+			name:         "Error - unknown event",
+			loan:         &domians.Loan{ID: 1, State: constant.Proposed},
+			trigger:      statemachine.Trigger{Event: "archive", Actor: staff},
+			mockBehavior: func(tx *mocks.DefaultLoanTransactionInterface) {},
+			wantState:    constant.Proposed,
+			expectedErr:  constant.ErrUnknownTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := new(mocks.DefaultLoanTransactionInterface)
+			tt.mockBehavior(tx)
+
+			grants := permission.Defaults
+			if tt.grants != nil {
+				grants = tt.grants
+			}
+			machine := statemachine.NewLoanMachine(permission.NewStatic(grants))
+			hooked := false
+			machine.Use(constant.EventApprove, func(
+				ctx context.Context,
+				tx statemachine.Store,
+				loan *domians.Loan,
+				transition statemachine.Transition,
+				trigger statemachine.Trigger,
+			) error {
+				hooked = loan.State == transition.To
+				return nil
+			})
+			if tt.guard != nil {
+				machine = statemachine.New(nil, statemachine.Transition{
+					Event:  constant.EventApprove,
+					From:   []string{constant.Proposed},
+					To:     constant.Approved,
+					Guards: []statemachine.Guard{tt.guard},
+				})
+			}
+
+			err := machine.Fire(context.Background(), tx, tt.loan, tt.trigger)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.wantState, tt.loan.State)
+			assert.Equal(t, tt.wantHook, hooked)
+			tx.AssertExpectations(t)
+		})
+	}
+}
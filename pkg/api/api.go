@@ -2,13 +2,17 @@ package api
 
 import (
 	"github.com/gin-gonic/gin"
-	"log"
+	"log/slog"
 )
 
 type (
 	Api struct {
 		server  *gin.Engine
 		routers []Router
+		// metricsServer answers the Prometheus scrapes on metricsAddr, an
+		// internal listener apart from the public API
+		metricsServer *gin.Engine
+		metricsAddr   string
 	}
 
 	Router interface {
@@ -22,10 +26,10 @@ func (a Api) Start() error {
 		router.Route(root)
 	}
 
-	err := a.server.Run(":8000")
-	if err != nil {
-		log.Println(err)
-		return err
-	}
-	return err
+	go func() {
+		if err := a.metricsServer.Run(a.metricsAddr); err != nil {
+			slog.Error("metrics listener stopped", "addr", a.metricsAddr, "error", err)
+		}
+	}()
+	return a.server.Run(":8000")
 }
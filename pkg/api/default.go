@@ -1,35 +1,192 @@
 package api
 
 import (
+	"context"
 	"fmt"
+	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	"github.com/bowoBp/LoanFlow/internal/agreement"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	"github.com/bowoBp/LoanFlow/internal/ledger"
+	"github.com/bowoBp/LoanFlow/internal/notification"
+	"github.com/bowoBp/LoanFlow/internal/permission"
+	"github.com/bowoBp/LoanFlow/internal/revocation"
+	"github.com/bowoBp/LoanFlow/internal/services/admin"
+	ledgerService "github.com/bowoBp/LoanFlow/internal/services/ledger"
 	"github.com/bowoBp/LoanFlow/internal/services/loan"
+	"github.com/bowoBp/LoanFlow/internal/services/repayment"
 	"github.com/bowoBp/LoanFlow/internal/services/user"
+	"github.com/bowoBp/LoanFlow/internal/statemachine"
 	"github.com/bowoBp/LoanFlow/pkg/db"
+	"github.com/bowoBp/LoanFlow/pkg/document"
 	"github.com/bowoBp/LoanFlow/pkg/environment"
+	"github.com/bowoBp/LoanFlow/pkg/logger"
+	"github.com/bowoBp/LoanFlow/pkg/mailer"
+	"github.com/bowoBp/LoanFlow/pkg/metrics"
 	"github.com/bowoBp/LoanFlow/pkg/middleware"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"github.com/bowoBp/LoanFlow/pkg/reporter"
+	"github.com/bowoBp/LoanFlow/pkg/validation"
 	"github.com/bowoBp/LoanFlow/utils/helper"
 	"github.com/gin-gonic/gin"
-	"log"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
 )
 
 func Default() *Api {
-	server := gin.Default()
+	// the database connection loads .env, it comes before the logger reads
+	// its settings
 	sqlConn, err := db.Default()
-	jwt := helper.NewJwt()
-	bcrypt := helper.NewBcrypt()
 	env := environment.NewEnvironment()
-	auth := middleware.NewAuth()
+	slog.SetDefault(logger.New(os.Stdout, env.Get("LOG_LEVEL"), env.Get("LOG_FORMAT")))
 	if err != nil {
-		log.Println(err)
 		panic(fmt.Sprintf("panic at db connection: %s", err.Error()))
 	}
-	fmt.Println("database connected: 3036")
+	slog.Info("database connected")
+	errReporter := reporter.NewLogReporter(slog.Default())
+	registry := prometheus.NewRegistry()
+	loanCollector := metrics.NewLoanCollector(Repository.NewLoanRepo(sqlConn), constant.MetricsQueryTimeout)
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		loanCollector,
+	)
+	go loanCollector.Run(
+		context.Background(),
+		time.Duration(env.GetUint("METRICS_REFRESH_SECONDS", 60))*time.Second,
+	)
+	appMetrics := metrics.New(registry)
+	if err = sqlConn.Use(appMetrics.GormPlugin()); err != nil {
+		panic(fmt.Sprintf("panic at db metrics: %s", err.Error()))
+	}
+	bcrypt := helper.NewBcrypt()
+	binding.Validator = validation.New()
+	server := gin.New()
+	if err = setTrustedProxies(server, env); err != nil {
+		panic(fmt.Sprintf("panic at trusted proxies: %s", err.Error()))
+	}
+	server.Use(middleware.NewRequestID().Handle())
+	server.Use(appMetrics.HTTP())
+	server.Use(middleware.NewErrorRenderer().Handle())
+	server.Use(gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		abortWithPanic(c, errReporter, recovered)
+	}))
+	server.Use(middleware.NewLocale().Handle())
+	keys, err := helper.LoadKeyRing(env.Get("JWT_KEYS_DIR"), env.Get("JWT_SIGNING_KID"))
+	if err != nil {
+		panic(fmt.Sprintf("panic at jwt key ring: %s", err.Error()))
+	}
+	jwt := helper.NewJwt(keys, env.Get("JWT_ISSUER"), env.Get("JWT_AUDIENCE"))
+	denylist := newDenylist(env, Repository.NewRevocationRepo(sqlConn))
+	go revocation.Run(context.Background(), denylist, time.Hour)
+	permissions := permission.NewDatabaseResolver(Repository.NewPermissionRepo(sqlConn), constant.PermissionCacheTTL)
+	loanMachine := statemachine.NewLoanMachine(permissions)
+	auth := middleware.NewAuth(jwt, denylist, Repository.NewAPIKeyRepo(sqlConn), permissions)
+	server.GET("/.well-known/jwks.json", jwksHandler(keys))
+	letterTemplate, err := agreement.ParseTemplate(env.Get("AGREEMENT_TEMPLATE"))
+	if err != nil {
+		panic(fmt.Sprintf("panic at agreement template: %s", err.Error()))
+	}
+	documents := document.NewLocalStore(env.Get("DOCUMENT_DIR"), env.Get("DOCUMENT_BASE_URL"))
+	notificationTemplates, err := notification.ParseTemplates(env.Get("NOTIFICATION_TEMPLATE_DIR"))
+	if err != nil {
+		panic(fmt.Sprintf("panic at notification templates: %s", err.Error()))
+	}
+	notifier := notification.NewNotifier(notificationTemplates)
+	dispatcher := notification.NewDispatcher(Repository.NewNotificationRepo(sqlConn), newMailSender(env))
+	go dispatcher.Run(
+		context.Background(),
+		time.Duration(env.GetUint("NOTIFICATION_INTERVAL_SECONDS", 30))*time.Second,
+	)
+	idempotency := middleware.NewIdempotency(Repository.NewIdempotencyRepo(sqlConn))
+	go idempotency.Run(context.Background(), time.Hour)
+	var repaymentFee money.Rate
+	if fee := env.Get("LEDGER_REPAYMENT_FEE_PERCENT"); fee != "" {
+		repaymentFee, err = money.ParseRate(fee)
+		if err != nil {
+			panic(fmt.Sprintf("panic at ledger repayment fee: %s", err.Error()))
+		}
+	}
+	book := ledger.NewBook(repaymentFee)
 	var routers = []Router{
-		user.NewRoute(sqlConn, jwt, bcrypt, env, auth),
-		loan.NewRoute(sqlConn, auth),
+		user.NewRoute(sqlConn, jwt, bcrypt, env, auth, notifier, denylist, permissions, errReporter),
+		admin.NewRoute(sqlConn, auth, bcrypt, idempotency, denylist, permissions, errReporter),
+		loan.NewRoute(
+			sqlConn,
+			auth,
+			loanMachine,
+			agreement.NewGenerator(letterTemplate, documents),
+			notifier,
+			book,
+			idempotency,
+			middleware.NewEmailVerification(Repository.NewUserRepo(sqlConn)),
+			permissions,
+			errReporter,
+			appMetrics,
+		),
+		repayment.NewRoute(sqlConn, auth, loanMachine, book, idempotency, permissions, errReporter),
+		ledgerService.NewRoute(sqlConn, auth, book, idempotency, permissions, errReporter),
+	}
+	metricsServer := gin.New()
+	metricsServer.GET("/metrics", metrics.Handler(registry))
+	metricsAddr := env.Get("METRICS_ADDR")
+	if metricsAddr == "" {
+		metricsAddr = "127.0.0.1:9100"
 	}
 	return &Api{
-		server:  server,
-		routers: routers,
+		server:        server,
+		routers:       routers,
+		metricsServer: metricsServer,
+		metricsAddr:   metricsAddr,
+	}
+}
+
+// abortWithPanic reports a panic of a handler and leaves the answer to the
+// ErrorRenderer, which hides it behind internal_error.
+func abortWithPanic(c *gin.Context, errReporter reporter.Reporter, recovered any) {
+	_ = c.Error(errReporter.Recover(c, recovered))
+	c.Abort()
+}
+
+// newMailSender sends through SMTP when MAIL_DRIVER is "smtp", otherwise
+// emails are written to the local outbox directory.
+func newMailSender(env environment.Environment) mailer.Sender {
+	if env.Get("MAIL_DRIVER") == "smtp" {
+		return mailer.NewSMTPSender(
+			env.Get("SMTP_HOST"),
+			env.Get("SMTP_PORT"),
+			env.Get("SMTP_USERNAME"),
+			env.Get("SMTP_PASSWORD"),
+			env.Get("MAIL_FROM"),
+		)
+	}
+	return mailer.NewOutboxSender(env.Get("MAIL_OUTBOX_DIR"), env.Get("MAIL_FROM"))
+}
+
+// setTrustedProxies lets the server take the client IP from X-Forwarded-For
+// only when the connection comes from one of the comma separated addresses
+// or CIDRs in TRUSTED_PROXIES. Without any the IP of the connection is used,
+// a client cannot pick the IP its failed logins are counted against.
+func setTrustedProxies(server *gin.Engine, env environment.Environment) error {
+	var proxies []string
+	for _, proxy := range strings.Split(env.Get("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return server.SetTrustedProxies(proxies)
+}
+
+// newDenylist keeps revoked tokens in memory when TOKEN_DENYLIST_DRIVER is
+// "memory", only for a single instance, otherwise in the database.
+func newDenylist(env environment.Environment, store Repository.RevocationRepoInterface) revocation.Denylist {
+	if env.Get("TOKEN_DENYLIST_DRIVER") == "memory" {
+		return revocation.NewMemoryDenylist()
 	}
+	return revocation.NewDatabaseDenylist(store)
 }
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"github.com/bowoBp/LoanFlow/internal/adapter/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetTrustedProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		proxies    string
+		remoteAddr string
+		wantIP     string
+	}{
+		{
+			name:       "No proxy - spoofed X-Forwarded-For is ignored",
+			proxies:    "",
+			remoteAddr: "203.0.113.7:52100",
+			wantIP:     "203.0.113.7",
+		},
+		{
+			name:       "Untrusted peer - spoofed X-Forwarded-For is ignored",
+			proxies:    "10.0.0.0/8",
+			remoteAddr: "203.0.113.7:52100",
+			wantIP:     "203.0.113.7",
+		},
+		{
+			name:       "Trusted proxy - forwarded client IP is used",
+			proxies:    "10.0.0.0/8, 192.168.1.1",
+			remoteAddr: "10.1.2.3:52100",
+			wantIP:     "198.51.100.20",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := new(mocks.Environment)
+			env.On("Get", "TRUSTED_PROXIES").Return(tt.proxies).Once()
+
+			server := gin.New()
+			assert.NoError(t, setTrustedProxies(server, env))
+			server.GET("/ip", func(c *gin.Context) {
+				c.String(http.StatusOK, c.ClientIP())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", "198.51.100.20")
+			req.Header.Set("X-Real-IP", "198.51.100.20")
+			rec := httptest.NewRecorder()
+			server.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantIP, rec.Body.String())
+			env.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetTrustedProxies_Invalid(t *testing.T) {
+	env := new(mocks.Environment)
+	env.On("Get", "TRUSTED_PROXIES").Return("not-an-ip").Once()
+
+	assert.Error(t, setTrustedProxies(gin.New(), env))
+}
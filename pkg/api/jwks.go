@@ -0,0 +1,17 @@
+package api
+
+import (
+	"github.com/bowoBp/LoanFlow/utils/helper"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// jwksHandler serves the public keys of the key ring, a key stays published
+// until it is removed from JWT_KEYS_DIR so tokens signed before a rotation
+// can still be verified.
+func jwksHandler(keys *helper.KeyRing) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, keys.JWKS())
+	}
+}
@@ -0,0 +1,6 @@
+-- Drop the repayment tables
+DROP TRIGGER IF EXISTS trigger_loan_repayments_set_updated_at ON loan_repayments;
+DROP TRIGGER IF EXISTS trigger_loan_installments_set_updated_at ON loan_installments;
+DROP TABLE IF EXISTS loan_repayments;
+DROP TABLE IF EXISTS loan_installments;
+ALTER TABLE loans DROP COLUMN IF EXISTS tenor;
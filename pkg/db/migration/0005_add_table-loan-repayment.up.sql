@@ -0,0 +1,44 @@
+-- Tenor (dalam bulan) untuk menghitung jadwal cicilan
+ALTER TABLE loans ADD COLUMN IF NOT EXISTS tenor INT NOT NULL DEFAULT 12;
+
+-- Create the loan_installments table
+CREATE TABLE IF NOT EXISTS loan_installments (
+                                                 id SERIAL PRIMARY KEY,                          -- Primary key
+                                                 loan_id INT NOT NULL,                           -- FK ke loans.id
+                                                 sequence INT NOT NULL,                          -- Urutan cicilan (1..tenor)
+                                                 due_date TIMESTAMP NOT NULL,                    -- Tanggal jatuh tempo
+                                                 principal_due DECIMAL(20,2) NOT NULL,           -- Porsi pokok
+                                                 interest_due DECIMAL(20,2) NOT NULL,            -- Porsi bunga
+                                                 amount_due DECIMAL(20,2) NOT NULL,              -- Total tagihan cicilan
+                                                 amount_paid DECIMAL(20,2) NOT NULL DEFAULT 0,   -- Total yang sudah dibayar
+                                                 status VARCHAR(20) NOT NULL,                    -- 'pending','partial','paid'
+                                                 paid_at TIMESTAMP,                              -- Waktu cicilan lunas
+                                                 created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_loan_installments FOREIGN KEY (loan_id) REFERENCES loans (id) ON DELETE CASCADE,
+    CONSTRAINT uq_loan_installments_sequence UNIQUE (loan_id, sequence)
+    );
+
+-- Create the loan_repayments table
+CREATE TABLE IF NOT EXISTS loan_repayments (
+                                               id SERIAL PRIMARY KEY,                          -- Primary key
+                                               loan_id INT NOT NULL,                           -- FK ke loans.id
+                                               paid_by INT NOT NULL,                           -- FK ke users.id
+                                               amount DECIMAL(20,2) NOT NULL,                  -- Jumlah pembayaran
+                                               paid_at TIMESTAMP NOT NULL,                     -- Waktu pembayaran
+                                               remarks TEXT,
+                                               created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_loan_repayments FOREIGN KEY (loan_id) REFERENCES loans (id) ON DELETE CASCADE,
+    CONSTRAINT fk_loan_repayments_user FOREIGN KEY (paid_by) REFERENCES users (id)
+    );
+
+CREATE TRIGGER trigger_loan_installments_set_updated_at
+    BEFORE UPDATE ON loan_installments
+    FOR EACH ROW
+    EXECUTE PROCEDURE set_updated_at();
+
+CREATE TRIGGER trigger_loan_repayments_set_updated_at
+    BEFORE UPDATE ON loan_repayments
+    FOR EACH ROW
+    EXECUTE PROCEDURE set_updated_at();
@@ -0,0 +1,3 @@
+ALTER TABLE loan_investors DROP COLUMN IF EXISTS released_at;
+ALTER TABLE loan_investors DROP COLUMN IF EXISTS status;
+ALTER TABLE loan_state_histories DROP COLUMN IF EXISTS reason_code;
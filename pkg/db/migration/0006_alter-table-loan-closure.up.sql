@@ -0,0 +1,6 @@
+-- Reason code untuk reject / cancel loan
+ALTER TABLE loan_state_histories ADD COLUMN IF NOT EXISTS reason_code VARCHAR(50);
+
+-- Status komitmen investor, 'released' jika loan dibatalkan sebelum fully invested
+ALTER TABLE loan_investors ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active';
+ALTER TABLE loan_investors ADD COLUMN IF NOT EXISTS released_at TIMESTAMP;
@@ -0,0 +1 @@
+ALTER TABLE loan_investors DROP COLUMN IF EXISTS agreement_letter_link;
@@ -0,0 +1,2 @@
+-- Link surat perjanjian (agreement letter) per investor
+ALTER TABLE loan_investors ADD COLUMN IF NOT EXISTS agreement_letter_link TEXT;
@@ -0,0 +1,4 @@
+-- Drop the notifications table
+DROP TRIGGER IF EXISTS trigger_notifications_set_updated_at ON notifications;
+DROP INDEX IF EXISTS idx_notifications_pending;
+DROP TABLE IF EXISTS notifications;
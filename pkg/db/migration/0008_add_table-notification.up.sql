@@ -0,0 +1,25 @@
+-- Create the notifications table, satu baris per penerima
+CREATE TABLE IF NOT EXISTS notifications (
+                                             id SERIAL PRIMARY KEY,                          -- Primary key
+                                             user_id INT NOT NULL,                           -- FK ke users.id
+                                             channel VARCHAR(20) NOT NULL,                   -- 'email'
+                                             recipient VARCHAR(100) NOT NULL,                -- Alamat tujuan
+                                             template VARCHAR(50) NOT NULL,                  -- Nama template
+                                             subject TEXT NOT NULL,
+                                             body TEXT NOT NULL,
+                                             status VARCHAR(20) NOT NULL,                    -- 'pending','sent','failed'
+                                             attempts INT NOT NULL DEFAULT 0,                -- Jumlah percobaan kirim
+                                             last_error TEXT,
+                                             next_attempt_at TIMESTAMP NOT NULL DEFAULT now(),
+                                             sent_at TIMESTAMP,
+                                             created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_notifications_user FOREIGN KEY (user_id) REFERENCES users (id)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_notifications_pending ON notifications (status, next_attempt_at);
+
+CREATE TRIGGER trigger_notifications_set_updated_at
+    BEFORE UPDATE ON notifications
+    FOR EACH ROW
+    EXECUTE PROCEDURE set_updated_at();
@@ -0,0 +1,3 @@
+-- Kembalikan principal_amount ke sisa yang belum didanai
+UPDATE loans SET principal_amount = principal_amount - funded_amount;
+ALTER TABLE loans DROP COLUMN IF EXISTS funded_amount;
@@ -0,0 +1,15 @@
+-- Jumlah yang sudah didanai investor, principal_amount tidak lagi dikurangi saat invest
+ALTER TABLE loans ADD COLUMN IF NOT EXISTS funded_amount DECIMAL(20,2) NOT NULL DEFAULT 0;
+
+-- Hitung ulang data lama dari loan_investors:
+-- principal_amount sebelumnya sudah dikurangi setiap investasi aktif
+UPDATE loans l
+SET funded_amount    = i.total,
+    principal_amount = l.principal_amount + i.total
+FROM (
+         SELECT loan_id, SUM(amount_invested) AS total
+         FROM loan_investors
+         WHERE status = 'active'
+         GROUP BY loan_id
+     ) i
+WHERE l.id = i.loan_id;
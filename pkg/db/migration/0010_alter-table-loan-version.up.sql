@@ -0,0 +1,2 @@
+-- Versi loan untuk optimistic locking, naik setiap perubahan state
+ALTER TABLE loans ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- Drop the idempotency_keys table
+DROP TRIGGER IF EXISTS trigger_idempotency_keys_set_updated_at ON idempotency_keys;
+DROP INDEX IF EXISTS idx_idempotency_keys_expires_at;
+DROP TABLE IF EXISTS idempotency_keys;
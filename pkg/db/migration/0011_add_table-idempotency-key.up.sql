@@ -0,0 +1,24 @@
+-- Create the idempotency_keys table, menyimpan response untuk request yang di-retry
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+                                                id SERIAL PRIMARY KEY,                          -- Primary key
+                                                user_id INT NOT NULL,                           -- FK ke users.id
+                                                idempotency_key VARCHAR(255) NOT NULL,          -- Nilai header Idempotency-Key
+                                                method VARCHAR(10) NOT NULL,
+                                                path TEXT NOT NULL,
+                                                fingerprint VARCHAR(64) NOT NULL,               -- sha256 dari method, path dan body
+                                                status VARCHAR(20) NOT NULL,                    -- 'processing','completed'
+                                                response_code INT NOT NULL DEFAULT 0,
+                                                response_body TEXT,
+                                                expires_at TIMESTAMP NOT NULL,
+                                                created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_idempotency_keys_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT uq_idempotency_keys_user_key UNIQUE (user_id, idempotency_key)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+CREATE TRIGGER trigger_idempotency_keys_set_updated_at
+    BEFORE UPDATE ON idempotency_keys
+    FOR EACH ROW
+    EXECUTE PROCEDURE set_updated_at();
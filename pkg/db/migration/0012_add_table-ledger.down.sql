@@ -0,0 +1,5 @@
+-- Drop the ledger tables
+DROP TRIGGER IF EXISTS trigger_ledger_accounts_set_updated_at ON ledger_accounts;
+DROP TABLE IF EXISTS journal_lines;
+DROP TABLE IF EXISTS journal_entries;
+DROP TABLE IF EXISTS ledger_accounts;
@@ -0,0 +1,51 @@
+-- Create the ledger_accounts table
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+                                               id SERIAL PRIMARY KEY,                          -- Primary key
+                                               code VARCHAR(100) NOT NULL UNIQUE,              -- Contoh 'investor_wallet:7', 'loan_escrow:3'
+                                               type VARCHAR(30) NOT NULL,                      -- 'platform_cash','platform_fee','investor_wallet','loan_escrow','loan_receivable'
+                                               user_id INT,                                    -- FK ke users.id (wallet investor / receivable borrower)
+                                               loan_id INT,                                    -- FK ke loans.id (escrow / receivable)
+                                               balance DECIMAL(20,2) NOT NULL DEFAULT 0,       -- Saldo di sisi normal akun
+                                               created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_ledger_accounts_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_ledger_accounts_loan FOREIGN KEY (loan_id) REFERENCES loans (id)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_ledger_accounts_user_id ON ledger_accounts (user_id);
+
+-- Create the journal_entries table
+CREATE TABLE IF NOT EXISTS journal_entries (
+                                               id SERIAL PRIMARY KEY,                          -- Primary key
+                                               type VARCHAR(30) NOT NULL,                      -- 'top_up','investment','release','disbursement','repayment'
+                                               reference VARCHAR(100) NOT NULL,                -- Contoh 'loan:3'
+                                               description TEXT,
+                                               created_by INT NOT NULL,                        -- FK ke users.id
+                                               created_at TIMESTAMP DEFAULT now(),
+                                               CONSTRAINT fk_journal_entries_user FOREIGN KEY (created_by) REFERENCES users (id)
+    );
+
+-- Create the journal_lines table
+CREATE TABLE IF NOT EXISTS journal_lines (
+                                             id SERIAL PRIMARY KEY,                          -- Primary key
+                                             journal_entry_id INT NOT NULL,                  -- FK ke journal_entries.id
+                                             account_id INT NOT NULL,                        -- FK ke ledger_accounts.id
+                                             debit DECIMAL(20,2) NOT NULL DEFAULT 0,
+                                             credit DECIMAL(20,2) NOT NULL DEFAULT 0,
+                                             balance_after DECIMAL(20,2) NOT NULL,           -- Saldo akun setelah baris ini
+                                             created_at TIMESTAMP DEFAULT now(),
+                                             CONSTRAINT fk_journal_lines_entry FOREIGN KEY (journal_entry_id) REFERENCES journal_entries (id) ON DELETE CASCADE,
+                                             CONSTRAINT fk_journal_lines_account FOREIGN KEY (account_id) REFERENCES ledger_accounts (id),
+                                             CONSTRAINT ck_journal_lines_side CHECK ((debit > 0 AND credit = 0) OR (credit > 0 AND debit = 0))
+    );
+
+CREATE INDEX IF NOT EXISTS idx_journal_lines_account_id ON journal_lines (account_id, id);
+
+CREATE TRIGGER trigger_ledger_accounts_set_updated_at
+    BEFORE UPDATE ON ledger_accounts
+    FOR EACH ROW
+    EXECUTE PROCEDURE set_updated_at();
+
+-- Akun platform
+INSERT INTO ledger_accounts (code, type) VALUES ('platform_cash', 'platform_cash') ON CONFLICT (code) DO NOTHING;
+INSERT INTO ledger_accounts (code, type) VALUES ('platform_fee', 'platform_fee') ON CONFLICT (code) DO NOTHING;
@@ -0,0 +1,2 @@
+-- Mata uang pinjaman, semua nominal pinjaman memakai mata uang ini
+ALTER TABLE loans ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR';
@@ -0,0 +1,3 @@
+-- Drop the role_audits table
+DROP INDEX IF EXISTS idx_role_audits_user_id;
+DROP TABLE IF EXISTS role_audits;
@@ -0,0 +1,14 @@
+-- Create the role_audits table, mencatat setiap perubahan role user oleh admin
+CREATE TABLE IF NOT EXISTS role_audits (
+                                           id SERIAL PRIMARY KEY,                          -- Primary key
+                                           user_id INT NOT NULL,                           -- FK ke users.id, user yang role-nya berubah
+                                           actor_id INT NOT NULL,                          -- FK ke users.id, admin yang mengubah
+                                           previous_role VARCHAR(50) NOT NULL DEFAULT '',  -- Kosong saat user dibuat oleh admin
+                                           new_role VARCHAR(50) NOT NULL,
+                                           reason TEXT,
+                                           created_at TIMESTAMP DEFAULT now(),
+                                           CONSTRAINT fk_role_audits_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+                                           CONSTRAINT fk_role_audits_actor FOREIGN KEY (actor_id) REFERENCES users (id)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_role_audits_user_id ON role_audits (user_id);
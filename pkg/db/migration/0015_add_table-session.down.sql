@@ -0,0 +1,14 @@
+-- Revert refresh_tokens to one plain token per user
+DELETE FROM refresh_tokens;
+DROP INDEX IF EXISTS idx_refresh_tokens_session_id;
+ALTER TABLE refresh_tokens
+    DROP CONSTRAINT IF EXISTS fk_refresh_tokens_session,
+    DROP COLUMN IF EXISTS used_at,
+    DROP COLUMN IF EXISTS session_id;
+ALTER TABLE refresh_tokens ALTER COLUMN token_hash TYPE TEXT;
+ALTER TABLE refresh_tokens RENAME COLUMN token_hash TO refresh_token;
+
+-- Drop the sessions table
+DROP TRIGGER IF EXISTS trigger_sessions_set_updated_at ON sessions;
+DROP INDEX IF EXISTS idx_sessions_user_id;
+DROP TABLE IF EXISTS sessions;
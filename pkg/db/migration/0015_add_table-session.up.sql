@@ -0,0 +1,33 @@
+-- Create the sessions table, satu baris per login/device
+CREATE TABLE IF NOT EXISTS sessions (
+                                        id SERIAL PRIMARY KEY,                          -- Primary key
+                                        user_id INT NOT NULL,                           -- FK ke users.id
+                                        user_agent TEXT,                                -- User-Agent saat login/refresh terakhir
+                                        ip_address VARCHAR(45),                         -- IPv4 atau IPv6
+                                        last_used_at TIMESTAMP NOT NULL DEFAULT now(),
+                                        expires_at TIMESTAMP NOT NULL,                  -- Kedaluwarsa refresh token terakhir
+                                        revoked_at TIMESTAMP,                           -- Diisi saat logout, dicabut user atau token dipakai ulang
+                                        created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_sessions_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
+
+CREATE TRIGGER trigger_sessions_set_updated_at
+    BEFORE UPDATE ON sessions
+    FOR EACH ROW
+    EXECUTE PROCEDURE set_updated_at();
+
+-- Refresh token lama dibuat dari secret statis, dihapus supaya semua user login ulang
+DELETE FROM refresh_tokens;
+
+-- Refresh token sekarang disimpan sebagai sha256 dan dirotasi dalam satu session
+ALTER TABLE refresh_tokens RENAME COLUMN refresh_token TO token_hash;
+ALTER TABLE refresh_tokens ALTER COLUMN token_hash TYPE CHAR(64);
+ALTER TABLE refresh_tokens
+    ADD COLUMN session_id INT NOT NULL,                 -- FK ke sessions.id
+    ADD COLUMN used_at TIMESTAMP,                       -- Diisi saat token dirotasi
+    ADD CONSTRAINT fk_refresh_tokens_session FOREIGN KEY (session_id) REFERENCES sessions (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON refresh_tokens (session_id);
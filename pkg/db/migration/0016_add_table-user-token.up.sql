@@ -0,0 +1,19 @@
+-- Email terverifikasi dibutuhkan untuk mengajukan dan mendanai pinjaman
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP;
+
+-- User yang sudah ada dianggap terverifikasi supaya tidak terblokir
+UPDATE users SET email_verified_at = created_at;
+
+-- Create the user_tokens table, token sekali pakai yang dikirim lewat email
+CREATE TABLE IF NOT EXISTS user_tokens (
+                                           id SERIAL PRIMARY KEY,                   -- Primary key
+                                           user_id INT NOT NULL,                    -- FK ke users.id
+                                           purpose VARCHAR(30) NOT NULL,            -- password_reset atau email_verification
+                                           token_hash VARCHAR(64) NOT NULL UNIQUE,  -- HMAC dari token, token aslinya tidak disimpan
+                                           expires_at TIMESTAMP NOT NULL,
+                                           used_at TIMESTAMP,                       -- Diisi saat token dipakai atau diganti token baru
+                                           created_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_user_tokens_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS idx_user_tokens_user_id ON user_tokens (user_id, purpose);
@@ -0,0 +1,10 @@
+-- Drop the recovery_codes table
+DROP INDEX IF EXISTS idx_recovery_codes_user_id;
+DROP TABLE IF EXISTS recovery_codes;
+
+ALTER TABLE user_tokens DROP COLUMN IF EXISTS attempts;
+ALTER TABLE sessions DROP COLUMN IF EXISTS mfa;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS totp_last_step,
+    DROP COLUMN IF EXISTS totp_enabled_at,
+    DROP COLUMN IF EXISTS totp_secret;
@@ -0,0 +1,23 @@
+-- Two-factor authentication (TOTP) untuk user
+ALTER TABLE users
+    ADD COLUMN totp_secret VARCHAR(64),                 -- Secret base32, terisi sejak enrolment
+    ADD COLUMN totp_enabled_at TIMESTAMP,               -- Terisi setelah kode pertama dikonfirmasi
+    ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0; -- Step terakhir yang dipakai, mencegah replay kode
+
+-- Session yang login-nya diselesaikan dengan kode TOTP atau recovery code
+ALTER TABLE sessions ADD COLUMN mfa BOOLEAN NOT NULL DEFAULT false;
+
+-- Jumlah kode salah untuk challenge login 2FA
+ALTER TABLE user_tokens ADD COLUMN attempts INT NOT NULL DEFAULT 0;
+
+-- Create the recovery_codes table, kode sekali pakai saat authenticator hilang
+CREATE TABLE IF NOT EXISTS recovery_codes (
+                                              id SERIAL PRIMARY KEY,              -- Primary key
+                                              user_id INT NOT NULL,               -- FK ke users.id
+                                              code_hash CHAR(64) NOT NULL,        -- sha256 dari kode
+                                              used_at TIMESTAMP,                  -- Diisi saat kode dipakai
+                                              created_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_recovery_codes_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_id ON recovery_codes (user_id);
@@ -0,0 +1,4 @@
+-- Drop the user_revocations and revoked_tokens tables
+DROP TABLE IF EXISTS user_revocations;
+DROP INDEX IF EXISTS idx_revoked_tokens_expires_at;
+DROP TABLE IF EXISTS revoked_tokens;
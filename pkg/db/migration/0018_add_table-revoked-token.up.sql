@@ -0,0 +1,17 @@
+-- Create the revoked_tokens table, access token (jti) yang sudah logout
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+                                              token_id VARCHAR(64) PRIMARY KEY,   -- Claim jti dari access token
+                                              user_id INT NOT NULL,               -- Pemilik token
+                                              expires_at TIMESTAMP NOT NULL,      -- Sama dengan exp token, setelahnya baris dihapus
+                                              created_at TIMESTAMP DEFAULT now()
+    );
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
+
+-- Create the user_revocations table, semua access token user yang terbit sebelum revoked_at ditolak
+CREATE TABLE IF NOT EXISTS user_revocations (
+                                                user_id INT PRIMARY KEY,            -- FK ke users.id
+                                                revoked_at TIMESTAMP NOT NULL,      -- Token dengan iat sebelum waktu ini ditolak
+                                                expires_at TIMESTAMP NOT NULL,      -- revoked_at + masa berlaku access token
+    CONSTRAINT fk_user_revocations_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+    );
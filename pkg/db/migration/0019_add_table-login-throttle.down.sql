@@ -0,0 +1,4 @@
+-- Drop the auth_events and login_throttles tables
+DROP INDEX IF EXISTS idx_auth_events_user_id;
+DROP TABLE IF EXISTS auth_events;
+DROP TABLE IF EXISTS login_throttles;
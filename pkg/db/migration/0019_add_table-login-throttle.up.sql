@@ -0,0 +1,23 @@
+-- Create the login_throttles table, jumlah login gagal per akun (email) dan per IP
+CREATE TABLE IF NOT EXISTS login_throttles (
+                                               scope VARCHAR(10) NOT NULL,         -- 'account' atau 'ip'
+                                               throttle_key VARCHAR(255) NOT NULL, -- Email (lowercase) atau alamat IP
+                                               failures INT NOT NULL DEFAULT 0,    -- Login gagal berturut-turut
+                                               locked_until TIMESTAMP,             -- Login ditolak sampai waktu ini
+                                               last_failed_at TIMESTAMP NOT NULL,  -- Kegagalan terakhir, hitungan direset setelah window
+    PRIMARY KEY (scope, throttle_key)
+    );
+
+-- Create the auth_events table, audit keamanan login
+CREATE TABLE IF NOT EXISTS auth_events (
+                                           id SERIAL PRIMARY KEY,              -- Primary key
+                                           user_id INT,                        -- FK ke users.id, kosong untuk email yang tidak terdaftar
+                                           actor_id INT,                       -- Admin yang membuka kunci akun
+                                           event VARCHAR(30) NOT NULL,         -- 'login_failed','login_locked','login_unlocked'
+                                           email VARCHAR(255) NOT NULL,        -- Email yang dipakai login
+                                           ip_address VARCHAR(45),             -- IP klien
+                                           created_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_auth_events_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS idx_auth_events_user_id ON auth_events (user_id);
@@ -0,0 +1,16 @@
+-- Create the api_keys table, API key untuk integrasi sistem partner
+CREATE TABLE IF NOT EXISTS api_keys (
+                                        id SERIAL PRIMARY KEY,              -- Primary key
+                                        name VARCHAR(255) NOT NULL,         -- Nama integrasi, contoh 'field-agent app'
+                                        user_id INT NOT NULL,               -- FK ke users.id, user yang diwakili key
+                                        prefix VARCHAR(16) NOT NULL,        -- Awal key untuk membedakan key tanpa secret
+                                        key_hash CHAR(64) NOT NULL UNIQUE,  -- sha256 dari key
+                                        scopes JSONB NOT NULL,              -- Contoh ["loans:read","loans:approve"]
+                                        created_by INT NOT NULL,            -- Admin yang membuat key
+                                        expires_at TIMESTAMP,               -- Kosong berarti tidak kedaluwarsa
+                                        last_used_at TIMESTAMP,             -- Pemakaian terakhir
+                                        revoked_at TIMESTAMP,               -- Diisi saat key dicabut
+                                        created_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_api_keys_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_api_keys_created_by FOREIGN KEY (created_by) REFERENCES users (id)
+    );
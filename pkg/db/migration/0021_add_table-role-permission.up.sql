@@ -0,0 +1,36 @@
+-- Create the role_permissions table, permission yang dimiliki setiap role dan bisa diubah admin
+CREATE TABLE IF NOT EXISTS role_permissions (
+                                                role VARCHAR(50) NOT NULL,          -- Contoh 'STAFF'
+                                                permission VARCHAR(50) NOT NULL,    -- Contoh 'loan.approve'
+                                                created_by INT,                     -- Admin yang memberi permission, kosong untuk bawaan
+                                                created_at TIMESTAMP DEFAULT now(),
+    PRIMARY KEY (role, permission),
+    CONSTRAINT fk_role_permissions_created_by FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL
+    );
+
+-- Permission bawaan, sama dengan role yang sebelumnya ditulis langsung di route
+INSERT INTO role_permissions (role, permission) VALUES
+    ('ADMIN', 'loan.read'),
+    ('STAFF', 'loan.read'),
+    ('BORROWER', 'loan.read'),
+    ('INVESTOR', 'loan.read'),
+    ('ADMIN', 'loan.create'),
+    ('BORROWER', 'loan.create'),
+    ('ADMIN', 'loan.approve'),
+    ('STAFF', 'loan.approve'),
+    ('ADMIN', 'loan.reject'),
+    ('STAFF', 'loan.reject'),
+    ('ADMIN', 'loan.disburse'),
+    ('STAFF', 'loan.disburse'),
+    ('BORROWER', 'loan.cancel'),
+    ('ADMIN', 'loan.repay'),
+    ('STAFF', 'loan.repay'),
+    ('BORROWER', 'loan.repay'),
+    ('INVESTOR', 'investment.create'),
+    ('ADMIN', 'ledger.read'),
+    ('STAFF', 'ledger.read'),
+    ('BORROWER', 'ledger.read'),
+    ('INVESTOR', 'ledger.read'),
+    ('INVESTOR', 'ledger.top_up'),
+    ('ADMIN', 'user.manage')
+ON CONFLICT (role, permission) DO NOTHING;
@@ -0,0 +1,2 @@
+-- Drop the locale column
+ALTER TABLE users DROP COLUMN IF EXISTS locale;
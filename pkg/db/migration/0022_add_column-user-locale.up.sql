@@ -0,0 +1,2 @@
+-- Bahasa pilihan user untuk pesan API dan email, kosong berarti mengikuti Accept-Language
+ALTER TABLE users ADD COLUMN locale VARCHAR(5) NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+-- Drop the top_ups table
+DELETE FROM role_permissions WHERE permission = 'ledger.top_up_request';
+DELETE FROM role_permissions WHERE role IN ('ADMIN', 'STAFF') AND permission = 'ledger.top_up';
+INSERT INTO role_permissions (role, permission) VALUES ('INVESTOR', 'ledger.top_up') ON CONFLICT (role, permission) DO NOTHING;
+
+DROP TRIGGER IF EXISTS trigger_top_ups_set_updated_at ON top_ups;
+DROP TABLE IF EXISTS top_ups;
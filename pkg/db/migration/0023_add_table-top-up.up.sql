@@ -0,0 +1,33 @@
+-- Create the top_ups table, saldo investor baru ditambah setelah staff mencocokkan pembayarannya
+CREATE TABLE IF NOT EXISTS top_ups (
+                                       id SERIAL PRIMARY KEY,                          -- Primary key
+                                       user_id INT NOT NULL,                           -- FK ke users.id (investor)
+                                       amount DECIMAL(20,2) NOT NULL,
+                                       payment_reference VARCHAR(100) NOT NULL UNIQUE, -- Nomor referensi transfer bank, hanya bisa dipakai sekali
+                                       status VARCHAR(20) NOT NULL,                    -- 'pending','confirmed','rejected'
+                                       journal_entry_id INT,                           -- FK ke journal_entries.id, terisi setelah dikonfirmasi
+                                       reviewed_by INT,                                -- FK ke users.id (staff yang mengkonfirmasi atau menolak)
+                                       reviewed_at TIMESTAMP,
+                                       remarks TEXT,
+                                       created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_top_ups_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_top_ups_journal_entry FOREIGN KEY (journal_entry_id) REFERENCES journal_entries (id),
+    CONSTRAINT fk_top_ups_reviewed_by FOREIGN KEY (reviewed_by) REFERENCES users (id)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_top_ups_status ON top_ups (status, id);
+CREATE INDEX IF NOT EXISTS idx_top_ups_user_id ON top_ups (user_id, id);
+
+CREATE TRIGGER trigger_top_ups_set_updated_at
+    BEFORE UPDATE ON top_ups
+    FOR EACH ROW
+    EXECUTE PROCEDURE set_updated_at();
+
+-- Investor hanya mengajukan top-up, yang menambah saldo adalah staff
+DELETE FROM role_permissions WHERE role = 'INVESTOR' AND permission = 'ledger.top_up';
+INSERT INTO role_permissions (role, permission) VALUES
+    ('INVESTOR', 'ledger.top_up_request'),
+    ('ADMIN', 'ledger.top_up'),
+    ('STAFF', 'ledger.top_up')
+ON CONFLICT (role, permission) DO NOTHING;
@@ -0,0 +1,3 @@
+ALTER TABLE top_ups DROP COLUMN IF EXISTS currency;
+ALTER TABLE journal_entries DROP COLUMN IF EXISTS currency;
+ALTER TABLE ledger_accounts DROP COLUMN IF EXISTS currency;
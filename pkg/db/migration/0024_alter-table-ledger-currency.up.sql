@@ -0,0 +1,10 @@
+-- Mata uang akun buku besar, jurnal dan top-up. Satu jurnal hanya memindahkan uang antar akun dengan mata uang yang sama
+ALTER TABLE ledger_accounts ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR';
+ALTER TABLE journal_entries ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR';
+ALTER TABLE top_ups ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR';
+
+-- Akun escrow dan piutang mengikuti mata uang pinjamannya
+UPDATE ledger_accounts
+SET currency = loans.currency
+FROM loans
+WHERE ledger_accounts.loan_id = loans.id;
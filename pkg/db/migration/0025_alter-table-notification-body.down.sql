@@ -0,0 +1,2 @@
+-- Isi email yang dikosongkan tidak bisa dikembalikan
+DROP INDEX IF EXISTS idx_notifications_finished;
@@ -0,0 +1,4 @@
+-- Isi email yang sudah terkirim atau gagal dikosongkan, isinya bisa berupa link token sekali pakai
+UPDATE notifications SET body = '' WHERE status IN ('sent', 'failed');
+
+CREATE INDEX IF NOT EXISTS idx_notifications_finished ON notifications (updated_at) WHERE status IN ('sent', 'failed');
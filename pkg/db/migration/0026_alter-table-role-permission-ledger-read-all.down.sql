@@ -0,0 +1,2 @@
+-- Drop the ledger.read_all permission
+DELETE FROM role_permissions WHERE permission = 'ledger.read_all';
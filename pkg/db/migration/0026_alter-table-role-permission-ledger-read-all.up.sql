@@ -0,0 +1,5 @@
+-- Admin dan staff membaca akun ledger user lain dan akun platform
+INSERT INTO role_permissions (role, permission) VALUES
+    ('ADMIN', 'ledger.read_all'),
+    ('STAFF', 'ledger.read_all')
+ON CONFLICT (role, permission) DO NOTHING;
@@ -0,0 +1,2 @@
+-- Drop the loan.read_all permission
+DELETE FROM role_permissions WHERE permission = 'loan.read_all';
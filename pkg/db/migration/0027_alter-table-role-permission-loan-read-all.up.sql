@@ -0,0 +1,5 @@
+-- Admin dan staff melihat semua pinjaman, bukan hanya pinjaman miliknya
+INSERT INTO role_permissions (role, permission) VALUES
+    ('ADMIN', 'loan.read_all'),
+    ('STAFF', 'loan.read_all')
+ON CONFLICT (role, permission) DO NOTHING;
@@ -0,0 +1,48 @@
+package document
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Store keeps generated documents and returns the link they can be
+// downloaded from.
+type Store interface {
+	Save(ctx context.Context, name string, content []byte) (string, error)
+}
+
+// NewLocalStore writes documents under dir. The returned link is baseURL
+// joined with the document name, or the file path when baseURL is empty.
+func NewLocalStore(dir, baseURL string) Store {
+	return localStore{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+type localStore struct {
+	dir     string
+	baseURL string
+}
+
+func (s localStore) Save(
+	ctx context.Context,
+	name string,
+	content []byte,
+) (string, error) {
+	path := filepath.Join(s.dir, filepath.FromSlash(name))
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(path, content, 0o644)
+	if err != nil {
+		return "", err
+	}
+	if s.baseURL == "" {
+		return path, nil
+	}
+	return s.baseURL + "/" + name, nil
+}
@@ -1,7 +1,7 @@
 package environment
 
 import (
-	"log"
+	"log/slog"
 	"os"
 	"strconv"
 )
@@ -37,8 +37,7 @@ func (e environment) GetUint(key string, defaultValue uint) uint {
 	str := os.Getenv(key)
 	value, err := strconv.ParseUint(str, 10, 64)
 	if err != nil {
-
-		log.Println("featureflag.environment.GetUint:", err)
+		slog.Warn("featureflag.environment.GetUint", "key", key, "error", err)
 		value = uint64(defaultValue)
 	}
 
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const (
+	// RequestIDKey is the context key of the ID of the request being served,
+	// it is logged as request_id by every logger of New.
+	RequestIDKey = "requestId"
+
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// New builds a structured logger writing to w. level is one of debug, info,
+// warn or error (info when empty or unknown), format is FormatText or
+// FormatJSON (the default).
+func New(w io.Writer, level, format string) *slog.Logger {
+	options := &slog.HandlerOptions{Level: parseLevel(level)}
+	var handler slog.Handler = slog.NewJSONHandler(w, options)
+	if strings.EqualFold(format, FormatText) {
+		handler = slog.NewTextHandler(w, options)
+	}
+	return slog.New(contextHandler{Handler: handler})
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, RequestIDKey, requestID)
+}
+
+// RequestID returns the request ID of ctx, empty outside of a request.
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(RequestIDKey).(string)
+	return requestID
+}
+
+func parseLevel(level string) slog.Level {
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(level)); err != nil {
+		return slog.LevelInfo
+	}
+	return parsed
+}
+
+// contextHandler adds the request ID of the context to the records, so the
+// lines of a request can be correlated with the X-Request-ID of its response.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithGroup(name)}
+}
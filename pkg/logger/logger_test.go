@@ -0,0 +1,76 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/bowoBp/LoanFlow/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name          string
+		level         string
+		ctx           context.Context
+		wantLines     int
+		wantRequestID string
+	}{
+		{
+			name:          "Info - request ID of the context is logged",
+			ctx:           logger.WithRequestID(context.Background(), "req-1"),
+			wantLines:     2,
+			wantRequestID: "req-1",
+		},
+		{
+			name:      "Info - no request ID outside of a request",
+			level:     "unknown",
+			ctx:       context.Background(),
+			wantLines: 2,
+		},
+		{
+			name:      "Warn - info lines are dropped",
+			level:     "warn",
+			ctx:       context.Background(),
+			wantLines: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := logger.New(&buf, tt.level, logger.FormatJSON).With("service", "test")
+
+			log.InfoContext(tt.ctx, "served")
+			log.ErrorContext(tt.ctx, "failed")
+
+			lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+			assert.Len(t, lines, tt.wantLines)
+			var record map[string]any
+			assert.NoError(t, json.Unmarshal(lines[len(lines)-1], &record))
+			assert.Equal(t, "failed", record["msg"])
+			assert.Equal(t, "test", record["service"])
+			if tt.wantRequestID == "" {
+				assert.NotContains(t, record, "request_id")
+			} else {
+				assert.Equal(t, tt.wantRequestID, record["request_id"])
+			}
+		})
+	}
+}
+
+func TestNew_Text(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(&buf, "debug", logger.FormatText)
+
+	log.DebugContext(logger.WithRequestID(context.Background(), "req-1"), "served")
+
+	assert.Contains(t, buf.String(), "msg=served request_id=req-1")
+}
+
+func TestRequestID(t *testing.T) {
+	assert.Equal(t, "req-1", logger.RequestID(logger.WithRequestID(context.Background(), "req-1")))
+	assert.Empty(t, logger.RequestID(context.Background()))
+	assert.Empty(t, logger.RequestID(nil))
+}
@@ -0,0 +1,90 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Message is a single email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers an email message.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewSMTPSender sends messages through an SMTP server, with plain auth when
+// username is set.
+func NewSMTPSender(host, port, username, password, from string) Sender {
+	return smtpSender{
+		addr:     net.JoinHostPort(host, port),
+		host:     host,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+type smtpSender struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+func (s smtpSender) Send(ctx context.Context, msg Message) error {
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+	return smtp.SendMail(s.addr, auth, s.from, []string{msg.To}, format(s.from, msg))
+}
+
+// NewOutboxSender writes every message as an .eml file under dir instead of
+// sending it, for local development and tests.
+func NewOutboxSender(dir, from string) Sender {
+	return outboxSender{
+		dir:  dir,
+		from: from,
+	}
+}
+
+type outboxSender struct {
+	dir  string
+	from string
+}
+
+func (s outboxSender) Send(ctx context.Context, msg Message) error {
+	err := os.MkdirAll(s.dir, 0o755)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), strings.NewReplacer("@", "_at_", "/", "_").Replace(msg.To))
+	return os.WriteFile(filepath.Join(s.dir, name), format(s.from, msg), 0o644)
+}
+
+// headerValue drops line breaks so a value can not add extra headers.
+var headerValue = strings.NewReplacer("\r", "", "\n", "")
+
+func format(from string, msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + headerValue.Replace(from) + "\r\n")
+	b.WriteString("To: " + headerValue.Replace(msg.To) + "\r\n")
+	b.WriteString("Subject: " + headerValue.Replace(msg.Subject) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+	return []byte(b.String())
+}
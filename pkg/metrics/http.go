@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+)
+
+// unmatchedRoute labels the requests no route answers, so unknown paths do
+// not create a series each.
+const unmatchedRoute = "unmatched"
+
+// HTTP observes the latency of every request under its gin route, e.g.
+// /api/v1/loans/:loanId rather than the requested path.
+func (m *Metrics) HTTP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		m.httpDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"context"
+	"github.com/bowoBp/LoanFlow/internal/dto"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"github.com/prometheus/client_golang/prometheus"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// StateDurationBuckets are the upper bounds, in seconds, of the histogram of
+// the time spent in a loan state, from a minute to a month.
+var StateDurationBuckets = []float64{
+	60, 300, 900, 3600, 4 * 3600, 12 * 3600,
+	86400, 3 * 86400, 7 * 86400, 14 * 86400, 30 * 86400,
+}
+
+type (
+	// LoanRecorder counts the loan events of the committed transactions.
+	LoanRecorder interface {
+		LoanEvent(event string)
+		LoanInvested(currency money.Currency, amount money.Money)
+	}
+
+	// LoanStats reads the loan figures kept in the database, usually the
+	// loan repository.
+	LoanStats interface {
+		CountLoansByState(ctx context.Context) ([]dto.LoanStateCount, error)
+		GetStateDurations(ctx context.Context, buckets []float64) ([]dto.LoanStateDuration, error)
+	}
+
+	// LoanCollector serves the loans per state and the time spent in each
+	// state read from the database, so every instance answers the same
+	// figures whatever it served. The figures are read by Run, a scrape
+	// answers the last ones read and never waits on the database.
+	LoanCollector struct {
+		stats         LoanStats
+		timeout       time.Duration
+		loans         *prometheus.Desc
+		stateDuration *prometheus.Desc
+
+		mu        sync.RWMutex
+		counts    []dto.LoanStateCount
+		durations []dto.LoanStateDuration
+	}
+)
+
+func (m *Metrics) LoanEvent(event string) {
+	m.loanEvents.WithLabelValues(event).Inc()
+}
+
+func (m *Metrics) LoanInvested(currency money.Currency, amount money.Money) {
+	m.amountInvested.WithLabelValues(string(currency)).Add(float64(amount.Cents()) / 100)
+}
+
+// NewLoanCollector returns the collector of stats, a refresh gives up on
+// the database after timeout.
+func NewLoanCollector(stats LoanStats, timeout time.Duration) *LoanCollector {
+	return &LoanCollector{
+		stats:   stats,
+		timeout: timeout,
+		loans: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "loan", "loans"),
+			"Loans by current state.",
+			[]string{"state"}, nil,
+		),
+		stateDuration: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "loan", "state_duration_seconds"),
+			"Time the loans spent in a state before leaving it, from the loan state history.",
+			[]string{"state"}, nil,
+		),
+	}
+}
+
+func (c *LoanCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.loans
+	ch <- c.stateDuration
+}
+
+// Run refreshes the figures every interval until ctx is done.
+func (c *LoanCollector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		c.Refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh reads the figures from the database, a figure the database does
+// not answer keeps its last value and the failure is logged.
+func (c *LoanCollector) Refresh(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	counts, err := c.stats.CountLoansByState(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "metrics.LoanCollector.Refresh", "error", err)
+	} else {
+		c.mu.Lock()
+		c.counts = counts
+		c.mu.Unlock()
+	}
+
+	durations, err := c.stats.GetStateDurations(ctx, StateDurationBuckets)
+	if err != nil {
+		slog.ErrorContext(ctx, "metrics.LoanCollector.Refresh", "error", err)
+	} else {
+		c.mu.Lock()
+		c.durations = durations
+		c.mu.Unlock()
+	}
+}
+
+// Collect answers the figures of the last Refresh.
+func (c *LoanCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, count := range c.counts {
+		ch <- prometheus.MustNewConstMetric(c.loans, prometheus.GaugeValue, float64(count.Count), count.State)
+	}
+	for _, duration := range c.durations {
+		ch <- prometheus.MustNewConstHistogram(
+			c.stateDuration,
+			duration.Count,
+			duration.Sum,
+			duration.Buckets,
+			duration.State,
+		)
+	}
+}
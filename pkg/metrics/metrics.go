@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace prefixes the name of every metric of the service.
+const Namespace = "loanflow"
+
+type (
+	// Metrics holds the collectors of the HTTP server, the database queries
+	// and the loan events. They are registered on the registerer given to
+	// New, tests pass a prometheus.NewRegistry to read them back.
+	Metrics struct {
+		httpDuration   *prometheus.HistogramVec
+		queryDuration  *prometheus.HistogramVec
+		loanEvents     *prometheus.CounterVec
+		amountInvested *prometheus.CounterVec
+	}
+)
+
+func New(registerer prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of the HTTP requests by route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "db",
+			Name:      "query_duration_seconds",
+			Help:      "Duration of the database queries by operation and table.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "table"}),
+		loanEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "loan",
+			Name:      "events_total",
+			Help:      "Committed loan transitions by event, e.g. propose for a created loan.",
+		}, []string{"event"}),
+		amountInvested: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "loan",
+			Name:      "invested_amount_total",
+			Help:      "Amount invested in loans by currency.",
+		}, []string{"currency"}),
+	}
+	registerer.MustRegister(m.httpDuration, m.queryDuration, m.loanEvents, m.amountInvested)
+	return m
+}
+
+// Handler serves the metrics of gatherer in the Prometheus text format.
+func Handler(gatherer prometheus.Gatherer) gin.HandlerFunc {
+	return gin.WrapH(promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}))
+}
@@ -0,0 +1,179 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"github.com/bowoBp/LoanFlow/internal/dto"
+	"github.com/bowoBp/LoanFlow/pkg/metrics"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics_HTTP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registry := prometheus.NewRegistry()
+	m := metrics.New(registry)
+
+	router := gin.New()
+	router.Use(m.HTTP())
+	router.GET("/loans/:loanId", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.GET("/metrics", metrics.Handler(registry))
+	for _, path := range []string{"/loans/1", "/loans/2", "/unknown"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(),
+		`loanflow_http_request_duration_seconds_count{method="GET",route="/loans/:loanId",status="200"} 2`)
+	assert.Contains(t, rec.Body.String(),
+		`loanflow_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`)
+}
+
+func TestMetrics_LoanRecorder(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m := metrics.New(registry)
+
+	m.LoanEvent("propose")
+	m.LoanEvent("propose")
+	m.LoanEvent("approve")
+	m.LoanInvested(money.IDR, money.MustParse("150000.50"))
+	m.LoanInvested(money.IDR, money.New(100000))
+
+	err := testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP loanflow_loan_events_total Committed loan transitions by event, e.g. propose for a created loan.
+# TYPE loanflow_loan_events_total counter
+loanflow_loan_events_total{event="approve"} 1
+loanflow_loan_events_total{event="propose"} 2
+# HELP loanflow_loan_invested_amount_total Amount invested in loans by currency.
+# TYPE loanflow_loan_invested_amount_total counter
+loanflow_loan_invested_amount_total{currency="IDR"} 250000.5
+`), "loanflow_loan_events_total", "loanflow_loan_invested_amount_total")
+	assert.NoError(t, err)
+}
+
+func TestMetrics_GormPlugin(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m := metrics.New(registry)
+	// a dry run builds the statements without a database
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, db.Use(m.GormPlugin()))
+
+	var loans []struct{ ID uint }
+	db.Table("loans").Find(&loans)
+	db.Table("loans").Where("id = ?", 1).Update("state", "approved")
+
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+	var observed []string
+	for _, family := range families {
+		if family.GetName() != "loanflow_db_query_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := metric.GetLabel()
+			observed = append(observed, labels[0].GetValue()+" "+labels[1].GetValue())
+			assert.Equal(t, uint64(1), metric.GetHistogram().GetSampleCount())
+		}
+	}
+	assert.Equal(t, []string{"query loans", "update loans"}, observed)
+}
+
+type loanStats struct {
+	counts    []dto.LoanStateCount
+	durations []dto.LoanStateDuration
+	err       error
+	queries   int
+}
+
+func (s *loanStats) CountLoansByState(ctx context.Context) ([]dto.LoanStateCount, error) {
+	s.queries++
+	return s.counts, s.err
+}
+
+func (s *loanStats) GetStateDurations(ctx context.Context, buckets []float64) ([]dto.LoanStateDuration, error) {
+	s.queries++
+	return s.durations, s.err
+}
+
+func TestLoanCollector_Collect(t *testing.T) {
+	buckets := make(map[float64]uint64, len(metrics.StateDurationBuckets))
+	for _, bucket := range metrics.StateDurationBuckets {
+		buckets[bucket] = 0
+		if bucket >= 86400 {
+			buckets[bucket] = 2
+		}
+	}
+	stats := &loanStats{
+		counts: []dto.LoanStateCount{
+			{State: "approved", Count: 3},
+			{State: "proposed", Count: 5},
+		},
+		durations: []dto.LoanStateDuration{
+			{State: "proposed", Count: 2, Sum: 150000, Buckets: buckets},
+		},
+	}
+	collector := metrics.NewLoanCollector(stats, time.Second)
+	collector.Refresh(context.Background())
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP loanflow_loan_loans Loans by current state.
+# TYPE loanflow_loan_loans gauge
+loanflow_loan_loans{state="approved"} 3
+loanflow_loan_loans{state="proposed"} 5
+# HELP loanflow_loan_state_duration_seconds Time the loans spent in a state before leaving it, from the loan state history.
+# TYPE loanflow_loan_state_duration_seconds histogram
+loanflow_loan_state_duration_seconds_bucket{state="proposed",le="60"} 0
+loanflow_loan_state_duration_seconds_bucket{state="proposed",le="300"} 0
+loanflow_loan_state_duration_seconds_bucket{state="proposed",le="900"} 0
+loanflow_loan_state_duration_seconds_bucket{state="proposed",le="3600"} 0
+loanflow_loan_state_duration_seconds_bucket{state="proposed",le="14400"} 0
+loanflow_loan_state_duration_seconds_bucket{state="proposed",le="43200"} 0
+loanflow_loan_state_duration_seconds_bucket{state="proposed",le="86400"} 2
+loanflow_loan_state_duration_seconds_bucket{state="proposed",le="259200"} 2
+loanflow_loan_state_duration_seconds_bucket{state="proposed",le="604800"} 2
+loanflow_loan_state_duration_seconds_bucket{state="proposed",le="1.2096e+06"} 2
+loanflow_loan_state_duration_seconds_bucket{state="proposed",le="2.592e+06"} 2
+loanflow_loan_state_duration_seconds_bucket{state="proposed",le="+Inf"} 2
+loanflow_loan_state_duration_seconds_sum{state="proposed"} 150000
+loanflow_loan_state_duration_seconds_count{state="proposed"} 2
+`))
+	assert.NoError(t, err)
+	// a scrape answers the figures of the refresh without the database
+	assert.Equal(t, 2, stats.queries)
+}
+
+func TestLoanCollector_Collect_Error(t *testing.T) {
+	stats := &loanStats{err: errors.New("connection refused")}
+	collector := metrics.NewLoanCollector(stats, time.Second)
+	collector.Refresh(context.Background())
+
+	assert.Equal(t, 0, testutil.CollectAndCount(collector))
+
+	// a failed refresh keeps the figures read before
+	stats.err = nil
+	stats.counts = []dto.LoanStateCount{{State: "approved", Count: 3}}
+	collector.Refresh(context.Background())
+	stats.err = errors.New("connection refused")
+	collector.Refresh(context.Background())
+
+	assert.Equal(t, 1, testutil.CollectAndCount(collector))
+}
@@ -2,88 +2,224 @@ package middleware
 
 import (
 	"fmt"
-	"github.com/bowoBp/LoanFlow/internal/dto"
+	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	"github.com/bowoBp/LoanFlow/internal/permission"
+	"github.com/bowoBp/LoanFlow/internal/revocation"
+	"github.com/bowoBp/LoanFlow/pkg/environment"
 	"github.com/bowoBp/LoanFlow/utils/helper"
 	"github.com/gin-gonic/gin"
-	"net/http"
+	"log/slog"
 	"slices"
+	"strings"
 	"time"
 )
 
 type Auth struct {
-	jwt helper.JwtInterface
+	jwt      helper.JwtInterface
+	denylist revocation.Denylist
+	apiKeys  Repository.APIKeyRepoInterface
+	// permissions grants the permissions checked by AuthorizePermission
+	permissions permission.Resolver
+	// mfaRoles must complete the login with a second factor to be authorized
+	mfaRoles []string
 }
 
 type AuthInterface interface {
-	Authentication() gin.HandlerFunc
+	Authentication(scopes ...string) gin.HandlerFunc
 	Authorize(roles ...string) gin.HandlerFunc
+	AuthorizePermission(permissions ...string) gin.HandlerFunc
 }
 
-func NewAuth() AuthInterface {
+// NewAuth reads the roles that require two-factor authentication from the
+// comma separated MFA_REQUIRED_ROLES, e.g. "ADMIN,STAFF". Tokens on the
+// denylist are rejected by Authentication, permissions tells
+// AuthorizePermission what each role may do.
+func NewAuth(
+	jwt helper.JwtInterface,
+	denylist revocation.Denylist,
+	apiKeys Repository.APIKeyRepoInterface,
+	permissions permission.Resolver,
+) AuthInterface {
+	var mfaRoles []string
+	for _, role := range strings.Split(environment.NewEnvironment().Get("MFA_REQUIRED_ROLES"), ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			mfaRoles = append(mfaRoles, role)
+		}
+	}
 	return &Auth{
-		jwt: helper.NewJwt(),
+		jwt:         jwt,
+		denylist:    denylist,
+		apiKeys:     apiKeys,
+		permissions: permissions,
+		mfaRoles:    mfaRoles,
 	}
 }
 
-func (receiver Auth) Authentication() gin.HandlerFunc {
+// Authentication accepts a Bearer access token or an API key in the
+// X-API-Key header. An API key is only accepted when it has one of scopes,
+// routes without scopes are for users only.
+func (receiver Auth) Authentication(scopes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		start := time.Now()
+		if key := c.GetHeader(constant.APIKeyHeader); key != "" {
+			receiver.authenticateAPIKey(c, key, scopes)
+			return
+		}
 		stringToken, err := receiver.jwt.VerifyToken(c)
 		if err != nil {
-			response := dto.DefaultErrorResponseWithMessage(err.Error())
-			response.ResponseTime = fmt.Sprint(time.Since(start).Milliseconds(), " ms.")
-			c.JSON(http.StatusUnauthorized, response)
-			c.Abort()
+			abort(c, fmt.Errorf("%w: %w", constant.ErrInvalidToken, err))
 			return
 		}
 
 		jwtPayload, _ := receiver.jwt.ExtractPayloadFromToken(stringToken)
 
+		// Token yang sudah logout atau dicabut admin ditolak sampai kedaluwarsa
+		revoked, err := receiver.denylist.IsRevoked(c.Request.Context(), revocation.Token{
+			ID:       jwtPayload.TokenID,
+			UserID:   jwtPayload.ID,
+			IssuedAt: jwtPayload.IssuedTime(),
+		})
+		if err != nil {
+			abort(c, err)
+			return
+		}
+		if revoked {
+			abort(c, constant.ErrTokenRevoked)
+			return
+		}
+
 		c.Set("id", jwtPayload.ID)
 		c.Set("userName", jwtPayload.UserName)
 		c.Set("createdAt", jwtPayload.CreatedAt)
 		c.Set("userRole", jwtPayload.UserRole)
+		c.Set("mfa", jwtPayload.MFA)
+		setLocale(c, jwtPayload.Locale)
+		c.Set("jti", jwtPayload.TokenID)
+		c.Set("exp", time.Unix(jwtPayload.ExpiresAt, 0))
+		c.Set(principalKey, Principal{
+			Type:     constant.PrincipalUser,
+			UserID:   jwtPayload.ID,
+			UserRole: jwtPayload.UserRole,
+			MFA:      jwtPayload.MFA,
+		})
 		c.Next()
 	}
 }
 
-func (receiver Auth) Authorize(roles ...string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		start := time.Now()
+// authenticateAPIKey puts the user owning the key into the context like an
+// access token would, so handlers act for that user.
+func (receiver Auth) authenticateAPIKey(c *gin.Context, key string, scopes []string) {
+	start := time.Now()
+	apiKey, err := receiver.apiKeys.GetAPIKeyByHash(c.Request.Context(), helper.HashAPIKey(key))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	if apiKey == nil || apiKey.User.ID == 0 || apiKey.RevokedAt != nil ||
+		(apiKey.ExpiresAt != nil && !apiKey.ExpiresAt.After(start)) {
+		abort(c, constant.ErrAPIKey)
+		return
+	}
+	if !slices.ContainsFunc(scopes, func(scope string) bool {
+		return slices.Contains(apiKey.Scopes, scope)
+	}) {
+		abort(c, constant.ErrAPIKeyScope)
+		return
+	}
 
-		// Ambil userRole dari context
-		valUserRole, ok := c.Get("userRole")
-		if !ok {
-			response := dto.DefaultBadRequestResponse()
-			response.Message = "invalid token"
-			response.ResponseTime = fmt.Sprintf("%d ms.", time.Since(start).Milliseconds())
-			c.JSON(http.StatusUnauthorized, response)
-			c.Abort()
-			return
+	// pemakaian terakhir cukup dicatat sekali per interval
+	if apiKey.LastUsedAt == nil || start.Sub(*apiKey.LastUsedAt) >= constant.APIKeyTouchInterval {
+		err = receiver.apiKeys.UpdateAPIKey(c.Request.Context(), apiKey, map[string]any{
+			"last_used_at": start,
+		})
+		if err != nil {
+			slog.ErrorContext(c, "middleware.Auth.Authentication", "error", err)
 		}
+	}
 
-		// Pastikan userRole adalah string
-		userRole, ok := valUserRole.(string)
+	c.Set("id", apiKey.User.ID)
+	c.Set("userName", apiKey.User.Name)
+	c.Set("createdAt", apiKey.User.CreatedAt)
+	c.Set("userRole", apiKey.User.Role)
+	c.Set("mfa", false)
+	setLocale(c, apiKey.User.Locale)
+	c.Set(principalKey, Principal{
+		Type:     constant.PrincipalAPIKey,
+		UserID:   apiKey.User.ID,
+		UserRole: apiKey.User.Role,
+		APIKeyID: apiKey.ID,
+		Scopes:   apiKey.Scopes,
+	})
+	c.Next()
+}
+
+func (receiver Auth) Authorize(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userRole, ok := receiver.userRole(c)
 		if !ok {
-			response := dto.DefaultBadRequestResponse()
-			response.Message = "invalid token"
-			response.ResponseTime = fmt.Sprintf("%d ms.", time.Since(start).Milliseconds())
-			c.JSON(http.StatusUnauthorized, response)
-			c.Abort()
 			return
 		}
 
 		// Periksa apakah role yang digunakan user ada di dalam list roles yang diizinkan
-		if slices.Contains(roles, userRole) {
+		if !slices.Contains(roles, userRole) {
+			abort(c, constant.ErrPermissionDenied)
+			return
+		}
+		if receiver.requireMFA(c, userRole) {
 			c.Next()
+		}
+	}
+}
+
+// AuthorizePermission lets the request through when the role of the user
+// has one of permissions, the grants of each role are edited by admins.
+func (receiver Auth) AuthorizePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userRole, ok := receiver.userRole(c)
+		if !ok {
 			return
 		}
 
-		// Jika tidak ada di dalam roles yang diizinkan
-		response := dto.DefaultBadRequestResponse()
-		response.Message = "Kamu tidak punya akses ke halaman ini"
-		response.ResponseTime = fmt.Sprintf("%d ms.", time.Since(start).Milliseconds())
-		c.JSON(http.StatusUnauthorized, response)
-		c.Abort()
+		for _, perm := range permissions {
+			allowed, err := receiver.permissions.Allowed(c.Request.Context(), userRole, perm)
+			if err != nil {
+				abort(c, err)
+				return
+			}
+			if allowed {
+				if receiver.requireMFA(c, userRole) {
+					c.Next()
+				}
+				return
+			}
+		}
+		abort(c, constant.ErrPermissionDenied)
+	}
+}
+
+// userRole reads the role put into the context by Authentication, the
+// request is aborted when there is none.
+func (receiver Auth) userRole(c *gin.Context) (string, bool) {
+	// Ambil userRole dari context dan pastikan userRole adalah string
+	valUserRole, _ := c.Get("userRole")
+	userRole, ok := valUserRole.(string)
+	if !ok {
+		abort(c, constant.ErrInvalidToken)
+		return "", false
+	}
+	return userRole, true
+}
+
+// requireMFA aborts the request when the role must log in with a second
+// factor and the user did not.
+func (receiver Auth) requireMFA(c *gin.Context, userRole string) bool {
+	// Role yang wajib 2FA harus login dengan kode TOTP atau recovery code,
+	// API key dibuat admin dan dibatasi scope sehingga tidak perlu 2FA
+	principal, _ := PrincipalFrom(c)
+	if mfa := c.GetBool("mfa"); !mfa && principal.Type != constant.PrincipalAPIKey &&
+		slices.Contains(receiver.mfaRoles, userRole) {
+		abort(c, constant.ErrMFARequired)
+		return false
 	}
+	return true
 }
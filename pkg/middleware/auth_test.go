@@ -0,0 +1,387 @@
+package middleware_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/bowoBp/LoanFlow/internal/adapter/mocks"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/i18n"
+	"github.com/bowoBp/LoanFlow/internal/revocation"
+	"github.com/bowoBp/LoanFlow/pkg/middleware"
+	"github.com/bowoBp/LoanFlow/utils/helper"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAuth_Authentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockJwt := new(mocks.JwtInterface)
+	mockDenylist := new(mocks.Denylist)
+	auth := middleware.NewAuth(mockJwt, mockDenylist, new(mocks.APIKeyRepoInterface), new(mocks.Resolver))
+
+	issuedAt := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	payload := helper.JwtPayload{
+		ID:       5,
+		UserRole: constant.RoleBorrower,
+		TokenID:  "jti-1",
+		IssuedAt: float64(issuedAt.UnixMicro()) / 1e6,
+	}
+	token := revocation.Token{ID: "jti-1", UserID: 5, IssuedAt: issuedAt}
+
+	preferred := payload
+	preferred.Locale = i18n.IdID
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		wantCode     int
+		wantBody     string
+		wantLocale   string
+	}{
+		{
+			name: "Success - token not revoked",
+			mockBehavior: func() {
+				mockJwt.On("VerifyToken", mock.Anything).Return("token", nil).Once()
+				mockJwt.On("ExtractPayloadFromToken", "token").Return(payload, nil).Once()
+				mockDenylist.On("IsRevoked", mock.Anything, token).Return(false, nil).Once()
+			},
+			wantCode:   http.StatusOK,
+			wantLocale: i18n.EnUS,
+		},
+		{
+			name: "Success - locale preference of the token",
+			mockBehavior: func() {
+				mockJwt.On("VerifyToken", mock.Anything).Return("token", nil).Once()
+				mockJwt.On("ExtractPayloadFromToken", "token").Return(preferred, nil).Once()
+				mockDenylist.On("IsRevoked", mock.Anything, token).Return(false, nil).Once()
+			},
+			wantCode:   http.StatusOK,
+			wantLocale: i18n.IdID,
+		},
+		{
+			name: "Error - token revoked",
+			mockBehavior: func() {
+				mockJwt.On("VerifyToken", mock.Anything).Return("token", nil).Once()
+				mockJwt.On("ExtractPayloadFromToken", "token").Return(payload, nil).Once()
+				mockDenylist.On("IsRevoked", mock.Anything, token).Return(true, nil).Once()
+			},
+			wantCode: http.StatusUnauthorized,
+			wantBody: constant.ErrTokenRevoked.Error(),
+		},
+		{
+			name: "Error - invalid token",
+			mockBehavior: func() {
+				mockJwt.On("VerifyToken", mock.Anything).Return("", errors.New("token invalid")).Once()
+			},
+			wantCode: http.StatusUnauthorized,
+			wantBody: constant.ErrInvalidToken.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+			router := gin.New()
+			router.Use(middleware.NewErrorRenderer().Handle())
+			router.Use(middleware.NewLocale().Handle())
+			router.GET("/users/current", auth.Authentication(), func(c *gin.Context) {
+				c.String(http.StatusOK, c.GetString("jti"))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/users/current", nil)
+			req.Header.Set("Accept-Language", "en-US")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+			if tt.wantLocale != "" {
+				assert.Equal(t, tt.wantLocale, rec.Header().Get("Content-Language"))
+			}
+			mockJwt.AssertExpectations(t)
+			mockDenylist.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuth_AuthenticationAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("MFA_REQUIRED_ROLES", "STAFF")
+	mockAPIKeyRepo := new(mocks.APIKeyRepoInterface)
+	auth := middleware.NewAuth(new(mocks.JwtInterface), new(mocks.Denylist), mockAPIKeyRepo, new(mocks.Resolver))
+
+	key := "lf_0123456789abcdef"
+	staff := domians.User{ID: 3, Name: "field-agent", Role: constant.RoleStaff}
+	expired := time.Now().Add(-time.Minute)
+	recent := time.Now().Add(-time.Second)
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		scopes       []string
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name: "Success - key with the scope acts for its user",
+			mockBehavior: func() {
+				mockAPIKeyRepo.On("GetAPIKeyByHash", mock.Anything, helper.HashAPIKey(key)).
+					Return(&domians.APIKey{ID: 8, User: staff, Scopes: []string{constant.ScopeLoansApprove}}, nil).Once()
+				mockAPIKeyRepo.On("UpdateAPIKey", mock.Anything, mock.Anything, mock.MatchedBy(func(data map[string]any) bool {
+					_, ok := data["last_used_at"]
+					return ok
+				})).Return(nil).Once()
+			},
+			scopes:   []string{constant.ScopeLoansApprove},
+			wantCode: http.StatusOK,
+			wantBody: "api_key:3:8",
+		},
+		{
+			name: "Success - recent use is not written again",
+			mockBehavior: func() {
+				mockAPIKeyRepo.On("GetAPIKeyByHash", mock.Anything, helper.HashAPIKey(key)).
+					Return(&domians.APIKey{ID: 8, User: staff, Scopes: []string{constant.ScopeLoansApprove}, LastUsedAt: &recent}, nil).Once()
+			},
+			scopes:   []string{constant.ScopeLoansApprove},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "Error - key without the scope",
+			mockBehavior: func() {
+				mockAPIKeyRepo.On("GetAPIKeyByHash", mock.Anything, helper.HashAPIKey(key)).
+					Return(&domians.APIKey{ID: 8, User: staff, Scopes: []string{constant.ScopeLoansRead}}, nil).Once()
+			},
+			scopes:   []string{constant.ScopeLoansApprove},
+			wantCode: http.StatusForbidden,
+			wantBody: constant.ErrAPIKeyScope.Error(),
+		},
+		{
+			name: "Error - route for users only",
+			mockBehavior: func() {
+				mockAPIKeyRepo.On("GetAPIKeyByHash", mock.Anything, helper.HashAPIKey(key)).
+					Return(&domians.APIKey{ID: 8, User: staff, Scopes: constant.APIKeyScopes}, nil).Once()
+			},
+			wantCode: http.StatusForbidden,
+			wantBody: constant.ErrAPIKeyScope.Error(),
+		},
+		{
+			name: "Error - expired key",
+			mockBehavior: func() {
+				mockAPIKeyRepo.On("GetAPIKeyByHash", mock.Anything, helper.HashAPIKey(key)).
+					Return(&domians.APIKey{ID: 8, User: staff, Scopes: constant.APIKeyScopes, ExpiresAt: &expired}, nil).Once()
+			},
+			scopes:   []string{constant.ScopeLoansApprove},
+			wantCode: http.StatusUnauthorized,
+			wantBody: constant.ErrAPIKey.Error(),
+		},
+		{
+			name: "Error - unknown key",
+			mockBehavior: func() {
+				mockAPIKeyRepo.On("GetAPIKeyByHash", mock.Anything, helper.HashAPIKey(key)).Return(nil, nil).Once()
+			},
+			scopes:   []string{constant.ScopeLoansApprove},
+			wantCode: http.StatusUnauthorized,
+			wantBody: constant.ErrAPIKey.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+			router := gin.New()
+			router.Use(middleware.NewErrorRenderer().Handle())
+			router.POST(
+				"/loans/:loanId/approve",
+				auth.Authentication(tt.scopes...),
+				auth.Authorize(constant.RoleStaff),
+				func(c *gin.Context) {
+					principal, _ := middleware.PrincipalFrom(c)
+					c.String(http.StatusOK, fmt.Sprintf("%s:%d:%d", principal.Type, principal.UserID, principal.APIKeyID))
+				},
+			)
+
+			req := httptest.NewRequest(http.MethodPost, "/loans/1/approve", nil)
+			req.Header.Set(constant.APIKeyHeader, key)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+			mockAPIKeyRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuth_Authorize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("MFA_REQUIRED_ROLES", "ADMIN, STAFF")
+	auth := middleware.NewAuth(new(mocks.JwtInterface), new(mocks.Denylist), new(mocks.APIKeyRepoInterface), new(mocks.Resolver))
+
+	tests := []struct {
+		name        string
+		role        string
+		mfa         bool
+		wantCode    int
+		wantBody    string
+		wantHandled bool
+	}{
+		{
+			name:        "Success - staff logged in with a second factor",
+			role:        constant.RoleStaff,
+			mfa:         true,
+			wantCode:    http.StatusOK,
+			wantHandled: true,
+		},
+		{
+			name:     "Error - staff without a second factor",
+			role:     constant.RoleStaff,
+			wantCode: http.StatusForbidden,
+			wantBody: constant.ErrMFARequired.Error(),
+		},
+		{
+			name:        "Success - borrower does not need a second factor",
+			role:        constant.RoleBorrower,
+			wantCode:    http.StatusOK,
+			wantHandled: true,
+		},
+		{
+			name:     "Error - role not allowed",
+			role:     constant.RoleInvestor,
+			mfa:      true,
+			wantCode: http.StatusForbidden,
+			wantBody: constant.ErrPermissionDenied.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handled := false
+			router := gin.New()
+			router.Use(middleware.NewErrorRenderer().Handle())
+			router.POST(
+				"/loans/:loanId/approve",
+				func(c *gin.Context) {
+					c.Set("userRole", tt.role)
+					c.Set("mfa", tt.mfa)
+				},
+				auth.Authorize(constant.RoleStaff, constant.RoleBorrower),
+				func(c *gin.Context) {
+					handled = true
+					c.Status(http.StatusOK)
+				},
+			)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/loans/1/approve", nil))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+			assert.Equal(t, tt.wantHandled, handled)
+		})
+	}
+}
+
+func TestAuth_AuthorizePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("MFA_REQUIRED_ROLES", "ADMIN, STAFF")
+	mockPermissions := new(mocks.Resolver)
+	auth := middleware.NewAuth(new(mocks.JwtInterface), new(mocks.Denylist), new(mocks.APIKeyRepoInterface), mockPermissions)
+	errDB := errors.New("db down")
+
+	tests := []struct {
+		name         string
+		role         string
+		mfa          bool
+		mockBehavior func()
+		wantCode     int
+		wantBody     string
+		wantHandled  bool
+	}{
+		{
+			name: "Success - role has the permission",
+			role: constant.RoleStaff,
+			mfa:  true,
+			mockBehavior: func() {
+				mockPermissions.On("Allowed", mock.Anything, constant.RoleStaff, constant.PermLoanApprove).
+					Return(true, nil).Once()
+			},
+			wantCode:    http.StatusOK,
+			wantHandled: true,
+		},
+		{
+			name: "Success - permission granted to another role",
+			role: constant.RoleInvestor,
+			mockBehavior: func() {
+				mockPermissions.On("Allowed", mock.Anything, constant.RoleInvestor, constant.PermLoanApprove).
+					Return(true, nil).Once()
+			},
+			wantCode:    http.StatusOK,
+			wantHandled: true,
+		},
+		{
+			name: "Error - staff without a second factor",
+			role: constant.RoleStaff,
+			mockBehavior: func() {
+				mockPermissions.On("Allowed", mock.Anything, constant.RoleStaff, constant.PermLoanApprove).
+					Return(true, nil).Once()
+			},
+			wantCode: http.StatusForbidden,
+			wantBody: constant.ErrMFARequired.Error(),
+		},
+		{
+			name: "Error - role does not have the permission",
+			role: constant.RoleBorrower,
+			mockBehavior: func() {
+				mockPermissions.On("Allowed", mock.Anything, constant.RoleBorrower, constant.PermLoanApprove).
+					Return(false, nil).Once()
+			},
+			wantCode: http.StatusForbidden,
+			wantBody: constant.ErrPermissionDenied.Error(),
+		},
+		{
+			name: "Error - permissions cannot be read",
+			role: constant.RoleBorrower,
+			mockBehavior: func() {
+				mockPermissions.On("Allowed", mock.Anything, constant.RoleBorrower, constant.PermLoanApprove).
+					Return(false, errDB).Once()
+			},
+			wantCode: http.StatusInternalServerError,
+			wantBody: constant.CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+			handled := false
+			router := gin.New()
+			router.Use(middleware.NewErrorRenderer().Handle())
+			router.POST(
+				"/loans/:loanId/approve",
+				func(c *gin.Context) {
+					c.Set("userRole", tt.role)
+					c.Set("mfa", tt.mfa)
+				},
+				auth.AuthorizePermission(constant.PermLoanApprove),
+				func(c *gin.Context) {
+					handled = true
+					c.Status(http.StatusOK)
+				},
+			)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/loans/1/approve", nil))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+			assert.Equal(t, tt.wantHandled, handled)
+			mockPermissions.AssertExpectations(t)
+		})
+	}
+}
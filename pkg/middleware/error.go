@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	"github.com/bowoBp/LoanFlow/internal/dto"
+	"github.com/bowoBp/LoanFlow/internal/i18n"
+	"github.com/bowoBp/LoanFlow/pkg/validation"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type ErrorRenderer struct{}
+
+type ErrorRendererInterface interface {
+	Handle() gin.HandlerFunc
+}
+
+var kindStatus = map[constant.Kind]int{
+	constant.KindInvalid:         http.StatusBadRequest,
+	constant.KindUnauthorized:    http.StatusUnauthorized,
+	constant.KindForbidden:       http.StatusForbidden,
+	constant.KindNotFound:        http.StatusNotFound,
+	constant.KindConflict:        http.StatusConflict,
+	constant.KindUnprocessable:   http.StatusUnprocessableEntity,
+	constant.KindTooManyRequests: http.StatusTooManyRequests,
+}
+
+func NewErrorRenderer() ErrorRendererInterface {
+	return &ErrorRenderer{}
+}
+
+// Handle answers the last error attached with c.Error by the middlewares and
+// handlers after it, unless a response has been written already. It must be
+// the first middleware of the server after RequestID.
+func (receiver ErrorRenderer) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		renderError(c, start)
+	}
+}
+
+// renderError writes the response of the last error of c in the locale of
+// the request. Validation errors are answered field by field, a domain
+// error with the status of its kind and its code, any other error is an
+// internal error and is logged since its message is not answered.
+func renderError(c *gin.Context, start time.Time) {
+	if len(c.Errors) == 0 || c.Writer.Written() {
+		return
+	}
+	err := c.Errors.Last().Err
+	locale := i18n.FromContext(c)
+	responseTime := fmt.Sprint(time.Since(start).Milliseconds(), " ms.")
+
+	var fieldErrs validation.Errors
+	if errors.As(err, &fieldErrs) {
+		response := dto.DefaultInvalidInputFormResponse(fieldErrs.Messages(locale))
+		response.MessageTitle = i18n.Translate(locale, i18n.TitleInvalidInput)
+		response.ResponseTime = responseTime
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	status, code := http.StatusInternalServerError, constant.CodeInternal
+	var domainErr *constant.Error
+	if errors.As(err, &domainErr) {
+		code = domainErr.Code
+		if kind, ok := kindStatus[domainErr.Kind]; ok {
+			status = kind
+		}
+	} else {
+		slog.ErrorContext(c, "middleware.ErrorRenderer.Handle", "error", err)
+	}
+	response := dto.NewErrorResponse(code, i18n.Translate(locale, code))
+	response.MessageTitle = i18n.Translate(locale, i18n.TitleError)
+	response.ResponseTime = responseTime
+	c.JSON(status, response)
+}
+
+// abort attaches err and stops the handlers after the middleware, the error
+// is answered by ErrorRenderer.
+func abort(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
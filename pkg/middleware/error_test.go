@@ -0,0 +1,134 @@
+package middleware_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	"github.com/bowoBp/LoanFlow/pkg/middleware"
+	"github.com/bowoBp/LoanFlow/pkg/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorRenderer_Handle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "Not found - domain error keeps its code and message",
+			err:      constant.ErrUserNotFound,
+			wantCode: http.StatusNotFound,
+			wantBody: `"code":"user_not_found"`,
+		},
+		{
+			name:     "Conflict - wrapped domain error is unwrapped",
+			err:      fmt.Errorf("store user: %w", constant.DuplicateEmail),
+			wantCode: http.StatusConflict,
+			wantBody: `"code":"email_duplicate"`,
+		},
+		{
+			name:     "Invalid request - bind error maps to bad request",
+			err:      constant.InvalidRequest(errors.New("amount is required")),
+			wantCode: http.StatusBadRequest,
+			wantBody: `"code":"request_invalid"`,
+		},
+		{
+			name: "Invalid input - validation errors are answered per field",
+			err: constant.InvalidRequest(validation.Errors{
+				{Field: "amount", Key: "validation.min", Args: []any{"amount", "0.01"}},
+			}),
+			wantCode: http.StatusBadRequest,
+			wantBody: `"data":{"amount":["amount must be at least 0.01"]}`,
+		},
+		{
+			name:     "Internal - unknown error hides behind internal_error",
+			err:      errors.New("connection refused"),
+			wantCode: http.StatusInternalServerError,
+			wantBody: `"code":"internal_error"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(middleware.NewErrorRenderer().Handle())
+			router.GET("/", func(c *gin.Context) {
+				_ = c.Error(tt.err)
+			})
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+func TestErrorRenderer_Handle_Written(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.NewErrorRenderer().Handle())
+	router.GET("/", func(c *gin.Context) {
+		c.String(http.StatusAccepted, "done")
+		_ = c.Error(constant.ErrUserNotFound)
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, "done", rec.Body.String())
+}
+
+func TestErrorRenderer_Handle_Locale(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		wantBody       string
+		wantLanguage   string
+	}{
+		{
+			name:         "Default locale without header",
+			wantBody:     `"message":"user not found"`,
+			wantLanguage: "en-US",
+		},
+		{
+			name:           "Accept-Language picks the locale",
+			acceptLanguage: "id-ID,en;q=0.8",
+			wantBody:       `"messageTitle":"Ups, terjadi kesalahan.","message":"pengguna tidak ditemukan"`,
+			wantLanguage:   "id-ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(middleware.NewErrorRenderer().Handle())
+			router.Use(middleware.NewLocale().Handle())
+			router.GET("/", func(c *gin.Context) {
+				_ = c.Error(constant.ErrUserNotFound)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+			assert.Equal(t, tt.wantLanguage, rec.Header().Get("Content-Language"))
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Idempotency struct {
+	store Repository.IdempotencyRepoInterface
+	ttl   time.Duration
+	lease time.Duration
+}
+
+type IdempotencyInterface interface {
+	Handle() gin.HandlerFunc
+	Run(ctx context.Context, interval time.Duration)
+}
+
+func NewIdempotency(store Repository.IdempotencyRepoInterface) IdempotencyInterface {
+	return &Idempotency{
+		store: store,
+		ttl:   constant.IdempotencyKeyTTL,
+		lease: constant.IdempotencyProcessingLease,
+	}
+}
+
+// Handle replays the stored response when a request is retried with the same
+// Idempotency-Key header. Keys are scoped to the user, so it must run after
+// Authentication. Requests without the header are passed through. The key is
+// held for a short lease while the request runs and the response is replayed
+// for the TTL once it is stored.
+func (receiver Idempotency) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		key := c.GetHeader(constant.IdempotencyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abort(c, constant.InvalidRequest(err))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		id, _ := c.Get("id")
+		userID, _ := id.(uint)
+		record := &domians.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			Fingerprint: fingerprint(c.Request.Method, c.Request.URL.Path, body),
+			Status:      constant.IdempotencyProcessing,
+			ExpiresAt:   start.Add(receiver.lease),
+			CreatedAt:   start,
+			UpdatedAt:   start,
+		}
+		existing, err := receiver.reserve(c.Request.Context(), record)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+		switch {
+		case existing == nil:
+		case existing.Fingerprint != record.Fingerprint:
+			abort(c, constant.ErrIdempotencyKeyReused)
+			return
+		case existing.Status != constant.IdempotencyCompleted:
+			abort(c, constant.ErrIdempotencyInProgress)
+			return
+		default:
+			c.Header(constant.IdempotencyReplayedHeader, "true")
+			c.Data(existing.ResponseCode, "application/json; charset=utf-8", []byte(existing.ResponseBody))
+			c.Abort()
+			return
+		}
+
+		// the key is freed when no response is stored for it, also when the
+		// handler panics, so the request can be retried right away
+		stored := false
+		defer func() {
+			if stored {
+				return
+			}
+			err := receiver.store.DeleteKey(context.WithoutCancel(c.Request.Context()), record)
+			if err != nil {
+				slog.ErrorContext(c, "middleware.Idempotency.Handle", "error", err)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+		// the error of the handler is answered now so it is stored with the key
+		renderError(c, start)
+
+		// server errors and conflicts are worth retrying, the key is freed
+		// instead of replaying them
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError || status == http.StatusConflict {
+			return
+		}
+		now := time.Now()
+		err = receiver.store.UpdateKey(
+			c.Request.Context(),
+			&domians.IdempotencyKey{
+				ID: record.ID,
+			},
+			map[string]any{
+				"status":        constant.IdempotencyCompleted,
+				"response_code": status,
+				"response_body": recorder.body.String(),
+				"expires_at":    now.Add(receiver.ttl),
+				"updated_at":    now,
+			},
+		)
+		if err != nil {
+			slog.ErrorContext(c, "middleware.Idempotency.Handle", "error", err)
+			return
+		}
+		stored = true
+	}
+}
+
+// Run deletes the expired keys every interval until ctx is done.
+func (receiver Idempotency) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := receiver.store.DeleteExpiredKeys(ctx, time.Now())
+		if err != nil {
+			slog.ErrorContext(ctx, "middleware.Idempotency.Run", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reserve stores the key for this request, the already stored key is
+// returned when the user used it before. An expired key is replaced.
+func (receiver Idempotency) reserve(
+	ctx context.Context,
+	record *domians.IdempotencyKey,
+) (*domians.IdempotencyKey, error) {
+	reserved, err := receiver.store.ReserveKey(ctx, record)
+	if err != nil || reserved {
+		return nil, err
+	}
+	existing, err := receiver.store.GetKey(ctx, record.UserID, record.Key)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.ExpiresAt.After(record.CreatedAt) {
+		return existing, nil
+	}
+
+	if existing != nil {
+		err = receiver.store.DeleteKey(ctx, existing)
+		if err != nil {
+			return nil, err
+		}
+	}
+	reserved, err = receiver.store.ReserveKey(ctx, record)
+	if err != nil || reserved {
+		return nil, err
+	}
+	// another retry took the key in between
+	existing, err = receiver.store.GetKey(ctx, record.UserID, record.Key)
+	if err == nil && existing == nil {
+		err = constant.ErrIdempotencyInProgress
+	}
+	return existing, err
+}
+
+func fingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body written by the handler.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
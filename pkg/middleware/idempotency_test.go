@@ -0,0 +1,232 @@
+package middleware_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/bowoBp/LoanFlow/internal/adapter/mocks"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/internal/dto"
+	"github.com/bowoBp/LoanFlow/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIdempotency_Handle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uint(3)
+	path := "/loans/1/invest"
+	body := `{"amount":10000}`
+	sum := sha256.Sum256([]byte("POST " + path + "\n" + body))
+	fingerprint := hex.EncodeToString(sum[:])
+	stored := `{"success":true,"messageTitle":"","message":"success invest","responseTime":"1 ms.","data":null}`
+
+	tests := []struct {
+		name         string
+		key          string
+		handlerCode  int
+		panics       bool
+		mockBehavior func(store *mocks.IdempotencyRepoInterface)
+		wantCode     int
+		wantBody     string
+		wantHandled  bool
+		wantReplayed bool
+	}{
+		{
+			name:        "Success - first request stores the response",
+			key:         "key-1",
+			handlerCode: http.StatusOK,
+			mockBehavior: func(store *mocks.IdempotencyRepoInterface) {
+				store.On("ReserveKey", mock.Anything, mock.MatchedBy(func(key *domians.IdempotencyKey) bool {
+					return key.UserID == userID &&
+						key.Key == "key-1" &&
+						key.Fingerprint == fingerprint &&
+						key.Status == constant.IdempotencyProcessing &&
+						time.Until(key.ExpiresAt) <= constant.IdempotencyProcessingLease
+				})).Return(true, nil).Once()
+				store.On("UpdateKey", mock.Anything, mock.Anything, mock.MatchedBy(func(data map[string]any) bool {
+					expiresAt, _ := data["expires_at"].(time.Time)
+					return data["status"] == constant.IdempotencyCompleted &&
+						data["response_code"] == http.StatusOK &&
+						time.Until(expiresAt) > constant.IdempotencyKeyTTL-time.Minute &&
+						strings.Contains(data["response_body"].(string), "success invest")
+				})).Return(nil).Once()
+			},
+			wantCode:    http.StatusOK,
+			wantBody:    "success invest",
+			wantHandled: true,
+		},
+		{
+			name: "Replay - same key and body returns the stored response",
+			key:  "key-1",
+			mockBehavior: func(store *mocks.IdempotencyRepoInterface) {
+				store.On("ReserveKey", mock.Anything, mock.Anything).Return(false, nil).Once()
+				store.On("GetKey", mock.Anything, userID, "key-1").
+					Return(&domians.IdempotencyKey{
+						ID:           1,
+						Fingerprint:  fingerprint,
+						Status:       constant.IdempotencyCompleted,
+						ResponseCode: http.StatusOK,
+						ResponseBody: stored,
+						ExpiresAt:    time.Now().Add(time.Hour),
+					}, nil).Once()
+			},
+			wantCode:     http.StatusOK,
+			wantBody:     stored,
+			wantReplayed: true,
+		},
+		{
+			name: "Error - key reused with a different body",
+			key:  "key-1",
+			mockBehavior: func(store *mocks.IdempotencyRepoInterface) {
+				store.On("ReserveKey", mock.Anything, mock.Anything).Return(false, nil).Once()
+				store.On("GetKey", mock.Anything, userID, "key-1").
+					Return(&domians.IdempotencyKey{
+						ID:          1,
+						Fingerprint: "other",
+						Status:      constant.IdempotencyCompleted,
+						ExpiresAt:   time.Now().Add(time.Hour),
+					}, nil).Once()
+			},
+			wantCode: http.StatusUnprocessableEntity,
+			wantBody: constant.ErrIdempotencyKeyReused.Error(),
+		},
+		{
+			name: "Error - first request still processing",
+			key:  "key-1",
+			mockBehavior: func(store *mocks.IdempotencyRepoInterface) {
+				store.On("ReserveKey", mock.Anything, mock.Anything).Return(false, nil).Once()
+				store.On("GetKey", mock.Anything, userID, "key-1").
+					Return(&domians.IdempotencyKey{
+						ID:          1,
+						Fingerprint: fingerprint,
+						Status:      constant.IdempotencyProcessing,
+						ExpiresAt:   time.Now().Add(time.Hour),
+					}, nil).Once()
+			},
+			wantCode: http.StatusConflict,
+			wantBody: constant.ErrIdempotencyInProgress.Error(),
+		},
+		{
+			name:        "Expired - old key is replaced",
+			key:         "key-1",
+			handlerCode: http.StatusOK,
+			mockBehavior: func(store *mocks.IdempotencyRepoInterface) {
+				expired := &domians.IdempotencyKey{
+					ID:          1,
+					Fingerprint: "other",
+					Status:      constant.IdempotencyCompleted,
+					ExpiresAt:   time.Now().Add(-time.Hour),
+				}
+				store.On("ReserveKey", mock.Anything, mock.Anything).Return(false, nil).Once()
+				store.On("GetKey", mock.Anything, userID, "key-1").Return(expired, nil).Once()
+				store.On("DeleteKey", mock.Anything, expired).Return(nil).Once()
+				store.On("ReserveKey", mock.Anything, mock.Anything).Return(true, nil).Once()
+				store.On("UpdateKey", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			wantCode:    http.StatusOK,
+			wantBody:    "success invest",
+			wantHandled: true,
+		},
+		{
+			name:        "Stale - processing key past its lease is replaced",
+			key:         "key-1",
+			handlerCode: http.StatusOK,
+			mockBehavior: func(store *mocks.IdempotencyRepoInterface) {
+				stale := &domians.IdempotencyKey{
+					ID:          1,
+					Fingerprint: fingerprint,
+					Status:      constant.IdempotencyProcessing,
+					ExpiresAt:   time.Now().Add(-time.Second),
+				}
+				store.On("ReserveKey", mock.Anything, mock.Anything).Return(false, nil).Once()
+				store.On("GetKey", mock.Anything, userID, "key-1").Return(stale, nil).Once()
+				store.On("DeleteKey", mock.Anything, stale).Return(nil).Once()
+				store.On("ReserveKey", mock.Anything, mock.Anything).Return(true, nil).Once()
+				store.On("UpdateKey", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			wantCode:    http.StatusOK,
+			wantBody:    "success invest",
+			wantHandled: true,
+		},
+		{
+			name:   "Panic - key is freed for a retry",
+			key:    "key-3",
+			panics: true,
+			mockBehavior: func(store *mocks.IdempotencyRepoInterface) {
+				store.On("ReserveKey", mock.Anything, mock.Anything).Return(true, nil).Once()
+				store.On("DeleteKey", mock.Anything, mock.MatchedBy(func(key *domians.IdempotencyKey) bool {
+					return key.Key == "key-3"
+				})).Return(nil).Once()
+			},
+			wantCode:    http.StatusInternalServerError,
+			wantHandled: true,
+		},
+		{
+			name:        "Server error - key is freed for a retry",
+			key:         "key-2",
+			handlerCode: http.StatusInternalServerError,
+			mockBehavior: func(store *mocks.IdempotencyRepoInterface) {
+				store.On("ReserveKey", mock.Anything, mock.Anything).Return(true, nil).Once()
+				store.On("DeleteKey", mock.Anything, mock.MatchedBy(func(key *domians.IdempotencyKey) bool {
+					return key.Key == "key-2"
+				})).Return(nil).Once()
+			},
+			wantCode:    http.StatusInternalServerError,
+			wantHandled: true,
+		},
+		{
+			name:         "No header - request passes through",
+			handlerCode:  http.StatusOK,
+			mockBehavior: func(store *mocks.IdempotencyRepoInterface) {},
+			wantCode:     http.StatusOK,
+			wantBody:     "success invest",
+			wantHandled:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := new(mocks.IdempotencyRepoInterface)
+			tt.mockBehavior(store)
+
+			handled := false
+			router := gin.New()
+			router.Use(middleware.NewErrorRenderer().Handle(), gin.RecoveryWithWriter(io.Discard))
+			router.POST(
+				"/loans/:loanId/invest",
+				func(c *gin.Context) {
+					c.Set("id", userID)
+				},
+				middleware.NewIdempotency(store).Handle(),
+				func(c *gin.Context) {
+					handled = true
+					if tt.panics {
+						panic("handler failed")
+					}
+					c.JSON(tt.handlerCode, dto.NewSuccessResponse(nil, "success invest", "1 ms."))
+				},
+			)
+
+			req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+			if tt.key != "" {
+				req.Header.Set(constant.IdempotencyHeader, tt.key)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+			assert.Equal(t, tt.wantHandled, handled)
+			assert.Equal(t, tt.wantReplayed, rec.Header().Get(constant.IdempotencyReplayedHeader) == "true")
+			store.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"github.com/bowoBp/LoanFlow/internal/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+type Locale struct{}
+
+type LocaleInterface interface {
+	Handle() gin.HandlerFunc
+}
+
+func NewLocale() LocaleInterface {
+	return &Locale{}
+}
+
+// Handle negotiates the locale of the request from the Accept-Language
+// header, Authentication replaces it with the preference of the user when
+// the user has one.
+func (receiver Locale) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		setLocale(c, i18n.Negotiate(c.GetHeader("Accept-Language")))
+		c.Next()
+	}
+}
+
+// setLocale stores the locale the responses of c are written in, an
+// unsupported locale is ignored.
+func setLocale(c *gin.Context, locale string) {
+	if !i18n.Supported(locale) {
+		return
+	}
+	c.Set(i18n.LocaleKey, locale)
+	c.Header("Content-Language", locale)
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+const principalKey = "principal"
+
+// Principal is the caller of a request: a user with an access token, or a
+// partner system with an API key acting for the user that owns the key.
+type Principal struct {
+	Type     string // constant.PrincipalUser or constant.PrincipalAPIKey
+	UserID   uint
+	UserRole string
+	APIKeyID uint
+	Scopes   []string
+	MFA      bool
+}
+
+// PrincipalFrom returns the principal put into the context by
+// Authentication.
+func PrincipalFrom(c *gin.Context) (Principal, bool) {
+	value, ok := c.Get(principalKey)
+	if !ok {
+		return Principal{}, false
+	}
+	principal, ok := value.(Principal)
+	return principal, ok
+}
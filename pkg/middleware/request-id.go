@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/bowoBp/LoanFlow/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"regexp"
+	"time"
+)
+
+// RequestIDHeader carries the ID of a request, it is read from the request
+// when the client sends one and always written to the response.
+const RequestIDHeader = "X-Request-ID"
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+type RequestID struct{}
+
+type RequestIDInterface interface {
+	Handle() gin.HandlerFunc
+}
+
+func NewRequestID() RequestIDInterface {
+	return &RequestID{}
+}
+
+// Handle gives the request an ID, kept from the X-Request-ID header when it
+// is well formed, puts it in the context of the request for the logger and
+// logs the request once it is answered. It must run before ErrorRenderer.
+func (receiver RequestID) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+		c.Set(logger.RequestIDKey, requestID)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), requestID))
+		c.Header(RequestIDHeader, requestID)
+
+		c.Next()
+
+		slog.InfoContext(c, "request",
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", c.Writer.Status()),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+		)
+	}
+}
+
+func newRequestID() string {
+	raw := make([]byte, 16)
+	_, _ = rand.Read(raw)
+	return hex.EncodeToString(raw)
+}
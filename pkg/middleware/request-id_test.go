@@ -0,0 +1,68 @@
+package middleware_test
+
+import (
+	"github.com/bowoBp/LoanFlow/pkg/logger"
+	"github.com/bowoBp/LoanFlow/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRequestID_Handle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	generated := regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+	tests := []struct {
+		name          string
+		header        string
+		wantRequestID string
+	}{
+		{
+			name:          "Success - ID of the client is kept",
+			header:        "client-req_1.a",
+			wantRequestID: "client-req_1.a",
+		},
+		{
+			name: "Success - ID is generated without header",
+		},
+		{
+			name:   "Success - malformed ID is replaced",
+			header: "bad id\n",
+		},
+		{
+			name:   "Success - too long ID is replaced",
+			header: strings.Repeat("a", 65),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inContext, inRequest string
+			router := gin.New()
+			router.Use(middleware.NewRequestID().Handle())
+			router.GET("/", func(c *gin.Context) {
+				inContext = logger.RequestID(c)
+				inRequest = logger.RequestID(c.Request.Context())
+				c.Status(http.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(middleware.RequestIDHeader, tt.header)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			requestID := rec.Header().Get(middleware.RequestIDHeader)
+			if tt.wantRequestID != "" {
+				assert.Equal(t, tt.wantRequestID, requestID)
+			} else {
+				assert.Regexp(t, generated, requestID)
+			}
+			assert.Equal(t, requestID, inContext)
+			assert.Equal(t, requestID, inRequest)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	Repository "github.com/bowoBp/LoanFlow/internal/adapter/repository"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	"github.com/gin-gonic/gin"
+)
+
+type EmailVerification struct {
+	users Repository.UserRepoInterface
+}
+
+type EmailVerificationInterface interface {
+	Handle() gin.HandlerFunc
+}
+
+func NewEmailVerification(users Repository.UserRepoInterface) EmailVerificationInterface {
+	return &EmailVerification{
+		users: users,
+	}
+}
+
+// Handle rejects users whose email is not verified yet. The user is read
+// from the database so a verification applies without a new token, it must
+// run after Authentication.
+func (receiver EmailVerification) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, _ := c.Get("id")
+		userID, _ := id.(uint)
+		user, err := receiver.users.GetUserByID(c.Request.Context(), userID)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+		if user == nil {
+			abort(c, constant.ErrInvalidToken)
+			return
+		}
+		if user.EmailVerifiedAt == nil {
+			abort(c, constant.ErrEmailNotVerified)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,77 @@
+package middleware_test
+
+import (
+	"github.com/bowoBp/LoanFlow/internal/adapter/mocks"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	domians "github.com/bowoBp/LoanFlow/internal/domain"
+	"github.com/bowoBp/LoanFlow/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEmailVerification_Handle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uint(3)
+	verifiedAt := time.Now()
+
+	tests := []struct {
+		name        string
+		user        *domians.User
+		wantCode    int
+		wantBody    string
+		wantHandled bool
+	}{
+		{
+			name:        "Success - verified user passes through",
+			user:        &domians.User{ID: userID, EmailVerifiedAt: &verifiedAt},
+			wantCode:    http.StatusOK,
+			wantHandled: true,
+		},
+		{
+			name:     "Error - email not verified",
+			user:     &domians.User{ID: userID},
+			wantCode: http.StatusForbidden,
+			wantBody: constant.ErrEmailNotVerified.Error(),
+		},
+		{
+			name:     "Error - user deleted",
+			wantCode: http.StatusUnauthorized,
+			wantBody: constant.ErrInvalidToken.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := new(mocks.UserRepoInterface)
+			users.On("GetUserByID", mock.Anything, userID).Return(tt.user, nil).Once()
+
+			handled := false
+			router := gin.New()
+			router.Use(middleware.NewErrorRenderer().Handle())
+			router.POST(
+				"/loans",
+				func(c *gin.Context) {
+					c.Set("id", userID)
+				},
+				middleware.NewEmailVerification(users).Handle(),
+				func(c *gin.Context) {
+					handled = true
+					c.Status(http.StatusOK)
+				},
+			)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/loans", nil))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+			assert.Equal(t, tt.wantHandled, handled)
+			users.AssertExpectations(t)
+		})
+	}
+}
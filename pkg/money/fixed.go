@@ -0,0 +1,85 @@
+package money
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// formatFixed writes an integer of hundredths as a decimal with two digits.
+func formatFixed(v int64) string {
+	sign := ""
+	u := uint64(v)
+	if v < 0 {
+		sign = "-"
+		u = uint64(-v)
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, u/100, u%100)
+}
+
+// parseFixed reads a decimal with at most two digits into hundredths.
+func parseFixed(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" {
+		return 0, ErrFormat
+	}
+	if len(strings.TrimRight(fraction, "0")) > 2 {
+		return 0, ErrPrecision
+	}
+	fraction = (strings.TrimRight(fraction, "0") + "00")[:2]
+	if whole == "" {
+		whole = "0"
+	}
+	if strings.ContainsAny(whole+fraction, "+-eE") {
+		return 0, ErrFormat
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, ErrFormat
+	}
+	hundredths, err := strconv.ParseInt(fraction, 10, 64)
+	if err != nil || units > (math.MaxInt64-hundredths)/100 {
+		return 0, ErrFormat
+	}
+	v := units*100 + hundredths
+	if negative {
+		v = -v
+	}
+	return v, nil
+}
+
+// unmarshalFixed reads a JSON number, a JSON string holding one or null.
+func unmarshalFixed(data []byte) (int64, error) {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return 0, nil
+	}
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		data = data[1 : len(data)-1]
+	}
+	return parseFixed(string(data))
+}
+
+// scanFixed reads a DECIMAL column, floats are rounded to two decimals.
+func scanFixed(src any) (int64, error) {
+	switch v := src.(type) {
+	case nil:
+		return 0, nil
+	case string:
+		return parseFixed(v)
+	case []byte:
+		return parseFixed(string(v))
+	case int64:
+		return v * 100, nil
+	case float64:
+		return int64(math.Round(v * 100)), nil
+	}
+	return 0, fmt.Errorf("money: cannot scan %T", src)
+}
@@ -0,0 +1,278 @@
+// Package money keeps amounts as exact decimals with two digits, the scale
+// of the DECIMAL(20,2) columns, instead of float64.
+//
+// Rounding: values with more decimals are rounded half away from zero.
+// Splitting an amount into equal parts puts the leftover cents on the last
+// part; allocating an amount by weights (e.g. to investors) gives every part
+// its share rounded down and hands the leftover cents out one by one to the
+// parts with the largest dropped fraction, the earlier part wins a tie.
+//
+// Currency: an amount carries the currency given with In, the amounts of a
+// loan or a ledger account are put in its currency when they are read.
+// An amount without currency, such as a constant or an amount of a request,
+// takes the currency of the amount it is added to or compared with. Mixing
+// two currencies panics with ErrCurrencyMismatch like an index out of range
+// would, callers check the currencies of their input first and answer with
+// an error of their own.
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+var (
+	ErrFormat    = errors.New("money: invalid decimal")
+	ErrPrecision = errors.New("money: more than two decimals")
+
+	ErrCurrencyMismatch = errors.New("money: currencies differ")
+)
+
+// Currency is an ISO 4217 code.
+type Currency string
+
+const (
+	IDR Currency = "IDR"
+
+	DefaultCurrency = IDR
+)
+
+// Currencies lists the currencies a loan can be created in.
+var Currencies = []Currency{IDR}
+
+// Valid reports whether c is one of Currencies.
+func (c Currency) Valid() bool {
+	return slices.Contains(Currencies, c)
+}
+
+// Money is an amount in cents of a currency. The zero value is zero without
+// currency.
+type Money struct {
+	cents    int64
+	currency Currency
+}
+
+var Zero Money
+
+// New returns units whole currency units.
+func New(units int64) Money {
+	return Money{cents: units * 100}
+}
+
+func FromCents(cents int64) Money {
+	return Money{cents: cents}
+}
+
+// Parse reads a plain decimal such as "1500", "-12.5" or "0.01", an error is
+// returned for more than two decimals instead of rounding them away.
+func Parse(s string) (Money, error) {
+	cents, err := parseFixed(s)
+	return Money{cents: cents}, err
+}
+
+// MustParse is Parse for constants, it panics on an invalid value.
+func MustParse(s string) Money {
+	m, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func (m Money) Cents() int64 {
+	return m.cents
+}
+
+// In returns m in currency c, an empty c leaves m as it is. It panics when
+// m already holds another currency.
+func (m Money) In(c Currency) Money {
+	return Money{cents: m.cents, currency: m.common(Money{currency: c})}
+}
+
+// Currency is the currency of m, empty when m has none.
+func (m Money) Currency() Currency {
+	return m.currency
+}
+
+// SameCurrency reports whether m and o can be combined, they hold the same
+// currency or one of them has none.
+func (m Money) SameCurrency(o Money) bool {
+	return m.currency == "" || o.currency == "" || m.currency == o.currency
+}
+
+// common is the currency of the result of m and o, it panics when they
+// hold different currencies.
+func (m Money) common(o Money) Currency {
+	if !m.SameCurrency(o) {
+		panic(fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency, o.currency))
+	}
+	if m.currency == "" {
+		return o.currency
+	}
+	return m.currency
+}
+
+func (m Money) Add(o Money) Money {
+	return Money{cents: m.cents + o.cents, currency: m.common(o)}
+}
+
+func (m Money) Sub(o Money) Money {
+	return Money{cents: m.cents - o.cents, currency: m.common(o)}
+}
+
+func (m Money) Mul(n int64) Money {
+	return Money{cents: m.cents * n, currency: m.currency}
+}
+
+// Cmp returns -1, 0 or +1 when m is less than, equal to or greater than o.
+func (m Money) Cmp(o Money) int {
+	m.common(o)
+	switch {
+	case m.cents < o.cents:
+		return -1
+	case m.cents > o.cents:
+		return 1
+	}
+	return 0
+}
+
+func (m Money) IsZero() bool {
+	return m.cents == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.cents > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.cents < 0
+}
+
+// Min returns the smaller of m and o.
+func (m Money) Min(o Money) Money {
+	currency := m.common(o)
+	if o.cents < m.cents {
+		return o.In(currency)
+	}
+	return m.In(currency)
+}
+
+// Ratio returns m / total, 0 when total is zero. It is meant for display
+// values such as a funded percentage, never for amounts.
+func (m Money) Ratio(total Money) float64 {
+	m.common(total)
+	if total.cents == 0 {
+		return 0
+	}
+	return float64(m.cents) / float64(total.cents)
+}
+
+// Split cuts m into n equal parts, the leftover cents go to the last part.
+func (m Money) Split(n int) []Money {
+	parts := make([]Money, n)
+	if n == 0 {
+		return parts
+	}
+	base := m.cents / int64(n)
+	for i := range parts {
+		parts[i] = Money{cents: base, currency: m.currency}
+	}
+	parts[n-1].cents += m.cents - base*int64(n)
+	return parts
+}
+
+// Allocate shares m by weights using the largest remainder method, the
+// parts always add up to m. Every part is zero when the weights add up to
+// zero or less. The weights must be in the currency of m.
+func (m Money) Allocate(weights []Money) []Money {
+	var (
+		parts     = make([]Money, len(weights))
+		remainder = make([]*big.Int, len(weights))
+		total     = new(big.Int)
+		currency  = m.currency
+	)
+	for _, weight := range weights {
+		currency = Money{currency: currency}.common(weight)
+		total.Add(total, big.NewInt(weight.cents))
+	}
+	for i := range parts {
+		parts[i].currency = currency
+	}
+	if total.Sign() <= 0 {
+		return parts
+	}
+
+	allocated := int64(0)
+	for i, weight := range weights {
+		quo, rem := new(big.Int).QuoRem(
+			new(big.Int).Mul(big.NewInt(m.cents), big.NewInt(weight.cents)),
+			total,
+			new(big.Int),
+		)
+		parts[i].cents = quo.Int64()
+		remainder[i] = rem
+		allocated += parts[i].cents
+	}
+
+	// truncation leaves fewer leftover cents than parts, one pass is enough
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return remainder[b].CmpAbs(remainder[a])
+	})
+	step := int64(1)
+	if m.cents < 0 {
+		step = -1
+	}
+	left := m.cents - allocated
+	for _, i := range order {
+		if left == 0 {
+			break
+		}
+		parts[i].cents += step
+		left -= step
+	}
+	return parts
+}
+
+// String formats m with exactly two decimals, e.g. "1500.00".
+func (m Money) String() string {
+	return formatFixed(m.cents)
+}
+
+// MarshalJSON writes m as a JSON number with two decimals.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(formatFixed(m.cents)), nil
+}
+
+// UnmarshalJSON reads a JSON number or a string holding one, without
+// currency.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	cents, err := unmarshalFixed(data)
+	if err != nil {
+		return err
+	}
+	*m = Money{cents: cents}
+	return nil
+}
+
+// Value stores m in a DECIMAL column.
+func (m Money) Value() (driver.Value, error) {
+	return formatFixed(m.cents), nil
+}
+
+// Scan reads m from a DECIMAL column without currency, the models put
+// their amounts in their currency once read.
+func (m *Money) Scan(src any) error {
+	cents, err := scanFixed(src)
+	if err != nil {
+		return err
+	}
+	*m = Money{cents: cents}
+	return nil
+}
@@ -0,0 +1,235 @@
+package money_test
+
+import (
+	"encoding/json"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    money.Money
+		wantErr error
+	}{
+		{in: "1500", want: money.New(1500)},
+		{in: "1500.5", want: money.FromCents(150050)},
+		{in: "-12.05", want: money.FromCents(-1205)},
+		{in: "0.01", want: money.FromCents(1)},
+		{in: ".5", want: money.FromCents(50)},
+		{in: "10.500", want: money.FromCents(1050)},
+		{in: "10.005", wantErr: money.ErrPrecision},
+		{in: "", wantErr: money.ErrFormat},
+		{in: "abc", wantErr: money.ErrFormat},
+		{in: "1e5", wantErr: money.ErrFormat},
+		{in: "99999999999999999999", wantErr: money.ErrFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := money.Parse(tt.in)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "1500.00", money.New(1500).String())
+	assert.Equal(t, "0.05", money.FromCents(5).String())
+	assert.Equal(t, "-0.05", money.FromCents(-5).String())
+	assert.Equal(t, "-12.30", money.MustParse("-12.3").String())
+}
+
+func TestMoney_JSON(t *testing.T) {
+	type payload struct {
+		Amount money.Money `json:"amount"`
+	}
+
+	data, err := json.Marshal(payload{Amount: money.MustParse("1234.5")})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"amount":1234.50}`, string(data))
+
+	tests := []struct {
+		name    string
+		in      string
+		want    money.Money
+		wantErr bool
+	}{
+		{name: "number", in: `{"amount":1234.5}`, want: money.MustParse("1234.5")},
+		{name: "string", in: `{"amount":"0.10"}`, want: money.FromCents(10)},
+		{name: "null", in: `{"amount":null}`, want: money.Zero},
+		{name: "too precise", in: `{"amount":0.001}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got payload
+			err := json.Unmarshal([]byte(tt.in), &got)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.Amount)
+		})
+	}
+}
+
+func TestMoney_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     any
+		want    money.Money
+		wantErr bool
+	}{
+		{name: "string", src: "1000.25", want: money.FromCents(100025)},
+		{name: "bytes", src: []byte("-3.10"), want: money.FromCents(-310)},
+		{name: "int64", src: int64(7), want: money.New(7)},
+		{name: "float64", src: 0.1 + 0.2, want: money.FromCents(30)},
+		{name: "nil", src: nil, want: money.Zero},
+		{name: "unsupported", src: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got money.Money
+			err := got.Scan(tt.src)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	value, err := money.MustParse("42.1").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "42.10", value)
+}
+
+func TestRate_Of(t *testing.T) {
+	tests := []struct {
+		name   string
+		rate   string
+		amount money.Money
+		want   money.Money
+	}{
+		{name: "exact", rate: "5.5", amount: money.New(1000000), want: money.New(55000)},
+		{name: "half rounds up", rate: "12.5", amount: money.FromCents(4), want: money.FromCents(1)},
+		{name: "half away from zero", rate: "50", amount: money.FromCents(1), want: money.FromCents(1)},
+		{name: "negative half away from zero", rate: "50", amount: money.FromCents(-1), want: money.FromCents(-1)},
+		{name: "below half rounds down", rate: "33.33", amount: money.FromCents(100), want: money.FromCents(33)},
+		{name: "zero rate", rate: "0", amount: money.New(500), want: money.Zero},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, money.MustParseRate(tt.rate).Of(tt.amount))
+		})
+	}
+}
+
+func TestMoney_Split(t *testing.T) {
+	parts := money.New(100).Split(3)
+
+	assert.Equal(t, []money.Money{
+		money.MustParse("33.33"),
+		money.MustParse("33.33"),
+		money.MustParse("33.34"),
+	}, parts)
+	assert.Empty(t, money.New(100).Split(0))
+}
+
+func TestMoney_Allocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  money.Money
+		weights []money.Money
+		want    []money.Money
+	}{
+		{
+			name:    "largest remainder gets the leftover cent",
+			amount:  money.New(100000),
+			weights: []money.Money{money.New(100000), money.New(200000)},
+			want:    []money.Money{money.MustParse("33333.33"), money.MustParse("66666.67")},
+		},
+		{
+			name:    "earlier part wins a tie",
+			amount:  money.FromCents(100),
+			weights: []money.Money{money.New(1), money.New(1), money.New(1)},
+			want:    []money.Money{money.FromCents(34), money.FromCents(33), money.FromCents(33)},
+		},
+		{
+			name:    "negative amount",
+			amount:  money.FromCents(-100),
+			weights: []money.Money{money.New(1), money.New(1), money.New(1)},
+			want:    []money.Money{money.FromCents(-34), money.FromCents(-33), money.FromCents(-33)},
+		},
+		{
+			name:    "zero weight gets nothing",
+			amount:  money.FromCents(101),
+			weights: []money.Money{money.New(1), money.Zero, money.New(1)},
+			want:    []money.Money{money.FromCents(51), money.Zero, money.FromCents(50)},
+		},
+		{
+			name:    "no weights",
+			amount:  money.New(10),
+			weights: []money.Money{money.Zero, money.Zero},
+			want:    []money.Money{money.Zero, money.Zero},
+		},
+		{
+			name:    "parts in the currency of the amount",
+			amount:  money.New(10).In(money.IDR),
+			weights: []money.Money{money.New(1), money.New(1).In(money.IDR)},
+			want:    []money.Money{money.New(5).In(money.IDR), money.New(5).In(money.IDR)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.amount.Allocate(tt.weights)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMoney_Currency(t *testing.T) {
+	idr := money.New(100).In(money.IDR)
+	usd := money.New(100).In("USD")
+
+	// an amount without currency takes the one of the amount it meets
+	assert.Equal(t, money.New(150).In(money.IDR), money.New(50).Add(idr))
+	assert.Equal(t, money.New(50).In(money.IDR), idr.Sub(money.New(50)))
+	assert.Equal(t, money.New(50).In(money.IDR), idr.Min(money.New(50)))
+	assert.Equal(t, 0, idr.Cmp(money.New(100)))
+	assert.Equal(t, money.IDR, idr.Mul(2).Currency())
+	assert.Equal(t, money.IDR, money.MustParseRate("10").Of(idr).Currency())
+	assert.Equal(t, idr, idr.In(""))
+	assert.True(t, idr.SameCurrency(money.New(1)))
+	assert.False(t, idr.SameCurrency(usd))
+
+	mismatch := map[string]func(){
+		"Add":      func() { idr.Add(usd) },
+		"Sub":      func() { idr.Sub(usd) },
+		"Cmp":      func() { idr.Cmp(usd) },
+		"Min":      func() { idr.Min(usd) },
+		"Ratio":    func() { idr.Ratio(usd) },
+		"In":       func() { idr.In("USD") },
+		"Allocate": func() { idr.Allocate([]money.Money{money.New(1), usd}) },
+	}
+	for name, fn := range mismatch {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				err, _ := recover().(error)
+				assert.ErrorIs(t, err, money.ErrCurrencyMismatch)
+			}()
+			fn()
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package money
+
+import (
+	"database/sql/driver"
+	"math/big"
+)
+
+// Rate is a percentage with two decimals, e.g. 12.5 for 12.5%.
+type Rate struct {
+	hundredths int64
+}
+
+// ParseRate reads a percentage such as "12.5", see Parse.
+func ParseRate(s string) (Rate, error) {
+	hundredths, err := parseFixed(s)
+	return Rate{hundredths: hundredths}, err
+}
+
+// MustParseRate is ParseRate for constants, it panics on an invalid value.
+func MustParseRate(s string) Rate {
+	r, err := ParseRate(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func (r Rate) Cmp(o Rate) int {
+	switch {
+	case r.hundredths < o.hundredths:
+		return -1
+	case r.hundredths > o.hundredths:
+		return 1
+	}
+	return 0
+}
+
+func (r Rate) IsNegative() bool {
+	return r.hundredths < 0
+}
+
+// Of returns r percent of m, rounded half away from zero to the cent.
+func (r Rate) Of(m Money) Money {
+	product := new(big.Int).Mul(big.NewInt(m.cents), big.NewInt(r.hundredths))
+	quo, rem := new(big.Int).QuoRem(product, big.NewInt(10000), new(big.Int))
+	// |rem| >= 5000 rounds away from zero
+	if new(big.Int).Abs(rem).Cmp(big.NewInt(5000)) >= 0 {
+		quo.Add(quo, big.NewInt(int64(product.Sign())))
+	}
+	return Money{cents: quo.Int64(), currency: m.currency}
+}
+
+func (r Rate) String() string {
+	return formatFixed(r.hundredths)
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(formatFixed(r.hundredths)), nil
+}
+
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	hundredths, err := unmarshalFixed(data)
+	if err != nil {
+		return err
+	}
+	r.hundredths = hundredths
+	return nil
+}
+
+func (r Rate) Value() (driver.Value, error) {
+	return formatFixed(r.hundredths), nil
+}
+
+func (r *Rate) Scan(src any) error {
+	hundredths, err := scanFixed(src)
+	if err != nil {
+		return err
+	}
+	r.hundredths = hundredths
+	return nil
+}
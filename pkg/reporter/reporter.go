@@ -0,0 +1,58 @@
+package reporter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+)
+
+type (
+	// Reporter receives the errors nobody answers for, the panics recovered
+	// by the usecases and the transactions failing to commit or roll back.
+	Reporter interface {
+		Report(ctx context.Context, err error)
+		// Recover reports a value returned by recover() and returns it as an
+		// error, so the transaction of the usecase is rolled back.
+		Recover(ctx context.Context, recovered any) error
+	}
+
+	// PanicError is a recovered panic with the stack it was raised from.
+	PanicError struct {
+		Value any
+		Stack []byte
+	}
+
+	// LogReporter is the local sink, it writes the reports to a logger.
+	LogReporter struct {
+		logger *slog.Logger
+	}
+)
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the value of the panic when it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+func NewLogReporter(logger *slog.Logger) LogReporter {
+	return LogReporter{logger: logger}
+}
+
+func (r LogReporter) Report(ctx context.Context, err error) {
+	attrs := []any{slog.String("error", err.Error())}
+	if panicErr, ok := err.(*PanicError); ok {
+		attrs = append(attrs, slog.String("stack", string(panicErr.Stack)))
+	}
+	r.logger.ErrorContext(ctx, "error reported", attrs...)
+}
+
+func (r LogReporter) Recover(ctx context.Context, recovered any) error {
+	err := &PanicError{Value: recovered, Stack: debug.Stack()}
+	r.Report(ctx, err)
+	return err
+}
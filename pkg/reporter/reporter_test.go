@@ -0,0 +1,39 @@
+package reporter_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/bowoBp/LoanFlow/pkg/logger"
+	"github.com/bowoBp/LoanFlow/pkg/reporter"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLogReporter_Report(t *testing.T) {
+	var buf bytes.Buffer
+	r := reporter.NewLogReporter(logger.New(&buf, "", logger.FormatJSON))
+
+	r.Report(logger.WithRequestID(context.Background(), "req-1"), errors.New("commit failed"))
+
+	assert.Contains(t, buf.String(), `"level":"ERROR"`)
+	assert.Contains(t, buf.String(), `"error":"commit failed"`)
+	assert.Contains(t, buf.String(), `"request_id":"req-1"`)
+	assert.NotContains(t, buf.String(), `"stack"`)
+}
+
+func TestLogReporter_Recover(t *testing.T) {
+	var buf bytes.Buffer
+	r := reporter.NewLogReporter(logger.New(&buf, "", logger.FormatJSON))
+	cause := errors.New("nil repository")
+
+	err := r.Recover(context.Background(), cause)
+
+	var panicErr *reporter.PanicError
+	assert.ErrorAs(t, err, &panicErr)
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "panic: nil repository", err.Error())
+	assert.NotEmpty(t, panicErr.Stack)
+	assert.Contains(t, buf.String(), `"error":"panic: nil repository"`)
+	assert.Contains(t, buf.String(), `"stack":"goroutine`)
+}
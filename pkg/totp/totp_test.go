@@ -0,0 +1,102 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"github.com/bowoBp/LoanFlow/pkg/totp"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// secret of the RFC 6238 test vectors, "12345678901234567890" in base32
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// the RFC lists 8 digits codes, the last 6 digits are the 6 digits code
+	tests := []struct {
+		name string
+		unix int64
+		want string
+	}{
+		{name: "RFC 6238 - 59", unix: 59, want: "287082"},
+		{name: "RFC 6238 - 1111111109", unix: 1111111109, want: "081804"},
+		{name: "RFC 6238 - 1234567890", unix: 1234567890, want: "005924"},
+		{name: "RFC 6238 - 2000000000", unix: 2000000000, want: "279037"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := totp.Code(rfcSecret, totp.Step(time.Unix(tt.unix, 0)))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	current := totp.Step(now)
+
+	tests := []struct {
+		name     string
+		code     func() string
+		wantOK   bool
+		wantStep int64
+	}{
+		{
+			name: "Success - current step",
+			code: func() string {
+				code, _ := totp.Code(rfcSecret, current)
+				return code
+			},
+			wantOK:   true,
+			wantStep: current,
+		},
+		{
+			name: "Success - previous step for a slow clock",
+			code: func() string {
+				code, _ := totp.Code(rfcSecret, current-1)
+				return code
+			},
+			wantOK:   true,
+			wantStep: current - 1,
+		},
+		{
+			name: "Error - code of two steps ago",
+			code: func() string {
+				code, _ := totp.Code(rfcSecret, current-2)
+				return code
+			},
+		},
+		{
+			name: "Error - wrong length",
+			code: func() string { return "12345" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok, err := totp.Validate(rfcSecret, tt.code(), now)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.wantStep, step)
+			}
+		})
+	}
+}
+
+func TestURI(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	uri, err := url.Parse(totp.URI("LoanFlow", "admin@loanflow.id", secret))
+	assert.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/LoanFlow:admin@loanflow.id", uri.Path)
+	assert.Equal(t, secret, uri.Query().Get("secret"))
+	assert.Equal(t, "LoanFlow", uri.Query().Get("issuer"))
+}
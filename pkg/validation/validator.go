@@ -0,0 +1,228 @@
+package validation
+
+import (
+	"errors"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	"github.com/bowoBp/LoanFlow/internal/i18n"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+type (
+	// Violation is a rule broken by Field, Key and Args are the message of
+	// the rule in the message catalogue.
+	Violation struct {
+		Field string
+		Key   string
+		Args  []any
+	}
+
+	// Errors lists the violations of a request, it is answered with
+	// dto.DefaultInvalidInputFormResponse.
+	Errors []Violation
+
+	// Validator checks the `validate` tags of the request DTOs. Besides the
+	// rules of go-playground/validator it knows the lists of lists and:
+	//
+	//	phone            a phone number of 8 to 15 digits, optionally with +
+	//	currency         one of money.Currencies
+	//	amount_min=0.01  a money.Money of at least the amount
+	//	amount_max=100   a money.Money of at most the amount
+	//	rate_min=0       a money.Rate of at least the percentage
+	//	rate_max=100     a money.Rate of at most the percentage
+	Validator struct {
+		validate *validator.Validate
+	}
+)
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9]{8,15}$`)
+
+// lists are the rules checking that the value is one of the values the code
+// knows, the tag is the name of the list, e.g. `validate:"required,role"`.
+var lists = map[string][]string{
+	"role":          constant.Roles,
+	"public_role":   constant.PublicRoles,
+	"api_key_scope": constant.APIKeyScopes,
+	"reject_reason": constant.RejectReasonCodes,
+	"cancel_reason": constant.CancelReasonCodes,
+	"locale":        i18n.Locales,
+}
+
+// New builds the validator, it is installed as gin's binding.Validator so
+// every ShouldBind of a request handler is checked.
+func New() *Validator {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	validate.SetTagName("validate")
+	validate.RegisterTagNameFunc(jsonName)
+
+	rules := map[string]validator.Func{
+		"phone":      phone,
+		"currency":   currency,
+		"amount_min": amountMin,
+		"amount_max": amountMax,
+		"rate_min":   rateMin,
+		"rate_max":   rateMax,
+	}
+	for tag, values := range lists {
+		rules[tag] = oneOf(values)
+	}
+	for tag, rule := range rules {
+		if err := validate.RegisterValidation(tag, rule); err != nil {
+			panic(err)
+		}
+	}
+	return &Validator{validate: validate}
+}
+
+// ValidateStruct returns Errors when a field of obj breaks its rules.
+func (v *Validator) ValidateStruct(obj any) error {
+	value := reflect.ValueOf(obj)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	err := v.validate.Struct(value.Interface())
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+	errs := make(Errors, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		errs = append(errs, violation(fieldErr))
+	}
+	return errs
+}
+
+func (v *Validator) Engine() any {
+	return v.validate
+}
+
+// Messages maps the JSON name of every invalid field to its messages in
+// locale.
+func (e Errors) Messages(locale string) map[string][]string {
+	msgs := make(map[string][]string, len(e))
+	for _, v := range e {
+		msgs[v.Field] = append(msgs[v.Field], i18n.Translate(locale, v.Key, v.Args...))
+	}
+	return msgs
+}
+
+func (e Errors) Error() string {
+	msgs := e.Messages(i18n.DefaultLocale)
+	fields := make([]string, 0, len(msgs))
+	for field := range msgs {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	joined := make([]string, 0, len(fields))
+	for _, field := range fields {
+		joined = append(joined, strings.Join(msgs[field], ", "))
+	}
+	return strings.Join(joined, "; ")
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// fieldName is the path of the field below the request, e.g. "amount" or
+// "scopes[0]".
+func fieldName(fieldErr validator.FieldError) string {
+	_, name, found := strings.Cut(fieldErr.Namespace(), ".")
+	if !found {
+		return fieldErr.Field()
+	}
+	return name
+}
+
+func violation(fieldErr validator.FieldError) Violation {
+	field, param := fieldErr.Field(), fieldErr.Param()
+	v := Violation{
+		Field: fieldName(fieldErr),
+		Key:   "validation.invalid",
+		Args:  []any{field},
+	}
+	switch fieldErr.Tag() {
+	case "required", "email", "phone", "currency":
+		v.Key = "validation." + fieldErr.Tag()
+	case "oneof":
+		v.Key = "validation.enum"
+		v.Args = append(v.Args, strings.Join(strings.Fields(param), ", "))
+	case "min", "gte", "amount_min", "rate_min":
+		v.Key = "validation.min" + kindSuffix(fieldErr.Kind())
+		v.Args = append(v.Args, param)
+	case "max", "lte", "amount_max", "rate_max":
+		v.Key = "validation.max" + kindSuffix(fieldErr.Kind())
+		v.Args = append(v.Args, param)
+	}
+	if values, ok := lists[fieldErr.Tag()]; ok {
+		v.Key = "validation.enum"
+		v.Args = append(v.Args, strings.Join(values, ", "))
+	}
+	return v
+}
+
+// kindSuffix picks the message of a min or max rule, a length for strings
+// and a count for slices.
+func kindSuffix(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "_length"
+	case reflect.Slice:
+		return "_items"
+	}
+	return ""
+}
+
+func oneOf(values []string) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		return slices.Contains(values, fl.Field().String())
+	}
+}
+
+func phone(fl validator.FieldLevel) bool {
+	return phonePattern.MatchString(fl.Field().String())
+}
+
+func currency(fl validator.FieldLevel) bool {
+	return money.Currency(fl.Field().String()).Valid()
+}
+
+func amountMin(fl validator.FieldLevel) bool {
+	amount, ok := fl.Field().Interface().(money.Money)
+	return ok && amount.Cmp(money.MustParse(fl.Param())) >= 0
+}
+
+func amountMax(fl validator.FieldLevel) bool {
+	amount, ok := fl.Field().Interface().(money.Money)
+	return ok && amount.Cmp(money.MustParse(fl.Param())) <= 0
+}
+
+func rateMin(fl validator.FieldLevel) bool {
+	rate, ok := fl.Field().Interface().(money.Rate)
+	return ok && rate.Cmp(money.MustParseRate(fl.Param())) >= 0
+}
+
+func rateMax(fl validator.FieldLevel) bool {
+	rate, ok := fl.Field().Interface().(money.Rate)
+	return ok && rate.Cmp(money.MustParseRate(fl.Param())) <= 0
+}
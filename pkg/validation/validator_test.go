@@ -0,0 +1,145 @@
+package validation_test
+
+import (
+	"github.com/bowoBp/LoanFlow/internal/constant"
+	"github.com/bowoBp/LoanFlow/internal/i18n"
+	"github.com/bowoBp/LoanFlow/pkg/money"
+	"github.com/bowoBp/LoanFlow/pkg/validation"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type request struct {
+	Email    string         `json:"email" validate:"required,email"`
+	Phone    string         `json:"phone" validate:"omitempty,phone"`
+	Password string         `json:"password" validate:"omitempty,min=8"`
+	Role     string         `json:"role" validate:"omitempty,public_role"`
+	Currency money.Currency `json:"currency" validate:"omitempty,currency"`
+	Amount   money.Money    `json:"amount" validate:"amount_min=0.01,amount_max=1000"`
+	Rate     money.Rate     `json:"rate" validate:"rate_min=0,rate_max=100"`
+	Scopes   []string       `json:"scopes" validate:"omitempty,dive,api_key_scope"`
+	Sort     string         `json:"sort" validate:"omitempty,oneof=ASC DESC"`
+}
+
+func valid() request {
+	return request{
+		Email:  "budi@example.com",
+		Amount: money.MustParse("10"),
+		Rate:   money.MustParseRate("12.5"),
+	}
+}
+
+func TestValidator_ValidateStruct(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(r *request)
+		wantErr map[string][]string
+	}{
+		{
+			name:   "Success - every rule passes",
+			modify: func(r *request) {},
+		},
+		{
+			name: "Success - optional fields with valid values",
+			modify: func(r *request) {
+				r.Phone = "+6281234567890"
+				r.Password = "secret123"
+				r.Role = "INVESTOR"
+				r.Currency = money.IDR
+				r.Scopes = []string{"loans:read"}
+				r.Sort = "DESC"
+			},
+		},
+		{
+			name: "Error - required and email",
+			modify: func(r *request) {
+				r.Email = ""
+			},
+			wantErr: map[string][]string{"email": {"email is required"}},
+		},
+		{
+			name: "Error - formats and enums",
+			modify: func(r *request) {
+				r.Email = "budi"
+				r.Phone = "0812-abc"
+				r.Password = "short"
+				r.Role = "ADMIN"
+				r.Currency = "USD"
+				r.Scopes = []string{"loans:read", "ledger:write"}
+				r.Sort = "NEWEST"
+			},
+			wantErr: map[string][]string{
+				"email":     {"email must be a valid email address"},
+				"phone":     {"phone must be a valid phone number"},
+				"password":  {"password must be at least 8 characters"},
+				"role":      {"role must be one of BORROWER, INVESTOR"},
+				"currency":  {"currency is not a supported currency"},
+				"scopes[1]": {"scopes[1] must be one of " + strings.Join(constant.APIKeyScopes, ", ")},
+				"sort":      {"sort must be one of ASC, DESC"},
+			},
+		},
+		{
+			name: "Error - zero amount and negative rate",
+			modify: func(r *request) {
+				r.Amount = money.Money{}
+				r.Rate = money.MustParseRate("-1")
+			},
+			wantErr: map[string][]string{
+				"amount": {"amount must be at least 0.01"},
+				"rate":   {"rate must be at least 0"},
+			},
+		},
+		{
+			name: "Error - amount and rate above the maximum",
+			modify: func(r *request) {
+				r.Amount = money.MustParse("1000.01")
+				r.Rate = money.MustParseRate("100.5")
+			},
+			wantErr: map[string][]string{
+				"amount": {"amount must be at most 1000"},
+				"rate":   {"rate must be at most 100"},
+			},
+		},
+	}
+
+	v := validation.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.modify(&r)
+
+			err := v.ValidateStruct(&r)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var errs validation.Errors
+			assert.ErrorAs(t, err, &errs)
+			assert.Equal(t, tt.wantErr, errs.Messages(i18n.EnUS))
+		})
+	}
+}
+
+func TestErrors_Messages(t *testing.T) {
+	r := valid()
+	r.Email = ""
+	r.Password = "short"
+
+	err := validation.New().ValidateStruct(&r)
+
+	var errs validation.Errors
+	assert.ErrorAs(t, err, &errs)
+	assert.Equal(t, map[string][]string{
+		"email":    {"email wajib diisi"},
+		"password": {"password minimal 8 karakter"},
+	}, errs.Messages(i18n.IdID))
+	assert.Equal(t, "email is required; password must be at least 8 characters", err.Error())
+}
+
+func TestValidator_ValidateStruct_NotStruct(t *testing.T) {
+	v := validation.New()
+	assert.NoError(t, v.ValidateStruct(nil))
+	assert.NoError(t, v.ValidateStruct((*request)(nil)))
+	assert.NoError(t, v.ValidateStruct(&map[string]any{"email": ""}))
+}
@@ -0,0 +1,26 @@
+package helper
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/bowoBp/LoanFlow/internal/constant"
+)
+
+// GenerateAPIKey returns a new API key with its prefix, the prefix is kept
+// to tell keys apart without storing the key.
+func GenerateAPIKey() (key, prefix string, err error) {
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", err
+	}
+	key = constant.APIKeyPrefix + hex.EncodeToString(b)
+	return key, key[:len(constant.APIKeyPrefix)+8], nil
+}
+
+// HashAPIKey is the sha256 of the key, a key is random enough to not need a
+// slow hash.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,8 @@
+package helper
+
+import "strings"
+
+// NormalizeEmail lowercases and trims an email so it can be used as a key.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -1,66 +1,100 @@
 package helper
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/bowoBp/LoanFlow/pkg/environment"
+	"github.com/bowoBp/LoanFlow/internal/constant"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
-	"os"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 )
 
 type Jwt struct {
-	env environment.Environment
+	keys     *KeyRing
+	issuer   string
+	audience string
 }
 
 type JwtPayload struct {
 	ID        uint
 	UserName  string
 	UserRole  string
+	Locale    string
 	CreatedAt time.Time
+	MFA       bool
+	TokenID   string  `json:"jti"`
+	IssuedAt  float64 `json:"iat"`
+	ExpiresAt int64   `json:"exp"`
 }
 
 type JwtInterface interface {
-	GenerateToken(id uint, userRole, userName string, createdAt time.Time) (string, error)
+	GenerateToken(id uint, userRole, userName, locale string, createdAt time.Time, mfa bool) (string, error)
 	VerifyToken(c *gin.Context) (string, error)
 	ExtractPayloadFromToken(requestToken string) (res JwtPayload, err error)
 }
 
-func NewJwt() JwtInterface {
+// NewJwt signs with the active key of keys, issuer and audience are set as
+// the iss and aud claims and required when a token is verified.
+func NewJwt(keys *KeyRing, issuer, audience string) JwtInterface {
 	return &Jwt{
-		env: environment.NewEnvironment(),
+		keys:     keys,
+		issuer:   issuer,
+		audience: audience,
 	}
 }
 
-func (receiver Jwt) GenerateToken(id uint, userRole, userName string, createdAt time.Time) (string, error) {
-	method := jwt.SigningMethodHS256
+// GenerateToken signs an access token, locale is the language preference
+// of the user and mfa tells the login was completed with a second factor.
+func (receiver Jwt) GenerateToken(id uint, userRole, userName, locale string, createdAt time.Time, mfa bool) (string, error) {
+	tokenID, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	key := receiver.keys.Signing()
 	claims := jwt.MapClaims{
+		"iss":       receiver.issuer,
+		"aud":       receiver.audience,
+		"sub":       strconv.FormatUint(uint64(id), 10),
+		"jti":       tokenID,
+		"iat":       float64(now.UnixMicro()) / 1e6,
+		"exp":       now.Add(constant.AccessTokenTTL).Unix(),
 		"id":        id,
 		"userName":  userName,
 		"userRole":  userRole,
+		"locale":    locale,
 		"createdAt": createdAt,
-		"exp":       time.Now().Add(time.Hour * 24).Unix(),
+		"mfa":       mfa,
 	}
 
 	token := &jwt.Token{
 		Header: map[string]interface{}{
-			"typ": "jwt",
-			"alg": method.Alg(),
+			"typ": "JWT",
+			"alg": key.Method.Alg(),
+			"kid": key.ID,
 		},
 		Claims: claims,
-		Method: method,
+		Method: key.Method,
 	}
-	secretKey := os.Getenv("SECRET")
-	tokenString, err := token.SignedString([]byte(secretKey))
+	tokenString, err := token.SignedString(key.Private)
 	if err != nil {
 		return "", err
 	}
 	return tokenString, nil
 }
 
+// IssuedTime is the iat claim, it carries microseconds so a token minted in
+// the same second as a revocation of its user is told apart from it.
+func (p JwtPayload) IssuedTime() time.Time {
+	return time.UnixMicro(int64(math.Round(p.IssuedAt * 1e6)))
+}
+
 func (receiver Jwt) VerifyToken(c *gin.Context) (string, error) {
 	errResponse := errors.New("token invalid")
 	headerToken := c.Request.Header.Get("Authorization")
@@ -77,49 +111,58 @@ func (receiver Jwt) VerifyToken(c *gin.Context) (string, error) {
 	}
 	stringToken := parts[1]
 
-	// Parse token menggunakan secret key
-	token, err := jwt.Parse(stringToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		secretKey := os.Getenv("SECRET")
-		return []byte(secretKey), nil
-	})
+	if _, err := receiver.parse(stringToken); err != nil {
+		return "", errResponse // Mengembalikan error jika token tidak dapat diparse atau tidak valid
+	}
+
+	return stringToken, nil
+}
 
+func (receiver Jwt) ExtractPayloadFromToken(requestToken string) (res JwtPayload, err error) {
+	claims, err := receiver.parse(requestToken)
 	if err != nil {
-		return "", errResponse // Mengembalikan error jika token tidak dapat diparse atau method salah
+		return res, err
 	}
 
-	// Memeriksa kevalidan token
-	if claims, ok := token.Claims.(jwt.MapClaims); !ok || !token.Valid {
-		return "", errResponse
-	} else {
-		// Jika token valid, Anda bisa mengakses claims-nya di sini jika diperlukan
-		fmt.Println(claims) // Opsional: Menampilkan claims
-	}
+	cb, _ := json.Marshal(claims)
+	json.Unmarshal(cb, &res)
 
-	return stringToken, nil
+	return res, nil
 }
 
-func (receiver Jwt) ExtractPayloadFromToken(requestToken string) (res JwtPayload, err error) {
+// parse verifies the token with the key of the ring named by the kid header
+// and checks the expiry, issuer and audience claims.
+func (receiver Jwt) parse(requestToken string) (jwt.MapClaims, error) {
 	token, err := jwt.Parse(requestToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method: " + token.Header["alg"].(string))
+		kid, _ := token.Header["kid"].(string)
+		key, ok := receiver.keys.Key(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key: %q", kid)
+		}
+		// Algoritma harus sama dengan kunci agar token tidak bisa memilih metode lain
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		secretKey := os.Getenv("SECRET")
-		return []byte(secretKey), nil
+		return key.Private.Public(), nil
 	})
 	if err != nil {
-		return res, err
+		return nil, err
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok && !token.Valid {
-		return res, errors.New("invalid token")
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
 	}
+	if !claims.VerifyIssuer(receiver.issuer, true) || !claims.VerifyAudience(receiver.audience, true) {
+		return nil, errors.New("invalid token issuer or audience")
+	}
+	return claims, nil
+}
 
-	cb, _ := json.Marshal(claims)
-	json.Unmarshal(cb, &res)
-
-	return res, nil
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
 }
@@ -0,0 +1,183 @@
+package helper_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/bowoBp/LoanFlow/utils/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newKeys(t *testing.T) (rsaKey, edKey helper.SigningKey) {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rsaKey, err = helper.NewSigningKey("2026-09", private)
+	require.NoError(t, err)
+	_, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	edKey, err = helper.NewSigningKey("2026-10", edPrivate)
+	require.NoError(t, err)
+	return rsaKey, edKey
+}
+
+func TestJwt_ExtractPayloadFromToken(t *testing.T) {
+	rsaKey, edKey := newKeys(t)
+	oldRing, err := helper.NewKeyRing(rsaKey.ID, rsaKey)
+	require.NoError(t, err)
+	rotatedRing, err := helper.NewKeyRing(edKey.ID, rsaKey, edKey)
+	require.NoError(t, err)
+	retiredRing, err := helper.NewKeyRing(edKey.ID, edKey)
+	require.NoError(t, err)
+
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	signedFrom := time.Now().Truncate(time.Microsecond)
+	sign := func(ring *helper.KeyRing, audience string) string {
+		token, err := helper.NewJwt(ring, "loanflow", audience).
+			GenerateToken(7, "STAFF", "staff", "id-ID", createdAt, true)
+		require.NoError(t, err)
+		return token
+	}
+
+	tests := []struct {
+		name    string
+		ring    *helper.KeyRing
+		token   string
+		wantErr bool
+	}{
+		{
+			name:  "Success - RS256 token",
+			ring:  oldRing,
+			token: sign(oldRing, "loanflow-api"),
+		},
+		{
+			name:  "Success - EdDSA token",
+			ring:  rotatedRing,
+			token: sign(rotatedRing, "loanflow-api"),
+		},
+		{
+			name:  "Success - token signed before rotation",
+			ring:  rotatedRing,
+			token: sign(oldRing, "loanflow-api"),
+		},
+		{
+			name:    "Error - signing key removed from the ring",
+			ring:    retiredRing,
+			token:   sign(oldRing, "loanflow-api"),
+			wantErr: true,
+		},
+		{
+			name:    "Error - other audience",
+			ring:    rotatedRing,
+			token:   sign(rotatedRing, "reporting"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := helper.NewJwt(tt.ring, "loanflow", "loanflow-api").ExtractPayloadFromToken(tt.token)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, uint(7), got.ID)
+			assert.Equal(t, "STAFF", got.UserRole)
+			assert.Equal(t, "id-ID", got.Locale)
+			assert.True(t, got.MFA)
+			assert.True(t, createdAt.Equal(got.CreatedAt))
+			assert.Len(t, got.TokenID, 32)
+			// iat keeps the microseconds to be compared with a revocation
+			assert.False(t, got.IssuedTime().Before(signedFrom))
+			assert.False(t, got.IssuedTime().After(time.Now()))
+		})
+	}
+}
+
+func TestKeyRing_JWKS(t *testing.T) {
+	rsaKey, edKey := newKeys(t)
+	ring, err := helper.NewKeyRing(edKey.ID, rsaKey, edKey)
+	require.NoError(t, err)
+
+	got := ring.JWKS()
+
+	require.Len(t, got.Keys, 2)
+	assert.Equal(t, "2026-09", got.Keys[0].Kid)
+	assert.Equal(t, "RSA", got.Keys[0].Kty)
+	assert.Equal(t, "RS256", got.Keys[0].Alg)
+	assert.Equal(t, "AQAB", got.Keys[0].E)
+	assert.NotEmpty(t, got.Keys[0].N)
+	assert.Equal(t, "2026-10", got.Keys[1].Kid)
+	assert.Equal(t, "OKP", got.Keys[1].Kty)
+	assert.Equal(t, "Ed25519", got.Keys[1].Crv)
+	assert.Equal(t, "EdDSA", got.Keys[1].Alg)
+	assert.NotEmpty(t, got.Keys[1].X)
+
+	_, err = helper.NewKeyRing("2026-11", rsaKey, edKey)
+	assert.Error(t, err)
+}
+
+func TestLoadKeyRing(t *testing.T) {
+	dir := t.TempDir()
+	for _, kid := range []string{"2026-09", "2026-10"} {
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		raw, err := x509.MarshalPKCS8PrivateKey(private)
+		require.NoError(t, err)
+		pemKey := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: raw})
+		require.NoError(t, os.WriteFile(filepath.Join(dir, kid+".pem"), pemKey, 0o600))
+	}
+
+	tests := []struct {
+		name    string
+		dir     string
+		active  string
+		wantKid string
+		wantErr string
+	}{
+		{
+			name:    "Success - signs with the configured key",
+			dir:     dir,
+			active:  "2026-09",
+			wantKid: "2026-09",
+		},
+		{
+			name:    "Success - signs with the newest key without a kid",
+			dir:     dir,
+			wantKid: "2026-10",
+		},
+		{
+			name:    "Error - configured key missing",
+			dir:     dir,
+			active:  "2026-11",
+			wantErr: "the keys of",
+		},
+		{
+			name:    "Error - no key in the directory",
+			dir:     t.TempDir(),
+			wantErr: "make jwt_key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ring, err := helper.LoadKeyRing(tt.dir, tt.active)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantKid, ring.Signing().ID)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package helper
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type (
+	// SigningKey is a private key of the key ring, ID is sent as the kid
+	// header of the tokens it signs.
+	SigningKey struct {
+		ID      string
+		Method  jwt.SigningMethod
+		Private crypto.Signer
+	}
+
+	// KeyRing signs tokens with the active key and verifies tokens signed by
+	// any key of the ring, so a key can be rotated without logging users out.
+	KeyRing struct {
+		active string
+		keys   map[string]SigningKey
+	}
+
+	JWK struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		Alg string `json:"alg"`
+		Crv string `json:"crv,omitempty"`
+		X   string `json:"x,omitempty"`
+		N   string `json:"n,omitempty"`
+		E   string `json:"e,omitempty"`
+	}
+
+	JWKS struct {
+		Keys []JWK `json:"keys"`
+	}
+)
+
+// NewSigningKey picks the signing method from the key type, RS256 for RSA
+// and EdDSA for Ed25519 keys.
+func NewSigningKey(id string, private crypto.Signer) (SigningKey, error) {
+	key := SigningKey{ID: id, Private: private}
+	switch private.(type) {
+	case *rsa.PrivateKey:
+		key.Method = jwt.SigningMethodRS256
+	case ed25519.PrivateKey:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return key, fmt.Errorf("key %s: unsupported key type %T", id, private)
+	}
+	return key, nil
+}
+
+func NewKeyRing(active string, keys ...SigningKey) (*KeyRing, error) {
+	ring := &KeyRing{
+		active: active,
+		keys:   make(map[string]SigningKey, len(keys)),
+	}
+	for _, key := range keys {
+		ring.keys[key.ID] = key
+	}
+	if _, ok := ring.keys[active]; !ok {
+		return nil, fmt.Errorf("signing key %q not found in key ring", active)
+	}
+	return ring, nil
+}
+
+// LoadKeyRing reads every "<kid>.pem" private key of dir, RSA keys in PKCS#1
+// or PKCS#8 and Ed25519 keys in PKCS#8, and signs with the key named active.
+// Without active it signs with the last kid in order, the newest when the
+// kids are dated like 2026-10.
+func LoadKeyRing(dir, active string) (*KeyRing, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]SigningKey, 0, len(files))
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		kid := strings.TrimSuffix(filepath.Base(file), ".pem")
+		private, err := parsePrivateKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", kid, err)
+		}
+		key, err := NewSigningKey(kid, private)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signing key found in %q, create one with `make jwt_key kid=<kid>`", dir)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ID < keys[j].ID
+	})
+	if active == "" {
+		active = keys[len(keys)-1].ID
+	}
+	ring, err := NewKeyRing(active, keys...)
+	if err != nil {
+		kids := make([]string, 0, len(keys))
+		for _, key := range keys {
+			kids = append(kids, key.ID)
+		}
+		return nil, fmt.Errorf("%w, the keys of %q are %s", err, dir, strings.Join(kids, ", "))
+	}
+	return ring, nil
+}
+
+func parsePrivateKey(raw []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+	return signer, nil
+}
+
+func (r *KeyRing) Signing() SigningKey {
+	return r.keys[r.active]
+}
+
+func (r *KeyRing) Key(kid string) (SigningKey, bool) {
+	key, ok := r.keys[kid]
+	return key, ok
+}
+
+// JWKS publishes the public keys of the ring for the services verifying
+// LoanFlow tokens.
+func (r *KeyRing) JWKS() JWKS {
+	kids := make([]string, 0, len(r.keys))
+	for kid := range r.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	set := JWKS{Keys: make([]JWK, 0, len(kids))}
+	for _, kid := range kids {
+		key := r.keys[kid]
+		jwk := JWK{Kid: kid, Use: "sig", Alg: key.Method.Alg()}
+		switch public := key.Private.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}